@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"crypto/cipher"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	crawlerConfigPath = "settings/crawler.yaml"
+	scraperConfigPath = "settings/scraper.yaml"
+)
+
+// newAppLoggerは、標準出力に出力するアプリケーションロガーを生成します。
+func newAppLogger() logger.AppLogger {
+	logHandler := slog.NewTextHandler(os.Stdout, nil)
+	return logger.NewAppLogger(slog.New(logHandler))
+}
+
+// newRedisClientは、環境変数の接続情報からRedisクライアントを生成します。
+func newRedisClient() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     os.Getenv("REDIS_ADDRESS"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       0,
+	})
+}
+
+// crawlWorkerIDEnvは、クロールジョブを実行するワーカーのIDを設定する環境変数の名前です。
+const crawlWorkerIDEnv = "CRAWLER_WORKER_ID"
+
+// crawlWorkerIDは、環境変数に設定されたワーカーのIDを返します。設定されていない場合は、ホスト名とプロセスIDから生成します。
+func crawlWorkerID() string {
+	if id := os.Getenv(crawlWorkerIDEnv); id != "" {
+		return id
+	}
+	return infra.NewCrawlWorkerID()
+}
+
+// htmlEncryptionKeyEnvは、HTMLの暗号化に使用する鍵を設定する環境変数の名前です。
+const htmlEncryptionKeyEnv = "HTML_ENCRYPTION_KEY"
+
+// newHTMLCipherは、環境変数に設定された鍵から、HTMLの暗号化と復号に使用するAES-GCMを生成します。
+// 鍵が設定されていない場合はnilを返します。
+func newHTMLCipher() (cipher.AEAD, error) {
+	key := os.Getenv(htmlEncryptionKeyEnv)
+	if key == "" {
+		return nil, nil
+	}
+	aead, err := infra.NewHTMLCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%sが不正です: %w", htmlEncryptionKeyEnv, err)
+	}
+	return aead, nil
+}
+
+// newObjectHTMLStorageは、環境変数の認証情報を使用して、オブジェクトストレージ（S3またはGCS）のHTMLの保存先を生成します。
+// S3の場合はAWS_ACCESS_KEY_ID・AWS_SECRET_ACCESS_KEY・AWS_SESSION_TOKENを、
+// GCSの場合はHMACキーのGCS_HMAC_ACCESS_ID・GCS_HMAC_SECRETを使用します。
+//
+// args:
+//
+//	ctx     : アップロードの再試行の待機を中断するためのコンテキスト
+//	storage : 保存先の種類（s3またはgcs）
+//	cfg     : バケットとアップロードの設定
+//	dir     : 保存時に使用する名前空間
+//
+// return:
+//
+//	infra.HTMLStorage : 生成された保存先
+//	error             : 設定や認証情報が不足している場合のエラー
+func newObjectHTMLStorage(ctx context.Context, storage config.StorageType, cfg *config.ObjectStorageConfig, dir string) (infra.HTMLStorage, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("storageが%sの場合はobject_storageが必要です", storage)
+	}
+
+	creds := infra.ObjectStorageCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if storage == config.StorageGCS {
+		creds = infra.ObjectStorageCredentials{
+			AccessKeyID:     os.Getenv("GCS_HMAC_ACCESS_ID"),
+			SecretAccessKey: os.Getenv("GCS_HMAC_SECRET"),
+		}
+	}
+	return infra.NewObjectHTMLStorage(ctx, storage, *cfg, creds, dir)
+}
+
+// newSignalContextは、SIGINTまたはSIGTERMを受け取るとキャンセルされるコンテキストを生成します。
+// 1回目のシグナルで処理中の作業を終えてから終了し、2回目のシグナルでは即座に強制終了できるよう、
+// キャンセル後はシグナルの扱いをデフォルトに戻します。
+func newSignalContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// notifyRunは、実行結果を通知先に送信します。通知に失敗しても、実行の結果には影響させずにログに出力します。
+//
+// args:
+//
+//	ctx       : 実行に使用したコンテキスト（中断されている場合は中断として通知する）
+//	notifier  : 通知先
+//	summary   : 開始日時と件数を設定した実行結果
+//	runErr    : 実行が失敗した場合の原因
+//	appLogger : ロガー
+func notifyRun(ctx context.Context, notifier infra.Notifier, summary infra.RunSummary, runErr error, appLogger logger.AppLogger) {
+	summary = summary.Finish(ctx, runErr)
+	// 中断された場合も通知を送信できるよう、キャンセルを伝播させない
+	if err := notifier.Notify(context.WithoutCancel(ctx), summary); err != nil {
+		appLogger.Warn("実行結果の通知に失敗しました", "error", err)
+	}
+}
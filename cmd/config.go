@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateCrawler bool
+	validateScraper bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "設定ファイルを操作します",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "設定ファイルを検証します",
+	Long: `設定ファイルを読み込み、バリデーション、CSSセレクターと正規表現の構文、ページネーションの設定の整合性を確認します。
+問題が見つかった場合は、項目ごとに内容と対処方法を表示して終了コード1で終了します。
+--crawler または --scraper で設定ファイルの種類を指定します。pathを省略した場合は settings/ 以下の設定ファイルを検証します。
+どちらも指定しない場合は、settings/ 以下のクローラーとスクレイパーの設定ファイルを両方検証します。`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		type target struct {
+			path     string
+			validate func(path string) ([]config.ConfigIssue, error)
+		}
+
+		var targets []target
+		switch {
+		case validateCrawler:
+			targets = []target{{crawlerConfigPath, config.ValidateCrawlerConfigFile}}
+		case validateScraper:
+			targets = []target{{scraperConfigPath, config.ValidateScraperConfigFile}}
+		case len(args) > 0:
+			fmt.Fprintln(os.Stderr, "pathを指定する場合は --crawler または --scraper で設定ファイルの種類を指定してください")
+			os.Exit(2)
+		default:
+			targets = []target{
+				{crawlerConfigPath, config.ValidateCrawlerConfigFile},
+				{scraperConfigPath, config.ValidateScraperConfigFile},
+			}
+		}
+		if len(args) > 0 {
+			targets[0].path = args[0]
+		}
+
+		failed := 0
+		for _, t := range targets {
+			issues, err := t.validate(t.path)
+			if err != nil {
+				failed++
+				fmt.Printf("[NG] %s\n     %v\n", t.path, err)
+				continue
+			}
+			if len(issues) == 0 {
+				fmt.Printf("[OK] %s\n", t.path)
+				continue
+			}
+
+			failed++
+			fmt.Printf("[NG] %s (%d件の問題)\n", t.path, len(issues))
+			for _, issue := range issues {
+				fmt.Printf("     %s\n", issue)
+			}
+		}
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&validateCrawler, "crawler", false, "クローラーの設定ファイルとして検証する")
+	configValidateCmd.Flags().BoolVar(&validateScraper, "scraper", false, "スクレイパーの設定ファイルとして検証する")
+	configValidateCmd.MarkFlagsMutuallyExclusive("crawler", "scraper")
+
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
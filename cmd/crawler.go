@@ -1,109 +1,485 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
-	"log"
-	"log/slog"
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
 	"github.com/nrad-K/go-crawler/internal/infra"
 	"github.com/nrad-K/go-crawler/internal/logger"
 	"github.com/nrad-K/go-crawler/internal/usecase"
-	"github.com/redis/go-redis/v9"
 	"github.com/spf13/cobra"
 )
 
 var (
-	generate bool
-	execute  bool
+	generate   bool
+	execute    bool
+	recrawlDue bool
 )
 
 var crawlerCmd = &cobra.Command{
 	Use:   "crawler",
 	Short: "求人情報をクロールし、HTMLを保存します",
-	Long:  `設定に基づき、求人情報のURLを収集（--generate）し、各URLのHTMLコンテンツを保存（--execute）します。`,
+	Long: `設定に基づき、求人情報のURLを収集（--generate）し、各URLのHTMLコンテンツを保存（--execute）します。
+--recrawl-due を指定した場合は、最後のクロールから job.recrawl_after_hours 以上経過したSUCCESSのジョブをPENDINGに戻し、再クロールの対象にします。`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if !generate && !execute {
+		if !generate && !execute && !recrawlDue {
 			cmd.Help()
 			return
 		}
+		runCrawler(crawlerRunOptions{generate: generate, execute: execute, recrawlDue: recrawlDue})
+	},
+}
 
-		ctx := context.Background()
+// crawlerRunOptionsは、クローラーの1回の実行で行う処理を指定します。
+//
+// フィールド:
+//
+//	generate         : クロールジョブを生成する
+//	execute          : クロールジョブを実行する
+//	refresh          : 実行の前に、SUCCESSのジョブをPENDINGに戻して再クロールの対象にする
+//	refreshOlderThan : refreshで対象とする、最後のクロールからの経過時間（0の場合はすべてのSUCCESSのジョブ）
+//	recrawlDue       : 実行の前に、job.recrawl_after_hours以上経過したSUCCESSのジョブをPENDINGに戻す
+type crawlerRunOptions struct {
+	generate         bool
+	execute          bool
+	refresh          bool
+	refreshOlderThan time.Duration
+	recrawlDue       bool
+}
 
-		err := godotenv.Load()
-		if err != nil {
-			// build 時の時は何もしない
+// crawlRunSummaryは、クローラーの1回の実行の結果です。
+//
+// フィールド:
+//
+//	Generated : 生成したクロールジョブ数
+//	Result    : クロールジョブの実行結果（成功・失敗したジョブ数）
+//	Usage     : リソース使用量
+//	Recycles  : ブラウザのページとコンテキストを作り直した回数
+type crawlRunSummary struct {
+	Generated int
+	Result    usecase.CrawlExecuteResult
+	Usage     infra.Usage
+	Recycles  infra.BrowserRecycles
+}
+
+// runCrawlerは、クローラーを1回実行し、失敗した場合はプロセスを終了します。
+//
+// args:
+//
+//	opts : 実行する処理
+func runCrawler(opts crawlerRunOptions) {
+	ctx, stop := newSignalContext()
+	defer stop()
+
+	// logger初期化
+	appLogger := newAppLogger()
+
+	if _, err := runCrawlerCycle(ctx, opts, appLogger); err != nil {
+		appLogger.Error("クローラーの実行に失敗しました", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runCrawlerCycleは、設定ファイルを読み込んでクローラーの依存関係を初期化し、指定された処理を1回実行します。
+// ブラウザやRedisクライアントは実行の終了時に閉じるため、crawler daemonから繰り返し呼び出せます。
+//
+// args:
+//
+//	ctx       : コンテキスト
+//	opts      : 実行する処理
+//	appLogger : ロガー
+//
+// return:
+//
+//	crawlRunSummary : 実行の結果
+//	error           : 初期化や処理に失敗した場合のエラー
+func runCrawlerCycle(ctx context.Context, opts crawlerRunOptions, appLogger logger.AppLogger) (summary crawlRunSummary, err error) {
+	// 設定ファイル読み込み
+	cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+	if err != nil {
+		return summary, fmt.Errorf("設定ファイルの読み込みに失敗: %w", err)
+	}
+
+	// 実行の終了時に、生成・実行したジョブ数や所要時間を通知先に送信する
+	var configHash string
+	startedAt := time.Now()
+	notifier := infra.NewNotifiersFromConfig(cfg.Notifications)
+	defer func() {
+		notifyRun(ctx, notifier, infra.RunSummary{
+			Command:    "crawler",
+			StartedAt:  startedAt,
+			Generated:  summary.Generated,
+			Executed:   summary.Result.Success + summary.Result.Failed,
+			Failed:     summary.Result.Failed,
+			ConfigHash: configHash,
+		}, err, appLogger)
+	}()
+
+	// 実行の終了時に、パイプラインの制御や監査のための実行結果のレポートを書き出す
+	// 他の終了処理で集計した結果を含めるよう、通知の直前に実行する
+	outputs := infra.CrawlRunOutputs{Storage: string(cmp.Or(cfg.Storage, config.StorageLocal)), HTMLDir: cfg.OutputDir, ListPageDir: cfg.ListPageDir}
+	if cfg.RunReport != "" {
+		defer func() {
+			writeCrawlRunReport(cfg.RunReport, crawlRunReportOf(opts, summary, outputs, configHash, startedAt, err), appLogger)
+		}()
+	}
+
+	// フック初期化
+	hook, err := infra.NewHooksFromConfig(cfg.Hooks, infra.DefaultExportFields())
+	if err != nil {
+		return summary, fmt.Errorf("フックの初期化に失敗しました: %w", err)
+	}
+
+	// Redisクライアント初期化
+	rdb := newRedisClient()
+	defer rdb.Close()
+	// Redisへの接続を確認 (ping)
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return summary, fmt.Errorf("Redisへの接続に失敗しました: %w", err)
+	}
+	appLogger.Info("Redisへの接続を確認しました")
+
+	// 取得したHTMLや集計結果から、使用した設定を辿れるようにする
+	configHash, err = cfg.Hash()
+	if err != nil {
+		return summary, fmt.Errorf("設定のハッシュ計算に失敗しました: %w", err)
+	}
+	outputs.ConfigSnapshots = writeCrawlerConfigSnapshots(cfg, configHash, appLogger)
+
+	// repository初期化
+	repo := infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL())
+
+	// browser client初期化
+	browserClient, err := infra.NewBrowserClient(&cfg)
+	if err != nil {
+		return summary, fmt.Errorf("ブラウザクライアントの初期化に失敗: %w", err)
+	}
+	defer browserClient.Close()
+	defer func() {
+		recycles := browserClient.Recycles()
+		summary.Recycles = recycles
+		if recycles.Total() > 0 {
+			appLogger.Info("ブラウザのページとコンテキストを作り直しました",
+				"total", recycles.Total(),
+				"by_navigations", recycles.ByNavigations,
+				"by_memory", recycles.ByMemory,
+			)
 		}
+	}()
 
-		// 設定ファイル読み込み
-		path := "settings/crawler.yaml"
-		cfg, err := config.LoadCrawlerConfig(path)
-		if err != nil {
-			log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	// ドメインごとのリクエスト数・間隔・エラー率・転送量を集計する
+	auditedClient := infra.NewAuditedBrowserClient(browserClient)
+	defer func() {
+		report := auditedClient.Report()
+		report.ConfigHash = configHash
+		for _, domain := range report.Domains {
+			appLogger.Info("ドメインごとのリクエスト",
+				"domain", domain.Domain,
+				"requests", domain.Requests,
+				"avg_interval_seconds", domain.AvgIntervalSeconds,
+				"error_rate", domain.ErrorRate,
+				"bytes", domain.Bytes,
+			)
+		}
+		if cfg.AuditDir == "" || len(report.Domains) == 0 {
+			return
+		}
+		path := filepath.Join(cfg.AuditDir, "politeness_"+report.StartedAt.Format("20060102_150405")+".json")
+		if err := infra.WritePolitenessReport(path, report); err != nil {
+			appLogger.Error("リクエストの集計結果を出力できませんでした", "path", path, "error", err)
+			return
 		}
+		outputs.PolitenessReport = path
+		appLogger.Info("リクエストの集計結果を出力しました", "path", path)
+	}()
 
-		// logger初期化
-		logHandler := slog.NewTextHandler(os.Stdout, nil)
-		appLogger := logger.NewAppLogger(slog.New(logHandler))
+	// 実行あたりのリソース使用量を計測し、上限に達したら処理を止める
+	// 上限により実行しなかったナビゲーションは集計しないよう、集計用のクライアントをラップする
+	meteredClient := infra.NewMeteredBrowserClient(auditedClient, cfg.Quota)
+	defer func() {
+		usage := meteredClient.Usage()
+		summary.Usage = usage
+		appLogger.Info("リソース使用量",
+			"pages", usage.Pages,
+			"bytes", usage.Bytes,
+			"browser_seconds", int(usage.BrowserTime.Seconds()),
+		)
+	}()
 
-		// Redisクライアント初期化
-		rdb := redis.NewClient(&redis.Options{
-			Addr:     os.Getenv("REDIS_ADDRESS"),
-			Password: os.Getenv("REDIS_PASSWORD"),
-			DB:       0,
-		})
-		// Redisへの接続を確認 (ping)
-		if err := rdb.Ping(ctx).Err(); err != nil {
-			appLogger.Error("Redisへの接続に失敗しました", "error", err)
-			os.Exit(1)
+	expiry, err := infra.NewExpiryDetector(cfg.Expired)
+	if err != nil {
+		return summary, fmt.Errorf("掲載終了の判定条件が不正です: %w", err)
+	}
+	blocks, err := infra.NewBlockDetector(cfg.Blocked)
+	if err != nil {
+		return summary, fmt.Errorf("アクセスを拒否されたページの判定条件が不正です: %w", err)
+	}
+	links, err := infra.NewDetailLinkPattern(cfg.Selector.DetailLinksPattern)
+	if err != nil {
+		return summary, fmt.Errorf("詳細ページのリンクの抽出条件が不正です: %w", err)
+	}
+
+	// HTMLの保存先を設定に応じて切り替える
+	var storage infra.HTMLWriter = browserClient
+	switch cfg.Storage {
+	case config.StorageRedis:
+		storage = infra.NewRedisHTMLStorage(rdb, cfg.OutputDir)
+	case config.StorageS3, config.StorageGCS:
+		storage, err = newObjectHTMLStorage(ctx, cfg.Storage, cfg.ObjectStorage, cfg.OutputDir)
+		if err != nil {
+			return summary, fmt.Errorf("HTMLの保存先の初期化に失敗しました: %w", err)
+		}
+	}
+	// 一覧ページのHTMLは、スクレイパーが求人として読み込まないよう詳細ページとは別の名前空間に保存する
+	var listPages infra.HTMLWriter
+	if cfg.ListPageDir != "" {
+		switch cfg.Storage {
+		case config.StorageRedis:
+			listPages = infra.NewRedisHTMLStorage(rdb, cfg.ListPageDir)
+		case config.StorageS3, config.StorageGCS:
+			listPages, err = newObjectHTMLStorage(ctx, cfg.Storage, cfg.ObjectStorage, cfg.ListPageDir)
+			if err != nil {
+				return summary, fmt.Errorf("一覧ページのHTMLの保存先の初期化に失敗しました: %w", err)
+			}
+		default:
+			listPages = infra.NewHTMLFileWriter(cfg.ListPageDir)
+		}
+	}
+	// 詳細ページのレスポンスのキャッシュの保存先を設定に応じて切り替える
+	var cache infra.ResponseCache
+	if cfg.Cache != nil {
+		if cfg.Cache.Storage == config.StorageRedis {
+			cache = infra.NewRedisResponseCache(rdb, cfg.Cache.DirOrDefault(cfg.OutputDir))
+		} else {
+			cache = infra.NewFileResponseCache(cfg.Cache.DirOrDefault(cfg.OutputDir))
 		}
-		appLogger.Info("Redisへの接続を確認しました")
+	}
+	if cfg.EncryptHTML {
+		aead, err := newHTMLCipher()
+		if err != nil {
+			return summary, fmt.Errorf("暗号化の鍵の読み込みに失敗しました: %w", err)
+		}
+		if aead == nil {
+			return summary, fmt.Errorf("encrypt_htmlが有効ですが、%sが設定されていません", htmlEncryptionKeyEnv)
+		}
+		// 第三者のページの内容を平文で保存しないよう、保存前に暗号化する
+		storage = infra.NewEncryptedHTMLWriter(storage, aead)
+		if listPages != nil {
+			listPages = infra.NewEncryptedHTMLWriter(listPages, aead)
+		}
+		if cache != nil {
+			cache = infra.NewEncryptedResponseCache(cache, aead)
+		}
+	}
 
-		// repository初期化
-		repo := infra.NewCrawlJobClient(rdb)
+	// 詳細ページのPDFは、HTMLと同じ保存先に保存する
+	var pdfs infra.PDFWriter
+	if cfg.ExportPDF {
+		writer, ok := storage.(infra.PDFWriter)
+		if !ok {
+			return summary, fmt.Errorf("storageが%sの場合はexport_pdfを使用できません", cmp.Or(cfg.Storage, config.StorageLocal))
+		}
+		pdfs = writer
+	}
 
-		// browser client初期化
-		browserClient, err := infra.NewBrowserClient(&cfg)
-		if err != nil {
-			log.Fatalf("ブラウザクライアントの初期化に失敗: %v", err)
+	// エラーが増えた場合にジョブの間の待機時間を広げる
+	throttle := infra.NewAdaptiveThrottle(cfg.Throttle)
+	if throttle != nil {
+		defer func() {
+			if stats := throttle.Stats(); stats.Increases > 0 {
+				appLogger.Info("エラーが増えたため、ジョブの間の待機時間を広げました",
+					"increases", stats.Increases,
+					"max_delay", stats.MaxDelay.String(),
+				)
+			}
+		}()
+	}
+
+	ucArgs := usecase.CrawlerArgs{
+		Cfg:        &cfg,
+		Client:     meteredClient,
+		Storage:    storage,
+		Repo:       repo,
+		Control:    infra.NewCrawlControlClient(rdb),
+		Hook:       hook,
+		Logger:     appLogger,
+		ConfigHash: configHash,
+		Expiry:     expiry,
+		Blocks:     blocks,
+		Links:      links,
+		PDFs:       pdfs,
+		Cache:      cache,
+		Hashes:     infra.NewContentHashClient(rdb),
+		ListPages:  listPages,
+		Throttle:   throttle,
+		Workers:    infra.NewCrawlWorkerClient(rdb),
+		WorkerID:   crawlWorkerID(),
+	}
+
+	// 一覧ページから生成し直さずに鮮度を保てるよう、再クロールの時期を過ぎたジョブのみを戻す
+	if opts.recrawlDue {
+		if cfg.Job.RecrawlAfter() == 0 {
+			return summary, fmt.Errorf("--recrawl-dueを指定する場合は、設定ファイルにjob.recrawl_after_hoursを指定してください")
 		}
-		defer browserClient.Close()
+		opts.refresh = true
+		opts.refreshOlderThan = cfg.Job.RecrawlAfter()
+	}
 
-		ucArgs := usecase.CrawlerArgs{
-			Cfg:    &cfg,
-			Client: browserClient,
+	// crawl refresh
+	if opts.refresh {
+		maintainUC := usecase.NewMaintainCrawlJobUseCase(usecase.MaintainCrawlJobArgs{
 			Repo:   repo,
 			Logger: appLogger,
+			StreamOptions: model.CrawlJobStreamOptions{
+				BatchSize: cfg.Job.StreamBatchSize,
+				Prefetch:  cfg.Job.StreamPrefetch,
+			},
+		})
+		appLogger.Info("クロール済みのジョブを再クロールの対象に戻します", "older_than", opts.refreshOlderThan.String())
+		requeued, err := maintainUC.Requeue(ctx, model.CrawlJobStatusSuccess, opts.refreshOlderThan, false)
+		if err != nil {
+			return summary, fmt.Errorf("クロール済みのジョブを再クロールの対象に戻せませんでした（%d件を戻しました）: %w", requeued, err)
 		}
+		appLogger.Info("クロール済みのジョブを再クロールの対象に戻しました", "requeued", requeued)
+	}
 
-		// crawl generate
-		if generate {
-			generateUC := usecase.NewGenerateCrawlJobUseCase(ucArgs)
-			appLogger.Info("クロールジョブの生成を開始します")
-			if err := generateUC.GenerateCrawlJob(ctx); err != nil {
-				appLogger.Error("クロールジョブの生成中にエラーが発生しました", "error", err)
-				os.Exit(1)
-			}
-			appLogger.Info("クロールジョブの生成が正常に完了しました")
+	// crawl generate
+	if opts.generate {
+		generateUC := usecase.NewGenerateCrawlJobUseCase(ucArgs)
+		appLogger.Info("クロールジョブの生成を開始します")
+		generated, err := generateUC.GenerateCrawlJob(ctx)
+		summary.Generated = generated
+		if err != nil {
+			return summary, fmt.Errorf("クロールジョブの生成中にエラーが発生しました: %w", err)
 		}
+		appLogger.Info("クロールジョブの生成が正常に完了しました")
+	}
 
-		// crawl execute
-		if execute {
-			executeUC := usecase.NewExecuteCrawlJobUseCase(ucArgs)
-			appLogger.Info("クロールジョブの実行を開始します")
-			if err := executeUC.ExecuteCrawlJob(ctx); err != nil {
-				appLogger.Error("クロールジョブの実行中にエラーが発生しました", "error", err)
-				os.Exit(1)
-			}
-			appLogger.Info("クロールジョブの実行が正常に完了しました")
+	// crawl execute
+	if opts.execute && ctx.Err() == nil {
+		executeUC := usecase.NewExecuteCrawlJobUseCase(ucArgs)
+		appLogger.Info("クロールジョブの実行を開始します")
+		result, err := executeUC.ExecuteCrawlJob(ctx)
+		summary.Result = result
+		if err != nil {
+			return summary, fmt.Errorf("クロールジョブの実行中にエラーが発生しました: %w", err)
 		}
-	},
+		appLogger.Info("クロールジョブの実行が正常に完了しました")
+	}
+	return summary, nil
+}
+
+// writeCrawlerConfigSnapshotsは、HTMLの保存先（ローカルの場合）と集計結果の出力先に、実行に使用した設定のスナップショットを書き込みます。
+// スナップショットの書き込みに失敗してもクロールは続行します。
+//
+// args:
+//
+//	cfg        : クローラーの設定
+//	configHash : 設定のハッシュ値
+//	appLogger  : ロガー
+//
+// return:
+//
+//	[]string : 書き込んだスナップショットのパス
+func writeCrawlerConfigSnapshots(cfg config.CrawlerConfig, configHash string, appLogger logger.AppLogger) []string {
+	var dirs []string
+	if cfg.Storage == "" || cfg.Storage == config.StorageLocal {
+		dirs = append(dirs, cfg.OutputDir)
+	}
+	if cfg.AuditDir != "" {
+		dirs = append(dirs, cfg.AuditDir)
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		name, err := infra.WriteConfigSnapshot(dir, "crawler", crawlerConfigPath, configHash)
+		if err != nil {
+			appLogger.Warn("設定のスナップショットを書き込めませんでした", "dir", dir, "error", err)
+			continue
+		}
+		path := filepath.Join(dir, name)
+		paths = append(paths, path)
+		appLogger.Info("設定のスナップショットを書き込みました", "path", path, "config_hash", configHash)
+	}
+	return paths
+}
+
+// crawlRunReportOfは、クローラーの1回の実行の結果から実行結果のレポートを作成します。
+//
+// args:
+//
+//	opts       : 実行した処理
+//	summary    : 実行の結果
+//	outputs    : 実行で書き込んだ出力先
+//	configHash : 設定のハッシュ値
+//	startedAt  : 実行を開始した日時
+//	runErr     : 実行に失敗した場合のエラー
+//
+// return:
+//
+//	infra.CrawlRunReport : 実行結果のレポート
+func crawlRunReportOf(opts crawlerRunOptions, summary crawlRunSummary, outputs infra.CrawlRunOutputs, configHash string, startedAt time.Time, runErr error) infra.CrawlRunReport {
+	var operations []string
+	if opts.refresh {
+		operations = append(operations, "refresh")
+	}
+	if opts.generate {
+		operations = append(operations, "generate")
+	}
+	if opts.execute {
+		operations = append(operations, "execute")
+	}
+
+	report := infra.CrawlRunReport{
+		ConfigHash:      configHash,
+		StartedAt:       startedAt,
+		FinishedAt:      time.Now(),
+		Operations:      operations,
+		Generated:       summary.Generated,
+		Success:         summary.Result.Success,
+		Failed:          summary.Result.Failed,
+		Blocked:         summary.Result.Blocked,
+		Drained:         summary.Result.Drained,
+		TopErrors:       infra.TopCrawlErrors(summary.Result.Errors),
+		Pages:           summary.Usage.Pages,
+		Bytes:           summary.Usage.Bytes,
+		BrowserSeconds:  int(summary.Usage.BrowserTime.Seconds()),
+		BrowserRecycles: summary.Recycles.Total(),
+		Outputs:         outputs,
+	}
+	if processed := summary.Result.Success + summary.Result.Failed; processed > 0 {
+		report.AvgFetchMillis = (summary.Result.FetchTime / time.Duration(processed)).Milliseconds()
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+	return report
+}
+
+// writeCrawlRunReportは、実行結果のレポートを書き出します。書き出しに失敗しても実行の結果は変えません。
+//
+// args:
+//
+//	path      : 出力先のパス
+//	report    : 実行結果のレポート
+//	appLogger : ロガー
+func writeCrawlRunReport(path string, report infra.CrawlRunReport, appLogger logger.AppLogger) {
+	if err := infra.WriteCrawlRunReport(path, report); err != nil {
+		appLogger.Warn("実行結果のレポートを出力できませんでした", "path", path, "error", err)
+		return
+	}
+	appLogger.Info("実行結果のレポートを出力しました", "path", path)
 }
 
 func init() {
 	rootCmd.AddCommand(crawlerCmd)
 	crawlerCmd.Flags().BoolVarP(&generate, "generate", "g", false, "クロールジョブを生成します")
 	crawlerCmd.Flags().BoolVarP(&execute, "execute", "e", false, "クロールジョブを実行します")
+	crawlerCmd.Flags().BoolVar(&recrawlDue, "recrawl-due", false, "最後のクロールからjob.recrawl_after_hours以上経過したSUCCESSのジョブを再クロールの対象に戻します")
 }
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/spf13/cobra"
+)
+
+// controlCommandsは、controlサブコマンドの引数と操作指示の対応です。
+var controlCommands = map[string]model.CrawlControlCommand{
+	"pause":  model.CrawlControlPause,
+	"resume": model.CrawlControlRun,
+	"stop":   model.CrawlControlStop,
+	"drain":  model.CrawlControlDrain,
+}
+
+var crawlerControlCmd = &cobra.Command{
+	Use:   "control [pause|resume|stop|drain|status]",
+	Short: "実行中のクローラーを一時停止・再開・停止します",
+	Long: `Redisに操作指示を書き込み、実行中のクローラー（--execute）を一時停止・再開・停止します。stopは処理中のジョブを終えてから停止します。
+stopの指示はキューを共有するすべてのクローラーを停止させるため残り続け、resumeを実行するまで（crawler daemonでは次の実行時刻まで）クローラーは実行されません。
+drainは新しいジョブの生成を止め、キューに残っているジョブを処理し終えた時点で停止します。crawler daemonは、ドレインの完了後に終了します。
+drainの指示もキューを共有するすべてのクローラーが停止できるよう残り続けるため、再びクロールするにはresumeを実行してください。
+statusは現在の操作指示を表示します。`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"pause", "resume", "stop", "drain", "status"},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		rdb := newRedisClient()
+		defer rdb.Close()
+		control := infra.NewCrawlControlClient(rdb)
+
+		if args[0] == "status" {
+			command, err := control.Get(ctx)
+			if err != nil {
+				log.Fatalf("操作指示の取得に失敗: %v", err)
+			}
+			fmt.Println(command)
+			return
+		}
+
+		command, ok := controlCommands[args[0]]
+		if !ok {
+			log.Fatalf("不明な操作です: %s", args[0])
+		}
+		if err := control.Set(ctx, command); err != nil {
+			log.Fatalf("操作指示の保存に失敗: %v", err)
+		}
+		fmt.Printf("操作指示を %s に設定しました\n", command)
+	},
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerControlCmd)
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// crawlRunLockTTLは、スケジュール実行中のロックの有効期限です。実行中はこの3分の1の間隔で延長します。
+const crawlRunLockTTL = 10 * time.Minute
+
+var crawlerDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "設定のスケジュールに従って、クロールジョブの生成と実行を繰り返します",
+	Long: `設定ファイルの schedule（cron式）に従って、クロールジョブの生成（--generate）と実行（--execute）を繰り返します。
+外部のcronやシェルスクリプトを用意せずに、定期的なクロールを行えます。
+前回の実行が次の実行時刻までに終わらなかった場合、その実行時刻は省略します。
+複数のプロセスでdaemonを起動しても、Redisのロックにより同時に実行されるのは1つのみです。
+job.recrawl_after_hours を指定した場合は、実行のたびに最後のクロールからその時間以上経過したSUCCESSのジョブを再クロールの対象に戻します。
+設定ファイルは実行のたびに読み込み直しますが、scheduleとjob.recrawl_after_hoursの有無の変更を反映するにはdaemonを再起動してください。
+crawler control stop で停止した場合は、次の実行時刻に停止の指示をRUNに戻して実行を続けます。
+crawler control drain を実行すると、実行中のクロールがキューを処理し終えた時点（実行中でない場合は次の実行時刻）でdaemonを終了します。
+drainの指示は終了後も残るため、daemonを再び起動する前に crawler control resume を実行してください。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCrawlerDaemon()
+	},
+}
+
+// runCrawlerDaemonは、設定のスケジュールに従って、中断されるまでクローラーを繰り返し実行します。
+func runCrawlerDaemon() {
+	ctx, stop := newSignalContext()
+	defer stop()
+
+	cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+	if err != nil {
+		log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+	if cfg.Schedule == "" {
+		log.Fatal("crawler daemonを実行するには、設定ファイルにschedule（cron式）を指定してください")
+	}
+	schedule, err := config.ParseCronSchedule(cfg.Schedule)
+	if err != nil {
+		log.Fatalf("scheduleが不正です: %v", err)
+	}
+
+	appLogger := newAppLogger()
+
+	rdb := newRedisClient()
+	defer rdb.Close()
+	lock := infra.NewCrawlRunLock(rdb, crawlRunLockTTL)
+	control := infra.NewCrawlControlClient(rdb)
+
+	appLogger.Info("スケジュール実行を開始します", "schedule", cfg.Schedule)
+	for run := 1; ; run++ {
+		scheduledAt := schedule.Next(time.Now())
+		appLogger.Info("次のクロールの実行時刻まで待機します", "next_run", scheduledAt)
+
+		select {
+		case <-ctx.Done():
+			appLogger.Info("スケジュール実行を終了します")
+			return
+		case <-time.After(time.Until(scheduledAt)):
+		}
+
+		drained := runScheduledCrawl(ctx, run, scheduledAt, cfg.Job.RecrawlAfter() > 0, lock, control, appLogger)
+		if ctx.Err() != nil {
+			appLogger.Info("スケジュール実行を終了します")
+			return
+		}
+		if drained {
+			appLogger.Info("ドレインが完了したため、スケジュール実行を終了します")
+			return
+		}
+
+		// 実行が長引いて過ぎてしまった実行時刻は、まとめて実行せずに省略する
+		skipped := 0
+		for t := schedule.Next(scheduledAt); !t.IsZero() && !t.After(time.Now()); t = schedule.Next(t) {
+			skipped++
+		}
+		if skipped > 0 {
+			appLogger.Warn("前回の実行が次の実行時刻までに終わらなかったため、実行を省略しました", "run", run, "skipped", skipped)
+		}
+	}
+}
+
+// runScheduledCrawlは、ロックを取得してクロールジョブの生成と実行を1回行い、実行の結果をログに出力します。
+// 他のプロセスがロックを保持している場合は実行しません。
+//
+// args:
+//
+//	ctx         : コンテキスト
+//	run         : daemonの起動からの実行回数
+//	scheduledAt : スケジュールされた実行時刻
+//	recrawlDue  : 再クロールの時期を過ぎたSUCCESSのジョブをPENDINGに戻す場合はtrue
+//	lock        : 実行中のロック
+//	control     : 操作指示のリポジトリ
+//	appLogger   : ロガー
+//
+// return:
+//
+//	bool : DRAINの指示を受けてキューを処理し終えた場合はtrue
+func runScheduledCrawl(ctx context.Context, run int, scheduledAt time.Time, recrawlDue bool, lock *infra.CrawlRunLock, control repository.CrawlControlRepository, appLogger logger.AppLogger) bool {
+	acquired, holder, err := lock.TryAcquire(ctx)
+	if err != nil {
+		appLogger.Error("実行中のロックを確認できなかったため、今回の実行を省略します", "run", run, "error", err)
+		return false
+	}
+	if !acquired {
+		appLogger.Warn("他のプロセスが実行中のため、今回の実行を省略します", "run", run, "holder", holder)
+		return false
+	}
+	defer func() {
+		if err := lock.Release(context.WithoutCancel(ctx)); err != nil {
+			appLogger.Warn("実行中のロックを解放できませんでした", "run", run, "error", err)
+		}
+	}()
+
+	// 実行が有効期限より長引いても他のプロセスが実行を始めないよう、実行中はロックを延長し続ける
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+	go func() {
+		ticker := time.NewTicker(crawlRunLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Extend(heartbeatCtx); err != nil && heartbeatCtx.Err() == nil {
+					appLogger.Warn("実行中のロックを延長できませんでした", "run", run, "error", err)
+				}
+			}
+		}
+	}()
+
+	// 前回の実行に対する停止の指示で、以降の実行がすべて停止したままにならないよう、実行の開始時にRUNに戻す
+	reset, err := control.Reset(ctx, model.CrawlControlStop)
+	if err != nil {
+		appLogger.Warn("停止の指示をRUNに戻せませんでした", "run", run, "error", err)
+	}
+	if reset {
+		appLogger.Info("前回の実行に対する停止の指示をRUNに戻しました", "run", run)
+	}
+
+	startedAt := time.Now()
+	appLogger.Info("スケジュールされたクロールを開始します", "run", run, "scheduled_at", scheduledAt)
+	summary, err := runCrawlerCycle(ctx, crawlerRunOptions{generate: true, execute: true, recrawlDue: recrawlDue}, appLogger)
+
+	attrs := []any{
+		"run", run,
+		"scheduled_at", scheduledAt,
+		"started_at", startedAt,
+		"duration_seconds", int(time.Since(startedAt).Seconds()),
+		"success", summary.Result.Success,
+		"failed", summary.Result.Failed,
+		"blocked", summary.Result.Blocked,
+		"pages", summary.Usage.Pages,
+		"bytes", summary.Usage.Bytes,
+		"browser_seconds", int(summary.Usage.BrowserTime.Seconds()),
+		"browser_recycles", summary.Recycles.Total(),
+	}
+	if err != nil {
+		appLogger.Error("スケジュールされたクロールが失敗しました", append(attrs, "error", err)...)
+		return false
+	}
+	appLogger.Info("スケジュールされたクロールが完了しました", attrs...)
+	return summary.Result.Drained
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerDaemonCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gcOlderThan  time.Duration
+	gcPartition  int
+	gcPartitions int
+)
+
+var crawlerGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "処理中のまま放置されたクロールジョブをPENDINGに戻します",
+	Long: `クラッシュなどでIN_PROGRESSのまま残ったクロールジョブを検出し、PENDINGに戻して次回の実行で処理されるようにします。
+ジョブが非常に多い場合は、--partitions と --partition を指定して複数のプロセスで分担できます。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := newSignalContext()
+		defer stop()
+
+		cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+		if err != nil {
+			log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+		}
+
+		if gcPartitions > 1 && (gcPartition < 0 || gcPartition >= gcPartitions) {
+			log.Fatalf("--partitionには0から%dまでの値を指定してください", gcPartitions-1)
+		}
+
+		appLogger := newAppLogger()
+
+		rdb := newRedisClient()
+		defer rdb.Close()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			appLogger.Error("Redisへの接続に失敗しました", "error", err)
+			os.Exit(1)
+		}
+
+		staleAfter := cfg.Job.StaleAfter()
+		if gcOlderThan > 0 {
+			staleAfter = gcOlderThan
+		}
+
+		reapUC := usecase.NewReapStaleCrawlJobUseCase(usecase.ReapStaleCrawlJobArgs{
+			Repo:    infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL()),
+			Workers: infra.NewCrawlWorkerClient(rdb),
+			Logger:  appLogger,
+			StreamOptions: model.CrawlJobStreamOptions{
+				BatchSize:  cfg.Job.StreamBatchSize,
+				Prefetch:   cfg.Job.StreamPrefetch,
+				Partition:  gcPartition,
+				Partitions: gcPartitions,
+			},
+		})
+		if _, err := reapUC.ReapStaleJobs(ctx, staleAfter); err != nil {
+			appLogger.Error("放置されたジョブの回収中にエラーが発生しました", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerGCCmd)
+	crawlerGCCmd.Flags().IntVar(&gcPartition, "partition", 0, "このプロセスが担当するパーティションの番号（0から始まる）")
+	crawlerGCCmd.Flags().IntVar(&gcPartitions, "partitions", 1, "ジョブを分担するパーティションの総数（複数のプロセスで並行して回収する場合に指定）")
+	crawlerGCCmd.Flags().DurationVar(&gcOlderThan, "older-than", 0, "放置されたとみなすまでの時間（例: 1h。省略時は設定ファイルのjob.stale_after_minutes）")
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jobsStatus    string
+	jobsOlderThan string
+	jobsDryRun    bool
+	jobsOut       string
+	jobsFormat    string
+)
+
+// jobsOperationは、jobsサブコマンドで行う保守処理の種類です。
+type jobsOperation int
+
+const (
+	jobsRequeue jobsOperation = iota // PENDINGに戻す
+	jobsPurge                        // 削除する
+	jobsExport                       // ファイルに出力する
+)
+
+var crawlerJobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "クロールジョブの再実行待ちへの変更・削除・ファイルへの出力・ファイルからの作成をまとめて行います",
+}
+
+var crawlerJobsRequeueCmd = &cobra.Command{
+	Use:   "requeue",
+	Short: "指定したステータスのクロールジョブをPENDINGに戻します",
+	Long: `指定したステータスのクロールジョブをPENDINGに戻し、次回の実行（--execute）で処理されるようにします。
+--older-than を指定した場合は、最後の更新から指定した時間以上経過したジョブのみを対象にします。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runJobsMaintenance(jobsRequeue)
+	},
+}
+
+var crawlerJobsPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "指定したステータスのクロールジョブを削除します",
+	Long: `指定したステータスのクロールジョブを削除します。
+--older-than を指定した場合は、最後の更新から指定した時間以上経過したジョブのみを対象にします。
+削除したジョブは元に戻せないため、先に --dry-run で対象のジョブ数を確認してください。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runJobsMaintenance(jobsPurge)
+	},
+}
+
+var crawlerJobsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "指定したステータスのクロールジョブをCSVまたはJSONに出力します",
+	Long: `指定したステータスのクロールジョブを、URL、ステータス、最後のクロールの結果（ステータスコード、エラーなど）、日時とともにファイルに出力します。
+失敗したジョブの監査や、手動で再クロールするURLの一覧の作成に使用します。
+出力形式は --format で指定します。省略した場合は --out の拡張子が .json ならJSON、それ以外はCSVです。
+--older-than を指定した場合は、最後の更新から指定した時間以上経過したジョブのみを対象にします。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runJobsMaintenance(jobsExport)
+	},
+}
+
+var crawlerJobsImportCmd = &cobra.Command{
+	Use:   "import <ファイル>",
+	Short: "ファイルに記載したURLのクロールジョブをまとめて作成します",
+	Long: `ファイルに記載したURLのPENDINGのクロールジョブをまとめて作成します。
+一覧ページをクロールせずに、他の情報源から得たURLの一覧をクロールの対象にできます。
+ファイルには1行に1つのURLを記述するか、ヘッダーにurl列を含むCSV（crawler jobs export の出力と同じ列名で、list_page・list_positionも読み込みます）を指定します。
+テキストの場合、空行と「#」で始まる行は読み飛ばします。ファイルに「-」を指定した場合は標準入力から読み込みます。
+URLは正規化して登録し、既にPENDINGのジョブがあるURLは読み飛ばします。`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runJobsImport(args[0])
+	},
+}
+
+// runJobsImportは、指定したファイルのURLからクロールジョブを作成します。
+func runJobsImport(path string) {
+	input := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("ファイルを開けませんでした: %v", err)
+		}
+		defer file.Close()
+		input = file
+	}
+	reader, err := infra.NewCrawlJobImportReader(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := newSignalContext()
+	defer stop()
+
+	cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+	if err != nil {
+		log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	appLogger := newAppLogger()
+
+	rdb := newRedisClient()
+	defer rdb.Close()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		appLogger.Error("Redisへの接続に失敗しました", "error", err)
+		os.Exit(1)
+	}
+
+	uc := usecase.NewMaintainCrawlJobUseCase(usecase.MaintainCrawlJobArgs{
+		Repo:   infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL()),
+		Logger: appLogger,
+	})
+
+	result, err := uc.Import(ctx, reader)
+	if err != nil {
+		appLogger.Error("クロールジョブの取り込み中にエラーが発生しました", "created", result.Created, "error", err)
+		os.Exit(1)
+	}
+	fmt.Printf("作成したジョブ: %d件（読み飛ばし: %d件、不正なURL: %d件）\n", result.Created, result.Skipped, result.Invalid)
+}
+
+// runJobsMaintenanceは、フラグから対象のステータスと経過時間を読み取り、ジョブの保守処理を実行します。
+func runJobsMaintenance(operation jobsOperation) {
+	status, err := parseJobStatus(jobsStatus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if operation == jobsRequeue && status == model.CrawlJobStatusPending {
+		log.Fatal("PENDINGのジョブはすでに再実行待ちです。pending以外のステータスを指定してください")
+	}
+	olderThan, err := parseAge(jobsOlderThan)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := newSignalContext()
+	defer stop()
+
+	cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+	if err != nil {
+		log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	appLogger := newAppLogger()
+
+	rdb := newRedisClient()
+	defer rdb.Close()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		appLogger.Error("Redisへの接続に失敗しました", "error", err)
+		os.Exit(1)
+	}
+
+	uc := usecase.NewMaintainCrawlJobUseCase(usecase.MaintainCrawlJobArgs{
+		Repo:   infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL()),
+		Logger: appLogger,
+		StreamOptions: model.CrawlJobStreamOptions{
+			BatchSize: cfg.Job.StreamBatchSize,
+			Prefetch:  cfg.Job.StreamPrefetch,
+		},
+	})
+
+	var count int
+	switch operation {
+	case jobsRequeue:
+		count, err = uc.Requeue(ctx, status, olderThan, jobsDryRun)
+	case jobsPurge:
+		count, err = uc.Purge(ctx, status, olderThan, jobsDryRun)
+	case jobsExport:
+		count, err = exportJobs(func(exporter infra.CrawlJobExporter) (int, error) {
+			return uc.Export(ctx, status, olderThan, exporter)
+		})
+	}
+	if err != nil {
+		appLogger.Error("ジョブの保守処理中にエラーが発生しました", "count", count, "error", err)
+		os.Exit(1)
+	}
+	if operation == jobsExport {
+		fmt.Printf("出力したジョブ: %d件（%s）\n", count, jobsOut)
+		return
+	}
+	if jobsDryRun {
+		fmt.Printf("対象のジョブ: %d件（--dry-runのため変更していません）\n", count)
+		return
+	}
+	fmt.Printf("処理したジョブ: %d件\n", count)
+}
+
+// exportJobsは、--outと--formatに従ってエクスポーターを作成し、対象のジョブを出力します。
+// 途中で失敗した場合や中断された場合は、不完全なファイルを残さないよう出力を確定しません。
+func exportJobs(export func(exporter infra.CrawlJobExporter) (int, error)) (int, error) {
+	format := jobsFormat
+	if format == "" {
+		format = infra.CrawlJobExportFormat(jobsOut)
+	}
+	exporter, err := infra.NewCrawlJobExporter(jobsOut, strings.ToLower(format))
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := export(exporter)
+	if err != nil {
+		exporter.Abort()
+		return count, err
+	}
+	if err := exporter.Close(); err != nil {
+		return count, fmt.Errorf("出力ファイルの確定に失敗しました: %w", err)
+	}
+	return count, nil
+}
+
+// parseJobStatusは、フラグで指定されたステータス（大文字・小文字を問わない）をジョブのステータスに変換します。
+func parseJobStatus(value string) (model.CrawlJobStatus, error) {
+	switch status := model.CrawlJobStatus(strings.ToUpper(value)); status {
+	case model.CrawlJobStatusPending, model.CrawlJobStatusInProgress, model.CrawlJobStatusSuccess, model.CrawlJobStatusFailed, model.CrawlJobStatusBlocked:
+		return status, nil
+	default:
+		return "", fmt.Errorf("不明なステータスです: %q（pending, in_progress, success, failed, blocked のいずれかを指定してください）", value)
+	}
+}
+
+// parseAgeは、経過時間を表す文字列を変換します。time.ParseDurationの書式に加え、日数（例: 30d）を指定できます。
+// 空文字列の場合は0を返します。
+func parseAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("経過時間の書式が不正です: %q（例: 30d, 12h）", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("経過時間の書式が不正です: %q（例: 30d, 12h）", value)
+	}
+	return d, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{crawlerJobsRequeueCmd, crawlerJobsPurgeCmd} {
+		c.Flags().StringVar(&jobsStatus, "status", "", "対象のジョブのステータス（pending, in_progress, success, failed, blocked）")
+		c.Flags().StringVar(&jobsOlderThan, "older-than", "", "最後の更新からこの時間以上経過したジョブのみを対象にする（例: 30d, 12h）")
+		c.Flags().BoolVar(&jobsDryRun, "dry-run", false, "対象のジョブ数を表示するのみで変更しない")
+		c.MarkFlagRequired("status")
+		crawlerJobsCmd.AddCommand(c)
+	}
+
+	crawlerJobsExportCmd.Flags().StringVar(&jobsStatus, "status", "", "対象のジョブのステータス（pending, in_progress, success, failed, blocked）")
+	crawlerJobsExportCmd.Flags().StringVar(&jobsOlderThan, "older-than", "", "最後の更新からこの時間以上経過したジョブのみを対象にする（例: 30d, 12h）")
+	crawlerJobsExportCmd.Flags().StringVar(&jobsOut, "out", "", "出力するファイルのパス")
+	crawlerJobsExportCmd.Flags().StringVar(&jobsFormat, "format", "", "出力形式（csv, json。省略時は--outの拡張子から判定）")
+	crawlerJobsExportCmd.MarkFlagRequired("status")
+	crawlerJobsExportCmd.MarkFlagRequired("out")
+	crawlerJobsCmd.AddCommand(crawlerJobsExportCmd)
+	crawlerJobsCmd.AddCommand(crawlerJobsImportCmd)
+
+	crawlerCmd.AddCommand(crawlerJobsCmd)
+}
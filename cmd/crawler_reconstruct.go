@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+var reconstructDryRun bool
+
+var crawlerReconstructCmd = &cobra.Command{
+	Use:   "reconstruct",
+	Short: "保存済みのHTMLからクロールジョブを復元します",
+	Long: `output_dir（storageがredisの場合はRedis）に保存されたHTMLのメタデータを読み込み、取得済みのページをSUCCESSのジョブとしてRedisに保存し直します。
+Redisのデータを失った場合に、すべてのページを再クロールせずに済むよう実行します。
+メタデータのないHTMLは取得元のURLが分からないため復元できません。復元中はクローラーを停止してください。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := newSignalContext()
+		defer stop()
+
+		cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+		if err != nil {
+			log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+		}
+
+		appLogger := newAppLogger()
+
+		rdb := newRedisClient()
+		defer rdb.Close()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			appLogger.Error("Redisへの接続に失敗しました", "error", err)
+			os.Exit(1)
+		}
+
+		var loader infra.HTMLLoader = infra.NewHTMLFileLoader()
+		switch cfg.Storage {
+		case config.StorageRedis:
+			loader = infra.NewRedisHTMLStorage(rdb, cfg.OutputDir)
+		case config.StorageS3, config.StorageGCS:
+			loader, err = newObjectHTMLStorage(ctx, cfg.Storage, cfg.ObjectStorage, cfg.OutputDir)
+			if err != nil {
+				log.Fatalf("HTMLの読み込み元の初期化に失敗しました: %v", err)
+			}
+		}
+		// 暗号化して保存されたメタデータは復号して読み込む
+		aead, err := newHTMLCipher()
+		if err != nil {
+			log.Fatalf("暗号化の鍵の読み込みに失敗しました: %v", err)
+		}
+		loader = infra.NewEncryptedHTMLLoader(loader, aead)
+
+		uc := usecase.NewReconstructCrawlJobUseCase(usecase.ReconstructCrawlJobArgs{
+			Repo:    infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL()),
+			Loader:  loader,
+			HTMLDir: cfg.OutputDir,
+			Logger:  appLogger,
+		})
+
+		result, err := uc.Reconstruct(ctx, reconstructDryRun)
+		if err != nil {
+			appLogger.Error("クロールジョブの復元に失敗しました", "restored", result.Restored, "error", err)
+			os.Exit(1)
+		}
+
+		if reconstructDryRun {
+			fmt.Printf("復元するジョブ: %d件（--dry-runのため保存していません）\n", result.Restored)
+		} else {
+			fmt.Printf("復元したジョブ: %d件\n", result.Restored)
+		}
+		fmt.Printf("既存のためスキップ: %d件 / メタデータなし: %d件 / 失敗: %d件\n", result.Skipped, result.Missing, result.Failed)
+		if result.Failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerReconstructCmd)
+	crawlerReconstructCmd.Flags().BoolVar(&reconstructDryRun, "dry-run", false, "復元するジョブ数を表示するのみで保存しない")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// refreshOlderThanは、再クロールの対象とする最後のクロールからの経過時間です。
+var refreshOlderThan string
+
+var crawlerRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "クロール済みのURLを再クロールし、内容が変更されたページを記録します",
+	Long: `SUCCESSのクロールジョブをPENDINGに戻してから実行し、クロール済みのURLを取得し直します。
+ページの内容は前回のクロール時のハッシュ値と比較され、変更されていないページはメタデータの unchanged が true になります。
+再クロール後に scrape --changed-only を実行すると、変更のあった求人のみを出力できます。
+--older-than を指定した場合は、最後のクロールから指定した時間以上経過したジョブのみを再クロールします。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, err := parseAge(refreshOlderThan)
+		if err != nil {
+			log.Fatal(err)
+		}
+		runCrawler(crawlerRunOptions{execute: true, refresh: true, refreshOlderThan: olderThan})
+	},
+}
+
+func init() {
+	crawlerRefreshCmd.Flags().StringVar(&refreshOlderThan, "older-than", "", "最後のクロールからこの時間以上経過したジョブのみを再クロールする（例: 7d, 12h）")
+	crawlerCmd.AddCommand(crawlerRefreshCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/spf13/cobra"
+)
+
+var crawlerReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "クロールジョブのキューとインデックスを再構築します",
+	Long: `保存済みのクロールジョブを走査し、キューとステータスごとのインデックスを再構築します。
+キューの導入前に保存されたジョブを処理対象にする場合や、クラッシュなどでキューから外れたジョブを戻す場合に実行します。
+再構築中はクローラーを停止してください。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := newSignalContext()
+		defer stop()
+
+		cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+		if err != nil {
+			log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+		}
+
+		appLogger := newAppLogger()
+
+		rdb := newRedisClient()
+		defer rdb.Close()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			appLogger.Error("Redisへの接続に失敗しました", "error", err)
+			os.Exit(1)
+		}
+
+		appLogger.Info("キューとインデックスの再構築を開始します")
+		indexed, err := infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL()).Reindex(ctx)
+		if err != nil {
+			appLogger.Error("キューとインデックスの再構築に失敗しました", "indexed", indexed, "error", err)
+			os.Exit(1)
+		}
+		appLogger.Info("キューとインデックスの再構築が完了しました", "indexed", indexed)
+	},
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerReindexCmd)
+}
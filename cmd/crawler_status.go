@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusJSON    bool
+	statusSamples int
+)
+
+var crawlerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "クロールジョブの進捗を表示します",
+	Long: `Redisに保存されたクロールジョブを集計し、ステータスごとのジョブ数とURLの例（最後のクロールの日時、ステータスコード、所要時間、失敗した回数、エラー）、キューの長さ、最も古いPENDINGのジョブの経過時間を表示します。
+最も古いPENDINGのジョブを求めるため、PENDINGのジョブはすべて読み込みます。--json を指定した場合はJSONで出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := newSignalContext()
+		defer stop()
+
+		rdb := newRedisClient()
+		defer rdb.Close()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			log.Fatalf("Redisへの接続に失敗しました: %v", err)
+		}
+
+		// 集計ではジョブを保存しないため、PENDINGの有効期限は使用しない
+		stats, err := infra.NewCrawlJobClient(rdb, 0).Stats(ctx, statusSamples)
+		if err != nil {
+			log.Fatalf("クロールジョブの集計に失敗しました: %v", err)
+		}
+
+		if statusJSON {
+			data, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				log.Fatalf("集計結果のJSONへの変換に失敗しました: %v", err)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("キュー: %d件\n", stats.Queued)
+		if stats.OldestPendingAt != nil {
+			age := time.Duration(stats.OldestPendingAgeSeconds) * time.Second
+			fmt.Printf("最も古いPENDINGのジョブ: %s（%s経過）\n", stats.OldestPendingAt.Format(time.DateTime), age)
+		}
+		for _, s := range stats.Statuses {
+			fmt.Printf("\n%-11s %d件\n", s.Status, s.Count)
+			for _, job := range s.SampleJobs {
+				fmt.Printf("  %s\n", job.URL)
+				if detail := formatLastAttempt(job); detail != "" {
+					fmt.Printf("    %s\n", detail)
+				}
+			}
+		}
+	},
+}
+
+// formatLastAttemptは、ジョブの最後のクロールの結果を1行の文字列にします。クロールしていない場合は空文字列を返します。
+func formatLastAttempt(job infra.CrawlJobRecord) string {
+	if job.StartedAt.IsZero() {
+		return ""
+	}
+	parts := []string{job.StartedAt.Format(time.DateTime)}
+	if job.HTTPStatus != 0 {
+		parts = append(parts, fmt.Sprintf("HTTP %d", job.HTTPStatus))
+	}
+	parts = append(parts, (time.Duration(job.DurationMS) * time.Millisecond).String())
+	if job.RetryCount > 0 {
+		parts = append(parts, fmt.Sprintf("失敗%d回", job.RetryCount))
+	}
+	if job.LastError != "" {
+		parts = append(parts, "エラー: "+job.LastError)
+	}
+	return strings.Join(parts, " / ")
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerStatusCmd)
+	crawlerStatusCmd.Flags().BoolVar(&statusJSON, "json", false, "集計結果をJSONで出力する")
+	crawlerStatusCmd.Flags().IntVar(&statusSamples, "samples", 3, "ステータスごとに表示するURLの例の数")
+}
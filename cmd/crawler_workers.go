@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workersJSON bool
+	workersReap bool
+)
+
+// crawlWorkersReportは、crawler workersの出力です。
+//
+// フィールド:
+//
+//	Workers     : 実行中のワーカーの状態
+//	DeadWorkers : IN_PROGRESSのジョブを獲得したまま停止したワーカー
+type crawlWorkersReport struct {
+	Workers     []infra.CrawlWorkerRecord `json:"workers"`
+	DeadWorkers []deadCrawlWorker         `json:"dead_workers"`
+}
+
+// deadCrawlWorkerは、IN_PROGRESSのジョブを獲得したまま停止したワーカーです。
+//
+// フィールド:
+//
+//	ID             : ワーカーのID
+//	InProgressJobs : 獲得したままのIN_PROGRESSのジョブ数
+type deadCrawlWorker struct {
+	ID             string `json:"id"`
+	InProgressJobs int    `json:"in_progress_jobs"`
+}
+
+var crawlerWorkersCmd = &cobra.Command{
+	Use:   "workers",
+	Short: "クロールジョブを生成・実行中のワーカーを表示します",
+	Long: `実行中のワーカーごとのID、処理、ホスト名、開始日時、最後に状態を報告した日時、生成・成功・失敗したジョブ数、処理中のURLを表示します。
+状態の報告が途絶えたワーカーは停止したものとみなし、IN_PROGRESSのジョブを獲得したまま停止したワーカーとそのジョブ数を表示します。
+--reap を指定した場合は、停止したワーカーが獲得したままのジョブをPENDINGに戻します。--json を指定した場合はJSONで出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := newSignalContext()
+		defer stop()
+
+		cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+		if err != nil {
+			log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+		}
+
+		rdb := newRedisClient()
+		defer rdb.Close()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			log.Fatalf("Redisへの接続に失敗しました: %v", err)
+		}
+
+		workerClient := infra.NewCrawlWorkerClient(rdb)
+		workers, err := workerClient.FindAll(ctx)
+		if err != nil {
+			log.Fatalf("ワーカーの取得に失敗しました: %v", err)
+		}
+
+		reapUC := usecase.NewReapStaleCrawlJobUseCase(usecase.ReapStaleCrawlJobArgs{
+			Repo:    infra.NewCrawlJobClient(rdb, cfg.Job.PendingTTL()),
+			Workers: workerClient,
+			Logger:  newAppLogger(),
+			StreamOptions: model.CrawlJobStreamOptions{
+				BatchSize: cfg.Job.StreamBatchSize,
+				Prefetch:  cfg.Job.StreamPrefetch,
+			},
+		})
+		if workersReap {
+			if _, err := reapUC.ReapDeadWorkerJobs(ctx); err != nil {
+				log.Fatalf("停止したワーカーが獲得したジョブの回収に失敗しました: %v", err)
+			}
+		}
+		deadJobs, err := reapUC.DeadWorkerJobs(ctx)
+		if err != nil {
+			log.Fatalf("停止したワーカーの検出に失敗しました: %v", err)
+		}
+
+		report := crawlWorkersReport{
+			Workers:     make([]infra.CrawlWorkerRecord, 0, len(workers)),
+			DeadWorkers: make([]deadCrawlWorker, 0, len(deadJobs)),
+		}
+		for _, worker := range workers {
+			report.Workers = append(report.Workers, infra.ToCrawlWorkerRecord(worker))
+		}
+		for id, count := range deadJobs {
+			report.DeadWorkers = append(report.DeadWorkers, deadCrawlWorker{ID: id, InProgressJobs: count})
+		}
+		slices.SortFunc(report.DeadWorkers, func(a, b deadCrawlWorker) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+
+		if workersJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalf("ワーカーのJSONへの変換に失敗しました: %v", err)
+			}
+			fmt.Println(string(data))
+			return
+		}
+		printCrawlWorkers(report)
+	},
+}
+
+// printCrawlWorkersは、実行中のワーカーと停止したワーカーを表示します。
+func printCrawlWorkers(report crawlWorkersReport) {
+	if len(report.Workers) == 0 {
+		fmt.Println("実行中のワーカーはありません")
+	}
+	for _, worker := range report.Workers {
+		fmt.Printf("%s（%s / %s）\n", worker.ID, worker.Role, worker.Hostname)
+		fmt.Printf("  開始: %s / 最終報告: %s（%s前）\n",
+			worker.StartedAt.Format(time.DateTime),
+			worker.LastSeen.Format(time.DateTime),
+			time.Since(worker.LastSeen).Round(time.Second),
+		)
+		if worker.Role == model.CrawlWorkerRoleGenerate {
+			fmt.Printf("  生成 %d件\n", worker.Generated)
+		} else {
+			fmt.Printf("  成功 %d件 / 失敗 %d件（うちアクセス拒否 %d件）\n", worker.Success, worker.Failed, worker.Blocked)
+		}
+		if worker.CurrentURL != "" {
+			fmt.Printf("  処理中: %s\n", worker.CurrentURL)
+		}
+	}
+
+	if len(report.DeadWorkers) == 0 {
+		return
+	}
+	fmt.Println("\nジョブを獲得したまま停止したワーカー:")
+	for _, worker := range report.DeadWorkers {
+		fmt.Printf("  %s: IN_PROGRESS %d件\n", worker.ID, worker.InProgressJobs)
+	}
+	fmt.Println("crawler workers --reap でPENDINGに戻せます")
+}
+
+func init() {
+	crawlerCmd.AddCommand(crawlerWorkersCmd)
+	crawlerWorkersCmd.Flags().BoolVar(&workersJSON, "json", false, "ワーカーの状態をJSONで出力する")
+	crawlerWorkersCmd.Flags().BoolVar(&workersReap, "reap", false, "停止したワーカーが獲得したままのジョブをPENDINGに戻す")
+}
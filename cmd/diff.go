@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat string
+	diffOutput string
+	diffIgnore []string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "2回の実行の出力を比較し、追加・削除・変更された求人情報を出力します",
+	Long: `同じサイトに対する2回の実行で出力されたCSVを比較し、追加・削除・変更された求人情報のレポートを出力します。
+求人情報は正規化したURLで対応付け、--ignore に指定した列を除く値のハッシュ値で変更を判定します。
+出力ファイルを分割している場合は、globパターン（例: 'tmp/csv/2025-01/type_*.csv'）で複数のファイルをまとめて指定できます。
+--format json の場合は、変更された列の値を含むレポートをJSONで出力します。`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		report, err := infra.DiffPostingRuns(args[0], args[1], infra.DefaultExportFields(), diffIgnore)
+		if err != nil {
+			log.Fatalf("出力の比較に失敗しました: %v", err)
+		}
+
+		var buf bytes.Buffer
+		switch diffFormat {
+		case "text":
+			writeDiffText(&buf, report)
+		case "json":
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalf("レポートの生成に失敗しました: %v", err)
+			}
+			buf.Write(data)
+			buf.WriteByte('\n')
+		default:
+			log.Fatalf("サポートされていない形式です: %s（text または json を指定してください）", diffFormat)
+		}
+
+		if diffOutput == "" {
+			fmt.Print(buf.String())
+			return
+		}
+		if err := os.WriteFile(diffOutput, buf.Bytes(), 0o644); err != nil {
+			log.Fatalf("レポートの書き込みに失敗しました: %v", err)
+		}
+	},
+}
+
+// writeDiffTextは、比較結果を人が読む形式で書き込みます。
+func writeDiffText(buf *bytes.Buffer, report infra.RunDiffReport) {
+	fmt.Fprintf(buf, "以前の実行: %d件 %v\n", report.Old.Postings, report.Old.Files)
+	fmt.Fprintf(buf, "新しい実行: %d件 %v\n", report.New.Postings, report.New.Files)
+	fmt.Fprintf(buf, "追加: %d件 / 削除: %d件 / 変更: %d件 / 変更なし: %d件\n",
+		len(report.Added), len(report.Removed), len(report.Changed), report.Unchanged)
+
+	if len(report.Added) > 0 {
+		fmt.Fprintf(buf, "\n## 追加\n")
+		for _, p := range report.Added {
+			fmt.Fprintf(buf, "+ %s %s (%s)\n", p.URL, p.Title, p.CompanyName)
+		}
+	}
+	if len(report.Removed) > 0 {
+		fmt.Fprintf(buf, "\n## 削除\n")
+		for _, p := range report.Removed {
+			fmt.Fprintf(buf, "- %s %s (%s)\n", p.URL, p.Title, p.CompanyName)
+		}
+	}
+	if len(report.Changed) > 0 {
+		fmt.Fprintf(buf, "\n## 変更\n")
+		for _, p := range report.Changed {
+			fmt.Fprintf(buf, "* %s %s (%s)\n", p.URL, p.Title, p.CompanyName)
+			for _, c := range p.Changes {
+				fmt.Fprintf(buf, "    %s: %q -> %q\n", c.Column, c.Old, c.New)
+			}
+		}
+	}
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "出力形式（text または json）")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "出力先のファイル（省略時は標準出力）")
+	diffCmd.Flags().StringSliceVar(&diffIgnore, "ignore", []string{"クロール日時"}, "比較しない列のヘッダー（カンマ区切りで複数指定可）")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/playwright-community/playwright-go"
+	"github.com/spf13/cobra"
+)
+
+// doctorCheckは、doctorコマンドで実行する1件の診断項目です。
+//
+// フィールド:
+//
+//	name : 診断項目の名前
+//	run  : 診断処理。問題がある場合はエラーと対処方法を返します。
+type doctorCheck struct {
+	name string
+	run  func(ctx context.Context) (fix string, err error)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "実行環境を診断します",
+	Long:  `設定ファイル、Redisへの接続、Playwrightのブラウザ、出力ディレクトリの書き込み権限を確認し、問題があれば対処方法を表示します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		crawlerCfg, crawlerCfgErr := config.LoadCrawlerConfig(crawlerConfigPath)
+		scraperCfg, scraperCfgErr := config.LoadScraperConfig(scraperConfigPath)
+
+		engine := config.BrowserChromium
+		if crawlerCfgErr == nil {
+			engine = crawlerCfg.BrowserEngineOrDefault()
+		}
+		browserCheck := doctorCheck{
+			name: "Playwrightのドライバーとブラウザ (" + string(engine) + ")",
+			run: func(ctx context.Context) (string, error) {
+				return checkPlaywright(ctx, engine)
+			},
+		}
+		if crawlerCfgErr == nil && crawlerCfg.RemoteBrowser.Endpoint != "" {
+			// リモートブラウザを使用する場合は、ローカルのブラウザの代わりに接続を確認する
+			browserCheck = doctorCheck{
+				name: "リモートブラウザへの接続 (" + crawlerCfg.RemoteBrowser.Endpoint + ")",
+				run: func(ctx context.Context) (string, error) {
+					return "remote_browserのendpointとprotocol、接続先のブラウザが起動していることを確認してください", checkRemoteBrowser(&crawlerCfg)
+				},
+			}
+		}
+
+		checks := []doctorCheck{
+			{
+				name: "クローラー設定ファイル (" + crawlerConfigPath + ")",
+				run: func(ctx context.Context) (string, error) {
+					return "docs/crawler.md を参照して設定ファイルを修正してください", crawlerCfgErr
+				},
+			},
+			{
+				name: "スクレイパー設定ファイル (" + scraperConfigPath + ")",
+				run: func(ctx context.Context) (string, error) {
+					return "docs/scraper.md を参照して設定ファイルを修正してください", scraperCfgErr
+				},
+			},
+			{name: "Redisへの接続", run: checkRedis},
+			browserCheck,
+		}
+
+		if crawlerCfgErr == nil {
+			checks = append(checks, doctorCheck{
+				name: "HTML出力ディレクトリの書き込み (" + crawlerCfg.OutputDir + ")",
+				run: func(ctx context.Context) (string, error) {
+					return "ディレクトリの権限を確認するか、output_dirを書き込み可能なパスに変更してください", checkWritableDir(crawlerCfg.OutputDir)
+				},
+			})
+		}
+		if scraperCfgErr == nil {
+			checks = append(checks, doctorCheck{
+				name: "CSV出力ディレクトリの書き込み (" + scraperCfg.OutputDir + ")",
+				run: func(ctx context.Context) (string, error) {
+					return "ディレクトリの権限を確認するか、output_dirを書き込み可能なパスに変更してください", checkWritableDir(scraperCfg.OutputDir)
+				},
+			})
+		}
+
+		failed := 0
+		for _, check := range checks {
+			fix, err := check.run(ctx)
+			if err != nil {
+				failed++
+				fmt.Printf("[NG] %s\n     原因: %v\n     対処: %s\n", check.name, err, fix)
+				continue
+			}
+			fmt.Printf("[OK] %s\n", check.name)
+		}
+
+		if failed > 0 {
+			fmt.Printf("\n%d件の問題が見つかりました\n", failed)
+			os.Exit(1)
+		}
+		fmt.Println("\n問題は見つかりませんでした")
+	},
+}
+
+// checkRedisは、環境変数に設定されたRedisへ接続できるかを確認します。
+func checkRedis(ctx context.Context) (string, error) {
+	if os.Getenv("REDIS_ADDRESS") == "" {
+		return ".env に REDIS_ADDRESS を設定してください（例: REDIS_ADDRESS=localhost:6379）", fmt.Errorf("REDIS_ADDRESSが設定されていません")
+	}
+
+	rdb := newRedisClient()
+	defer rdb.Close()
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := rdb.Ping(pingCtx).Err(); err != nil {
+		return "`docker-compose up -d` でRedisを起動し、REDIS_ADDRESS と REDIS_PASSWORD を確認してください", err
+	}
+	return "", nil
+}
+
+// checkPlaywrightは、Playwrightのドライバーとクローラーが使用するエンジンのブラウザがインストールされているかを確認します。
+func checkPlaywright(ctx context.Context, engine config.BrowserEngine) (string, error) {
+	fix := "`go run github.com/playwright-community/playwright-go/cmd/playwright@v0.5200.0 install --with-deps` を実行してください"
+
+	pw, err := playwright.Run(&playwright.RunOptions{Verbose: false})
+	if err != nil {
+		return fix, err
+	}
+	defer pw.Stop()
+
+	executable := infra.BrowserType(pw, engine).ExecutablePath()
+	if _, err := os.Stat(executable); err != nil {
+		return fix, fmt.Errorf("ブラウザの実行ファイルが見つかりません: %s", executable)
+	}
+	return "", nil
+}
+
+// checkRemoteBrowserは、設定されたリモートブラウザに接続し、ページを作成できるかを確認します。
+func checkRemoteBrowser(cfg *config.CrawlerConfig) error {
+	client, err := infra.NewBrowserClient(cfg)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// checkWritableDirは、ディレクトリを作成し、一時ファイルを書き込めるかを確認します。
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("ディレクトリを作成できません: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fmt.Errorf("ファイルを書き込めません: %w", err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
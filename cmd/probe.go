@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/spf13/cobra"
+)
+
+var (
+	probeURL       string
+	probeSelectors []string
+	probeAttr      string
+	probeLimit     int
+)
+
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "ページに対するセレクターの一致結果を表示します",
+	Long: `クローラーと同じ設定でブラウザを起動して指定したURLへ遷移し、セレクターに一致した要素の件数とテキスト（または属性値）を表示します。
+新しいサイトの list_links_selector や detail_links_selector を決める際に、クロールを実行せずにセレクターを試せます。
+--selector は複数指定できます。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadCrawlerConfig(crawlerConfigPath)
+		if err != nil {
+			log.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+		}
+
+		client, err := infra.NewBrowserClient(&cfg)
+		if err != nil {
+			log.Fatalf("ブラウザの起動に失敗しました: %v", err)
+		}
+		defer client.Close()
+
+		if err := client.Navigate(cmd.Context(), probeURL); err != nil {
+			log.Fatalf("%sへの遷移に失敗しました: %v", probeURL, err)
+		}
+		fmt.Printf("URL: %s (HTTP %d)\n", probeURL, client.LastStatus())
+
+		for _, selector := range probeSelectors {
+			fmt.Printf("\nセレクター: %s\n", selector)
+
+			var values []string
+			if probeAttr == "" {
+				values, err = client.ExtractText(cmd.Context(), selector)
+			} else {
+				values, err = client.ExtractAttribute(cmd.Context(), selector, probeAttr)
+			}
+			if err != nil {
+				fmt.Printf("  一致する要素がありません: %v\n", err)
+				continue
+			}
+
+			if probeAttr == "" {
+				fmt.Printf("  一致した要素: %d件\n", len(values))
+			} else {
+				fmt.Printf("  %s属性を持つ要素: %d件\n", probeAttr, len(values))
+			}
+			for i, value := range values {
+				if probeLimit > 0 && i >= probeLimit {
+					fmt.Printf("  ...ほか%d件\n", len(values)-probeLimit)
+					break
+				}
+				fmt.Printf("  [%d] %s\n", i+1, strings.Join(strings.Fields(value), " "))
+			}
+		}
+	},
+}
+
+func init() {
+	probeCmd.Flags().StringVar(&probeURL, "url", "", "遷移するURL")
+	probeCmd.Flags().StringArrayVar(&probeSelectors, "selector", nil, "試すセレクター（複数指定可）")
+	probeCmd.Flags().StringVar(&probeAttr, "attr", "", "テキストの代わりに表示する属性（例: href）")
+	probeCmd.Flags().IntVar(&probeLimit, "limit", 20, "セレクターごとに表示する件数の上限（0は無制限）")
+	probeCmd.MarkFlagRequired("url")
+	probeCmd.MarkFlagRequired("selector")
+
+	rootCmd.AddCommand(probeCmd)
+}
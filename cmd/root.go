@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +14,12 @@ var rootCmd = &cobra.Command{
 	Short: "求人情報サイトのクローリングとスクレイピングを行うツールです。",
 	Long: `go-crawlerは、求人情報のURLを収集するクローラー機能と、
 ダウンロード済みのHTMLファイルから詳細情報を抽出するスクレイパー機能を提供します。`,
+	// すべてのサブコマンドで、実行前に.envから環境変数を読み込む
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// .envが存在しない場合は環境変数をそのまま使用する
+		_ = godotenv.Load()
+		return nil
+	},
 }
 
 // Executeは、全てのサブコマンドをルートコマンドに追加し、フラグを適切に設定します。
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/constants"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaOutput   string
+	schemaFormat   string
+	schemaSource   string
+	schemaViewName string
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "エクスポートされる求人情報のスキーマを出力します",
+	Long: `スクレイパーが出力する求人情報の構造を出力します。
+--format json-schema（デフォルト）の場合はJSON Schemaを出力します。下流のシステムで出力の検証やコード生成に使用できます。
+--format duckdb の場合は、CSV出力を型付きで参照するDuckDBのビューを作成するSQLを出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fields := infra.DefaultExportFields()
+
+		var data []byte
+		switch schemaFormat {
+		case "json-schema":
+			schema := infra.BuildJSONSchema(fields, constants.ExportSchemaVersion)
+			var err error
+			data, err = json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				log.Fatalf("JSON Schemaの生成に失敗しました: %v", err)
+			}
+			data = append(data, '\n')
+		case "duckdb":
+			// ビューの列をCSVの列に合わせるため、スクレイパーと同じ設定から列を決定する
+			cfg, err := config.LoadScraperConfig(scraperConfigPath)
+			if err != nil {
+				log.Fatalf("スクレイプの設定ファイルを読み込めませんでした: %v", err)
+			}
+			output, err := schemaCSVOutput(cfg)
+			if err != nil {
+				log.Fatalf("ビューの列を決定できませんでした: %v", err)
+			}
+			fields, err = schemaViewFields(cfg, output)
+			if err != nil {
+				log.Fatalf("ビューの列を決定できませんでした: %v", err)
+			}
+
+			source := schemaSource
+			if source == "" {
+				source = filepath.Join(cfg.OutputDir, output.FileName)
+				if cfg.OutputPartition != "" {
+					source = infra.PartitionPath(source, "*")
+				}
+			}
+			data = []byte(infra.BuildDuckDBView(fields, constants.ExportSchemaVersion, schemaViewName, source))
+		default:
+			log.Fatalf("サポートされていない形式です: %s（json-schema または duckdb を指定してください）", schemaFormat)
+		}
+
+		if schemaOutput == "" {
+			fmt.Print(string(data))
+			return
+		}
+		if err := os.WriteFile(schemaOutput, data, 0o644); err != nil {
+			log.Fatalf("スキーマの書き込みに失敗しました: %v", err)
+		}
+	},
+}
+
+// schemaCSVOutputは、スクレイパーの設定ファイルからビューで読み込むCSVの出力先を返します。
+// CSVの出力先が複数ある場合は先頭の出力先を使用します。
+//
+// args:
+//
+//	cfg : スクレイパーの設定
+//
+// return:
+//
+//	config.OutputConfig : CSVの出力先
+//	error               : CSVの出力先が設定されていない場合のエラー
+func schemaCSVOutput(cfg config.ScraperConfig) (config.OutputConfig, error) {
+	for _, output := range cfg.OutputTargets() {
+		if output.Format == config.OutputCSV {
+			return output, nil
+		}
+	}
+	return config.OutputConfig{}, fmt.Errorf("CSVの出力先が設定されていません")
+}
+
+// schemaViewFieldsは、CSVの出力先に書き込まれる列の定義を、スクレイパーと同じ手順で決定します。
+// 追加の項目を加え、設定で選択した列に絞り込んだうえで、出力先の変換を適用します。
+//
+// args:
+//
+//	cfg    : スクレイパーの設定
+//	output : CSVの出力先
+//
+// return:
+//
+//	[]infra.ExportField : CSVに書き込まれる列の定義
+//	error               : 列の設定が不正な場合のエラー
+func schemaViewFields(cfg config.ScraperConfig, output config.OutputConfig) ([]infra.ExportField, error) {
+	fields, err := scrapeExportFields(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("追加の項目の設定が不正です: %w", err)
+	}
+	fields, err = infra.SelectExportFields(fields, cfg.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("出力する列の設定が不正です: %w", err)
+	}
+	fields, err = infra.ApplyExportTransforms(fields, output.Transforms)
+	if err != nil {
+		return nil, fmt.Errorf("%s の列の変換の設定が不正です: %w", outputName(output), err)
+	}
+	return fields, nil
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "出力先のファイルパス（省略時は標準出力）")
+	schemaCmd.Flags().StringVarP(&schemaFormat, "format", "f", "json-schema", "出力する形式（json-schema または duckdb）")
+	schemaCmd.Flags().StringVar(&schemaSource, "source", "", "duckdb形式で読み込むCSVのパス（省略時はスクレイパーの設定ファイルから決定）")
+	schemaCmd.Flags().StringVar(&schemaViewName, "view", "job_postings", "duckdb形式で作成するビューの名前")
+}
@@ -2,10 +2,11 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/nrad-K/go-crawler/internal/config"
 	"github.com/nrad-K/go-crawler/internal/constants"
@@ -15,49 +16,344 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	// scrapeQuietは、スクレイピングの進捗の表示を無効にするかどうかです。
+	scrapeQuiet bool
+	// scrapeChangedOnlyは、前回のクロールから内容が変更されたページのみを出力するかどうかです。
+	scrapeChangedOnly bool
+)
+
+// scrapeProgressIntervalは、スクレイピングの進捗を表示する間隔です。
+const scrapeProgressInterval = 2 * time.Second
+
 var scraperCmd = &cobra.Command{
 	Use:   "scrape",
 	Short: "HTMLファイルから求人情報をスクレイピングします",
-	Long:  `ローカルに保存されたHTMLファイルを解析し、設定されたセレクターに基づいて求人情報を抽出し、結果をCSVファイルに保存します`,
+	Long:  `保存されたHTMLファイルを解析し、設定されたセレクターに基づいて求人情報を抽出し、結果をCSVなどのファイルに保存します`,
 	Run: func(cmd *cobra.Command, args []string) {
-		logHandler := slog.NewTextHandler(os.Stdout, nil)
-		appLogger := logger.NewAppLogger(slog.New(logHandler))
+		appLogger := newAppLogger()
 
-		path := "settings/scraper.yaml"
-		scraperCfg, err := config.LoadScraperConfig(path)
+		scraperCfg, err := config.LoadScraperConfig(scraperConfigPath)
 		if err != nil {
 			log.Fatalf("スクレイプの設定ファイルを読み込めませんでした: %v", err)
 		}
 
+		ctx, stop := newSignalContext()
+		defer stop()
+
+		// 実行の終了時に、処理したHTMLの数や所要時間を通知先に送信する
+		notifier := infra.NewNotifiersFromConfig(scraperCfg.Notifications)
+		run := infra.RunSummary{Command: "scrape", StartedAt: time.Now()}
+		// fatalは、実行の失敗を通知してから終了します
+		fatal := func(format string, args ...any) {
+			err := fmt.Errorf(format, args...)
+			notifyRun(ctx, notifier, run, err, appLogger)
+			log.Fatal(err)
+		}
+
 		patterns := constants.GetScraperCompiledPatterns()
-		headers := constants.GetScraperCSVHeaders()
+		fields, err := scrapeExportFields(scraperCfg)
+		if err != nil {
+			fatal("追加の項目の設定が不正です: %v", err)
+		}
+		// 出力する列を設定に応じて選択・並べ替える（フックには選択前のすべての列を渡す）
+		columns, err := infra.SelectExportFields(fields, scraperCfg.Columns)
+		if err != nil {
+			fatal("出力する列の設定が不正です: %v", err)
+		}
+
+		// HTMLの読み込み元を設定に応じて切り替える
+		var loader infra.HTMLLoader = infra.NewHTMLFileLoader()
+		switch scraperCfg.Storage {
+		case config.StorageRedis:
+			rdb := newRedisClient()
+			defer rdb.Close()
+			loader = infra.NewRedisHTMLStorage(rdb, scraperCfg.HtmlDir)
+		case config.StorageS3, config.StorageGCS:
+			loader, err = newObjectHTMLStorage(ctx, scraperCfg.Storage, scraperCfg.ObjectStorage, scraperCfg.HtmlDir)
+			if err != nil {
+				fatal("HTMLの読み込み元の初期化に失敗しました: %v", err)
+			}
+		}
+		// 暗号化して保存されたHTMLは復号して読み込む（暗号化されていないHTMLはそのまま読み込む）
+		aead, err := newHTMLCipher()
+		if err != nil {
+			fatal("暗号化の鍵の読み込みに失敗しました: %v", err)
+		}
+		loader = infra.NewEncryptedHTMLLoader(loader, aead)
+		if scraperCfg.MaxHTMLBytes > 0 {
+			// 巨大なHTMLでワーカーのメモリを使い切らないよう、読み込むサイズに上限を設ける
+			loader = infra.NewCappedHTMLLoader(loader, scraperCfg.MaxHTMLBytes)
+		}
+		htmlParser := infra.NewHTMLParser()
+		dictionary := infra.DefaultParserDictionary()
+		if scraperCfg.Dictionary != "" {
+			// 表記の揺れに合わせてキーワードを調整できるよう、辞書ファイルで既定の辞書を上書きする
+			dictionary, err = infra.LoadParserDictionary(scraperCfg.Dictionary)
+			if err != nil {
+				fatal("辞書の読み込みに失敗しました: %v", err)
+			}
+		}
+		municipalityCodes := infra.DefaultMunicipalityCodes()
+		if scraperCfg.Municipalities != "" {
+			// 既定の対応表にない市区町村も統計と結合できるよう、全国地方公共団体コードの一覧を読み込む
+			municipalityCodes, err = infra.LoadMunicipalityCodes(scraperCfg.Municipalities)
+			if err != nil {
+				fatal("市区町村コードの読み込みに失敗しました: %v", err)
+			}
+		}
+		var parser infra.JobPostingParser = infra.NewJobPostingParser(patterns, dictionary, municipalityCodes, infra.SalaryTypeOf(scraperCfg.Salary.PreferredUnit))
+		if scraperCfg.ParserCacheSize > 0 {
+			// 同じ文字列の解析を繰り返さないよう、解析結果をキャッシュする
+			parser = infra.NewCachedJobPostingParser(parser, scraperCfg.ParserCacheSize)
+		}
+		configHash, err := scraperCfg.Hash()
+		if err != nil {
+			fatal("設定のハッシュ計算に失敗しました: %v", err)
+		}
+		run.ConfigHash = configHash
+
+		// 出力から使用した設定を辿れるよう、出力先に設定のスナップショットを書き込む
+		configFile, err := infra.WriteConfigSnapshot(scraperCfg.OutputDir, "scraper", scraperConfigPath, configHash)
+		if err != nil {
+			appLogger.Warn("設定のスナップショットを書き込めませんでした", "dir", scraperCfg.OutputDir, "error", err)
+		}
+
+		hook, err := infra.NewHooksFromConfig(scraperCfg.Hooks, fields)
+		if err != nil {
+			fatal("フックの初期化に失敗しました: %v", err)
+		}
+
+		expiry, err := infra.NewExpiryDetector(scraperCfg.Expired)
+		if err != nil {
+			fatal("掲載終了の判定条件が不正です: %v", err)
+		}
 
-		loader := infra.NewHTMLFileLoader()
-		document := infra.NewHTMLDocument()
-		parser := infra.NewJobPostingParser(patterns)
-		exporter, err := infra.NewCSVExporter(
-			filepath.Join(scraperCfg.OutputDir, scraperCfg.FileName),
-			headers,
-		)
+		var enrichers infra.EnricherChain
+		if scraperCfg.Categories != "" {
+			// 下流の分析でタイトルの文字列を照合しなくて済むよう、職種カテゴリーを判定して出力する
+			taxonomy, err := infra.LoadCategoryTaxonomy(scraperCfg.Categories)
+			if err != nil {
+				fatal("職種カテゴリーの読み込みに失敗しました: %v", err)
+			}
+			enrichers = append(enrichers, infra.NewCategoryClassifier(taxonomy))
+		}
+		if len(scraperCfg.IncomeBands) > 0 {
+			enrichers = append(enrichers, infra.NewIncomeBandClassifier(scraperCfg.IncomeBands))
+		}
+		reportCorporateNumbers := func() {}
+		if scraperCfg.CorporateNumber != nil {
+			// 他のデータと会社単位で結合できるよう、会社名と本社の都道府県から法人番号を取得して出力する
+			client := infra.NewNTACorporateNumberClient(scraperCfg.CorporateNumber.AppID, scraperCfg.CorporateNumber.Endpoint, scraperCfg.CorporateNumber.Timeout())
+			enricher := infra.NewCorporateNumberEnricher(infra.NewCachedCorporateNumberClient(client))
+			enrichers = append(enrichers, enricher)
+			reportCorporateNumbers = func() {
+				if failures := enricher.Failures(); failures > 0 {
+					appLogger.Warn("法人番号の問い合わせに失敗した求人情報があります", "count", failures)
+				}
+			}
+		}
 
+		// 出力の一時ファイルを作成した後に初期化に失敗して一時ファイルが残らないよう、失敗しうる初期化を済ませてからエクスポーターを生成する
+		exporter, reportSpills, err := newScrapeExporter(ctx, scraperCfg, columns, configHash, configFile, appLogger)
 		if err != nil {
-			log.Fatalf("CSVエクスポーターの初期化に失敗しました: %v", err)
+			fatal("エクスポーターの初期化に失敗しました: %v", err)
+		}
+		if scraperCfg.Dedup {
+			// 複数の一覧ページに掲載された同じ求人を、すべての出力先で1件にまとめる
+			dedup := infra.NewDedupExporter(exporter)
+			exporter = dedup
+			spills := reportSpills
+			reportSpills = func() {
+				spills()
+				if duplicates := dedup.Duplicates(); duplicates > 0 {
+					appLogger.Info("重複した求人情報を除外しました", "count", duplicates)
+				}
+			}
+		}
+
+		// 進捗は標準エラー出力に表示し、--quiet の場合は表示しない
+		var progress infra.ProgressReporter = infra.NewTerminalProgressReporter(os.Stderr, scrapeProgressInterval)
+		if scrapeQuiet {
+			progress = infra.NopProgressReporter{}
 		}
 
 		scraperArgs := usecase.ScraperArgs{
-			Loader:   *loader,
-			Document: document,
-			Exporter: exporter,
-			Cfg:      scraperCfg,
-			Parser:   parser,
-			Logger:   appLogger,
+			Loader:      loader,
+			HTMLParser:  htmlParser,
+			Exporter:    exporter,
+			Cfg:         scraperCfg,
+			Parser:      parser,
+			Hook:        hook,
+			Progress:    progress,
+			Expiry:      expiry,
+			Enricher:    enrichers,
+			Logger:      appLogger,
+			ChangedOnly: scrapeChangedOnly,
 		}
 		scraper := usecase.NewSaveJobPostingFromHTMLUseCase(scraperArgs)
-		if err := scraper.SaveJobPostingCSV(context.Background()); err != nil {
-			log.Fatalf("スクレイプに失敗しました: %v", err)
+
+		result, err := scraper.SaveJobPostingCSV(ctx)
+		reportSpills()
+		reportCorporateNumbers()
+		run.Executed, run.Failed, run.Written = result.Processed, result.Failed, result.Written
+		if err != nil {
+			fatal("スクレイプに失敗しました: %v", err)
 		}
+		if scraperCfg.StatsReport != "" {
+			// サイトごとのセレクターの品質を比較できるよう、充足率をレポートとして残す
+			reportPath := filepath.Join(scraperCfg.OutputDir, scraperCfg.StatsReport)
+			if err := infra.WriteScrapeStatsReport(reportPath, result.Stats); err != nil {
+				appLogger.Warn("充足率のレポートを書き込めませんでした", "path", reportPath, "error", err)
+			}
+		}
+		notifyRun(ctx, notifier, run, nil, appLogger)
 	}}
 
+// scrapeExportFieldsは、既定の列にextra_fieldsで定義した追加の項目の列を加えた列定義を返します。
+//
+// args:
+//
+//	cfg : スクレイパーの設定
+//
+// return:
+//
+//	[]infra.ExportField : 列定義
+//	error               : 追加の項目名が既存の列と重複する場合のエラー
+func scrapeExportFields(cfg config.ScraperConfig) ([]infra.ExportField, error) {
+	return infra.AppendExtraExportFields(infra.DefaultExportFields(), cfg.ExtraFields.Names())
+}
+
+// newScrapeExporterは、設定の出力先ごとにエクスポーターを生成し、すべてに書き込むエクスポーターを返します。
+// 各出力先には、出力ファイルの分割とマニフェストの出力、書き込みの再試行を設定に応じて適用します。
+//
+// args:
+//
+//	ctx        : 書き込みの再試行の待機を中断するためのコンテキスト
+//	cfg        : スクレイパーの設定
+//	fields     : 出力する列の定義
+//	configHash : 出力を生成した設定のハッシュ値
+//	configFile : 出力を生成した設定のスナップショットのファイル名
+//	appLogger  : ロガー
+//
+// return:
+//
+//	infra.FileExporter : 生成されたエクスポーター
+//	func()             : 再試行しても書き込めずに退避した件数をログに出力する関数
+//	error              : いずれかのエクスポーターの生成に失敗した場合のエラー
+func newScrapeExporter(ctx context.Context, cfg config.ScraperConfig, fields []infra.ExportField, configHash, configFile string, appLogger logger.AppLogger) (infra.FileExporter, func(), error) {
+	var keyFunc infra.PartitionKeyFunc
+	if cfg.OutputPartition != "" {
+		var err error
+		keyFunc, err = infra.NewPartitionKeyFunc(cfg.OutputPartition)
+		if err != nil {
+			return nil, nil, fmt.Errorf("出力ファイルの分割の設定が不正です: %w", err)
+		}
+	}
+
+	sinks := make([]infra.ExportSink, 0, len(cfg.OutputTargets()))
+	var reports []func()
+	for _, output := range cfg.OutputTargets() {
+		// 匿名化などの変換は出力先ごとに適用し、他の出力先の列には影響させない
+		fields, err := infra.ApplyExportTransforms(fields, output.Transforms)
+		if err != nil {
+			infra.NewMultiExporter(nil, sinks...).Abort()
+			return nil, nil, fmt.Errorf("%s の列の変換の設定が不正です: %w", outputName(output), err)
+		}
+		headers := infra.ExportHeaders(fields)
+		keys := make([]string, 0, len(fields))
+		for _, field := range fields {
+			keys = append(keys, field.Key)
+		}
+
+		newExporter := func(path string) (infra.FileExporter, error) {
+			switch output.Format {
+			case config.OutputJSONL:
+				jsonlExporter, err := infra.NewJSONLExporter(path, fields, cfg.KeepPartial)
+				if err != nil {
+					return nil, err
+				}
+				return infra.NewManifestExporter(jsonlExporter, path, constants.ExportSchemaVersion, keys, configHash, configFile), nil
+			case config.OutputParquet:
+				parquetExporter, err := infra.NewParquetExporter(path, fields, cfg.KeepPartial)
+				if err != nil {
+					return nil, err
+				}
+				return infra.NewManifestExporter(parquetExporter, path, constants.ExportSchemaVersion, keys, configHash, configFile), nil
+			default:
+				csvExporter, err := infra.NewCSVExporter(path, fields, cfg.KeepPartial)
+				if err != nil {
+					return nil, err
+				}
+				return infra.NewManifestExporter(csvExporter, path, constants.ExportSchemaVersion, headers, configHash, configFile), nil
+			}
+		}
+
+		var exporter infra.FileExporter
+		outputPath := filepath.Join(cfg.OutputDir, output.FileName)
+		switch {
+		case output.Format == config.OutputWebhook:
+			// 送信先はファイルではないため、分割とマニフェストは適用しない
+			exporter = infra.NewWebhookExporter(output.URL, fields, output.BatchSizeOrDefault(), output.Timeout())
+		case keyFunc != nil:
+			// 分割キーごとのファイルは、該当する求人情報が初めて現れた時点で作成する
+			exporter = infra.NewPartitionedExporter(outputPath, keyFunc, newExporter)
+		default:
+			exporter, err = newExporter(outputPath)
+			if err != nil {
+				// 作成済みの一時ファイルを残さないよう後始末する
+				infra.NewMultiExporter(nil, sinks...).Abort()
+				return nil, nil, fmt.Errorf("%s の出力の初期化に失敗しました: %w", output.FileName, err)
+			}
+		}
+
+		if output.Retry != nil {
+			retrying := infra.NewRetryingExporter(
+				ctx,
+				exporter,
+				fields,
+				output.Retry.MaxAttempts,
+				time.Duration(output.Retry.BackoffMillis)*time.Millisecond,
+				output.Retry.SpillFile,
+			)
+			reports = append(reports, func() {
+				if replayed := retrying.Replayed(); replayed > 0 {
+					appLogger.Info("退避していた求人情報を再送しました", "format", output.Format, "count", replayed)
+				}
+				if spilled := retrying.Spilled(); spilled > 0 {
+					appLogger.Warn("書き込めなかった求人情報を退避しました", "format", output.Format, "count", spilled, "spill_file", retrying.SpillPath())
+				}
+			})
+			exporter = retrying
+		}
+		sinks = append(sinks, infra.ExportSink{Name: outputName(output), Exporter: exporter})
+	}
+
+	reportSpills := func() {
+		for _, report := range reports {
+			report()
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0].Exporter, reportSpills, nil
+	}
+	// 1つの出力先が失敗しても、他の出力先への書き込みは続ける
+	onFailure := func(name string, err error) {
+		appLogger.Error("出力先への書き込みに失敗したため、この出力先への出力を中止します", "output", name, "error", err)
+	}
+	return infra.NewMultiExporter(onFailure, sinks...), reportSpills, nil
+}
+
+// outputNameは、ログやエラーに表示する出力先の名前を返します。
+func outputName(output config.OutputConfig) string {
+	if output.Format == config.OutputWebhook {
+		return output.URL
+	}
+	return output.FileName
+}
+
 func init() {
 	rootCmd.AddCommand(scraperCmd)
+	scraperCmd.Flags().BoolVar(&scrapeQuiet, "quiet", false, "処理済みのファイル数・処理速度・残り時間の目安の表示を無効にする")
+	scraperCmd.Flags().BoolVar(&scrapeChangedOnly, "changed-only", false, "前回のクロールから内容が変更されていないページ（メタデータのunchangedがtrue）を出力しない")
 }
@@ -0,0 +1,31 @@
+package config
+
+type DetailActionType string
+
+const (
+	ActionClick        DetailActionType = "click"         // 要素をクリックする
+	ActionScrollBottom DetailActionType = "scroll_bottom" // ページの末尾までスクロールする
+	ActionWait         DetailActionType = "wait"          // 指定した時間だけ待機する
+	ActionPress        DetailActionType = "press"         // キーを押す
+)
+
+// DetailActionは、詳細ページのHTMLを取得する前に実行する1件の操作を定義します。
+// 給与や福利厚生が「もっと見る」ボタンの奥に隠れているページや、スクロールで遅延読み込みされるページで使用します。
+type DetailAction struct {
+	Type       DetailActionType `yaml:"type" validate:"required,oneof=click scroll_bottom wait press"` // 操作の種類
+	Selector   string           `yaml:"selector" validate:"required_if=Type click"`                    // クリックする要素のセレクター（pressの場合はキーを押す要素。省略時はページ全体）
+	WaitMillis int              `yaml:"wait_ms" validate:"min=0,required_if=Type wait"`                // 待機する時間（ミリ秒）
+	Key        string           `yaml:"key" validate:"required_if=Type press"`                         // 押すキー（例: End, Enter）
+}
+
+// DetailActionsは、詳細ページのHTMLを取得する前に実行する操作を実行順に返します。
+// actionsが未指定でtab_click_selectorが指定されている場合は、そのセレクターをクリックする操作を返します。
+func (c CrawlerConfig) DetailActions() []DetailAction {
+	if len(c.Actions) > 0 {
+		return c.Actions
+	}
+	if c.Selector.TabClickSelector != "" {
+		return []DetailAction{{Type: ActionClick, Selector: c.Selector.TabClickSelector}}
+	}
+	return nil
+}
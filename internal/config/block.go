@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type BlockedAction string
+
+const (
+	BlockedContinue BlockedAction = "continue" // ジョブをBLOCKEDにして次のジョブの処理を続ける
+	BlockedPause    BlockedAction = "pause"    // ジョブをBLOCKEDにして、キューを共有するすべてのクローラーを一時停止する
+	BlockedStop     BlockedAction = "stop"     // ジョブをBLOCKEDにして、このクローラーを停止する
+)
+
+// BlockConfigは、ボット検知のチャレンジページやCAPTCHAなど、アクセスを拒否されたページを判定する条件を定義します。
+// いずれかの条件に一致したページは求人のHTMLとして保存せず、ジョブをBLOCKEDにします。
+type BlockConfig struct {
+	TextPatterns []string      `yaml:"text_patterns"`                                         // ページのタイトルまたはテキストに含まれる場合にブロックと判定する正規表現（例: Access Denied）
+	Selectors    []string      `yaml:"selectors"`                                             // 一致する要素がある場合にブロックと判定するCSSセレクター（例: iframe[src*="recaptcha"]）
+	Action       BlockedAction `yaml:"action" validate:"omitempty,oneof=continue pause stop"` // ブロックと判定した場合の動作（省略時はcontinue）
+}
+
+// Enabledは、ブロックの判定条件が1つ以上指定されているかを返します。
+func (c BlockConfig) Enabled() bool {
+	return len(c.TextPatterns) > 0 || len(c.Selectors) > 0
+}
+
+// ActionOrDefaultは、ブロックと判定した場合の動作を返します。
+func (c BlockConfig) ActionOrDefault() BlockedAction {
+	if c.Action == "" {
+		return BlockedContinue
+	}
+	return c.Action
+}
+
+// blockIssuesは、ブロックの判定条件の正規表現が解釈できるかを確認します。
+func blockIssues(field string, c BlockConfig) []ConfigIssue {
+	var issues []ConfigIssue
+	for i, pattern := range c.TextPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			issues = append(issues, ConfigIssue{fmt.Sprintf("%s.text_patterns[%d]", field, i), fmt.Sprintf("正規表現として解釈できません。Goの正規表現（RE2）の構文で記述してください: %v", err)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,29 @@
+package config
+
+import "fmt"
+
+// BrowserEngineは、クロールに使用するブラウザのエンジンです。
+type BrowserEngine string
+
+const (
+	BrowserChromium BrowserEngine = "chromium" // Chromium（デフォルト）
+	BrowserFirefox  BrowserEngine = "firefox"  // Firefox（ヘッドレスChromiumを拒否するサイト向け）
+	BrowserWebKit   BrowserEngine = "webkit"   // WebKit（Safari相当）
+)
+
+// BrowserEngineOrDefaultは、クロールに使用するブラウザのエンジンを返します。未指定の場合はchromiumです。
+func (c CrawlerConfig) BrowserEngineOrDefault() BrowserEngine {
+	if c.BrowserEngine == "" {
+		return BrowserChromium
+	}
+	return c.BrowserEngine
+}
+
+// browserEngineIssuesは、ブラウザのエンジンとリモートブラウザの接続プロトコルの組み合わせを確認します。
+// Chrome DevTools ProtocolはChromiumでのみ使用できます。
+func browserEngineIssues(cfg CrawlerConfig) []ConfigIssue {
+	if cfg.RemoteBrowser.Endpoint != "" && cfg.RemoteBrowser.Protocol == RemoteBrowserCDP && cfg.BrowserEngineOrDefault() != BrowserChromium {
+		return []ConfigIssue{{"browser_engine", fmt.Sprintf("remote_browserのprotocolがcdpの場合、%sは使用できません。chromiumを指定するか、protocolをplaywrightにしてください", cfg.BrowserEngine)}}
+	}
+	return nil
+}
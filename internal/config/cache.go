@@ -0,0 +1,38 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// ResponseCacheConfigは、詳細ページのレスポンスをURLごとにキャッシュする設定を定義します。
+// 変更のない詳細ページを再クロールする際に、ページを取得し直さずに保存済みのHTMLを再利用します。
+type ResponseCacheConfig struct {
+	Storage    StorageType `yaml:"storage" validate:"omitempty,oneof=local redis"` // キャッシュの保存先（省略時はlocal）
+	Dir        string      `yaml:"dir"`                                            // キャッシュを保存するディレクトリ（redisの場合はキーの名前空間、省略時はoutput_dir/.cache）
+	TTLSeconds int         `yaml:"cache_ttl" validate:"min=0"`                     // 変更を確認せずにキャッシュを再利用する期間（秒、0の場合は毎回ETag・Last-Modifiedで変更を確認する）
+}
+
+// defaultCacheDirNameは、dirが未指定の場合にoutput_dirの下に作成するキャッシュのディレクトリ名です。
+const defaultCacheDirName = ".cache"
+
+// TTLは、変更を確認せずにキャッシュを再利用する期間を返します。
+func (c ResponseCacheConfig) TTL() time.Duration {
+	return time.Duration(c.TTLSeconds) * time.Second
+}
+
+// DirOrDefaultは、キャッシュを保存するディレクトリ（redisの場合はキーの名前空間）を返します。
+//
+// args:
+//
+//	outputDir : クローラーのoutput_dir
+//
+// return:
+//
+//	string : キャッシュを保存するディレクトリ
+func (c ResponseCacheConfig) DirOrDefault(outputDir string) string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return filepath.Join(outputDir, defaultCacheDirName)
+}
@@ -0,0 +1,18 @@
+package config
+
+// CookieRuleは、特定のURLへ遷移する前に設定するCookieを定義します。
+// 地域の選択や同意の状態をCookieで保持し、その値によって給与などの表示が変わるサイトで使用します。
+type CookieRule struct {
+	URLPattern string         `yaml:"url_pattern" validate:"required"`        // 対象とするURLの正規表現
+	Cookies    []CookieConfig `yaml:"cookies" validate:"required,min=1,dive"` // 遷移前に設定するCookie
+}
+
+// CookieConfigは、ブラウザに設定する1件のCookieを定義します。
+type CookieConfig struct {
+	Name     string `yaml:"name" validate:"required"` // Cookieの名前
+	Value    string `yaml:"value"`                    // Cookieの値
+	Domain   string `yaml:"domain"`                   // Cookieのドメイン（省略時は遷移先のURLのホスト。サブドメインにも適用する場合は先頭に「.」を付ける）
+	Path     string `yaml:"path"`                     // Cookieのパス（省略時は「/」）
+	Secure   bool   `yaml:"secure"`                   // HTTPSの場合のみ送信する
+	HTTPOnly bool   `yaml:"http_only"`                // JavaScriptから参照できないようにする
+}
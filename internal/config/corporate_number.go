@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// defaultCorporateNumberTimeoutSecondsは、timeout_secondsが未指定の場合の問い合わせのタイムアウト（秒）です。
+const defaultCorporateNumberTimeoutSeconds = 10
+
+// CorpNumberConfigは、国税庁の法人番号システムWeb-APIから会社の法人番号を取得する設定を定義します。
+// 会社名と本社の都道府県で法人を検索し、1件に特定できた場合に法人番号を出力します。
+type CorpNumberConfig struct {
+	AppID          string `yaml:"app_id" validate:"required"`        // Web-APIのアプリケーションID（環境変数の参照で指定することを推奨）
+	Endpoint       string `yaml:"endpoint" validate:"omitempty,url"` // 法人名で検索するAPIのURL（省略時は国税庁のWeb-API）
+	TimeoutSeconds int    `yaml:"timeout_seconds" validate:"min=0"`  // 1回の問い合わせのタイムアウト（秒、0の場合は10秒）
+}
+
+// Timeoutは、1回の問い合わせのタイムアウトを返します。
+func (c CorpNumberConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds > 0 {
+		return time.Duration(c.TimeoutSeconds) * time.Second
+	}
+	return defaultCorporateNumberTimeoutSeconds * time.Second
+}
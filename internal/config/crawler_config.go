@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/goccy/go-yaml"
@@ -11,8 +13,9 @@ import (
 type CrawlStrategy string
 
 const (
-	CrawlByNextLink   CrawlStrategy = "next_link"   // "次へ" ボタンをたどる
-	CrawlByTotalCount CrawlStrategy = "total_count" // 件数を取得してページ数を計算
+	CrawlByNextLink       CrawlStrategy = "next_link"       // "次へ" ボタンをたどる
+	CrawlByTotalCount     CrawlStrategy = "total_count"     // 件数を取得してページ数を計算
+	CrawlByInfiniteScroll CrawlStrategy = "infinite_scroll" // 一覧ページをスクロールして求人を読み込む
 )
 
 type CrawlMode string
@@ -24,29 +27,197 @@ const (
 
 // CrawlerConfigはクローラーの動作設定をまとめる構造体です。
 type CrawlerConfig struct {
-	Mode                    CrawlMode         `yaml:"mode" validate:"required,oneof=auto manual"`
-	Strategy                CrawlStrategy     `yaml:"strategy" validate:"required,oneof=next_link total_count url_list"` // クロール戦略（次へボタンをたどるか、総件数からページ数を計算するか）
-	BaseURL                 string            `yaml:"base_url" validate:"url"`                                           // クロールを開始するベースURL
-	JobDetailResolveBaseURL string            `yaml:"job_detail_resolve_base_url" validate:"omitempty,url"`              // 求人詳細リンクが相対パスだった場合に使用する明示的な基準URL
-	CrawlSleepSeconds       int               `yaml:"crawl_sleep_seconds" validate:"min=1,max=60"`                       // 各リクエスト間の待機時間（秒）
-	CrawlTimeoutSeconds     int               `yaml:"crawl_timeout_seconds" validate:"min=1,max=100"`                    // リクエストのタイムアウト時間（秒）
-	EnableHeadless          bool              `yaml:"enable_headless"`
-	UserAgent               string            `yaml:"user_agent" validate:"required,min=1"` // リクエストヘッダーに設定するUser-Agent
-	OutputDir               string            `yaml:"output_dir" validate:"required"`       // クロール結果を保存するディレクトリ
-	Headers                 map[string]string `yaml:"headers"`                              // リクエストに追加するカスタムヘッダー
-	Selector                CrawlerSelector   `yaml:"selector" validate:"required"`         // クロール対象要素のCSSセレクター設定
-	Pagination              PaginationConfig  `yaml:"pagination" validate:"required"`       // ページネーションに関する設定
-	Urls                    []string          `yaml:"urls"`                                 // クロール対象のURLリスト（url_list戦略の場合必須）
-	WorkerNum               int               `yaml:"worker_num" validate:"min=1,max=10"`   // 並列実行するワーカーの数
+	Mode                    CrawlMode            `yaml:"mode" validate:"required,oneof=auto manual"`
+	Strategy                CrawlStrategy        `yaml:"strategy" validate:"required,oneof=next_link total_count infinite_scroll url_list"` // クロール戦略（次へボタンをたどるか、総件数からページ数を計算するか、スクロールするか）
+	BaseURL                 string               `yaml:"base_url" validate:"url"`                                                           // クロールを開始するベースURL
+	JobDetailResolveBaseURL string               `yaml:"job_detail_resolve_base_url" validate:"omitempty,url"`                              // 求人詳細リンクが相対パスだった場合に使用する明示的な基準URL
+	CrawlSleepSeconds       int                  `yaml:"crawl_sleep_seconds" validate:"min=1,max=60"`                                       // 各リクエスト間の待機時間（秒）
+	CrawlTimeoutSeconds     int                  `yaml:"crawl_timeout_seconds" validate:"min=1,max=100"`                                    // リクエストのタイムアウト時間（秒）
+	BrowserEngine           BrowserEngine        `yaml:"browser_engine" validate:"omitempty,oneof=chromium firefox webkit"`                 // クロールに使用するブラウザのエンジン（省略時はchromium）
+	EnableHeadless          bool                 `yaml:"enable_headless"`
+	RetryCount              int                  `yaml:"retry_count" validate:"min=0,max=10"`                   // 一時的な失敗（タイムアウト・5xx・通信エラー）でナビゲーションとAPIの呼び出しを再試行する回数（0は再試行しない）
+	Retry                   FetchRetryConfig     `yaml:"retry"`                                                 // 再試行までの待機時間と、再試行する失敗の種類
+	UserAgent               string               `yaml:"user_agent" validate:"required,min=1"`                  // リクエストヘッダーに設定するUser-Agent
+	OutputDir               string               `yaml:"output_dir" validate:"required"`                        // クロール結果を保存するディレクトリ（redis・s3・gcsの場合はキーの名前空間）
+	Storage                 StorageType          `yaml:"storage" validate:"omitempty,oneof=local redis s3 gcs"` // HTMLの保存先（省略時はlocal）
+	ObjectStorage           *ObjectStorageConfig `yaml:"object_storage"`                                        // storageがs3・gcsの場合の保存先のバケットとアップロードの設定
+	ListPageDir             string               `yaml:"list_page_dir"`                                         // 一覧ページのHTMLを保存するディレクトリ（redis・s3・gcsの場合はキーの名前空間、省略時は保存しない）
+	EncryptHTML             bool                 `yaml:"encrypt_html"`                                          // HTMLとメタデータを暗号化して保存するかどうか（鍵は環境変数HTML_ENCRYPTION_KEY）
+	ExportPDF               bool                 `yaml:"export_pdf"`                                            // 詳細ページのHTMLと同じ名前で、ブラウザで描画したページのPDFも保存するかどうか（chromiumのみ）
+	Headers                 map[string]string    `yaml:"headers"`                                               // リクエストに追加するカスタムヘッダー
+	Cookies                 []CookieRule         `yaml:"cookies" validate:"omitempty,dive"`                     // URLのパターンごとに遷移前に設定するCookie
+	Selector                CrawlerSelector      `yaml:"selector" validate:"required"`                          // クロール対象要素のCSSセレクター設定
+	Actions                 []DetailAction       `yaml:"actions" validate:"omitempty,dive"`                     // 詳細ページのHTMLを取得する前に実行順に行う操作
+	Expired                 ExpiryConfig         `yaml:"expired"`                                               // 掲載が終了した求人ページを判定する条件
+	Blocked                 BlockConfig          `yaml:"blocked"`                                               // ボット検知のチャレンジページやCAPTCHAなど、アクセスを拒否されたページを判定する条件
+	Pagination              PaginationConfig     `yaml:"pagination" validate:"required"`                        // ページネーションに関する設定
+	Urls                    []string             `yaml:"urls"`                                                  // クロール対象のURLリスト（url_list戦略の場合必須）
+	URLMatrix               *URLMatrixConfig     `yaml:"url_matrix" validate:"omitempty"`                       // manualモードで一覧ページのURLをテンプレートとパラメーターの組み合わせから生成する設定
+	WorkerNum               int                  `yaml:"worker_num" validate:"min=1,max=10"`                    // 並列実行するワーカーの数
+	MaxPages                int                  `yaml:"max_pages" validate:"min=0"`                            // next_link戦略で一覧ページごとに辿るページ数の上限（0は無制限）
+	MaxJobs                 int                  `yaml:"max_jobs" validate:"min=0"`                             // next_link・infinite_scroll戦略で一覧ページごとに作成するジョブ数の上限（0は無制限）
+	SampleRate              float64              `yaml:"sample_rate" validate:"min=0,max=1"`                    // next_link・infinite_scroll戦略で詳細ページのリンクを間引く割合（0.1の場合は10件ごとに1件、0または1の場合は間引かない）
+	MaxJobsPerPage          int                  `yaml:"max_jobs_per_page" validate:"min=0"`                    // next_link・infinite_scroll戦略で1ページ（infinite_scrollの場合は1回のスクロール）から作成するジョブ数の上限（0は無制限）
+	InfiniteScroll          InfiniteScrollConfig `yaml:"infinite_scroll"`                                       // infinite_scroll戦略でのスクロールの設定
+	TotalCountAPI           *TotalCountAPIConfig `yaml:"total_count_api" validate:"omitempty"`                  // total_count戦略で総件数をJSONのAPIから取得する設定（指定した場合はtotal_count_selectorより優先）
+	Quota                   QuotaConfig          `yaml:"quota"`                                                 // 1回の実行あたりのリソース使用量の上限
+	Stealth                 StealthConfig        `yaml:"stealth"`                                               // ボット検知を回避するための設定
+	Pierce                  PierceConfig         `yaml:"pierce"`                                                // shadow DOMとiframeの中の要素を抽出と保存するHTMLに含める設定
+	RemoteBrowser           RemoteBrowserConfig  `yaml:"remote_browser"`                                        // 接続するリモートブラウザの設定（未指定の場合はローカルで起動）
+	BrowserRecycle          BrowserRecycleConfig `yaml:"browser_recycle"`                                       // 長時間の実行でブラウザのメモリ使用量が増え続けないよう、ページとコンテキストを作り直す条件
+	Job                     CrawlJobConfig       `yaml:"job"`                                                   // クロールジョブの有効期限や回収に関する設定
+	Hooks                   []HookConfig         `yaml:"hooks" validate:"omitempty,dive"`                       // クロールジョブの完了時に呼び出すフック
+	AuditDir                string               `yaml:"audit_dir"`                                             // ドメインごとのリクエストの集計結果を出力するディレクトリ（未指定の場合はログのみ）
+	RunReport               string               `yaml:"run_report"`                                            // 実行の終了時に実行結果のレポート（JSON）を書き出すパス（実行ごとに上書き、未指定の場合は出力しない）
+	Cache                   *ResponseCacheConfig `yaml:"cache" validate:"omitempty"`                            // 詳細ページのレスポンスのキャッシュの設定（未指定の場合はキャッシュしない）
+	Throttle                *ThrottleConfig      `yaml:"throttle" validate:"omitempty"`                         // エラーが増えた場合にジョブの間の待機時間を自動的に広げる設定（未指定の場合は広げない）
+	SearchForm              *SearchFormConfig    `yaml:"search_form" validate:"omitempty"`                      // 一覧ページで詳細ページのリンクを抽出する前に入力・送信する検索フォーム（未指定の場合は送信しない）
+	Schedule                string               `yaml:"schedule"`                                              // crawler daemonでクロールを実行するスケジュール（cron式、例: "0 3 * * *"）
+	Notifications           []NotificationConfig `yaml:"notifications" validate:"omitempty,dive"`               // 実行の終了時に実行結果を通知するWebhook
+}
+
+// CrawlJobConfigは、クロールジョブの有効期限と、処理中のまま残ったジョブの回収に関する設定を定義します。
+type CrawlJobConfig struct {
+	PendingTTLHours   int `yaml:"pending_ttl_hours" validate:"min=0"`   // PENDINGのジョブの有効期限（時間、0は無期限）
+	StaleAfterMinutes int `yaml:"stale_after_minutes" validate:"min=0"` // IN_PROGRESSのまま放置されたとみなすまでの時間（分、0の場合は30分）
+	RecrawlAfterHours int `yaml:"recrawl_after_hours" validate:"min=0"` // SUCCESSのジョブを再クロールの対象に戻すまでの、最後のクロールからの経過時間（時間、0は戻さない）
+	StreamBatchSize   int `yaml:"stream_batch_size" validate:"min=0"`   // ステータスごとのジョブを走査する際に1回で取得するジョブ数（0の場合は100）
+	StreamPrefetch    int `yaml:"stream_prefetch" validate:"min=0"`     // ステータスごとのジョブを走査する際に先行して取得しておくジョブ数（0の場合はstream_batch_size）
+}
+
+// defaultStaleAfterMinutesは、stale_after_minutesが未指定の場合に使用する時間（分）です。
+const defaultStaleAfterMinutes = 30
+
+// PendingTTLは、PENDINGのジョブの有効期限を返します。0は無期限を表します。
+func (c CrawlJobConfig) PendingTTL() time.Duration {
+	return time.Duration(c.PendingTTLHours) * time.Hour
+}
+
+// StaleAfterは、IN_PROGRESSのジョブを放置されたとみなすまでの時間を返します。
+func (c CrawlJobConfig) StaleAfter() time.Duration {
+	if c.StaleAfterMinutes == 0 {
+		return defaultStaleAfterMinutes * time.Minute
+	}
+	return time.Duration(c.StaleAfterMinutes) * time.Minute
+}
+
+// RecrawlAfterは、SUCCESSのジョブを再クロールの対象に戻すまでの経過時間を返します。0は戻さないことを表します。
+func (c CrawlJobConfig) RecrawlAfter() time.Duration {
+	return time.Duration(c.RecrawlAfterHours) * time.Hour
+}
+
+// InfiniteScrollConfigは、infinite_scroll戦略で一覧ページをスクロールする際の動作を定義します。
+type InfiniteScrollConfig struct {
+	WaitMillis     int `yaml:"wait_ms" validate:"min=0"`          // スクロール後に求人の読み込みを待つ時間（ミリ秒、0の場合は1000）
+	MaxIdleScrolls int `yaml:"max_idle_scrolls" validate:"min=0"` // 新しい求人が見つからないスクロールがこの回数続いたら停止する（0の場合は3）
+	MaxScrolls     int `yaml:"max_scrolls" validate:"min=0"`      // 一覧ページごとのスクロール回数の上限（0は無制限）
+}
+
+const (
+	defaultScrollWaitMillis = 1000 // wait_msが未指定の場合に待つ時間（ミリ秒）
+	defaultMaxIdleScrolls   = 3    // max_idle_scrollsが未指定の場合の回数
+)
+
+// Waitは、スクロール後に求人の読み込みを待つ時間を返します。
+func (c InfiniteScrollConfig) Wait() time.Duration {
+	if c.WaitMillis == 0 {
+		return defaultScrollWaitMillis * time.Millisecond
+	}
+	return time.Duration(c.WaitMillis) * time.Millisecond
+}
+
+// MaxIdleScrollsOrDefaultは、新しい求人が見つからないまま続けるスクロールの回数を返します。
+func (c InfiniteScrollConfig) MaxIdleScrollsOrDefault() int {
+	if c.MaxIdleScrolls == 0 {
+		return defaultMaxIdleScrolls
+	}
+	return c.MaxIdleScrolls
+}
+
+type RemoteBrowserProtocol string
+
+const (
+	RemoteBrowserPlaywright RemoteBrowserProtocol = "playwright" // Playwrightサーバー（browserType.launchServerなど）
+	RemoteBrowserCDP        RemoteBrowserProtocol = "cdp"        // Chrome DevTools Protocolのエンドポイント（browserlessなど）
+)
+
+// RemoteBrowserConfigは、ローカルでブラウザを起動する代わりに接続するリモートブラウザの設定を定義します。
+type RemoteBrowserConfig struct {
+	Endpoint string                `yaml:"endpoint" validate:"omitempty,url"`                  // 接続先のエンドポイント（例: ws://localhost:3000）
+	Protocol RemoteBrowserProtocol `yaml:"protocol" validate:"omitempty,oneof=playwright cdp"` // 接続プロトコル（省略時はplaywright）
+	Headers  map[string]string     `yaml:"headers"`                                            // 接続時に送信するヘッダー（認証トークンなど）
+}
+
+// BrowserRecycleConfigは、ブラウザのページとコンテキストを作り直す条件を定義します。
+// 作り直しはナビゲーションの直前に行い、Cookieとローカルストレージは新しいコンテキストに引き継ぎます。
+type BrowserRecycleConfig struct {
+	EveryNavigations int `yaml:"every_navigations" validate:"min=0"` // この回数ナビゲーションするごとに作り直す（0は回数で作り直さない）
+	MaxRSSMegabytes  int `yaml:"max_rss_mb" validate:"min=0"`        // ブラウザのプロセスのメモリ使用量（RSS）の合計がこの値（MB）を超えたら作り直す（0は確認しない、Linuxのローカルのブラウザのみ）
+}
+
+// MaxRSSBytesは、作り直すメモリ使用量のしきい値をバイト単位で返します。0は確認しないことを表します。
+func (c BrowserRecycleConfig) MaxRSSBytes() int64 {
+	return int64(c.MaxRSSMegabytes) * 1024 * 1024
+}
+
+// StealthConfigは、ヘッドレスブラウザの特徴を隠し、ボット検知を回避するための設定を定義します。
+type StealthConfig struct {
+	RandomViewport bool     `yaml:"random_viewport"`                                 // ビューポートのサイズをランダムにする
+	Locale         string   `yaml:"locale"`                                          // ブラウザのロケール（例: ja-JP）
+	TimezoneID     string   `yaml:"timezone_id"`                                     // ブラウザのタイムゾーン（例: Asia/Tokyo）
+	HideWebdriver  bool     `yaml:"hide_webdriver"`                                  // navigator.webdriverなど自動操作の痕跡を隠す
+	MinDelayMillis int      `yaml:"min_delay_ms" validate:"min=0"`                   // 操作間のランダムな待機時間の最小値（ミリ秒）
+	MaxDelayMillis int      `yaml:"max_delay_ms" validate:"gtefield=MinDelayMillis"` // 操作間のランダムな待機時間の最大値（ミリ秒）
+	UserAgents     []string `yaml:"user_agents" validate:"omitempty,dive,required"`  // リクエストごとにランダムに選択するUser-Agentのリスト
+}
+
+// QuotaConfigは、1回の実行あたりのリソース使用量の上限を定義します。いずれも0の場合は無制限です。
+type QuotaConfig struct {
+	MaxPages          int   `yaml:"max_pages" validate:"min=0"`           // ナビゲーションするページ数の上限
+	MaxBytes          int64 `yaml:"max_bytes" validate:"min=0"`           // 取得するHTMLの合計バイト数の上限
+	MaxBrowserSeconds int   `yaml:"max_browser_seconds" validate:"min=0"` // ブラウザ操作に要する時間の合計の上限（秒）
+}
+
+// RetryConditionは、ナビゲーションやAPIの呼び出しを再試行する失敗の種類です。
+type RetryCondition string
+
+const (
+	RetryOnTimeout     RetryCondition = "timeout" // タイムアウト
+	RetryOnServerError RetryCondition = "5xx"     // 5xxのステータスコード
+	RetryOnNetwork     RetryCondition = "network" // 接続の失敗などの通信エラー
+)
+
+// FetchRetryConfigは、ナビゲーションやAPIの呼び出しが一時的に失敗した場合の再試行を定義します。
+// 再試行する回数はretry_countで指定します。404・410などの5xx以外のステータスコードは再試行しません。
+type FetchRetryConfig struct {
+	BackoffMillis int              `yaml:"backoff_ms" validate:"min=0"`                                  // 最初の再試行までの待機時間（ミリ秒、再試行のたびに2倍、0の場合は1000）
+	RetryOn       []RetryCondition `yaml:"retry_on" validate:"omitempty,dive,oneof=timeout 5xx network"` // 再試行する失敗の種類（省略時はすべて）
+}
+
+// defaultRetryBackoffMillisは、backoff_msが未指定の場合の最初の再試行までの待機時間（ミリ秒）です。
+const defaultRetryBackoffMillis = 1000
+
+// Backoffは、最初の再試行までの待機時間を返します。
+func (c FetchRetryConfig) Backoff() time.Duration {
+	if c.BackoffMillis == 0 {
+		return defaultRetryBackoffMillis * time.Millisecond
+	}
+	return time.Duration(c.BackoffMillis) * time.Millisecond
+}
+
+// RetriesOnは、指定した種類の失敗を再試行するかを返します。retry_onが未指定の場合はすべて再試行します。
+func (c FetchRetryConfig) RetriesOn(condition RetryCondition) bool {
+	return len(c.RetryOn) == 0 || slices.Contains(c.RetryOn, condition)
 }
 
 // CrawlerSelectorはWebページから特定の要素を選択するためのCSSセレクターを定義します。
 type CrawlerSelector struct {
-	ListLinksSelector   string `yaml:"list_links_selector" validate:"required,min=1"`   // 一覧ページのリンクのCSSセレクター(複数)
-	NextPageLocator     string `yaml:"next_page_locator"`                               // 次のページへのリンクのロケータ-,CrawlByNextLink戦略用）(単一)
-	TotalCountSelector  string `yaml:"total_count_selector"`                            // 総件数を取得するためのCSSセレクター（CrawlByTotalCount戦略用）(単一)
-	TabClickSelector    string `yaml:"tab_click_selector"`                              // 詳細画面でclickした時にtabで遷移させるセレクター
-	DetailLinksSelector string `yaml:"detail_links_selector" validate:"required,min=1"` // 求人（または詳細情報）リンクのCSSセレクター(複数)
+	ListLinksSelector   string `yaml:"list_links_selector" validate:"required,min=1"`                        // 一覧ページのリンクのCSSセレクター(複数)
+	NextPageLocator     string `yaml:"next_page_locator"`                                                    // 次のページへのリンクのロケータ-,CrawlByNextLink戦略用）(単一)
+	TotalCountSelector  string `yaml:"total_count_selector"`                                                 // 総件数を取得するためのCSSセレクター（CrawlByTotalCount戦略用、total_count_apiを指定した場合は無視される）(単一)
+	TabClickSelector    string `yaml:"tab_click_selector"`                                                   // 詳細画面でclickした時にtabで遷移させるセレクター（actionsを指定した場合は無視される）
+	DetailLinksSelector string `yaml:"detail_links_selector" validate:"required_without=DetailLinksPattern"` // 求人（または詳細情報）リンクのCSSセレクター(複数、detail_links_patternを指定した場合は無視される)
+	DetailLinksPattern  string `yaml:"detail_links_pattern"`                                                 // 一覧ページのHTMLから求人リンクを1番目のキャプチャグループで取り出す正規表現（CSSセレクターで指定しにくい場合に使用）
 }
 
 type PaginationType string
@@ -71,12 +242,16 @@ type PaginationConfig struct {
 // バリデーターのインスタンス
 var v = validator.New()
 
-// YAMLファイルからCrawlerConfigを読み込む
+// YAMLファイルからCrawlerConfigを読み込む（${VAR}形式の環境変数の参照は展開する）
 func LoadCrawlerConfig(path string) (CrawlerConfig, error) {
 	f, err := os.ReadFile(path)
 	if err != nil {
 		return CrawlerConfig{}, err
 	}
+	f, err = expandEnv(f)
+	if err != nil {
+		return CrawlerConfig{}, err
+	}
 
 	var cfg CrawlerConfig
 	if err := yaml.Unmarshal(f, &cfg); err != nil {
@@ -89,18 +264,36 @@ func LoadCrawlerConfig(path string) (CrawlerConfig, error) {
 	}
 
 	// カスタムバリデーション
-	if cfg.Strategy == CrawlByTotalCount && cfg.Selector.TotalCountSelector == "" {
-		return CrawlerConfig{}, fmt.Errorf("total_count戦略にはtotal_count_selectorが必要です")
+	if cfg.Strategy == CrawlByTotalCount && cfg.Selector.TotalCountSelector == "" && cfg.TotalCountAPI == nil {
+		return CrawlerConfig{}, fmt.Errorf("total_count戦略にはtotal_count_selectorまたはtotal_count_apiが必要です")
 	}
 	if cfg.Strategy == CrawlByNextLink && cfg.Selector.NextPageLocator == "" {
 		return CrawlerConfig{}, fmt.Errorf("next_link戦略にはnext_page_selectorが必要です")
 	}
-	if cfg.Mode == Manual && len(cfg.Urls) == 0 {
-		return CrawlerConfig{}, fmt.Errorf("url_list戦略にはurlsが必要です")
+	if cfg.Mode == Manual && len(cfg.ListURLs()) == 0 {
+		return CrawlerConfig{}, fmt.Errorf("url_list戦略にはurlsまたはurl_matrixが必要です")
+	}
+	if issues := urlMatrixIssues(cfg.URLMatrix); len(issues) > 0 {
+		return CrawlerConfig{}, fmt.Errorf("url_matrixの設定が不正です: %s", issues[0].Message)
 	}
 	if cfg.Pagination.Type != None && cfg.Pagination.ParamIdentifier == "" {
 		return CrawlerConfig{}, fmt.Errorf("ページネーションタイプがnone以外の場合はparam_identifierが必要です")
 	}
+	if cfg.Storage.IsObjectStorage() && cfg.ObjectStorage == nil {
+		return CrawlerConfig{}, fmt.Errorf("storageが%sの場合はobject_storageが必要です", cfg.Storage)
+	}
+	if issues := listPageDirIssues(cfg); len(issues) > 0 {
+		return CrawlerConfig{}, fmt.Errorf("list_page_dirの設定が不正です: %s", issues[0].Message)
+	}
+	if issues := browserEngineIssues(cfg); len(issues) > 0 {
+		return CrawlerConfig{}, fmt.Errorf("browser_engineの設定が不正です: %s", issues[0].Message)
+	}
+	if issues := exportPDFIssues(cfg); len(issues) > 0 {
+		return CrawlerConfig{}, fmt.Errorf("export_pdfの設定が不正です: %s", issues[0].Message)
+	}
+	if issues := searchFormIssues(cfg); len(issues) > 0 {
+		return CrawlerConfig{}, fmt.Errorf("search_formの設定が不正です: %s", issues[0].Message)
+	}
 
 	return cfg, nil
 }
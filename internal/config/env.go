@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envRefPatternは、設定ファイル中の環境変数の参照（${VAR} または ${VAR:-デフォルト値}）に一致する正規表現です。
+// 正規表現のセレクターなどで使われる $ と区別するため、波括弧で囲んだ形式のみを展開します。
+// $${VAR} と書いた場合は展開せずに ${VAR} として扱います。
+var envRefPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnvは、設定ファイルの内容に含まれる環境変数の参照を環境変数の値で置き換えます。
+// URLや認証情報、出力先などを、環境ごとに異なる値や秘密の値としてYAMLに直接書かずに済むようにします。
+//
+// args:
+//
+//	data : 設定ファイルの内容
+//
+// return:
+//
+//	[]byte : 環境変数を展開した設定ファイルの内容
+//	error  : デフォルト値のない環境変数が設定されていない場合のエラー
+func expandEnv(data []byte) ([]byte, error) {
+	var missing []string
+	expanded := envRefPattern.ReplaceAllFunc(data, func(ref []byte) []byte {
+		if strings.HasPrefix(string(ref), "$$") {
+			return ref[1:]
+		}
+
+		m := envRefPattern.FindSubmatch(ref)
+		name := string(m[1])
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			return []byte(value)
+		}
+		// ":-" を含む場合は、空のデフォルト値も明示的な指定として扱う
+		if strings.Contains(string(ref), ":-") {
+			return m[2]
+		}
+		missing = append(missing, name)
+		return ref
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("設定ファイルで参照している環境変数が設定されていません: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type ExpiredAction string
+
+const (
+	ExpiredSkip ExpiredAction = "skip" // 掲載終了と判定した求人を出力しない
+	ExpiredMark ExpiredAction = "mark" // 掲載終了の列を設定して出力する
+)
+
+// ExpiryConfigは、掲載が終了した求人ページ（ソフト404）を判定する条件を定義します。
+// いずれかの条件に一致したページを掲載終了と判定し、中身のない求人情報が出力されることを防ぎます。
+type ExpiryConfig struct {
+	TextPatterns        []string      `yaml:"text_patterns"`                               // ページのテキストに含まれる場合に掲載終了と判定する正規表現（例: この求人は掲載を終了しました）
+	MinContentBytes     int           `yaml:"min_content_bytes" validate:"min=0"`          // ページのテキストがこのバイト数未満の場合に掲載終了と判定する（0は判定しない）
+	RedirectURLPatterns []string      `yaml:"redirect_url_patterns"`                       // 別のURLへリダイレクトされ、リダイレクト先がこの正規表現に一致する場合に掲載終了と判定する（例: 一覧ページのURL）
+	Action              ExpiredAction `yaml:"action" validate:"omitempty,oneof=skip mark"` // 掲載終了と判定した求人の扱い（スクレイパーのみ、省略時はskip）
+}
+
+// Enabledは、掲載終了の判定条件が1つ以上指定されているかを返します。
+func (c ExpiryConfig) Enabled() bool {
+	return len(c.TextPatterns) > 0 || c.MinContentBytes > 0 || len(c.RedirectURLPatterns) > 0
+}
+
+// ActionOrDefaultは、掲載終了と判定した求人の扱いを返します。
+func (c ExpiryConfig) ActionOrDefault() ExpiredAction {
+	if c.Action == "" {
+		return ExpiredSkip
+	}
+	return c.Action
+}
+
+// expiryIssuesは、掲載終了の判定条件の正規表現が解釈できるかを確認します。
+func expiryIssues(field string, c ExpiryConfig) []ConfigIssue {
+	var issues []ConfigIssue
+	patterns := map[string][]string{
+		"text_patterns":         c.TextPatterns,
+		"redirect_url_patterns": c.RedirectURLPatterns,
+	}
+	for _, name := range []string{"text_patterns", "redirect_url_patterns"} {
+		for i, pattern := range patterns[name] {
+			if _, err := regexp.Compile(pattern); err != nil {
+				issues = append(issues, ConfigIssue{fmt.Sprintf("%s.%s[%d]", field, name, i), fmt.Sprintf("正規表現として解釈できません。Goの正規表現（RE2）の構文で記述してください: %v", err)})
+			}
+		}
+	}
+	return issues
+}
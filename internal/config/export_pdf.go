@@ -0,0 +1,26 @@
+package config
+
+import "fmt"
+
+// exportPDFIssuesは、詳細ページのPDFを保存できる設定かを確認します。
+// PDFの出力はヘッドレスモードのChromiumのみが対応しており、PDFはRedisに保存せず、暗号化もしないため、これらの設定とは併用できません。
+func exportPDFIssues(cfg CrawlerConfig) []ConfigIssue {
+	if !cfg.ExportPDF {
+		return nil
+	}
+
+	var issues []ConfigIssue
+	if engine := cfg.BrowserEngineOrDefault(); engine != BrowserChromium {
+		issues = append(issues, ConfigIssue{"export_pdf", fmt.Sprintf("PDFの出力はchromiumのみが対応しています。browser_engineの%sでは使用できません", engine)})
+	}
+	if !cfg.EnableHeadless {
+		issues = append(issues, ConfigIssue{"export_pdf", "PDFの出力はヘッドレスモードのみが対応しています。enable_headlessをtrueにしてください"})
+	}
+	if cfg.Storage == StorageRedis {
+		issues = append(issues, ConfigIssue{"export_pdf", "storageがredisの場合は使用できません。PDFはlocal・s3・gcsに保存してください"})
+	}
+	if cfg.EncryptHTML {
+		issues = append(issues, ConfigIssue{"export_pdf", "PDFは暗号化せずに保存するため、encrypt_htmlとは併用できません"})
+	}
+	return issues
+}
@@ -0,0 +1,17 @@
+package config
+
+// ExtractionModeは、求人ページから求人情報を抽出する方法です。
+type ExtractionMode string
+
+const (
+	ExtractBySelector ExtractionMode = "selector" // 設定したCSSセレクターのみで抽出する
+	ExtractByJSONLD   ExtractionMode = "json_ld"  // JSON-LDのschema.org JobPostingを優先し、含まれない項目はCSSセレクターで抽出する
+)
+
+// ExtractionOrDefaultは、求人情報を抽出する方法を返します。未指定の場合はselectorです。
+func (c ScraperConfig) ExtractionOrDefault() ExtractionMode {
+	if c.Extraction == "" {
+		return ExtractBySelector
+	}
+	return c.Extraction
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Hashは、実効的なスクレイパー設定のSHA-256ハッシュを返します。
+// 出力物がどの設定によって生成されたかを追跡するために使用します。
+//
+// return:
+//
+//	string : 16進数表記のハッシュ値
+//	error  : 設定のシリアライズに失敗した場合のエラー
+func (c ScraperConfig) Hash() (string, error) {
+	return hashConfig(c)
+}
+
+// Hashは、実効的なクローラー設定のSHA-256ハッシュを返します。
+//
+// return:
+//
+//	string : 16進数表記のハッシュ値
+//	error  : 設定のシリアライズに失敗した場合のエラー
+func (c CrawlerConfig) Hash() (string, error) {
+	return hashConfig(c)
+}
+
+// hashConfigは、設定をYAMLにシリアライズした結果のSHA-256ハッシュを返します。
+func hashConfig(cfg any) (string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("設定のシリアライズに失敗しました: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,27 @@
+package config
+
+// HookTypeは、設定ファイルから登録するフックの種類です。
+type HookType string
+
+const (
+	HookWebhook HookType = "webhook" // 指定したURLにJSONをPOSTする
+	HookScript  HookType = "script"  // 指定したコマンドの標準入力にJSONを渡して実行する
+)
+
+// HookEventは、フックを呼び出す処理の段階です。
+type HookEvent string
+
+const (
+	HookEventPostingParsed HookEvent = "posting_parsed" // 求人情報を抽出した直後（拒否できる）
+	HookEventRowExported   HookEvent = "row_exported"   // 求人情報を出力した直後
+	HookEventJobCompleted  HookEvent = "job_completed"  // クロールジョブの処理が完了した直後
+)
+
+// HookConfigは、設定ファイルから登録するフック（Webhookまたはスクリプト）を定義します。
+type HookConfig struct {
+	Type           HookType    `yaml:"type" validate:"required,oneof=webhook script"`                                         // フックの種類
+	URL            string      `yaml:"url" validate:"required_if=Type webhook,omitempty,url"`                                 // WebhookのURL
+	Command        []string    `yaml:"command" validate:"required_if=Type script"`                                            // 実行するコマンドと引数
+	Events         []HookEvent `yaml:"events" validate:"required,min=1,dive,oneof=posting_parsed row_exported job_completed"` // フックを呼び出すイベント
+	TimeoutSeconds int         `yaml:"timeout_seconds" validate:"min=0,max=300"`                                              // 1回の呼び出しのタイムアウト（秒、0の場合は10秒）
+}
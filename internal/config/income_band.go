@@ -0,0 +1,34 @@
+package config
+
+import "fmt"
+
+// IncomeBandは、年収に換算した給与を分類する年収帯を1つ定義します。年収帯は金額の小さい順に並べます。
+type IncomeBand struct {
+	Label string `yaml:"label" validate:"required"` // 出力する年収帯の名前（例: "300–500万"）
+	Below uint64 `yaml:"below"`                     // 年収帯の上限（円、この金額未満）。0の場合は上限なし（最後の年収帯のみ）
+}
+
+// incomeBandsIssuesは、年収帯の上限が小さい順に並んでいるか、名前が重複していないかを確認します。
+func incomeBandsIssues(bands []IncomeBand) []ConfigIssue {
+	var issues []ConfigIssue
+	labels := make(map[string]bool, len(bands))
+	var previous uint64
+	for i, band := range bands {
+		field := fmt.Sprintf("income_bands[%d]", i)
+		if band.Label != "" && labels[band.Label] {
+			issues = append(issues, ConfigIssue{field + ".label", fmt.Sprintf("%sが重複しています", band.Label)})
+		}
+		labels[band.Label] = true
+
+		switch {
+		case band.Below == 0 && i != len(bands)-1:
+			issues = append(issues, ConfigIssue{field + ".below", "上限を省略できるのは最後の年収帯のみです"})
+		case band.Below != 0 && band.Below <= previous:
+			issues = append(issues, ConfigIssue{field + ".below", fmt.Sprintf("前の年収帯の上限（%d）より大きい金額を指定してください", previous)})
+		}
+		if band.Below != 0 {
+			previous = band.Below
+		}
+	}
+	return issues
+}
@@ -0,0 +1,41 @@
+package config
+
+import "slices"
+
+// NotificationFormatは、実行結果を通知する際のリクエストボディの形式です。
+type NotificationFormat string
+
+const (
+	NotificationJSON  NotificationFormat = "json"  // 実行結果をJSONとしてPOSTする
+	NotificationSlack NotificationFormat = "slack" // SlackのIncoming Webhookの形式（text）でPOSTする
+)
+
+// NotificationEventは、実行結果を通知する契機です。
+type NotificationEvent string
+
+const (
+	NotifyOnSuccess NotificationEvent = "success" // 実行が正常に完了した場合
+	NotifyOnFailure NotificationEvent = "failure" // 実行が失敗した場合、または中断された場合
+)
+
+// NotificationConfigは、クローラー・スクレイパーの実行の終了時に、実行結果を通知するWebhookを定義します。
+// 無人で実行している場合に、失敗や件数の異常にすぐ気付けるようにします。
+type NotificationConfig struct {
+	URL            string              `yaml:"url" validate:"required,url"`                        // 通知先のWebhookのURL
+	Format         NotificationFormat  `yaml:"format" validate:"omitempty,oneof=json slack"`       // リクエストボディの形式（省略時はjson）
+	On             []NotificationEvent `yaml:"on" validate:"omitempty,dive,oneof=success failure"` // 通知する契機（省略時はsuccessとfailureの両方）
+	TimeoutSeconds int                 `yaml:"timeout_seconds" validate:"min=0,max=300"`           // 1回の通知のタイムアウト（秒、0の場合は10秒）
+}
+
+// FormatOrDefaultは、リクエストボディの形式を返します。
+func (c NotificationConfig) FormatOrDefault() NotificationFormat {
+	if c.Format == "" {
+		return NotificationJSON
+	}
+	return c.Format
+}
+
+// Notifiesは、指定した契機で通知するかを返します。
+func (c NotificationConfig) Notifies(event NotificationEvent) bool {
+	return len(c.On) == 0 || slices.Contains(c.On, event)
+}
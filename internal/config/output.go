@@ -0,0 +1,83 @@
+package config
+
+import "time"
+
+// OutputFormatは、スクレイプ結果の出力形式です。
+type OutputFormat string
+
+const (
+	OutputCSV     OutputFormat = "csv"     // CSV
+	OutputJSONL   OutputFormat = "jsonl"   // 1行に1件のJSON（JSON Lines）
+	OutputParquet OutputFormat = "parquet" // Parquet
+	OutputWebhook OutputFormat = "webhook" // HTTPのエンドポイントへの送信
+)
+
+// defaultOutputBatchSizeは、batch_sizeが未指定の場合に1回のリクエストで送信する件数です。
+const defaultOutputBatchSize = 100
+
+// defaultOutputTimeoutSecondsは、timeout_secondsが未指定の場合のリクエストのタイムアウト（秒）です。
+const defaultOutputTimeoutSeconds = 30
+
+// OutputConfigは、スクレイプ結果の出力先を1つ定義します。
+type OutputConfig struct {
+	Format         OutputFormat       `yaml:"format" validate:"required,oneof=csv jsonl parquet webhook"` // 出力形式
+	FileName       string             `yaml:"file_name" validate:"required_unless=Format webhook"`        // output_dir内に出力するファイルの名前（webhookの場合は不要）
+	URL            string             `yaml:"url" validate:"required_if=Format webhook,omitempty,url"`    // 送信先のURL（webhookの場合のみ）
+	BatchSize      int                `yaml:"batch_size" validate:"min=0"`                                // 1回のリクエストで送信する件数（webhookの場合のみ、0の場合は100件）
+	TimeoutSeconds int                `yaml:"timeout_seconds" validate:"min=0"`                           // 1回のリクエストのタイムアウト（webhookの場合のみ、0の場合は30秒）
+	Retry          *OutputRetryConfig `yaml:"retry"`                                                      // 書き込みに失敗した場合の再試行と退避の設定（未指定の場合は再試行しない）
+	Transforms     []ColumnTransform  `yaml:"transforms" validate:"omitempty,dive"`                       // この出力先に書き込む前に列ごとに適用する変換（匿名化など）
+}
+
+// ColumnTransformTypeは、出力する列に適用する変換の種類です。
+type ColumnTransformType string
+
+const (
+	TransformHash   ColumnTransformType = "hash"   // 値をハッシュ値に置き換える
+	TransformBucket ColumnTransformType = "bucket" // 整数の値を範囲に丸める
+	TransformDrop   ColumnTransformType = "drop"   // 列を出力しない
+)
+
+// ColumnTransformは、出力する1列に適用する変換を定義します。
+type ColumnTransform struct {
+	Column     string              `yaml:"column" validate:"required"`                      // 変換する列のキー（JSON Schemaのプロパティ名）
+	Type       ColumnTransformType `yaml:"type" validate:"required,oneof=hash bucket drop"` // 変換の種類
+	Salt       string              `yaml:"salt"`                                            // hashの場合にハッシュ値の計算に使用する秘密の値（環境変数の参照で指定することを推奨）
+	BucketSize uint64              `yaml:"bucket_size" validate:"required_if=Type bucket"`  // bucketの場合に丸める範囲の幅（例: 1000000）
+}
+
+// OutputRetryConfigは、出力先への書き込みに失敗した場合の再試行と、再試行しても失敗した求人情報の退避を定義します。
+type OutputRetryConfig struct {
+	MaxAttempts   int    `yaml:"max_attempts" validate:"min=1"` // 書き込みを試行する最大回数（初回を含む）
+	BackoffMillis int    `yaml:"backoff_ms" validate:"min=0"`   // 最初の再試行までの待機時間（ミリ秒、再試行のたびに2倍）
+	SpillFile     string `yaml:"spill_file"`                    // 再試行しても失敗した求人情報を退避するファイルのパス（未指定の場合は退避せずに失敗する）
+}
+
+// BatchSizeOrDefaultは、1回のリクエストで送信する件数を返します。
+func (c OutputConfig) BatchSizeOrDefault() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return defaultOutputBatchSize
+}
+
+// Timeoutは、1回のリクエストのタイムアウトを返します。
+func (c OutputConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds > 0 {
+		return time.Duration(c.TimeoutSeconds) * time.Second
+	}
+	return defaultOutputTimeoutSeconds * time.Second
+}
+
+// OutputTargetsは、スクレイプ結果の出力先の一覧を返します。
+// outputsが未指定の場合は、file_nameへのCSV出力のみを返します。
+//
+// return:
+//
+//	[]OutputConfig : 出力先の一覧
+func (c ScraperConfig) OutputTargets() []OutputConfig {
+	if len(c.Outputs) > 0 {
+		return c.Outputs
+	}
+	return []OutputConfig{{Format: OutputCSV, FileName: c.FileName}}
+}
@@ -0,0 +1,9 @@
+package config
+
+// OutputPartitionは、スクレイプ結果の出力ファイルを分割する単位です。
+type OutputPartition string
+
+const (
+	PartitionPrefecture  OutputPartition = "prefecture"   // 勤務地の都道府県ごとに分割する
+	PartitionPostedMonth OutputPartition = "posted_month" // 投稿月ごとに分割する
+)
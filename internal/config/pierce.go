@@ -0,0 +1,13 @@
+package config
+
+// PierceConfigは、Webコンポーネントやiframeの中に描画される要素を、要素の抽出と保存するHTMLに含めるための設定を定義します。
+// 求人の一覧や詳細を外部のウィジェットとしてiframeやshadow DOMの中に描画するサイトで使用します。
+type PierceConfig struct {
+	ShadowDOM bool `yaml:"shadow_dom"` // 保存するHTMLにopenなshadow rootの内容を含める（要素の抽出はPlaywrightのCSSセレクターが常にshadow rootの中も対象にする）
+	Iframes   bool `yaml:"iframes"`    // 同一オリジンのiframeの中の要素も抽出の対象にし、保存するHTMLにiframeの内容を含める
+}
+
+// Enabledは、shadow DOMまたはiframeの内容を含める設定が有効かどうかを返します。
+func (c PierceConfig) Enabled() bool {
+	return c.ShadowDOM || c.Iframes
+}
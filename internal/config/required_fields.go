@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ScrapeFieldNamesは、required_fieldsに指定できる項目名です。設定ファイルのセレクターの名前と同じです。
+var ScrapeFieldNames = []string{
+	"title",
+	"company_name",
+	"summary_url",
+	"location",
+	"headquarters",
+	"job_type",
+	"salary",
+	"posted_at",
+	"expires_at",
+	"job_name",
+	"description",
+	"requirements",
+	"workplace_type",
+	"holidays_per_year",
+	"holiday_policy",
+	"work_hours",
+	"raise",
+	"bonus",
+	"access",
+	"contract_period",
+	"probation",
+	"smoking_policy",
+	"benefits",
+}
+
+// requiredFieldsIssuesは、required_fieldsに指定できない項目名がないかを確認します。
+func requiredFieldsIssues(fields []string) []ConfigIssue {
+	var issues []ConfigIssue
+	for i, field := range fields {
+		if !slices.Contains(ScrapeFieldNames, field) {
+			issues = append(issues, ConfigIssue{
+				fmt.Sprintf("required_fields[%d]", i),
+				fmt.Sprintf("%sは指定できません。次のいずれかを指定してください: %s", field, strings.Join(ScrapeFieldNames, ", ")),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacrosは、cron式の代わりに指定できる別名と、対応するcron式です。
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames   = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	weekdayNames = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+// cronSearchYearsは、次の実行日時を探す期間（年）です。2月29日のみを指定した場合でも見つかるよう、閏年の周期より長くします。
+const cronSearchYears = 8
+
+// CronScheduleは、5つのフィールド（分 時 日 月 曜日）からなるcron式を解釈した実行スケジュールです。
+// 各フィールドは、取り得る値をビットで表した集合として保持します。
+//
+// フィールド:
+//
+//	minutes    : 実行する分（0〜59）
+//	hours      : 実行する時（0〜23）
+//	days       : 実行する日（1〜31）
+//	months     : 実行する月（1〜12）
+//	weekdays   : 実行する曜日（0〜6、0は日曜日）
+//	anyDay     : 日のフィールドが * の場合はtrue
+//	anyWeekday : 曜日のフィールドが * の場合はtrue
+type CronSchedule struct {
+	minutes    uint64
+	hours      uint64
+	days       uint64
+	months     uint64
+	weekdays   uint64
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseCronScheduleは、cron式を解釈します。
+// 各フィールドには、*・値・範囲（1-5）・列挙（1,3,5）・間隔（*/15、1-30/5）を指定できます。
+// 月と曜日には英語の略称（JAN、MONなど）を、曜日には日曜日として7も指定できます。
+// @daily・@hourlyなどの別名も指定できます。
+//
+// args:
+//
+//	expr : cron式（例: "0 3 * * *"）
+//
+// return:
+//
+//	CronSchedule : 解釈した実行スケジュール
+//	error        : cron式として解釈できない場合のエラー
+func ParseCronSchedule(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return CronSchedule{}, fmt.Errorf("cron式は「分 時 日 月 曜日」の5つのフィールドで指定してください: %q", expr)
+	}
+
+	var s CronSchedule
+	var err error
+	if s.minutes, err = parseCronField(fields[0], "分", 0, 59, nil); err != nil {
+		return CronSchedule{}, err
+	}
+	if s.hours, err = parseCronField(fields[1], "時", 0, 23, nil); err != nil {
+		return CronSchedule{}, err
+	}
+	if s.days, err = parseCronField(fields[2], "日", 1, 31, nil); err != nil {
+		return CronSchedule{}, err
+	}
+	if s.months, err = parseCronField(fields[3], "月", 1, 12, monthNames); err != nil {
+		return CronSchedule{}, err
+	}
+	if s.weekdays, err = parseCronField(fields[4], "曜日", 0, 7, weekdayNames); err != nil {
+		return CronSchedule{}, err
+	}
+	// 7は日曜日として扱う
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays = s.weekdays&^(1<<7) | 1
+	}
+	s.anyDay = fields[2] == "*" || fields[2] == "?"
+	s.anyWeekday = fields[4] == "*" || fields[4] == "?"
+	if s.Next(time.Now()).IsZero() {
+		return CronSchedule{}, fmt.Errorf("cron式 %q に一致する日時がありません", expr)
+	}
+	return s, nil
+}
+
+// parseCronFieldは、cron式の1つのフィールドを、取り得る値をビットで表した集合に変換します。
+//
+// args:
+//
+//	field : フィールドの文字列
+//	name  : エラーに表示するフィールドの名前
+//	lo    : フィールドの最小値
+//	hi    : フィールドの最大値
+//	names : 値の代わりに指定できる略称（大文字）と値の対応（略称がない場合はnil）
+//
+// return:
+//
+//	uint64 : 取り得る値の集合
+//	error  : フィールドを解釈できない場合のエラー
+func parseCronField(field, name string, lo, hi int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%sのフィールドの間隔 %q が不正です", name, stepPart)
+			}
+			step = n
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			start, end = lo, hi
+		case strings.Contains(rangePart, "-"):
+			first, last, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(first, name, lo, hi, names); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(last, name, lo, hi, names); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("%sのフィールドの範囲 %q の開始が終了より大きくなっています", name, rangePart)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangePart, name, lo, hi, names); err != nil {
+				return 0, err
+			}
+			end = start
+			// 「5/10」のように開始値に間隔を指定した場合は、最大値までを範囲とする
+			if hasStep {
+				end = hi
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValueは、cron式のフィールドの1つの値（数値または略称）を変換します。
+func parseCronValue(value, name string, lo, hi int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%sのフィールドの値 %q を解釈できません", name, value)
+	}
+	if n < lo || n > hi {
+		return 0, fmt.Errorf("%sのフィールドの値 %d は %d〜%d の範囲で指定してください", name, n, lo, hi)
+	}
+	return n, nil
+}
+
+// Nextは、afterより後で、スケジュールに一致する最初の日時（分単位）を返します。
+// 日時はafterのタイムゾーンで評価します。一致する日時が見つからない場合（2月30日のみを指定した場合など）はゼロ値を返します。
+//
+// args:
+//
+//	after : 基準の日時
+//
+// return:
+//
+//	time.Time : 次の実行日時
+func (s CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDayは、日時の日付がスケジュールの日・月・曜日に一致するかを判定します。
+// 日と曜日の両方が指定されている場合は、一般的なcronと同様にいずれかに一致すれば実行します。
+func (s CronSchedule) matchDay(t time.Time) bool {
+	if s.months&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dayMatch := s.days&(1<<t.Day()) != 0
+	weekdayMatch := s.weekdays&(1<<int(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return dayMatch && weekdayMatch
+	}
+	return dayMatch || weekdayMatch
+}
+
+// scheduleIssuesは、実行スケジュールのcron式を解釈できるかを確認します。
+func scheduleIssues(schedule string) []ConfigIssue {
+	if schedule == "" {
+		return nil
+	}
+	if _, err := ParseCronSchedule(schedule); err != nil {
+		return []ConfigIssue{{"schedule", err.Error()}}
+	}
+	return nil
+}
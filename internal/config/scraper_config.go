@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"runtime"
+	"sort"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/goccy/go-yaml"
@@ -17,50 +19,117 @@ type SelectorConfig struct {
 
 // SalaryConfigは給与情報のセレクターと正規表現を定義します。
 type SalaryConfig struct {
-	Selector string `yaml:"selector" validate:"required,min=1"`
+	Selector      string     `yaml:"selector" validate:"required,min=1"`
+	PreferredUnit SalaryUnit `yaml:"preferred_unit" validate:"omitempty,oneof=hourly daily monthly yearly"` // 月給と年収などが併記されている場合に優先する単位（省略時は先に記載された単位）
 }
 
+// SalaryUnitは、給与の単位です。
+type SalaryUnit string
+
+const (
+	SalaryUnitHourly  SalaryUnit = "hourly"  // 時給
+	SalaryUnitDaily   SalaryUnit = "daily"   // 日給
+	SalaryUnitMonthly SalaryUnit = "monthly" // 月給
+	SalaryUnitYearly  SalaryUnit = "yearly"  // 年給（年収・年俸）
+)
+
 // DetailsConfigは求人詳細情報のセレクターを定義します。
 type DetailsConfig struct {
-	JobName         SelectorConfig `yaml:"job_name" validate:"required"`
-	Raise           SelectorConfig `yaml:"raise" validate:"required"`
-	Bonus           SelectorConfig `yaml:"bonus" validate:"required"`
-	Description     SelectorConfig `yaml:"description" validate:"required"`
-	Requirements    SelectorConfig `yaml:"requirements" validate:"required"`
-	WorkplaceType   SelectorConfig `yaml:"workplace_type" validate:"required"`
-	HolidaysPerYear SelectorConfig `yaml:"holidays_per_year" validate:"required"`
-	HolidayPolicy   SelectorConfig `yaml:"holiday_policy" validate:"required"`
-	WorkHours       SelectorConfig `yaml:"work_hours" validate:"required"`
-	Benefits        SelectorConfig `yaml:"benefits" validate:"required"`
+	JobName         SelectorConfig  `yaml:"job_name" validate:"required"`
+	Raise           SelectorConfig  `yaml:"raise" validate:"required"`
+	Bonus           SelectorConfig  `yaml:"bonus" validate:"required"`
+	Description     SelectorConfig  `yaml:"description" validate:"required"`
+	Requirements    SelectorConfig  `yaml:"requirements" validate:"required"`
+	WorkplaceType   SelectorConfig  `yaml:"workplace_type" validate:"required"`
+	HolidaysPerYear SelectorConfig  `yaml:"holidays_per_year" validate:"required"`
+	HolidayPolicy   SelectorConfig  `yaml:"holiday_policy" validate:"required"`
+	WorkHours       SelectorConfig  `yaml:"work_hours" validate:"required"`
+	Benefits        SelectorConfig  `yaml:"benefits" validate:"required"`
+	Access          *SelectorConfig `yaml:"access" validate:"omitempty"`          // 最寄り駅などのアクセス情報（任意）
+	ContractPeriod  *SelectorConfig `yaml:"contract_period" validate:"omitempty"` // 契約期間（任意）
+	Probation       *SelectorConfig `yaml:"probation" validate:"omitempty"`       // 試用期間（任意）
+	SmokingPolicy   *SelectorConfig `yaml:"smoking_policy" validate:"omitempty"`  // 受動喫煙対策（任意）
+}
+
+// ExtraFieldSelectorsは、ドメインモデルにない項目を追加で抽出するための、項目名とセレクターの対応です。
+type ExtraFieldSelectors map[string]SelectorConfig
+
+// Namesは、追加の項目名を辞書順に返します。出力する列はこの順に並べます。
+//
+// return:
+//
+//	[]string : 追加の項目名
+func (e ExtraFieldSelectors) Names() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // ScraperConfigはスクレイパーの動作設定をまとめる構造体です。
 type ScraperConfig struct {
-	BaseURL      string         `yaml:"base_url" validate:"required,url,min=1"`
-	HtmlDir      string         `yaml:"html_dir" validate:"required,min=1"`
-	OutputDir    string         `yaml:"output_dir" validate:"required,min=1"`
-	MaxWorkers   int            `yaml:"max_workers" validate:"required,gt=0,max=10"`
-	FileName     string         `yaml:"file_name" validate:"required,min=1,max=20"`
-	Title        SelectorConfig `yaml:"title" validate:"required"`
-	CompanyName  SelectorConfig `yaml:"company_name" validate:"required"`
-	SummaryURL   SelectorConfig `yaml:"summary_url" validate:"required"`
-	Location     SelectorConfig `yaml:"location" validate:"required"`
-	Headquarters SelectorConfig `yaml:"headquarters" validate:"required"`
-	JobType      SelectorConfig `yaml:"job_type" validate:"required"`
-	Salary       SalaryConfig   `yaml:"salary" validate:"required"`
-	PostedAt     SelectorConfig `yaml:"posted_at" validate:"required"`
-	Details      DetailsConfig  `yaml:"details" validate:"required"`
+	BaseURL         string               `yaml:"base_url" validate:"required,url,min=1"`
+	HtmlDir         string               `yaml:"html_dir" validate:"required,min=1"`                    // HTMLを読み込むディレクトリ（redis・s3・gcsの場合はキーの名前空間）
+	Storage         StorageType          `yaml:"storage" validate:"omitempty,oneof=local redis s3 gcs"` // HTMLの読み込み元（省略時はlocal）
+	ObjectStorage   *ObjectStorageConfig `yaml:"object_storage"`                                        // storageがs3・gcsの場合の読み込み元のバケット
+	OutputDir       string               `yaml:"output_dir" validate:"required,min=1"`
+	MaxWorkers      int                  `yaml:"max_workers" validate:"min=0,max=10"`
+	FileName        string               `yaml:"file_name" validate:"required_without=Outputs,max=20"`
+	Outputs         []OutputConfig       `yaml:"outputs" validate:"omitempty,dive"`                                   // 複数の出力先（指定した場合はfile_nameより優先）
+	KeepPartial     bool                 `yaml:"keep_partial"`                                                        // 失敗時に書き込み途中の出力を.partialとして残すかどうか
+	MaxHTMLBytes    int                  `yaml:"max_html_bytes" validate:"omitempty,gt=0"`                            // 読み込むHTMLの最大バイト数（省略時は無制限。指定した場合はscriptとstyleの内容を除く。JSON-LDのscriptは残す）
+	OutputPartition OutputPartition      `yaml:"output_partition" validate:"omitempty,oneof=prefecture posted_month"` // 出力ファイルを分割する単位（省略時は分割しない）
+	ParserCacheSize int                  `yaml:"parser_cache_size" validate:"min=0"`                                  // 解析結果をキャッシュする件数の上限（0はキャッシュしない）
+	Extraction      ExtractionMode       `yaml:"extraction" validate:"omitempty,oneof=selector json_ld"`              // 求人情報を抽出する方法（省略時はselector）
+	MetaFallback    bool                 `yaml:"meta_fallback"`                                                       // セレクターで値が得られなかった項目をOpenGraphなどのメタタグで補うかどうか
+	StatsReport     string               `yaml:"stats_report"`                                                        // 項目ごとの充足率をJSONで書き込むoutput_dir内のファイル名（省略時は書き込まない）
+	RequiredFields  []string             `yaml:"required_fields"`                                                     // 値が得られなかった場合に求人情報を出力しない項目
+	Dedup           bool                 `yaml:"dedup"`                                                               // 会社名・タイトル・勤務地が同じ求人情報を1回の実行で1件だけ出力するかどうか
+	Columns         []string             `yaml:"columns"`                                                             // 出力する列のキーと順序（省略時はすべての列を既定の順序で出力する）
+	ExtraFields     ExtraFieldSelectors  `yaml:"extra_fields" validate:"omitempty,dive"`                              // 追加で抽出し、値をそのまま出力する項目（項目名 → セレクター）
+	Dictionary      string               `yaml:"dictionary"`                                                          // 福利厚生や雇用形態などをキーワードで判定する辞書ファイルのパス（省略時は既定の辞書）
+	Categories      string               `yaml:"categories"`                                                          // 職種カテゴリーの分類体系のファイルのパス（省略時は判定しない）
+	IncomeBands     []IncomeBand         `yaml:"income_bands" validate:"omitempty,dive"`                              // 年収に換算した給与を分類する年収帯（省略時は分類しない）
+	Municipalities  string               `yaml:"municipality_codes"`                                                  // 全国地方公共団体コードの一覧のCSVのパス（省略時は特別区と政令指定都市のみの既定の対応表）
+	CorporateNumber *CorpNumberConfig    `yaml:"corporate_number"`                                                    // 会社名から法人番号を取得する設定（省略時は取得しない）
+	Title           SelectorConfig       `yaml:"title" validate:"required"`
+	CompanyName     SelectorConfig       `yaml:"company_name" validate:"required"`
+	SummaryURL      SelectorConfig       `yaml:"summary_url" validate:"required"`
+	Location        SelectorConfig       `yaml:"location" validate:"required"`
+	Headquarters    SelectorConfig       `yaml:"headquarters" validate:"required"`
+	JobType         SelectorConfig       `yaml:"job_type" validate:"required"`
+	Salary          SalaryConfig         `yaml:"salary" validate:"required"`
+	PostedAt        SelectorConfig       `yaml:"posted_at" validate:"required"`
+	ExpiresAt       *SelectorConfig      `yaml:"expires_at" validate:"omitempty"` // 応募締切日・掲載終了日（任意）
+	Details         DetailsConfig        `yaml:"details" validate:"required"`
+	Hooks           []HookConfig         `yaml:"hooks" validate:"omitempty,dive"`         // 求人情報の抽出・出力時に呼び出すフック
+	Expired         ExpiryConfig         `yaml:"expired"`                                 // 掲載が終了した求人ページを判定する条件と、判定した求人の扱い
+	Notifications   []NotificationConfig `yaml:"notifications" validate:"omitempty,dive"` // 実行の終了時に実行結果を通知するWebhook
+}
+
+// MaxWorkersOrDefaultは、スクレイピングの並行ワーカー数を返します。max_workersが0の場合はCPUのコア数です。
+func (c ScraperConfig) MaxWorkersOrDefault() int {
+	if c.MaxWorkers > 0 {
+		return c.MaxWorkers
+	}
+	return runtime.NumCPU()
 }
 
 // バリデーターのインスタンス
 var validate = validator.New()
 
-// YAMLファイルからScraperConfigを読み込む
+// YAMLファイルからScraperConfigを読み込む（${VAR}形式の環境変数の参照は展開する）
 func LoadScraperConfig(path string) (ScraperConfig, error) {
 	f, err := os.ReadFile(path)
 	if err != nil {
 		return ScraperConfig{}, fmt.Errorf("設定ファイルを読み込めませんでした: %w", err)
 	}
+	f, err = expandEnv(f)
+	if err != nil {
+		return ScraperConfig{}, err
+	}
 
 	var cfg ScraperConfig
 	if err := yaml.Unmarshal(f, &cfg); err != nil {
@@ -72,5 +141,16 @@ func LoadScraperConfig(path string) (ScraperConfig, error) {
 		return ScraperConfig{}, fmt.Errorf("設定のバリデーションに失敗しました: %w", err)
 	}
 
+	// カスタムバリデーション
+	if cfg.Storage.IsObjectStorage() && cfg.ObjectStorage == nil {
+		return ScraperConfig{}, fmt.Errorf("storageが%sの場合はobject_storageが必要です", cfg.Storage)
+	}
+	if issues := requiredFieldsIssues(cfg.RequiredFields); len(issues) > 0 {
+		return ScraperConfig{}, fmt.Errorf("required_fieldsの設定が不正です: %s", issues[0].Message)
+	}
+	if issues := incomeBandsIssues(cfg.IncomeBands); len(issues) > 0 {
+		return ScraperConfig{}, fmt.Errorf("income_bandsの設定が不正です: %s", issues[0])
+	}
+
 	return cfg, nil
 }
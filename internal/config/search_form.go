@@ -0,0 +1,55 @@
+package config
+
+import "time"
+
+// defaultSearchFormWaitは、検索フォームを送信した後に一覧の表示を待つ時間の既定値です。
+const defaultSearchFormWait = time.Second
+
+// FormFieldTypeは、検索フォームの入力項目に値を入力する方法です。
+type FormFieldType string
+
+const (
+	FormFieldFill   FormFieldType = "fill"   // テキストを入力する
+	FormFieldSelect FormFieldType = "select" // プルダウンの選択肢を選ぶ
+	FormFieldCheck  FormFieldType = "check"  // チェックボックスやラジオボタンにチェックを入れる
+)
+
+// SearchFormConfigは、検索条件を入力して送信しないと求人の一覧が表示されないサイトで、
+// 一覧ページの詳細ページのリンクを抽出する前に入力・送信する検索フォームを定義します。
+type SearchFormConfig struct {
+	Fields         []FormField `yaml:"fields" validate:"required,min=1,dive"` // 入力する項目（記載した順に入力する）
+	SubmitSelector string      `yaml:"submit_selector"`                       // 送信ボタンのセレクター（省略時は最後の項目でEnterキーを押す）
+	WaitMillis     int         `yaml:"wait_ms" validate:"min=0"`              // 送信した後に一覧の表示を待つ時間（ミリ秒、0の場合は1000）
+}
+
+// FormFieldは、検索フォームの1件の入力項目を定義します。
+type FormField struct {
+	Selector string        `yaml:"selector" validate:"required"`                      // 入力する要素のセレクター
+	Type     FormFieldType `yaml:"type" validate:"omitempty,oneof=fill select check"` // 入力の種類（省略時はfill）
+	Value    string        `yaml:"value"`                                             // 入力する値（selectの場合は選択肢のvalueまたは表示名、checkの場合は"false"でチェックを外す）
+}
+
+// Waitは、検索フォームを送信した後に一覧の表示を待つ時間を返します。
+func (c SearchFormConfig) Wait() time.Duration {
+	if c.WaitMillis <= 0 {
+		return defaultSearchFormWait
+	}
+	return time.Duration(c.WaitMillis) * time.Millisecond
+}
+
+// TypeOrDefaultは、入力の種類を返します。未指定の場合はfillを返します。
+func (f FormField) TypeOrDefault() FormFieldType {
+	if f.Type == "" {
+		return FormFieldFill
+	}
+	return f.Type
+}
+
+// searchFormIssuesは、検索フォームを送信して表示した一覧をクロールできる戦略かを確認します。
+// total_count戦略は2ページ目以降のURLを組み立てて遷移するため、送信した検索条件が引き継がれず、検索フォームとは併用できません。
+func searchFormIssues(cfg CrawlerConfig) []ConfigIssue {
+	if cfg.SearchForm == nil || cfg.Strategy != CrawlByTotalCount {
+		return nil
+	}
+	return []ConfigIssue{{"search_form", "total_count戦略は2ページ目以降のURLに遷移するため、送信した検索条件が引き継がれません。next_linkまたはinfinite_scroll戦略を使用してください"}}
+}
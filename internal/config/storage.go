@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StorageTypeは、クロールしたHTMLの保存先の種類です。
+type StorageType string
+
+const (
+	StorageLocal StorageType = "local" // ローカルのディレクトリに保存する
+	StorageRedis StorageType = "redis" // Redisに保存する（クロールとスクレイプを別のマシンで実行する場合）
+	StorageS3    StorageType = "s3"    // Amazon S3（またはS3互換のオブジェクトストレージ）に保存する
+	StorageGCS   StorageType = "gcs"   // Google Cloud Storageに保存する
+)
+
+// IsObjectStorageは、保存先がオブジェクトストレージ（s3またはgcs）かどうかを返します。
+func (s StorageType) IsObjectStorage() bool {
+	return s == StorageS3 || s == StorageGCS
+}
+
+// defaultS3Regionは、regionが未指定の場合に使用するS3のリージョンです。
+const defaultS3Region = "us-east-1"
+
+// ObjectStorageConfigは、HTMLを保存するオブジェクトストレージのバケットとアップロードの方法を定義します。
+// storageがs3またはgcsの場合に使用します。
+type ObjectStorageConfig struct {
+	Bucket   string                    `yaml:"bucket" validate:"required"`        // バケット名
+	Prefix   string                    `yaml:"prefix"`                            // オブジェクトのキーの先頭に付ける文字列（例: crawl/site-a）
+	Region   string                    `yaml:"region"`                            // S3のリージョン（省略時はus-east-1）
+	Endpoint string                    `yaml:"endpoint" validate:"omitempty,url"` // S3互換のストレージ（MinIOなど）を使用する場合のエンドポイント
+	Compress bool                      `yaml:"compress"`                          // HTMLとメタデータをgzipで圧縮してアップロードするかどうか
+	Retry    *ObjectStorageRetryConfig `yaml:"retry"`                             // アップロードに失敗した場合の再試行の設定（未指定の場合はデフォルトの回数だけ再試行する）
+}
+
+// ObjectStorageRetryConfigは、オブジェクトストレージへのアップロードに失敗した場合の再試行を定義します。
+// 認証エラーなど、再試行しても成功しない失敗は再試行しません。
+type ObjectStorageRetryConfig struct {
+	MaxAttempts   int `yaml:"max_attempts" validate:"min=1"` // アップロードを試行する最大回数（初回を含む）
+	BackoffMillis int `yaml:"backoff_ms" validate:"min=0"`   // 最初の再試行までの待機時間（ミリ秒、再試行のたびに2倍）
+}
+
+const (
+	defaultUploadAttempts = 3                      // retryが未指定の場合にアップロードを試行する最大回数
+	defaultUploadBackoff  = 500 * time.Millisecond // retryが未指定の場合の最初の再試行までの待機時間
+)
+
+// UploadAttemptsは、アップロードを試行する最大回数（初回を含む）を返します。
+func (c ObjectStorageConfig) UploadAttempts() int {
+	if c.Retry == nil {
+		return defaultUploadAttempts
+	}
+	return c.Retry.MaxAttempts
+}
+
+// UploadBackoffは、アップロードの最初の再試行までの待機時間を返します。
+func (c ObjectStorageConfig) UploadBackoff() time.Duration {
+	if c.Retry == nil {
+		return defaultUploadBackoff
+	}
+	return time.Duration(c.Retry.BackoffMillis) * time.Millisecond
+}
+
+// RegionOrDefaultは、リクエストの署名に使用するリージョンを返します。gcsの場合は常にautoです。
+func (c ObjectStorageConfig) RegionOrDefault(storage StorageType) string {
+	switch {
+	case storage == StorageGCS:
+		return "auto"
+	case c.Region != "":
+		return c.Region
+	default:
+		return defaultS3Region
+	}
+}
+
+// listPageDirIssuesは、一覧ページのHTMLの保存先が、詳細ページのHTMLの保存先と重ならないかを確認します。
+// スクレイパーはoutput_dir以下のHTMLをすべて求人として読み込むため、一覧ページは別のディレクトリに保存する必要があります。
+func listPageDirIssues(cfg CrawlerConfig) []ConfigIssue {
+	if cfg.ListPageDir == "" {
+		return nil
+	}
+	listDir, outputDir := filepath.Clean(cfg.ListPageDir), filepath.Clean(cfg.OutputDir)
+	if listDir == outputDir || strings.HasPrefix(listDir, outputDir+string(filepath.Separator)) {
+		return []ConfigIssue{{"list_page_dir", fmt.Sprintf("output_dir（%s）と同じか、その中のディレクトリは指定できません。スクレイパーが一覧ページを求人として読み込まないよう、別のディレクトリを指定してください", cfg.OutputDir)}}
+	}
+	return nil
+}
+
+// objectStorageIssuesは、保存先がオブジェクトストレージの場合に、バケットの設定があるかを確認します。
+func objectStorageIssues(storage StorageType, cfg *ObjectStorageConfig) []ConfigIssue {
+	if storage.IsObjectStorage() && cfg == nil {
+		return []ConfigIssue{{"object_storage", fmt.Sprintf("storageが%sの場合は必須です。保存先のバケットをbucketに指定してください", storage)}}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultThrottleWindow         = 20
+	defaultThrottleFailureRate    = 0.5
+	defaultThrottleInitialDelayMS = 5000
+	defaultThrottleMaxDelayMS     = 300000
+	defaultThrottleDecayAfter     = 10
+)
+
+// defaultThrottleStatusesは、statusesが未指定の場合に受け取った時点で待機時間を広げるステータスコードです。
+var defaultThrottleStatuses = []int{http.StatusTooManyRequests, http.StatusForbidden}
+
+// ThrottleConfigは、エラーが増えた場合にジョブの間の待機時間を自動的に広げる設定を定義します。
+// 直近のジョブの失敗率がしきい値以上になるか、statusesのステータスコードを受け取ると待機時間を2倍にし（最初はinitial_delay_ms）、
+// 成功が続くと半分に戻します。長時間の無人の実行で、アクセス元がブロックされることを防ぎます。
+type ThrottleConfig struct {
+	Window         int     `yaml:"window" validate:"min=0"`                            // 失敗率を計算する直近のジョブ数（0の場合は20）
+	FailureRate    float64 `yaml:"failure_rate" validate:"min=0,max=1"`                // 待機時間を広げる失敗率（0の場合は0.5）
+	Statuses       []int   `yaml:"statuses" validate:"omitempty,dive,min=100,max=599"` // 受け取った時点で待機時間を広げるステータスコード（省略時は429・403）
+	InitialDelayMS int     `yaml:"initial_delay_ms" validate:"min=0"`                  // 最初に広げる待機時間（ミリ秒、0の場合は5000）
+	MaxDelayMS     int     `yaml:"max_delay_ms" validate:"min=0"`                      // 待機時間の上限（ミリ秒、0の場合は300000）
+	DecayAfter     int     `yaml:"decay_after" validate:"min=0"`                       // 待機時間を半分に戻すまでに連続して成功するジョブ数（0の場合は10）
+}
+
+// WindowOrDefaultは、失敗率を計算する直近のジョブ数を返します。
+func (c ThrottleConfig) WindowOrDefault() int {
+	if c.Window > 0 {
+		return c.Window
+	}
+	return defaultThrottleWindow
+}
+
+// FailureRateOrDefaultは、待機時間を広げる失敗率を返します。
+func (c ThrottleConfig) FailureRateOrDefault() float64 {
+	if c.FailureRate > 0 {
+		return c.FailureRate
+	}
+	return defaultThrottleFailureRate
+}
+
+// StatusesOrDefaultは、受け取った時点で待機時間を広げるステータスコードを返します。
+func (c ThrottleConfig) StatusesOrDefault() []int {
+	if len(c.Statuses) > 0 {
+		return c.Statuses
+	}
+	return defaultThrottleStatuses
+}
+
+// InitialDelayは、最初に広げる待機時間を返します。
+func (c ThrottleConfig) InitialDelay() time.Duration {
+	if c.InitialDelayMS > 0 {
+		return time.Duration(c.InitialDelayMS) * time.Millisecond
+	}
+	return defaultThrottleInitialDelayMS * time.Millisecond
+}
+
+// MaxDelayは、待機時間の上限を返します。上限が最初に広げる待機時間より短い場合は、最初に広げる待機時間を返します。
+func (c ThrottleConfig) MaxDelay() time.Duration {
+	maxDelay := time.Duration(defaultThrottleMaxDelayMS) * time.Millisecond
+	if c.MaxDelayMS > 0 {
+		maxDelay = time.Duration(c.MaxDelayMS) * time.Millisecond
+	}
+	return max(maxDelay, c.InitialDelay())
+}
+
+// DecayAfterOrDefaultは、待機時間を半分に戻すまでに連続して成功するジョブ数を返します。
+func (c ThrottleConfig) DecayAfterOrDefault() int {
+	if c.DecayAfter > 0 {
+		return c.DecayAfter
+	}
+	return defaultThrottleDecayAfter
+}
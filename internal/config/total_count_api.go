@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// TotalCountAPIConfigは、total_count戦略で総件数を一覧ページの要素ではなくJSONのAPIから取得する設定を定義します。
+// 総件数をXHRのレスポンスで返すサイトでは、画面の表示テキストを解析するよりも確実に件数を取得できます。
+type TotalCountAPIConfig struct {
+	URL      string `yaml:"url" validate:"required"`       // 総件数を返すAPIのURL（一覧ページのURLからの相対URLも可。{query}は一覧ページのクエリ文字列に置き換える）
+	JSONPath string `yaml:"json_path" validate:"required"` // レスポンスのJSONから総件数を取り出すパス（例: $.meta.total, $.results[0].count）
+}
+
+// jsonPathPatternは、サポートするJSONPathの構文（$から始まる .キー・['キー']・[添字] の連結）に一致する正規表現です。
+var jsonPathPattern = regexp.MustCompile(`^\$?((\.[^.\[\]]+)|(\['[^']*'\])|(\["[^"]*"\])|(\[[0-9]+\]))*$`)
+
+// totalCountAPIIssuesは、総件数を取得するAPIの設定を確認します。
+func totalCountAPIIssues(c *TotalCountAPIConfig) []ConfigIssue {
+	if c == nil || c.JSONPath == "" {
+		return nil
+	}
+	if !jsonPathPattern.MatchString(c.JSONPath) {
+		return []ConfigIssue{{"total_count_api.json_path", fmt.Sprintf("JSONPathとして解釈できません: %q。$.キー・['キー']・[添字] を連結して指定してください", c.JSONPath)}}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// URLMatrixConfigは、manualモードで一覧ページのURLをテンプレートとパラメーターの組み合わせから生成する設定を定義します。
+// 都道府県コード×職種のように、条件の組み合わせごとに一覧ページが分かれているサイトで、urlsに数百件のURLを列挙せずに済むようにします。
+type URLMatrixConfig struct {
+	Template string           `yaml:"template" validate:"required"`          // 一覧ページのURLのテンプレート（{パラメーター名}を値に置き換える。例: https://example.com/jobs?pref={pref}&cat={category}）
+	Params   []URLMatrixParam `yaml:"params" validate:"required,min=1,dive"` // テンプレートに埋め込むパラメーター（記載した順に、先のパラメーターほど外側で組み合わせる）
+}
+
+// URLMatrixParamは、URLのテンプレートに埋め込む1件のパラメーターを定義します。
+type URLMatrixParam struct {
+	Name   string   `yaml:"name" validate:"required"`         // テンプレート内の{パラメーター名}の名前
+	Values []string `yaml:"values" validate:"required,min=1"` // 埋め込む値（そのままURLに埋め込む）
+}
+
+// urlMatrixPlaceholderPatternは、URLのテンプレート内の{パラメーター名}に一致する正規表現です。
+var urlMatrixPlaceholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Expandは、パラメーターの値のすべての組み合わせをテンプレートに埋め込んだURLを返します。
+// 先に記載したパラメーターほど外側で組み合わせるため、最初のパラメーターの値ごとにURLがまとまります。
+func (m URLMatrixConfig) Expand() []string {
+	urls := []string{m.Template}
+	for _, param := range m.Params {
+		placeholder := "{" + param.Name + "}"
+		expanded := make([]string, 0, len(urls)*len(param.Values))
+		for _, u := range urls {
+			for _, value := range param.Values {
+				expanded = append(expanded, strings.ReplaceAll(u, placeholder, value))
+			}
+		}
+		urls = expanded
+	}
+	return urls
+}
+
+// ListURLsは、manualモードでクロールする一覧ページのURLを返します。
+// urlsに記載したURLの後に、url_matrixから生成したURLを続けます。
+func (c CrawlerConfig) ListURLs() []string {
+	if c.URLMatrix == nil {
+		return c.Urls
+	}
+	return append(slices.Clone(c.Urls), c.URLMatrix.Expand()...)
+}
+
+// urlMatrixIssuesは、テンプレートのパラメーターと定義したパラメーターが対応しているか、生成したURLがURLとして解釈できるかを確認します。
+func urlMatrixIssues(m *URLMatrixConfig) []ConfigIssue {
+	if m == nil || m.Template == "" {
+		return nil
+	}
+
+	var issues []ConfigIssue
+	names := make([]string, 0, len(m.Params))
+	for i, param := range m.Params {
+		if slices.Contains(names, param.Name) {
+			issues = append(issues, ConfigIssue{fmt.Sprintf("url_matrix.params[%d].name", i), fmt.Sprintf("%sが重複しています", param.Name)})
+		}
+		names = append(names, param.Name)
+		if param.Name != "" && !strings.Contains(m.Template, "{"+param.Name+"}") {
+			issues = append(issues, ConfigIssue{fmt.Sprintf("url_matrix.params[%d].name", i), fmt.Sprintf("templateに{%s}が含まれていません", param.Name)})
+		}
+	}
+	for _, match := range urlMatrixPlaceholderPattern.FindAllStringSubmatch(m.Template, -1) {
+		if !slices.Contains(names, match[1]) {
+			issues = append(issues, ConfigIssue{"url_matrix.template", fmt.Sprintf("{%s}に埋め込むパラメーターがparamsに定義されていません", match[1])})
+		}
+	}
+	if len(issues) > 0 {
+		return issues
+	}
+
+	for _, rawURL := range m.Expand() {
+		if err := issueValidator.Var(rawURL, "url"); err != nil {
+			issues = append(issues, ConfigIssue{"url_matrix", fmt.Sprintf("生成したURLがURLとして解釈できません: %q", rawURL)})
+			break
+		}
+	}
+	return issues
+}
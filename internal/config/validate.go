@@ -0,0 +1,336 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/go-playground/validator/v10"
+	"github.com/goccy/go-yaml"
+)
+
+// ConfigIssueは、設定ファイルの検証で見つかった1件の問題です。
+//
+// フィールド:
+//
+//	Field   : 問題のある項目（YAMLのキーをドットでつないだパス）
+//	Message : 問題の内容と対処方法
+type ConfigIssue struct {
+	Field   string
+	Message string
+}
+
+// Stringは、問題を「項目: 内容」の形式で返します。
+func (i ConfigIssue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// issueValidatorは、エラーの項目名をYAMLのキーで返すバリデーターです。
+var issueValidator = newIssueValidator()
+
+// playwrightSelectorPatternは、CSSセレクターとしては解釈できないPlaywright固有のロケーターの構文です。
+var playwrightSelectorPattern = regexp.MustCompile(`^(?:xpath|text|id|data-testid|role|internal:[\w-]+)=|^//|>>|:has-text\(|:text(?:-is|-matches)?\(|:visible|:nth-match\(|:(?:left-of|right-of|above|below|near)\(`)
+
+// newIssueValidatorは、構造体のフィールド名の代わりにyamlタグの名前を使用するバリデーターを生成します。
+func newIssueValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// ValidateCrawlerConfigFileは、クローラーの設定ファイルを読み込み、見つかったすべての問題を返します。
+// バリデーションに加え、セレクターの構文とページネーションの設定の整合性を確認します。
+//
+// args:
+//
+//	path : 設定ファイルのパス
+//
+// return:
+//
+//	[]ConfigIssue : 見つかった問題（問題がない場合は空）
+//	error         : ファイルの読み込みまたはYAMLの解析に失敗した場合のエラー
+func ValidateCrawlerConfigFile(path string) ([]ConfigIssue, error) {
+	var cfg CrawlerConfig
+	if err := unmarshalConfigFile(path, &cfg); err != nil {
+		return nil, err
+	}
+
+	issues := structIssues(cfg)
+
+	switch cfg.Strategy {
+	case CrawlByNextLink:
+		if cfg.Selector.NextPageLocator == "" {
+			issues = append(issues, ConfigIssue{"selector.next_page_locator", "next_link戦略では必須です。「次へ」のリンクのセレクターを指定してください"})
+		}
+		if cfg.Pagination.Type != "" && cfg.Pagination.Type != None {
+			issues = append(issues, ConfigIssue{"pagination.type", "next_link戦略ではページネーションの設定は使用されません。noneを指定してください"})
+		}
+	case CrawlByTotalCount:
+		if cfg.Selector.TotalCountSelector == "" && cfg.TotalCountAPI == nil {
+			issues = append(issues, ConfigIssue{"selector.total_count_selector", "total_count戦略では必須です。総件数が表示される要素のセレクター、または総件数を返すAPIのtotal_count_apiを指定してください"})
+		}
+		if cfg.Pagination.Type == None {
+			issues = append(issues, ConfigIssue{"pagination.type", "total_count戦略ではページのURLを組み立てるため、query・path・segmentのいずれかを指定してください"})
+		}
+	case CrawlByInfiniteScroll:
+		if cfg.Pagination.Type != "" && cfg.Pagination.Type != None {
+			issues = append(issues, ConfigIssue{"pagination.type", "infinite_scroll戦略ではページネーションの設定は使用されません。noneを指定してください"})
+		}
+	}
+
+	if cfg.Mode == Manual && len(cfg.ListURLs()) == 0 {
+		issues = append(issues, ConfigIssue{"urls", "manualモードでは必須です。クロール対象のURLを1件以上指定するか、url_matrixを指定してください"})
+	}
+	for i, rawURL := range cfg.Urls {
+		if err := issueValidator.Var(rawURL, "url"); err != nil {
+			issues = append(issues, ConfigIssue{fmt.Sprintf("urls[%d]", i), fmt.Sprintf("URLとして解釈できません: %q", rawURL)})
+		}
+	}
+
+	issues = append(issues, paginationIssues(cfg.Pagination)...)
+	issues = append(issues, expiryIssues("expired", cfg.Expired)...)
+	issues = append(issues, blockIssues("blocked", cfg.Blocked)...)
+	issues = append(issues, totalCountAPIIssues(cfg.TotalCountAPI)...)
+	issues = append(issues, urlMatrixIssues(cfg.URLMatrix)...)
+	issues = append(issues, scheduleIssues(cfg.Schedule)...)
+	issues = append(issues, objectStorageIssues(cfg.Storage, cfg.ObjectStorage)...)
+	issues = append(issues, listPageDirIssues(cfg)...)
+	issues = append(issues, browserEngineIssues(cfg)...)
+	issues = append(issues, exportPDFIssues(cfg)...)
+	issues = append(issues, searchFormIssues(cfg)...)
+
+	for i, rule := range cfg.Cookies {
+		if _, err := regexp.Compile(rule.URLPattern); err != nil {
+			issues = append(issues, ConfigIssue{fmt.Sprintf("cookies[%d].url_pattern", i), fmt.Sprintf("正規表現として解釈できません。Goの正規表現（RE2）の構文で記述してください: %v", err)})
+		}
+	}
+
+	if cfg.Selector.DetailLinksPattern != "" {
+		if re, err := regexp.Compile(cfg.Selector.DetailLinksPattern); err != nil {
+			issues = append(issues, ConfigIssue{"selector.detail_links_pattern", fmt.Sprintf("正規表現として解釈できません。Goの正規表現（RE2）の構文で記述してください: %v", err)})
+		} else if re.NumSubexp() == 0 {
+			issues = append(issues, ConfigIssue{"selector.detail_links_pattern", "リンクを取り出すキャプチャグループ（括弧）を1つ以上含めてください"})
+		}
+	}
+
+	type selectorField struct {
+		field    string
+		selector string
+	}
+	selectors := []selectorField{
+		{"selector.list_links_selector", cfg.Selector.ListLinksSelector},
+		{"selector.next_page_locator", cfg.Selector.NextPageLocator},
+		{"selector.total_count_selector", cfg.Selector.TotalCountSelector},
+		{"selector.tab_click_selector", cfg.Selector.TabClickSelector},
+		{"selector.detail_links_selector", cfg.Selector.DetailLinksSelector},
+	}
+	for i, action := range cfg.Actions {
+		if action.Type == ActionClick || action.Type == ActionPress {
+			selectors = append(selectors, selectorField{fmt.Sprintf("actions[%d].selector", i), action.Selector})
+		}
+	}
+	if cfg.SearchForm != nil {
+		for i, field := range cfg.SearchForm.Fields {
+			selectors = append(selectors, selectorField{fmt.Sprintf("search_form.fields[%d].selector", i), field.Selector})
+		}
+		selectors = append(selectors, selectorField{"search_form.submit_selector", cfg.SearchForm.SubmitSelector})
+	}
+	for _, s := range selectors {
+		// クローラーのセレクターはPlaywrightのロケーターとして使用されるため、Playwright固有の構文は検証しない
+		if s.selector == "" || playwrightSelectorPattern.MatchString(s.selector) {
+			continue
+		}
+		if err := compileSelector(strings.TrimPrefix(s.selector, "css=")); err != nil {
+			issues = append(issues, ConfigIssue{s.field, err.Error()})
+		}
+	}
+
+	return issues, nil
+}
+
+// ValidateScraperConfigFileは、スクレイパーの設定ファイルを読み込み、見つかったすべての問題を返します。
+// バリデーションに加え、セレクターと正規表現の構文を確認します。
+//
+// args:
+//
+//	path : 設定ファイルのパス
+//
+// return:
+//
+//	[]ConfigIssue : 見つかった問題（問題がない場合は空）
+//	error         : ファイルの読み込みまたはYAMLの解析に失敗した場合のエラー
+func ValidateScraperConfigFile(path string) ([]ConfigIssue, error) {
+	var cfg ScraperConfig
+	if err := unmarshalConfigFile(path, &cfg); err != nil {
+		return nil, err
+	}
+
+	issues := structIssues(cfg)
+	issues = append(issues, expiryIssues("expired", cfg.Expired)...)
+	issues = append(issues, objectStorageIssues(cfg.Storage, cfg.ObjectStorage)...)
+	issues = append(issues, requiredFieldsIssues(cfg.RequiredFields)...)
+	issues = append(issues, incomeBandsIssues(cfg.IncomeBands)...)
+
+	selectors := map[string]SelectorConfig{
+		"title":                     cfg.Title,
+		"company_name":              cfg.CompanyName,
+		"summary_url":               cfg.SummaryURL,
+		"location":                  cfg.Location,
+		"headquarters":              cfg.Headquarters,
+		"job_type":                  cfg.JobType,
+		"salary":                    {Selector: cfg.Salary.Selector},
+		"posted_at":                 cfg.PostedAt,
+		"details.job_name":          cfg.Details.JobName,
+		"details.raise":             cfg.Details.Raise,
+		"details.bonus":             cfg.Details.Bonus,
+		"details.description":       cfg.Details.Description,
+		"details.requirements":      cfg.Details.Requirements,
+		"details.workplace_type":    cfg.Details.WorkplaceType,
+		"details.holidays_per_year": cfg.Details.HolidaysPerYear,
+		"details.holiday_policy":    cfg.Details.HolidayPolicy,
+		"details.work_hours":        cfg.Details.WorkHours,
+		"details.benefits":          cfg.Details.Benefits,
+	}
+	if cfg.ExpiresAt != nil {
+		selectors["expires_at"] = *cfg.ExpiresAt
+	}
+	if cfg.Details.Access != nil {
+		selectors["details.access"] = *cfg.Details.Access
+	}
+	if cfg.Details.ContractPeriod != nil {
+		selectors["details.contract_period"] = *cfg.Details.ContractPeriod
+	}
+	if cfg.Details.Probation != nil {
+		selectors["details.probation"] = *cfg.Details.Probation
+	}
+	if cfg.Details.SmokingPolicy != nil {
+		selectors["details.smoking_policy"] = *cfg.Details.SmokingPolicy
+	}
+
+	// 出力の順序を安定させるため、項目名の順に確認する
+	for _, field := range slices.Sorted(maps.Keys(selectors)) {
+		s := selectors[field]
+		if s.Selector != "" {
+			if err := compileSelector(s.Selector); err != nil {
+				issues = append(issues, ConfigIssue{field + ".selector", err.Error()})
+			}
+		}
+		if s.Regex != "" {
+			if _, err := regexp.Compile(s.Regex); err != nil {
+				issues = append(issues, ConfigIssue{field + ".regex", fmt.Sprintf("正規表現として解釈できません。Goの正規表現（RE2）の構文で記述してください: %v", err)})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// unmarshalConfigFileは、設定ファイルを読み込み、YAMLを構造体に変換します。
+func unmarshalConfigFile(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルを読み込めませんでした: %w", err)
+	}
+	data, err = expandEnv(data)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("YAMLの解析に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// structIssuesは、構造体のバリデーションで見つかったすべての問題を返します。
+func structIssues(cfg any) []ConfigIssue {
+	err := issueValidator.Struct(cfg)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []ConfigIssue{{Message: err.Error()}}
+	}
+
+	issues := make([]ConfigIssue, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		// 先頭の構造体名を除き、YAMLのキーのパスにする
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		issues = append(issues, ConfigIssue{Field: field, Message: validationMessage(fe)})
+	}
+	return issues
+}
+
+// validationMessageは、バリデーションエラーを対処方法がわかるメッセージに変換します。
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "必須の項目です"
+	case "required_if", "required_unless", "required_without":
+		return fmt.Sprintf("この設定では必須の項目です（条件: %s %s）", fe.Tag(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%vは指定できません。次のいずれかを指定してください: %s", fe.Value(), strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "url":
+		return fmt.Sprintf("URLとして解釈できません: %q", fe.Value())
+	case "min":
+		if fe.Kind() == reflect.String || fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s件（文字）以上を指定してください", fe.Param())
+		}
+		return fmt.Sprintf("%s以上の値を指定してください（現在の値: %v）", fe.Param(), fe.Value())
+	case "max":
+		if fe.Kind() == reflect.String || fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s件（文字）以下にしてください", fe.Param())
+		}
+		return fmt.Sprintf("%s以下の値を指定してください（現在の値: %v）", fe.Param(), fe.Value())
+	case "gt":
+		return fmt.Sprintf("%sより大きい値を指定してください（現在の値: %v）", fe.Param(), fe.Value())
+	case "gtefield":
+		return fmt.Sprintf("%s以上の値を指定してください（現在の値: %v）", fe.Param(), fe.Value())
+	default:
+		return fmt.Sprintf("値が条件 %s を満たしていません（現在の値: %v）", fe.ActualTag(), fe.Value())
+	}
+}
+
+// paginationIssuesは、ページネーションのタイプに対して必要な設定が揃っているかを確認します。
+func paginationIssues(p PaginationConfig) []ConfigIssue {
+	if p.Type == None || p.Type == "" {
+		return nil
+	}
+
+	var issues []ConfigIssue
+	if p.ParamIdentifier == "" {
+		issues = append(issues, ConfigIssue{"pagination.param_identifier", fmt.Sprintf("%sタイプでは必須です。ページ番号を表すクエリパラメータ名またはパスの文字列を指定してください（例: page）", p.Type)})
+	}
+	if p.Type == Path || p.Type == Segment {
+		if p.PageFormat == "" {
+			issues = append(issues, ConfigIssue{"pagination.page_format", fmt.Sprintf("%sタイプでは必須です。ページ番号の書式を指定してください（例: %%d）", p.Type)})
+		} else if formatted := fmt.Sprintf(p.PageFormat, p.Start); strings.Contains(formatted, "%!") {
+			issues = append(issues, ConfigIssue{"pagination.page_format", fmt.Sprintf("ページ番号の書式として解釈できません。整数の書式を1つだけ含めてください（例: %%d、%%02d）: %q", p.PageFormat)})
+		}
+	}
+	return issues
+}
+
+// compileSelectorは、goqueryと同じ実装でCSSセレクターを解析できるかを確認します。
+func compileSelector(selector string) error {
+	if _, err := cascadia.ParseGroup(selector); err != nil {
+		return fmt.Errorf("CSSセレクターとして解釈できません: %q: %w", selector, err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package constants
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/nrad-K/go-crawler/internal/infra"
 )
@@ -26,23 +27,34 @@ func GetScraperCompiledPatterns() infra.CompiledPatterns {
 			regexp.MustCompile(`ボーナス.*年(\d+)回`),
 		},
 		AmountPattern:       regexp.MustCompile(`(\d+(?:\.\d+)?)`),
-		SalaryRangePattern:  regexp.MustCompile(`([\d.,]+(?:万|千|億)?円?)\s*[~～]\s*([\d.,]+(?:万|千|億)?円?)`),
-		SalarySinglePattern: regexp.MustCompile(`(\d+(?:\.\d+)?[万億千]?)`),
+		SalaryRangePattern:  regexp.MustCompile(`([\d.,]+(?:万|千|億)?円?台?)\s*[~～〜\-–]\s*\$?([\d.,]+(?:万|千|億)?円?台?)`),
+		SalarySinglePattern: regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?[万億千]?)(円?台)?`),
+		SalaryUnitPattern:   regexp.MustCompile(`年収|年給|年俸|月給|月収|日給|時給`),
+		OvertimePattern:     regexp.MustCompile(`(?:固定残業|みなし残業|見込み残業|固定時間外|みなし時間外)(?:代|手当)?`),
+		OvertimeHourPattern: regexp.MustCompile(`(\d+)(?:\.\d+)?\s*(?:時間|h)`),
+		YenPattern:          regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?[万千]?)\s*円`),
+		DatePattern:         regexp.MustCompile(`(\d{4})\s*[年/.\-]\s*(\d{1,2})\s*[月/.\-]\s*(\d{1,2})`),
+		RelativeDatePattern: regexp.MustCompile(`(\d+)\s*(分|時間|日|週間|週|ヶ月|ケ月|か月|カ月|ヵ月)\s*(前|以内)`),
 		LocationPattern:     regexp.MustCompile(`(?:都|道|府|県)[\s ]*(\S+?[市区町村])`),
+		WorkHoursPattern:    regexp.MustCompile(`(\d{1,2})[:時](?:(\d{2})分?)?\s*[~〜\-]\s*(?:翌\s*)?(\d{1,2})[:時](?:(\d{2})分?)?`),
+		BreakTimePattern:    regexp.MustCompile(`休憩(?:時間)?[:\s]*(\d+(?:\.\d+)?)\s*(分|時間|h)`),
+		StationPattern:      regexp.MustCompile(`((?:JR|東京メトロ|都営|地下鉄)?[^\s、。・,()「」/:]*?線|JR|東京メトロ|都営|地下鉄)?\s*「?([^\s、。・,()「」/:]+?)」?駅」?\s*(?:から|より)?\s*(?:徒歩\s*(?:約\s*)?(\d+)\s*分)?`),
+		PeriodPattern:       regexp.MustCompile(`(\d+)\s*(ヶ月|ケ月|か月|カ月|ヵ月|年)`),
 	}
 }
 
 // GetScraperCSVHeadersは、スクレイパーが出力するCSVファイルのヘッダーを返します。
+// ヘッダーはinfra.DefaultExportFieldsの列定義から生成されます。
 func GetScraperCSVHeaders() []string {
-	return []string{
-		"会社名", "タイトル", "URL",
-		"勤務地(都道府県コード)", "勤務地(都道府県)", "勤務地(市区町村)", "勤務地(原文)",
-		"本社(都道府県コード)", "本社(都道府県)", "本社(市区町村)", "本社(原文)",
-		"雇用形態", "給与(下限)", "給与(上限)", "給与(単位)", "投稿日",
-		"職務内容", "昇給", "賞与", "業務内容詳細", "応募要件", "勤務形態", "年間休日", "休日・休暇", "勤務時間", "福利厚生(原文)",
-	}
+	return infra.ExportHeaders(infra.DefaultExportFields())
 }
 
 const (
 	LogBatchCount = 100
+	// ExportFlushIntervalは、スクレイプ中に出力のバッファを出力先へ書き出す間隔です。
+	ExportFlushInterval = 5 * time.Second
 )
+
+// ExportSchemaVersionは、エクスポートされるデータのスキーマバージョンです。
+// 出力列を変更した場合は、docs/scraper.mdの互換性ポリシーと変更履歴に従って更新してください。
+var ExportSchemaVersion = infra.SchemaVersion{Major: 2, Minor: 15}
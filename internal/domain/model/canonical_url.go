@@ -0,0 +1,78 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// trackingParamsは、同一ページへの到達経路を記録するだけで内容に影響しないクエリパラメータです。
+var trackingParams = map[string]struct{}{
+	"gclid":   {},
+	"fbclid":  {},
+	"yclid":   {},
+	"msclkid": {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"_ga":     {},
+}
+
+// CanonicalizeURLは、同じページを指すURLが同一の文字列になるように正規化します。
+// 異なるクエリ文字列から辿った同じ求人を重複してクロールしないために使用します。
+//
+// 正規化の内容:
+//   - スキームとホストを小文字にし、デフォルトポートを除去
+//   - フラグメントを除去
+//   - utm_*などのトラッキング用パラメータを除去し、残りのパラメータをキー順に並べ替え
+//   - パス末尾のスラッシュを除去（ルートパスは"/"に統一）
+//
+// args:
+//
+//	rawURL : 正規化するURL
+//
+// return:
+//
+//	string : 正規化されたURL
+//	error  : URLとして解釈できない場合のエラー
+func CanonicalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("不正なURLです: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("不正なURLです: スキームとホストを含む絶対URLではありません: %q", rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+	for key := range query {
+		if _, ok := trackingParams[strings.ToLower(key)]; ok || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	// Encodeはキー順に並べ替えて出力する
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	if u.Path == "" {
+		u.Path = "/"
+		u.RawPath = ""
+	}
+
+	return u.String(), nil
+}
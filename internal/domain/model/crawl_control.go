@@ -0,0 +1,28 @@
+package model
+
+import "errors"
+
+// CrawlControlCommandは、実行中のクローラーに対する操作指示です。
+type CrawlControlCommand string
+
+const (
+	CrawlControlRun   CrawlControlCommand = "RUN"   // 通常どおり実行する
+	CrawlControlPause CrawlControlCommand = "PAUSE" // 次のジョブの前で一時停止する
+	CrawlControlStop  CrawlControlCommand = "STOP"  // 処理中のジョブを終えてから停止する
+	CrawlControlDrain CrawlControlCommand = "DRAIN" // 新しいジョブを生成せず、キューに残っているジョブを処理し終えたら停止する
+)
+
+func ParseCrawlControlCommand(s string) (CrawlControlCommand, error) {
+	switch s {
+	case string(CrawlControlRun):
+		return CrawlControlRun, nil
+	case string(CrawlControlPause):
+		return CrawlControlPause, nil
+	case string(CrawlControlStop):
+		return CrawlControlStop, nil
+	case string(CrawlControlDrain):
+		return CrawlControlDrain, nil
+	default:
+		return "", errors.New("無効な操作指示です")
+	}
+}
@@ -2,7 +2,9 @@ package model
 
 import (
 	"errors"
+	"hash/fnv"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,9 +12,11 @@ import (
 type CrawlJobStatus string
 
 const (
-	CrawlJobStatusPending CrawlJobStatus = "PENDING"
-	CrawlJobStatusSuccess CrawlJobStatus = "SUCCESS"
-	CrawlJobStatusFailed  CrawlJobStatus = "FAILED"
+	CrawlJobStatusPending    CrawlJobStatus = "PENDING"
+	CrawlJobStatusInProgress CrawlJobStatus = "IN_PROGRESS"
+	CrawlJobStatusSuccess    CrawlJobStatus = "SUCCESS"
+	CrawlJobStatusFailed     CrawlJobStatus = "FAILED"
+	CrawlJobStatusBlocked    CrawlJobStatus = "BLOCKED"
 )
 
 type CrawlJobStream struct {
@@ -20,10 +24,105 @@ type CrawlJobStream struct {
 	Err error
 }
 
+// CrawlJobStreamOptionsは、ステータスごとのジョブをストリームで取得する際の取得方法を指定します。
+// ジョブ数が非常に多い場合は、Partitionsを指定して複数のプロセスで分担して取得できます。
+//
+// フィールド:
+//
+//	BatchSize  : 1回の走査で取得するジョブ数（0以下の場合は100）
+//	Prefetch   : 受信側が処理する前に先行して取得しておくジョブ数（0以下の場合はBatchSize）
+//	Partition  : このストリームが担当するパーティションの番号（0から始まる）
+//	Partitions : パーティションの総数（1以下の場合は分割しない）
+type CrawlJobStreamOptions struct {
+	BatchSize  int
+	Prefetch   int
+	Partition  int
+	Partitions int
+}
+
+// defaultStreamBatchSizeは、BatchSizeが未指定の場合に1回の走査で取得するジョブ数です。
+const defaultStreamBatchSize = 100
+
+// BatchSizeOrDefaultは、1回の走査で取得するジョブ数を返します。
+func (o CrawlJobStreamOptions) BatchSizeOrDefault() int {
+	if o.BatchSize > 0 {
+		return o.BatchSize
+	}
+	return defaultStreamBatchSize
+}
+
+// PrefetchOrDefaultは、先行して取得しておくジョブ数を返します。
+func (o CrawlJobStreamOptions) PrefetchOrDefault() int {
+	if o.Prefetch > 0 {
+		return o.Prefetch
+	}
+	return o.BatchSizeOrDefault()
+}
+
+// Includesは、正規化したURLのジョブがこのストリームの担当するパーティションに含まれるかを判定します。
+// パーティションはURLのハッシュ値で決まるため、同じPartitionsを指定したストリーム同士で担当が重複することはありません。
+//
+// args:
+//
+//	canonicalURL : 正規化したジョブのURL
+//
+// return:
+//
+//	bool : 担当するパーティションに含まれる場合はtrue
+func (o CrawlJobStreamOptions) Includes(canonicalURL string) bool {
+	if o.Partitions <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(canonicalURL))
+	return int(h.Sum32()%uint32(o.Partitions)) == o.Partition
+}
+
+// ListingPositionは、詳細ページのリンクが見つかった一覧ページのページ番号と、ページ内の掲載位置です。
+// 求人サイトが求人をどの順位で掲載しているかの分析に使用します。値が不明な場合はゼロ値です。
+//
+// フィールド:
+//
+//	Page     : 一覧ページのページ番号（1始まり）
+//	Position : 一覧ページ内の掲載位置（1始まり）
+type ListingPosition struct {
+	Page     int
+	Position int
+}
+
+// IsZeroは、掲載位置が不明かどうかを返します。
+func (p ListingPosition) IsZero() bool {
+	return p.Page == 0 && p.Position == 0
+}
+
+// CrawlAttemptは、ジョブを最後にクロールした際の結果です。
+// 失敗したジョブを再実行せずに原因を調べられるよう、ジョブとともに保存します。値が不明な場合はゼロ値です。
+//
+// フィールド:
+//
+//	StartedAt  : クロールを開始した日時
+//	FinishedAt : クロールを終了した日時
+//	Duration   : ページの取得と保存にかかった時間
+//	HTTPStatus : ページのHTTPステータスコード（キャッシュを使用した場合はキャッシュしたステータスコード、応答がない場合は0）
+//	Error      : クロールに失敗した場合のエラーメッセージ（成功した場合は空）
+type CrawlAttempt struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Duration   time.Duration
+	HTTPStatus int
+	Error      string
+}
+
 type CrawlJob struct {
-	id     uuid.UUID
-	url    url.URL
-	status CrawlJobStatus
+	id        uuid.UUID
+	url       url.URL
+	status    CrawlJobStatus
+	createdAt time.Time
+	updatedAt time.Time
+	listing   ListingPosition
+	attempt   CrawlAttempt
+	retries   int
+	worker    string
 }
 
 func NewCrawlJob(rawURL string) (CrawlJob, error) {
@@ -32,14 +131,19 @@ func NewCrawlJob(rawURL string) (CrawlJob, error) {
 		return CrawlJob{}, errors.New("不正なURLです")
 	}
 
+	now := time.Now()
 	return CrawlJob{
-		id:     uuid.New(),
-		url:    *parseURL,
-		status: CrawlJobStatusPending,
+		id:        uuid.New(),
+		url:       *parseURL,
+		status:    CrawlJobStatusPending,
+		createdAt: now,
+		updatedAt: now,
 	}, nil
 }
 
-func Reconstruct(id, rawURL, status string) (CrawlJob, error) {
+// Reconstructは、永続化されたデータからCrawlJobを復元します。
+// updatedAtが記録されていない古いデータの場合はゼロ値を渡します。
+func Reconstruct(id, rawURL, status string, updatedAt time.Time) (CrawlJob, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return CrawlJob{}, errors.New("不正なIDです")
@@ -54,18 +158,23 @@ func Reconstruct(id, rawURL, status string) (CrawlJob, error) {
 	switch status {
 	case string(CrawlJobStatusPending):
 		st = CrawlJobStatusPending
+	case string(CrawlJobStatusInProgress):
+		st = CrawlJobStatusInProgress
 	case string(CrawlJobStatusSuccess):
 		st = CrawlJobStatusSuccess
 	case string(CrawlJobStatusFailed):
 		st = CrawlJobStatusFailed
+	case string(CrawlJobStatusBlocked):
+		st = CrawlJobStatusBlocked
 	default:
 		return CrawlJob{}, errors.New("無効なステータスです")
 	}
 
 	return CrawlJob{
-		id:     uid,
-		url:    *parsedURL,
-		status: st,
+		id:        uid,
+		url:       *parsedURL,
+		status:    st,
+		updatedAt: updatedAt,
 	}, nil
 
 }
@@ -73,12 +182,19 @@ func Reconstruct(id, rawURL, status string) (CrawlJob, error) {
 func (c *CrawlJob) ChangeStatus(newStatus CrawlJobStatus) (CrawlJob, error) {
 	switch newStatus {
 
-	case CrawlJobStatusPending, CrawlJobStatusSuccess, CrawlJobStatusFailed:
+	case CrawlJobStatusPending, CrawlJobStatusInProgress, CrawlJobStatusSuccess, CrawlJobStatusFailed, CrawlJobStatusBlocked:
 		c.status = newStatus
+		c.updatedAt = time.Now()
 		return CrawlJob{
-			id:     c.id,
-			url:    c.url,
-			status: newStatus,
+			id:        c.id,
+			url:       c.url,
+			status:    newStatus,
+			createdAt: c.createdAt,
+			updatedAt: c.updatedAt,
+			listing:   c.listing,
+			attempt:   c.attempt,
+			retries:   c.retries,
+			worker:    c.worker,
 		}, nil
 
 	default:
@@ -97,3 +213,68 @@ func (c *CrawlJob) URL() string {
 func (c *CrawlJob) Status() CrawlJobStatus {
 	return c.status
 }
+
+// CreatedAtは、ジョブが作成された日時を返します。記録されていない古いジョブの場合はゼロ値です。
+func (c *CrawlJob) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// UpdatedAtは、ジョブが作成またはステータスが最後に変更された日時を返します。不明な場合はゼロ値です。
+func (c *CrawlJob) UpdatedAt() time.Time {
+	return c.updatedAt
+}
+
+// ListingPositionは、詳細ページのリンクが見つかった一覧ページでの掲載位置を返します。不明な場合はゼロ値です。
+func (c *CrawlJob) ListingPosition() ListingPosition {
+	return c.listing
+}
+
+// WithListingPositionは、一覧ページでの掲載位置を設定したCrawlJobを返します。
+func (c *CrawlJob) WithListingPosition(listing ListingPosition) CrawlJob {
+	job := *c
+	job.listing = listing
+	return job
+}
+
+// LastAttemptは、ジョブを最後にクロールした際の結果を返します。クロールしていない場合はゼロ値です。
+func (c *CrawlJob) LastAttempt() CrawlAttempt {
+	return c.attempt
+}
+
+// RetryCountは、ジョブのクロールに失敗した回数を返します。失敗したジョブは次回の実行で再試行されるため、再試行の回数にあたります。
+func (c *CrawlJob) RetryCount() int {
+	return c.retries
+}
+
+// WithCreatedAtは、作成日時を設定したCrawlJobを返します。永続化されたデータから復元する場合に使用します。
+func (c *CrawlJob) WithCreatedAt(createdAt time.Time) CrawlJob {
+	job := *c
+	job.createdAt = createdAt
+	return job
+}
+
+// WithLastAttemptは、最後にクロールした際の結果を設定したCrawlJobを返します。
+func (c *CrawlJob) WithLastAttempt(attempt CrawlAttempt) CrawlJob {
+	job := *c
+	job.attempt = attempt
+	return job
+}
+
+// WithRetryCountは、クロールに失敗した回数を設定したCrawlJobを返します。
+func (c *CrawlJob) WithRetryCount(retries int) CrawlJob {
+	job := *c
+	job.retries = retries
+	return job
+}
+
+// WorkerIDは、ジョブを最後に獲得したワーカーのIDを返します。獲得されていない場合や記録されていない古いジョブの場合は空です。
+func (c *CrawlJob) WorkerID() string {
+	return c.worker
+}
+
+// WithWorkerIDは、ジョブを獲得したワーカーのIDを設定したCrawlJobを返します。
+func (c *CrawlJob) WithWorkerID(workerID string) CrawlJob {
+	job := *c
+	job.worker = workerID
+	return job
+}
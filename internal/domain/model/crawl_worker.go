@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// CrawlWorkerRoleは、ワーカーが実行している処理です。
+type CrawlWorkerRole string
+
+const (
+	CrawlWorkerRoleGenerate CrawlWorkerRole = "generate" // クロールジョブを生成する
+	CrawlWorkerRoleExecute  CrawlWorkerRole = "execute"  // クロールジョブを実行する
+)
+
+// CrawlWorkerは、キューを共有してクロールジョブを実行しているプロセス（ワーカー）の状態です。
+// 各ワーカーは実行中に定期的に状態を報告し、報告が途絶えたワーカーは停止したものとみなされます。
+//
+// フィールド:
+//
+//	ID         : ワーカーを識別する値（ジョブの獲得時にジョブに記録される）
+//	Role       : ワーカーが実行している処理
+//	Hostname   : ワーカーが動作しているホスト名
+//	StartedAt  : 実行を開始した日時
+//	LastSeen   : 最後に状態を報告した日時
+//	Generated  : 生成したジョブ数
+//	Success    : 成功したジョブ数
+//	Failed     : 失敗したジョブ数
+//	Blocked    : 失敗したジョブのうち、アクセスを拒否されたジョブ数
+//	CurrentURL : 処理中のジョブまたは一覧ページのURL（処理していない場合は空）
+type CrawlWorker struct {
+	ID         string
+	Role       CrawlWorkerRole
+	Hostname   string
+	StartedAt  time.Time
+	LastSeen   time.Time
+	Generated  int
+	Success    int
+	Failed     int
+	Blocked    int
+	CurrentURL string
+}
@@ -11,12 +11,20 @@ type JobPostingArgs struct {
 	Title        string
 	CompanyName  string
 	SummaryURL   string
-	Location     Location
+	Locations    []Location
 	Headquarters Location
 	JobType      JobType
 	Salary       Salary
 	PostedAt     time.Time
+	ExpiresAt    time.Time
+	CrawledAt    time.Time
+	Expired      bool
+	Listing      ListingPosition
 	Details      JobPostingDetail
+	Extras       map[string]string
+	Category     string
+	IncomeBand   string
+	CorporateNo  string
 }
 
 type JobPosting struct {
@@ -24,12 +32,20 @@ type JobPosting struct {
 	title        string
 	companyName  string
 	summaryURL   string
-	location     Location
+	locations    []Location
 	headquarters Location
 	jobType      JobType
 	salary       Salary
 	postedAt     time.Time
+	expiresAt    time.Time
+	crawledAt    time.Time
+	expired      bool
+	listing      ListingPosition
 	details      JobPostingDetail
+	extras       map[string]string
+	category     string
+	incomeBand   string
+	corporateNo  string
 }
 
 func NewJobPosting(args JobPostingArgs) JobPosting {
@@ -38,12 +54,20 @@ func NewJobPosting(args JobPostingArgs) JobPosting {
 		title:        args.Title,
 		companyName:  args.CompanyName,
 		summaryURL:   args.SummaryURL,
-		location:     args.Location,
+		locations:    args.Locations,
 		headquarters: args.Headquarters,
 		jobType:      args.JobType,
 		salary:       args.Salary,
 		postedAt:     args.PostedAt,
+		expiresAt:    args.ExpiresAt,
+		crawledAt:    args.CrawledAt,
+		expired:      args.Expired,
+		listing:      args.Listing,
 		details:      args.Details,
+		extras:       args.Extras,
+		category:     args.Category,
+		incomeBand:   args.IncomeBand,
+		corporateNo:  args.CorporateNo,
 	}
 }
 
@@ -63,8 +87,9 @@ func (j *JobPosting) SummaryURL() string {
 	return j.summaryURL
 }
 
-func (j *JobPosting) Location() Location {
-	return j.location
+// Locationsは、勤務地の一覧を記載順に返します。
+func (j *JobPosting) Locations() []Location {
+	return j.locations
 }
 
 func (j *JobPosting) Headquarters() Location {
@@ -83,6 +108,78 @@ func (j *JobPosting) PostedAt() time.Time {
 	return j.postedAt
 }
 
+// ExpiresAtは、応募締切日（掲載終了日）を返します。記載がない場合はゼロ値です。
+func (j *JobPosting) ExpiresAt() time.Time {
+	return j.expiresAt
+}
+
+// CrawledAtは、求人ページを取得した日時を返します。不明な場合はゼロ値です。
+func (j *JobPosting) CrawledAt() time.Time {
+	return j.crawledAt
+}
+
+// Expiredは、求人ページが掲載終了と判定されたかどうかを返します。
+func (j *JobPosting) Expired() bool {
+	return j.expired
+}
+
+// Listingは、求人が見つかった一覧ページでの掲載位置を返します。不明な場合はゼロ値です。
+func (j *JobPosting) Listing() ListingPosition {
+	return j.listing
+}
+
 func (j *JobPosting) Details() JobPostingDetail {
 	return j.details
 }
+
+// Extraは、設定ファイルのextra_fieldsで定義した追加の項目の値を返します。値が得られなかった場合は空文字列です。
+func (j *JobPosting) Extra(name string) string {
+	return j.extras[name]
+}
+
+// WithExtraは、追加の項目の値を設定した求人情報を返します。元の求人情報は変更しません。
+func (j *JobPosting) WithExtra(name, value string) JobPosting {
+	posting := *j
+	posting.extras = make(map[string]string, len(j.extras)+1)
+	for k, v := range j.extras {
+		posting.extras[k] = v
+	}
+	posting.extras[name] = value
+	return posting
+}
+
+// Categoryは、職種カテゴリーを返します。判定していない場合や、いずれのカテゴリーにも該当しない場合は空文字列です。
+func (j *JobPosting) Category() string {
+	return j.category
+}
+
+// WithCategoryは、職種カテゴリーを設定した求人情報を返します。元の求人情報は変更しません。
+func (j *JobPosting) WithCategory(category string) JobPosting {
+	posting := *j
+	posting.category = category
+	return posting
+}
+
+// IncomeBandは、年収に換算した給与の年収帯を返します。分類していない場合や、給与が不明な場合は空文字列です。
+func (j *JobPosting) IncomeBand() string {
+	return j.incomeBand
+}
+
+// WithIncomeBandは、年収帯を設定した求人情報を返します。元の求人情報は変更しません。
+func (j *JobPosting) WithIncomeBand(band string) JobPosting {
+	posting := *j
+	posting.incomeBand = band
+	return posting
+}
+
+// CorporateNumberは、会社の法人番号（13桁）を返します。取得していない場合や、法人を特定できなかった場合は空文字列です。
+func (j *JobPosting) CorporateNumber() string {
+	return j.corporateNo
+}
+
+// WithCorporateNumberは、法人番号を設定した求人情報を返します。元の求人情報は変更しません。
+func (j *JobPosting) WithCorporateNumber(number string) JobPosting {
+	posting := *j
+	posting.corporateNo = number
+	return posting
+}
@@ -10,6 +10,15 @@ const (
 	UnknownSalaryType SalaryType = "不明"
 )
 
+// Currencyは、給与の通貨（ISO 4217の通貨コード）です。
+type Currency string
+
+const (
+	JPY             Currency = "JPY" // 日本円
+	USD             Currency = "USD" // 米ドル
+	UnknownCurrency Currency = ""    // 不明
+)
+
 type JobType string
 
 const (
@@ -43,6 +52,17 @@ const (
 	UnknownWorkplace WorkplaceType = "不明"
 )
 
+// SmokingPolicyは、受動喫煙対策（就業場所の喫煙の可否）です。
+type SmokingPolicy string
+
+const (
+	NoSmokingPremises SmokingPolicy = "敷地内禁煙"
+	NoSmokingIndoors  SmokingPolicy = "屋内禁煙"
+	SmokingRoom       SmokingPolicy = "喫煙室あり"
+	SmokingAllowed    SmokingPolicy = "喫煙可"
+	UnknownSmoking    SmokingPolicy = "不明"
+)
+
 type PrefectureCode string
 
 const (
@@ -14,6 +14,11 @@ func (a *Amount) Format() string {
 	return fmt.Sprintf("%d", a.value)
 }
 
+// Valueは、金額と、金額が有効かどうかを返します。
+func (a *Amount) Value() (uint64, bool) {
+	return a.value, a.valid
+}
+
 func NewAmount(value uint64) Amount {
 	return Amount{
 		value: uint64(value),
@@ -29,9 +34,12 @@ func NewNullAmount() Amount {
 }
 
 type Salary struct {
-	minAmount Amount
-	maxAmount Amount
-	unit      SalaryType
+	minAmount  Amount
+	maxAmount  Amount
+	unit       SalaryType
+	negotiable bool
+	currency   Currency
+	overtime   FixedOvertime
 }
 
 func NewSalary(minAmount Amount, maxAmount Amount, salaryType SalaryType) Salary {
@@ -54,11 +62,76 @@ func (s Salary) Unit() SalaryType {
 	return s.unit
 }
 
+// Negotiableは、給与が「応相談」と記載されているかどうかを返します。
+func (s Salary) Negotiable() bool {
+	return s.negotiable
+}
+
+// Currencyは、給与の通貨を返します。不明な場合はUnknownCurrencyです。
+func (s Salary) Currency() Currency {
+	return s.currency
+}
+
+// WithCurrencyは、通貨を設定した給与を返します。元の給与は変更しません。
+func (s Salary) WithCurrency(currency Currency) Salary {
+	s.currency = currency
+	return s
+}
+
+// WithNegotiableは、「応相談」かどうかを設定した給与を返します。元の給与は変更しません。
+func (s Salary) WithNegotiable(negotiable bool) Salary {
+	s.negotiable = negotiable
+	return s
+}
+
+// FixedOvertimeは、給与に含まれる固定残業代（みなし残業）を返します。含まれない場合はゼロ値です。
+func (s Salary) FixedOvertime() FixedOvertime {
+	return s.overtime
+}
+
+// WithFixedOvertimeは、固定残業代を設定した給与を返します。元の給与は変更しません。
+func (s Salary) WithFixedOvertime(overtime FixedOvertime) Salary {
+	s.overtime = overtime
+	return s
+}
+
+// FixedOvertimeは、給与に含まれる固定残業代（みなし残業）を表します。
+// 固定残業の時間数と金額は記載がない場合があるため、省略可能です。
+type FixedOvertime struct {
+	included bool
+	hours    *uint
+	amount   Amount
+}
+
+func NewFixedOvertime(hours *uint, amount Amount) FixedOvertime {
+	return FixedOvertime{
+		included: true,
+		hours:    hours,
+		amount:   amount,
+	}
+}
+
+// Includedは、給与に固定残業代（みなし残業）が含まれるかどうかを返します。
+func (f FixedOvertime) Included() bool {
+	return f.included
+}
+
+// Hoursは、固定残業代に含まれる残業時間（月あたりの時間数）を返します。記載がない場合はnilを返します。
+func (f FixedOvertime) Hours() *uint {
+	return f.hours
+}
+
+// Amountは、固定残業代の金額を返します。記載がない場合は無効な金額を返します。
+func (f FixedOvertime) Amount() Amount {
+	return f.amount
+}
+
 type Location struct {
-	prefectureCode PrefectureCode
-	prefectureName string
-	city           string
-	raw            string
+	prefectureCode   PrefectureCode
+	prefectureName   string
+	city             string
+	municipalityCode string
+	raw              string
 }
 
 func NewLocation(code PrefectureCode, name, city, raw string) Location {
@@ -82,6 +155,17 @@ func (l Location) City() string {
 	return l.city
 }
 
+// MunicipalityCodeは、市区町村の全国地方公共団体コード（JIS X 0402の5桁）を返します。特定できない場合は空を返します。
+func (l Location) MunicipalityCode() string {
+	return l.municipalityCode
+}
+
+// WithMunicipalityCodeは、市区町村の全国地方公共団体コードを設定した所在地を返します。
+func (l Location) WithMunicipalityCode(code string) Location {
+	l.municipalityCode = code
+	return l
+}
+
 func (l Location) Raw() string {
 	return l.raw
 }
@@ -176,6 +260,146 @@ func (b Benefits) RawBenefits() string {
 	return b.rawBenefits
 }
 
+// WorkShiftは、勤務時間帯（開始・終了・休憩）を表します。
+// 時刻は0時からの経過分で保持し、終了が開始より前の場合は日をまたぐ勤務として扱います。
+type WorkShift struct {
+	startMinutes uint
+	endMinutes   uint
+	breakMinutes *uint
+}
+
+func NewWorkShift(startMinutes, endMinutes uint, breakMinutes *uint) WorkShift {
+	return WorkShift{
+		startMinutes: startMinutes,
+		endMinutes:   endMinutes,
+		breakMinutes: breakMinutes,
+	}
+}
+
+// Startは、勤務開始時刻を"HH:MM"形式で返します。
+func (w WorkShift) Start() string {
+	return formatClock(w.startMinutes)
+}
+
+// Endは、勤務終了時刻を"HH:MM"形式で返します。
+func (w WorkShift) End() string {
+	return formatClock(w.endMinutes)
+}
+
+func (w WorkShift) BreakMinutes() *uint {
+	return w.breakMinutes
+}
+
+// WorkingMinutesは、拘束時間から休憩時間を差し引いた実働時間（分）を返します。
+func (w WorkShift) WorkingMinutes() uint {
+	end := w.endMinutes
+	if end <= w.startMinutes {
+		end += 24 * 60
+	}
+	total := end - w.startMinutes
+
+	if w.breakMinutes != nil {
+		if *w.breakMinutes >= total {
+			return 0
+		}
+		total -= *w.breakMinutes
+	}
+	return total
+}
+
+// WorkingHoursは、実働時間を時間単位で返します。
+func (w WorkShift) WorkingHours() float64 {
+	return float64(w.WorkingMinutes()) / 60
+}
+
+// formatClockは、0時からの経過分を"HH:MM"形式に変換します。
+func formatClock(minutes uint) string {
+	return fmt.Sprintf("%02d:%02d", (minutes/60)%24, minutes%60)
+}
+
+// NearestStationは、最寄り駅（路線名・駅名・駅からの徒歩分数）を表します。
+// 路線名と徒歩分数は記載がない場合があるため、省略可能です。
+type NearestStation struct {
+	line        string
+	name        string
+	walkMinutes *uint
+}
+
+func NewNearestStation(line, name string, walkMinutes *uint) NearestStation {
+	return NearestStation{
+		line:        line,
+		name:        name,
+		walkMinutes: walkMinutes,
+	}
+}
+
+func (n NearestStation) Line() string {
+	return n.line
+}
+
+func (n NearestStation) Name() string {
+	return n.name
+}
+
+func (n NearestStation) WalkMinutes() *uint {
+	return n.walkMinutes
+}
+
+// ContractPeriodは、契約期間（期間の月数・長期かどうか・更新の有無）を表します。
+// 契約社員や派遣の求人で記載されます。期間の月数と更新の有無は記載がない場合があるため、省略可能です。
+type ContractPeriod struct {
+	months    *uint
+	longTerm  bool
+	renewable *bool
+}
+
+func NewContractPeriod(months *uint, longTerm bool, renewable *bool) ContractPeriod {
+	return ContractPeriod{
+		months:    months,
+		longTerm:  longTerm,
+		renewable: renewable,
+	}
+}
+
+// Monthsは、契約期間の月数を返します。記載がない場合はnilを返します。
+func (c ContractPeriod) Months() *uint {
+	return c.months
+}
+
+// LongTermは、「長期」と記載されている場合にtrueを返します。
+func (c ContractPeriod) LongTerm() bool {
+	return c.longTerm
+}
+
+// Renewableは、契約の更新の有無を返します。記載がない場合はnilを返します。
+func (c ContractPeriod) Renewable() *bool {
+	return c.renewable
+}
+
+// ProbationPeriodは、試用期間（期間の月数と試用期間中の労働条件の変更の有無）を表します。
+// 期間と条件の変更の有無は記載がない場合があるため、省略可能です。
+type ProbationPeriod struct {
+	months           *uint
+	conditionChanged *bool
+}
+
+func NewProbationPeriod(months *uint, conditionChanged *bool) ProbationPeriod {
+	return ProbationPeriod{
+		months:           months,
+		conditionChanged: conditionChanged,
+	}
+}
+
+// Monthsは、試用期間の月数を返します。試用期間がない場合は0、記載がない場合はnilを返します。
+func (p ProbationPeriod) Months() *uint {
+	return p.months
+}
+
+// ConditionChangedは、試用期間中に給与などの労働条件が変わるかどうかを返します。記載がない場合はnilを返します。
+func (p ProbationPeriod) ConditionChanged() *bool {
+	return p.conditionChanged
+}
+
 type JobPostingDetailArgs struct {
 	JobName         string
 	Raise           *uint
@@ -186,6 +410,11 @@ type JobPostingDetailArgs struct {
 	HolidaysPerYear *uint
 	HolidayPolicy   HolidayPolicy
 	WorkHours       string
+	WorkShifts      []WorkShift
+	NearestStation  NearestStation
+	ContractPeriod  ContractPeriod
+	ProbationPeriod ProbationPeriod
+	SmokingPolicy   SmokingPolicy
 	Benefits        Benefits
 }
 
@@ -199,6 +428,11 @@ type JobPostingDetail struct {
 	holidaysPerYear *uint
 	holidayPolicy   HolidayPolicy
 	workHours       string
+	workShifts      []WorkShift
+	nearestStation  NearestStation
+	contractPeriod  ContractPeriod
+	probationPeriod ProbationPeriod
+	smokingPolicy   SmokingPolicy
 	benefits        Benefits
 }
 
@@ -238,6 +472,26 @@ func (d JobPostingDetail) WorkHours() string {
 	return d.workHours
 }
 
+func (d JobPostingDetail) WorkShifts() []WorkShift {
+	return d.workShifts
+}
+
+func (d JobPostingDetail) NearestStation() NearestStation {
+	return d.nearestStation
+}
+
+func (d JobPostingDetail) ContractPeriod() ContractPeriod {
+	return d.contractPeriod
+}
+
+func (d JobPostingDetail) ProbationPeriod() ProbationPeriod {
+	return d.probationPeriod
+}
+
+func (d JobPostingDetail) SmokingPolicy() SmokingPolicy {
+	return d.smokingPolicy
+}
+
 func (d JobPostingDetail) Benefits() Benefits {
 	return d.benefits
 }
@@ -253,6 +507,11 @@ func NewJobPostingDetail(args JobPostingDetailArgs) JobPostingDetail {
 		holidaysPerYear: args.HolidaysPerYear,
 		holidayPolicy:   args.HolidayPolicy,
 		workHours:       args.WorkHours,
+		workShifts:      args.WorkShifts,
+		nearestStation:  args.NearestStation,
+		contractPeriod:  args.ContractPeriod,
+		probationPeriod: args.ProbationPeriod,
+		smokingPolicy:   args.SmokingPolicy,
 		benefits:        args.Benefits,
 	}
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+type CrawlControlRepository interface {
+	Get(ctx context.Context) (model.CrawlControlCommand, error)
+	Set(ctx context.Context, command model.CrawlControlCommand) error
+	Reset(ctx context.Context, command model.CrawlControlCommand) (bool, error)
+}
@@ -8,7 +8,12 @@ import (
 
 type CrawlJobRepository interface {
 	Save(ctx context.Context, job model.CrawlJob) error
+	SaveAll(ctx context.Context, jobs []model.CrawlJob) (int, error)
 	Delete(ctx context.Context, job model.CrawlJob) error
-	FindListByStatusStream(ctx context.Context, size int, status model.CrawlJobStatus) <-chan model.CrawlJobStream
+	FindListByStatusStream(ctx context.Context, status model.CrawlJobStatus, opts model.CrawlJobStreamOptions) <-chan model.CrawlJobStream
 	Exists(ctx context.Context, job model.CrawlJob) (bool, error)
+	Claim(ctx context.Context, job model.CrawlJob, workerID string) (model.CrawlJob, bool, error)
+	Dequeue(ctx context.Context, workerID string) (model.CrawlJob, bool, error)
+	ProcessingWorkerIDs(ctx context.Context) ([]string, error)
+	RequeueProcessing(ctx context.Context, workerID string) (int, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+type CrawlWorkerRepository interface {
+	Heartbeat(ctx context.Context, worker model.CrawlWorker) error
+	Remove(ctx context.Context, id string) error
+	FindAll(ctx context.Context) ([]model.CrawlWorker, error)
+}
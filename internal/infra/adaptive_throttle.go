@@ -0,0 +1,132 @@
+package infra
+
+import (
+	"slices"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// ThrottleStatsは、実行中にジョブの間の待機時間を調整した結果です。
+//
+// フィールド:
+//
+//	Increases : 待機時間を広げた回数（上限に達していて広げなかった場合を除く）
+//	MaxDelay  : 実行中の待機時間の最大値
+type ThrottleStats struct {
+	Increases int
+	MaxDelay  time.Duration
+}
+
+// AdaptiveThrottleは、ジョブの結果からジョブの間の待機時間を調整するフィードバック制御です。
+// 直近のジョブの失敗率がしきい値以上になるか、指定したステータスコードを受け取ると待機時間を2倍にし、
+// 成功が続くと半分に戻します。待機時間を広げた後は、広げた待機時間での結果で判定し直すため直近の結果を破棄します。
+// 1つのクローラーのジョブを順に処理する前提のため、排他制御は行いません。
+//
+// フィールド:
+//
+//	cfg       : 待機時間の調整の設定
+//	statuses  : 受け取った時点で待機時間を広げるステータスコード
+//	results   : 直近のジョブが失敗したかどうか（古い順）
+//	delay     : 現在の待機時間
+//	successes : 最後に待機時間を変更してから連続して成功したジョブ数
+//	stats     : 待機時間を調整した結果
+type AdaptiveThrottle struct {
+	cfg       config.ThrottleConfig
+	statuses  []int
+	results   []bool
+	delay     time.Duration
+	successes int
+	stats     ThrottleStats
+}
+
+// NewAdaptiveThrottleは、AdaptiveThrottleの新しいインスタンスを生成します。
+//
+// args:
+//
+//	cfg : 待機時間の調整の設定（nilの場合は調整しない）
+//
+// return:
+//
+//	*AdaptiveThrottle : 生成されたインスタンス（cfgがnilの場合はnil）
+func NewAdaptiveThrottle(cfg *config.ThrottleConfig) *AdaptiveThrottle {
+	if cfg == nil {
+		return nil
+	}
+	return &AdaptiveThrottle{
+		cfg:      *cfg,
+		statuses: cfg.StatusesOrDefault(),
+		results:  make([]bool, 0, cfg.WindowOrDefault()),
+	}
+}
+
+// Recordは、ジョブの結果を記録し、待機時間を調整します。
+//
+// args:
+//
+//	status : ジョブで受け取ったHTTPステータスコード（応答がない場合は0）
+//	failed : ジョブが失敗した場合はtrue
+//
+// return:
+//
+//	time.Duration : 調整前の待機時間
+//	time.Duration : 調整後の待機時間
+func (t *AdaptiveThrottle) Record(status int, failed bool) (time.Duration, time.Duration) {
+	before := t.delay
+
+	if len(t.results) == t.cfg.WindowOrDefault() {
+		t.results = t.results[1:]
+	}
+	t.results = append(t.results, failed)
+
+	if slices.Contains(t.statuses, status) || t.failureRateExceeded() {
+		t.delay = min(max(t.delay*2, t.cfg.InitialDelay()), t.cfg.MaxDelay())
+		t.results = t.results[:0]
+		t.successes = 0
+		if t.delay > before {
+			t.stats.Increases++
+			t.stats.MaxDelay = max(t.stats.MaxDelay, t.delay)
+		}
+		return before, t.delay
+	}
+
+	if failed || t.delay == 0 {
+		t.successes = 0
+		return before, t.delay
+	}
+	t.successes++
+	if t.successes >= t.cfg.DecayAfterOrDefault() {
+		t.successes = 0
+		t.delay /= 2
+		// 最初に広げる待機時間より短くなった場合は、調整前の状態に戻す
+		if t.delay < t.cfg.InitialDelay() {
+			t.delay = 0
+		}
+	}
+	return before, t.delay
+}
+
+// failureRateExceededは、直近のジョブがwindowの件数そろっており、失敗率がしきい値以上かどうかを返します。
+// 件数が少ないうちは1件の失敗で失敗率が大きく振れるため判定しません。
+func (t *AdaptiveThrottle) failureRateExceeded() bool {
+	if len(t.results) < t.cfg.WindowOrDefault() {
+		return false
+	}
+	failures := 0
+	for _, failed := range t.results {
+		if failed {
+			failures++
+		}
+	}
+	return float64(failures)/float64(len(t.results)) >= t.cfg.FailureRateOrDefault()
+}
+
+// Delayは、次のジョブの前に待機する時間を返します。
+func (t *AdaptiveThrottle) Delay() time.Duration {
+	return t.delay
+}
+
+// Statsは、実行中に待機時間を調整した結果を返します。
+func (t *AdaptiveThrottle) Stats() ThrottleStats {
+	return t.stats
+}
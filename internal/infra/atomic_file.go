@@ -0,0 +1,159 @@
+package infra
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// partialSuffixは、書き込みに失敗したファイルを残す場合に付与するサフィックスです。
+const partialSuffix = ".partial"
+
+// outputFileModeは、出力ファイルのパーミッションです。実際のパーミッションはumaskを適用した値になります。
+// os.CreateTempは0600で作成するため、os.Createで作成した場合と同じく他のユーザーも読み込めるよう、リネームの前に変更します。
+const outputFileMode os.FileMode = 0o644
+
+// defaultUmaskは、プロセスのumaskを取得できない場合に使用するumaskです。
+const defaultUmask os.FileMode = 0o022
+
+var (
+	umaskOnce    sync.Once
+	processUmask os.FileMode
+)
+
+// currentUmaskは、プロセスのumaskを返します。
+// umaskを変更せずに取得するため/proc/self/statusを読み込み、Linux以外や古いカーネルなど取得できない場合は022とみなします。
+//
+// return:
+//
+//	os.FileMode : プロセスのumask
+func currentUmask() os.FileMode {
+	umaskOnce.Do(func() {
+		processUmask = defaultUmask
+		file, err := os.Open("/proc/self/status")
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			value, ok := strings.CutPrefix(scanner.Text(), "Umask:")
+			if !ok {
+				continue
+			}
+			if umask, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32); err == nil {
+				processUmask = os.FileMode(umask)
+			}
+			return
+		}
+	})
+	return processUmask
+}
+
+// atomicFileは、一時ファイルに書き込み、Commit時にのみ最終パスへアトミックにリネームするファイルです。
+// 書き込み途中のファイルが最終パスに現れないため、下流のジョブが不完全なファイルを読み込むことを防ぎます。
+//
+// フィールド:
+//
+//	File        : 書き込み対象の一時ファイル
+//	path        : 最終的な出力先のパス
+//	keepPartial : 失敗時に一時ファイルを.partialとして残すかどうか
+type atomicFile struct {
+	*os.File
+	path        string
+	keepPartial bool
+}
+
+// createAtomicFileは、出力先と同じディレクトリに一時ファイルを作成します。
+// 同一ファイルシステム上に作成することで、Commit時のリネームをアトミックに行えます。
+//
+// args:
+//
+//	path        : 最終的な出力先のパス
+//	keepPartial : 失敗時に一時ファイルを.partialとして残すかどうか
+//
+// return:
+//
+//	*atomicFile : 作成された一時ファイル
+//	error       : ディレクトリや一時ファイルの作成に失敗した場合のエラー
+func createAtomicFile(path string, keepPartial bool) (*atomicFile, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("出力ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("一時ファイルの作成に失敗しました: %w", err)
+	}
+
+	return &atomicFile{
+		File:        file,
+		path:        path,
+		keepPartial: keepPartial,
+	}, nil
+}
+
+// Commitは、一時ファイルをディスクに同期してクローズし、最終パスへリネームします。
+// 途中で失敗した場合はAbortと同様に一時ファイルを後始末します。
+//
+// return:
+//
+//	error : 同期・クローズ・リネームに失敗した場合のエラー
+func (f *atomicFile) Commit() error {
+	if err := f.Sync(); err != nil {
+		f.Abort()
+		return fmt.Errorf("一時ファイルの同期に失敗しました: %w", err)
+	}
+
+	if err := f.File.Close(); err != nil {
+		f.discard()
+		return fmt.Errorf("一時ファイルのクローズに失敗しました: %w", err)
+	}
+
+	if err := os.Chmod(f.Name(), outputFileMode&^currentUmask()); err != nil {
+		f.discard()
+		return fmt.Errorf("一時ファイルのパーミッションの変更に失敗しました: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		f.discard()
+		return fmt.Errorf("出力ファイルへのリネームに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// Abortは、一時ファイルをクローズし、設定に応じて削除するか.partialとして残します。
+//
+// return:
+//
+//	error : 後始末に失敗した場合のエラー
+func (f *atomicFile) Abort() error {
+	f.File.Close()
+	return f.discard()
+}
+
+// discardは、クローズ済みの一時ファイルを削除するか、.partialサフィックス付きで残します。
+//
+// return:
+//
+//	error : 削除またはリネームに失敗した場合のエラー
+func (f *atomicFile) discard() error {
+	if f.keepPartial {
+		if err := os.Rename(f.Name(), f.path+partialSuffix); err != nil {
+			return fmt.Errorf("部分ファイルの保存に失敗しました: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.Remove(f.Name()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("一時ファイルの削除に失敗しました: %w", err)
+	}
+	return nil
+}
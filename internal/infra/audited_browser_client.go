@@ -0,0 +1,301 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// DomainAuditは、1つのドメインに対するリクエストの集計結果です。
+//
+// フィールド:
+//
+//	Domain             : ドメイン名
+//	Requests           : ナビゲーションしたページ数
+//	Errors             : ナビゲーションに失敗したか、HTTPステータスが400以上だったリクエスト数
+//	TooManyRequests    : HTTPステータスが429だったリクエスト数
+//	ErrorRate          : エラー率（Errors / Requests）
+//	AvgIntervalSeconds : 同じドメインへのリクエスト間隔の平均（秒）
+//	MinIntervalSeconds : 同じドメインへのリクエスト間隔の最小値（秒）
+//	Bytes              : 取得したHTMLのバイト数
+type DomainAudit struct {
+	Domain             string  `json:"domain"`
+	Requests           int     `json:"requests"`
+	Errors             int     `json:"errors"`
+	TooManyRequests    int     `json:"too_many_requests"`
+	ErrorRate          float64 `json:"error_rate"`
+	AvgIntervalSeconds float64 `json:"avg_interval_seconds"`
+	MinIntervalSeconds float64 `json:"min_interval_seconds"`
+	Bytes              int64   `json:"bytes"`
+}
+
+// PolitenessReportは、1回の実行におけるドメインごとのリクエストの集計結果です。
+// クロールが節度を保って行われたことの説明や、待機時間の調整に使用します。
+//
+// フィールド:
+//
+//	StartedAt  : 集計を開始した日時
+//	FinishedAt : 集計を終了した日時
+//	Domains    : ドメインごとの集計結果（ドメイン名順）
+//	ConfigHash : 実行に使用したクローラーの設定のハッシュ値
+type PolitenessReport struct {
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Domains    []DomainAudit `json:"domains"`
+	ConfigHash string        `json:"config_hash,omitempty"`
+}
+
+// domainStatsは、ドメインごとの集計中の値です。
+type domainStats struct {
+	requests        int
+	errors          int
+	tooManyRequests int
+	bytes           int64
+	lastRequestAt   time.Time
+	intervalTotal   time.Duration
+	intervalCount   int
+	minInterval     time.Duration
+}
+
+// auditedBrowserClientは、BrowserClientをラップしてドメインごとのリクエスト数・間隔・エラー・転送量を記録するBrowserClientの実装です。
+// 集計対象外のメソッドはラップしたクライアントにそのまま委譲します。
+//
+// フィールド:
+//
+//	BrowserClient : ラップするクライアント
+//	startedAt     : 集計を開始した日時
+//	currentDomain : 直前にナビゲーションしたドメイン（取得したHTMLのバイト数の計上先）
+//	stats         : ドメインごとの集計中の値
+type auditedBrowserClient struct {
+	BrowserClient
+	mu            sync.Mutex
+	startedAt     time.Time
+	currentDomain string
+	stats         map[string]*domainStats
+}
+
+// NewAuditedBrowserClientは、auditedBrowserClientの新しいインスタンスを生成します。
+//
+// args:
+//
+//	client : ラップするクライアント
+//
+// return:
+//
+//	*auditedBrowserClient : 生成されたクライアント
+func NewAuditedBrowserClient(client BrowserClient) *auditedBrowserClient {
+	return &auditedBrowserClient{
+		BrowserClient: client,
+		startedAt:     time.Now(),
+		stats:         make(map[string]*domainStats),
+	}
+}
+
+// Navigateは、ナビゲーションし、遷移先のドメインへのリクエストとして記録します。
+//
+// args:
+//
+//	ctx: 再試行の待機を中断するためのコンテキスト
+//	rawURL: 遷移先のURL
+//
+// return:
+//
+//	error: ナビゲーションの失敗時のエラー
+func (a *auditedBrowserClient) Navigate(ctx context.Context, rawURL string) error {
+	domain := requestDomain(rawURL)
+
+	requestedAt := time.Now()
+	err := a.BrowserClient.Navigate(ctx, rawURL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.recordRequest(domain, requestedAt, a.BrowserClient.LastStatus(), err)
+	a.currentDomain = domain
+
+	return err
+}
+
+// Fetchは、リクエストを送り、リクエスト先のドメインへのリクエストと転送量として記録します。
+//
+// args:
+//
+//	ctx: 再試行の待機を中断するためのコンテキスト
+//	rawURL: リクエスト先のURL
+//
+// return:
+//
+//	[]byte: レスポンスのボディ
+//	error: リクエストの失敗時のエラー
+func (a *auditedBrowserClient) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	domain := requestDomain(rawURL)
+
+	requestedAt := time.Now()
+	body, err := a.BrowserClient.Fetch(ctx, rawURL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.recordRequest(domain, requestedAt, a.BrowserClient.LastStatus(), err)
+	a.stats[domain].bytes += int64(len(body))
+
+	return body, err
+}
+
+// CheckNotModifiedは、条件付きリクエストを送り、リクエスト先のドメインへのリクエストとして記録します。
+//
+// args:
+//
+//	rawURL: 確認するURL
+//	etag: 前回のレスポンスのETagヘッダー
+//	lastModified: 前回のレスポンスのLast-Modifiedヘッダー
+//
+// return:
+//
+//	bool: 変更がない場合はtrue
+//	error: リクエストの失敗時のエラー
+func (a *auditedBrowserClient) CheckNotModified(rawURL, etag, lastModified string) (bool, error) {
+	domain := requestDomain(rawURL)
+
+	requestedAt := time.Now()
+	notModified, err := a.BrowserClient.CheckNotModified(rawURL, etag, lastModified)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.recordRequest(domain, requestedAt, a.BrowserClient.LastStatus(), err)
+
+	return notModified, err
+}
+
+// FillAndSubmitは、検索フォームを送信し、直前にナビゲーションしたドメインへのリクエストとして記録します。
+// 送信先のステータスコードは取得できないため、送信に失敗した場合のみエラーとして記録します。
+func (a *auditedBrowserClient) FillAndSubmit(ctx context.Context, form config.SearchFormConfig) error {
+	requestedAt := time.Now()
+	err := a.BrowserClient.FillAndSubmit(ctx, form)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.recordRequest(a.currentDomain, requestedAt, 0, err)
+
+	return err
+}
+
+// requestDomainは、リクエスト先のURLから集計に使用するドメインを返します。URLを解釈できない場合はURLをそのまま返します。
+func requestDomain(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return rawURL
+}
+
+// recordRequestは、ドメインへのリクエストの間隔・件数・エラーを記録します。呼び出し側でロックを取得してください。
+func (a *auditedBrowserClient) recordRequest(domain string, requestedAt time.Time, status int, err error) {
+	stats, ok := a.stats[domain]
+	if !ok {
+		stats = &domainStats{}
+		a.stats[domain] = stats
+	}
+	if !stats.lastRequestAt.IsZero() {
+		interval := requestedAt.Sub(stats.lastRequestAt)
+		stats.intervalTotal += interval
+		stats.intervalCount++
+		if stats.intervalCount == 1 || interval < stats.minInterval {
+			stats.minInterval = interval
+		}
+	}
+	stats.lastRequestAt = requestedAt
+	stats.requests++
+	if err != nil || status >= http.StatusBadRequest {
+		stats.errors++
+	}
+	if status == http.StatusTooManyRequests {
+		stats.tooManyRequests++
+	}
+}
+
+// GetHTMLは、HTMLを取得し、直前にナビゲーションしたドメインの転送量として記録します。
+func (a *auditedBrowserClient) GetHTML() (string, error) {
+	html, err := a.BrowserClient.GetHTML()
+
+	a.mu.Lock()
+	if stats, ok := a.stats[a.currentDomain]; ok {
+		stats.bytes += int64(len(html))
+	}
+	a.mu.Unlock()
+
+	return html, err
+}
+
+// Reportは、現在までの集計結果をドメイン名順に返します。
+//
+// return:
+//
+//	PolitenessReport : 集計結果
+func (a *auditedBrowserClient) Report() PolitenessReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := PolitenessReport{
+		StartedAt:  a.startedAt,
+		FinishedAt: time.Now(),
+		Domains:    make([]DomainAudit, 0, len(a.stats)),
+	}
+	for domain, stats := range a.stats {
+		audit := DomainAudit{
+			Domain:          domain,
+			Requests:        stats.requests,
+			Errors:          stats.errors,
+			TooManyRequests: stats.tooManyRequests,
+			Bytes:           stats.bytes,
+		}
+		if stats.requests > 0 {
+			audit.ErrorRate = float64(stats.errors) / float64(stats.requests)
+		}
+		if stats.intervalCount > 0 {
+			audit.AvgIntervalSeconds = (stats.intervalTotal / time.Duration(stats.intervalCount)).Seconds()
+			audit.MinIntervalSeconds = stats.minInterval.Seconds()
+		}
+		report.Domains = append(report.Domains, audit)
+	}
+	sort.Slice(report.Domains, func(i, j int) bool {
+		return report.Domains[i].Domain < report.Domains[j].Domain
+	})
+
+	return report
+}
+
+// WritePolitenessReportは、集計結果をJSONファイルとして書き出します。
+// 書き込み途中のファイルが残らないよう、一時ファイルに書き込んでからリネームします。
+//
+// args:
+//
+//	path   : 出力先のパス
+//	report : 書き出す集計結果
+//
+// return:
+//
+//	error : 書き込みに失敗した場合のエラー
+func WritePolitenessReport(path string, report PolitenessReport) error {
+	file, err := createAtomicFile(path, false)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		file.Abort()
+		return fmt.Errorf("レポートの書き込みに失敗しました: %w", err)
+	}
+
+	return file.Commit()
+}
@@ -0,0 +1,83 @@
+package infra
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// BlockDetectorは、ボット検知のチャレンジページやCAPTCHAなど、アクセスを拒否されたページを判定します。
+// 多くのサイトはアクセスを拒否したページもHTTPステータス200で返すため、ページのテキストと要素から判定します。
+//
+// フィールド:
+//
+//	textPatterns : ページのタイトルまたはテキストに含まれる場合にブロックと判定する正規表現
+//	selectors    : 一致する要素がある場合にブロックと判定するCSSセレクター
+type BlockDetector struct {
+	textPatterns []*regexp.Regexp
+	selectors    []string
+}
+
+// NewBlockDetectorは、設定からBlockDetectorを生成します。判定条件が指定されていない場合はnilを返します。
+//
+// args:
+//
+//	cfg : ブロックの判定条件
+//
+// return:
+//
+//	*BlockDetector : 生成された判定器（判定条件がない場合はnil）
+//	error          : 正規表現の解釈に失敗した場合のエラー
+func NewBlockDetector(cfg config.BlockConfig) (*BlockDetector, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	d := &BlockDetector{selectors: cfg.Selectors}
+	for _, pattern := range cfg.TextPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("text_patternsの正規表現 %q を解釈できません: %w", pattern, err)
+		}
+		d.textPatterns = append(d.textPatterns, re)
+	}
+	return d, nil
+}
+
+// Detectは、ページがアクセスを拒否されたページかどうかを判定し、該当する場合はその理由を返します。
+//
+// args:
+//
+//	html : ページのHTML
+//
+// return:
+//
+//	string : ブロックと判定した理由（該当しない場合は空文字列）
+//	bool   : ブロックと判定した場合はtrue
+func (d *BlockDetector) Detect(html string) (string, bool) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", false
+	}
+
+	// CAPTCHAのiframeなどはスクリプトで挿入されることが多いため、要素の判定はスクリプトを取り除く前に行う
+	for _, selector := range d.selectors {
+		if doc.Find(selector).Length() > 0 {
+			return fmt.Sprintf("セレクター %s に一致する要素があります", selector), true
+		}
+	}
+
+	// スクリプトなどに含まれる文字列に反応しないよう、タイトルと表示されるテキストのみを判定に使用する
+	doc.Find("script, style, noscript").Remove()
+	text := doc.Find("title").Text() + "\n" + doc.Find("body").Text()
+	for _, re := range d.textPatterns {
+		if match := re.FindString(text); match != "" {
+			return fmt.Sprintf("ページに %q が含まれています", match), true
+		}
+	}
+
+	return "", false
+}
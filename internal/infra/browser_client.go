@@ -1,10 +1,17 @@
 package infra
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/nrad-K/go-crawler/internal/config"
 	"github.com/playwright-community/playwright-go"
@@ -13,22 +20,55 @@ import (
 // BrowserClientは、クローリングで利用するブラウザ操作のインターフェースです。
 type BrowserClient interface {
 	Click(selector string) error
+	ScrollToBottom() error
+	Press(selector, key string) error
+	FillAndSubmit(ctx context.Context, form config.SearchFormConfig) error
 	GetHTML() (string, error)
+	PDF() ([]byte, error)
 	SaveHTML(filename string, content string) error
+	SaveHTMLMetadata(filename string, meta HTMLMetadata) error
+	LastStatus() int
+	LastRedirects() []string
+	LastHeaders() map[string]string
 	CurrentURL() (*url.URL, error)
-	Navigate(url string) error
-	ExtractText(selector string) ([]string, error)
-	ExtractAttribute(selector, attr string) ([]string, error)
+	Navigate(ctx context.Context, url string) error
+	Fetch(ctx context.Context, url string) ([]byte, error)
+	CheckNotModified(url, etag, lastModified string) (bool, error)
+	LastHeader(name string) string
+	ExtractText(ctx context.Context, selector string) ([]string, error)
+	ExtractAttribute(ctx context.Context, selector, attr string) ([]string, error)
 	Exists(selector string) (bool, error)
 	Close() error
 }
 
+// BrowserRecyclesは、ブラウザのページとコンテキストを作り直した回数です。
+//
+// フィールド:
+//
+//	ByNavigations : ナビゲーションの回数が上限に達したため作り直した回数
+//	ByMemory      : メモリ使用量がしきい値を超えたため作り直した回数
+type BrowserRecycles struct {
+	ByNavigations int
+	ByMemory      int
+}
+
+// Totalは、作り直した回数の合計を返します。
+func (r BrowserRecycles) Total() int {
+	return r.ByNavigations + r.ByMemory
+}
+
 type browserClient struct {
-	pw      *playwright.Playwright
-	cfg     *config.CrawlerConfig
-	browser playwright.Browser
-	page    playwright.Page
-	context playwright.BrowserContext
+	pw          *playwright.Playwright
+	cfg         *config.CrawlerConfig
+	browser     playwright.Browser
+	page        playwright.Page
+	context     playwright.BrowserContext
+	cookies     []cookieRule
+	lastStatus  int
+	lastHeaders map[string]string
+	redirects   []string
+	navigations int
+	recycles    BrowserRecycles
 }
 
 // NewBrowserClientは、Playwrightを用いたbrowserClientを生成します。
@@ -47,30 +87,23 @@ func NewBrowserClient(cfg *config.CrawlerConfig) (*browserClient, error) {
 		return nil, fmt.Errorf("playwrightの起動に失敗しました: %w", err)
 	}
 
-	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
-		Headless: playwright.Bool(cfg.EnableHeadless),
-	})
+	cookies, err := compileCookieRules(cfg.Cookies)
 	if err != nil {
-		return nil, fmt.Errorf("ブラウザの起動に失敗しました: %w", err)
+		pw.Stop()
+		return nil, err
 	}
 
-	context, err := browser.NewContext(playwright.BrowserNewContextOptions{
-		ExtraHttpHeaders: cfg.Headers,
-		UserAgent:        &cfg.UserAgent,
-	})
+	browser, err := launchBrowser(pw, cfg)
 	if err != nil {
-		browser.Close()
 		pw.Stop()
-		return nil, fmt.Errorf("ブラウザコンテキストの作成に失敗しました: %w", err)
+		return nil, err
 	}
 
-	if err := setupResourceBlocking(context); err != nil {
-		return nil, fmt.Errorf("リソースブロックの設定に失敗しました: %w", err)
-	}
-
-	page, err := context.NewPage()
+	context, page, err := newBrowserPage(browser, cfg, nil)
 	if err != nil {
-		return nil, fmt.Errorf("ページの作成に失敗しました: %w", err)
+		browser.Close()
+		pw.Stop()
+		return nil, err
 	}
 
 	return &browserClient{
@@ -79,9 +112,125 @@ func NewBrowserClient(cfg *config.CrawlerConfig) (*browserClient, error) {
 		context: context,
 		page:    page,
 		cfg:     cfg,
+		cookies: cookies,
 	}, nil
 }
 
+// launchBrowserは、リモートブラウザが設定されている場合はそのエンドポイントに接続し、
+// 設定されていない場合はローカルでbrowser_engineのブラウザを起動します。
+//
+// args:
+//
+//	pw  : Playwrightのインスタンス
+//	cfg : クローラー設定
+//
+// return:
+//
+//	playwright.Browser : 起動または接続したブラウザ
+//	error              : 起動や接続に失敗した場合のエラー
+func launchBrowser(pw *playwright.Playwright, cfg *config.CrawlerConfig) (playwright.Browser, error) {
+	browserType := BrowserType(pw, cfg.BrowserEngineOrDefault())
+	remote := cfg.RemoteBrowser
+	if remote.Endpoint == "" {
+		browser, err := browserType.Launch(playwright.BrowserTypeLaunchOptions{
+			Headless: playwright.Bool(cfg.EnableHeadless),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ブラウザの起動に失敗しました: %w", err)
+		}
+		return browser, nil
+	}
+
+	timeout := playwright.Float(float64(cfg.CrawlTimeoutSeconds * 1000))
+	switch remote.Protocol {
+
+	case config.RemoteBrowserCDP:
+		browser, err := pw.Chromium.ConnectOverCDP(remote.Endpoint, playwright.BrowserTypeConnectOverCDPOptions{
+			Headers: remote.Headers,
+			Timeout: timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("CDPエンドポイント %s への接続に失敗しました: %w", remote.Endpoint, err)
+		}
+		return browser, nil
+
+	default:
+		browser, err := browserType.Connect(remote.Endpoint, playwright.BrowserTypeConnectOptions{
+			Headers: remote.Headers,
+			Timeout: timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Playwrightサーバー %s への接続に失敗しました: %w", remote.Endpoint, err)
+		}
+		return browser, nil
+	}
+}
+
+// BrowserTypeは、ブラウザのエンジンに対応するPlaywrightのBrowserTypeを返します。
+//
+// args:
+//
+//	pw     : Playwrightのインスタンス
+//	engine : ブラウザのエンジン
+//
+// return:
+//
+//	playwright.BrowserType : エンジンに対応するBrowserType（不明なエンジンの場合はChromium）
+func BrowserType(pw *playwright.Playwright, engine config.BrowserEngine) playwright.BrowserType {
+	switch engine {
+	case config.BrowserFirefox:
+		return pw.Firefox
+	case config.BrowserWebKit:
+		return pw.WebKit
+	default:
+		return pw.Chromium
+	}
+}
+
+// newBrowserPageは、設定に応じたブラウザコンテキストと、そのコンテキストのページを作成します。
+//
+// args:
+//
+//	browser : ブラウザ
+//	cfg     : クローラー設定
+//	state   : 新しいコンテキストに引き継ぐCookieとローカルストレージ（nilの場合は引き継がない）
+//
+// return:
+//
+//	playwright.BrowserContext : 作成したコンテキスト
+//	playwright.Page           : 作成したページ
+//	error                     : 作成に失敗した場合のエラー
+func newBrowserPage(browser playwright.Browser, cfg *config.CrawlerConfig, state *playwright.OptionalStorageState) (playwright.BrowserContext, playwright.Page, error) {
+	contextOptions := playwright.BrowserNewContextOptions{
+		ExtraHttpHeaders: cfg.Headers,
+		UserAgent:        &cfg.UserAgent,
+		StorageState:     state,
+	}
+	applyStealthOptions(&contextOptions, cfg.Stealth)
+
+	context, err := browser.NewContext(contextOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ブラウザコンテキストの作成に失敗しました: %w", err)
+	}
+
+	if err := setupStealthScripts(context, cfg.Stealth); err != nil {
+		context.Close()
+		return nil, nil, fmt.Errorf("ステルス用スクリプトの設定に失敗しました: %w", err)
+	}
+
+	if err := setupResourceBlocking(context); err != nil {
+		context.Close()
+		return nil, nil, fmt.Errorf("リソースブロックの設定に失敗しました: %w", err)
+	}
+
+	page, err := context.NewPage()
+	if err != nil {
+		context.Close()
+		return nil, nil, fmt.Errorf("ページの作成に失敗しました: %w", err)
+	}
+	return context, page, nil
+}
+
 func setupResourceBlocking(context playwright.BrowserContext) error {
 	return context.Route("**/*.{png,jpg,jpeg,gif,svg,woff,woff2,ttf,eot,otf}", func(route playwright.Route) {
 		route.Abort()
@@ -89,24 +238,354 @@ func setupResourceBlocking(context playwright.BrowserContext) error {
 }
 
 // Navigateは、指定したURLにブラウザを遷移させます。
+// URLに一致するCookieの設定ルールがある場合は、遷移前にそのCookieを設定します。
+// browser_recycleの条件を満たしている場合は、遷移前にページとコンテキストを作り直します。
+// タイムアウト・5xx・通信エラーの場合はretry_countの回数まで再試行し、404・410などは再試行しません。
 //
 // args:
 //
+//	ctx: 再試行の待機を中断するためのコンテキスト
 //	url: 遷移先のURL
 //
 // return:
 //
 //	error: 失敗時のエラー
-func (b *browserClient) Navigate(url string) error {
-	if _, err := b.page.Goto(url, playwright.PageGotoOptions{
-		Timeout:   playwright.Float(float64(b.cfg.CrawlTimeoutSeconds * 1000)),
-		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
-	}); err != nil {
-		return fmt.Errorf("ナビゲーションに失敗しました: %v", err)
+func (b *browserClient) Navigate(ctx context.Context, url string) error {
+	b.lastStatus = 0
+	b.lastHeaders = nil
+	b.redirects = nil
+	if err := b.recycleIfNeeded(); err != nil {
+		return err
+	}
+	humanDelay(b.cfg.Stealth)
+	if err := rotateUserAgent(b.page, b.cfg.Headers, b.cfg.Stealth); err != nil {
+		return fmt.Errorf("User-Agentの切り替えに失敗しました: %w", err)
+	}
+	if cookies := cookiesForURL(b.cookies, url); len(cookies) > 0 {
+		if err := b.context.AddCookies(cookies); err != nil {
+			return fmt.Errorf("Cookieの設定に失敗しました: %w", err)
+		}
+	}
+
+	return b.retry(ctx, func() error {
+		b.lastStatus = 0
+		b.lastHeaders = nil
+		b.redirects = nil
+		b.navigations++
+		response, err := b.page.Goto(url, playwright.PageGotoOptions{
+			Timeout:   playwright.Float(float64(b.cfg.CrawlTimeoutSeconds * 1000)),
+			WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+		})
+		if err != nil {
+			return fmt.Errorf("ナビゲーションに失敗しました: %w", err)
+		}
+		// 同一ドキュメント内のアンカー遷移などではレスポンスがnilになる
+		if response != nil {
+			b.lastStatus = response.Status()
+			b.lastHeaders = response.Headers()
+			b.redirects = redirectChain(response)
+		}
+		return nil
+	})
+}
+
+// redirectChainは、レスポンスに至るまでにリダイレクトされたURLを、最初のリクエストのURLから最終的なURLまで順に返します。
+//
+// args:
+//
+//	response: 最終的なレスポンス
+//
+// return:
+//
+//	[]string: リダイレクトされたURLの一覧（リダイレクトされていない場合はnil）
+func redirectChain(response playwright.Response) []string {
+	var chain []string
+	for request := response.Request().RedirectedFrom(); request != nil; request = request.RedirectedFrom() {
+		chain = append(chain, request.URL())
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	slices.Reverse(chain)
+	return append(chain, response.URL())
+}
+
+// retryは、リクエストを送る処理を実行し、一時的な失敗の場合はretry_countの回数まで待機時間を2倍にしながら再試行します。
+// 失敗の種類は、直前に受け取ったステータスコードと処理のエラーから判定します。
+// 5xxの場合は、再試行しても失敗した時点のステータスコードを残したまま、処理の結果を返します。
+// 再試行を待機している間にコンテキストがキャンセルされた場合は、直前の処理の結果を返します。
+//
+// args:
+//
+//	ctx: 再試行の待機を中断するためのコンテキスト
+//	attempt: 1回分のリクエストを送る処理（受け取ったステータスコードをlastStatusに記録する）
+//
+// return:
+//
+//	error: 再試行の対象でない失敗の場合、または再試行しても失敗した場合のエラー
+func (b *browserClient) retry(ctx context.Context, attempt func() error) error {
+	backoff := b.cfg.Retry.Backoff()
+	for retries := 0; ; retries++ {
+		err := attempt()
+		condition, ok := retryCondition(b.lastStatus, err)
+		if !ok || retries >= b.cfg.RetryCount || !b.cfg.Retry.RetriesOn(condition) {
+			if err != nil && retries > 0 {
+				return fmt.Errorf("%d回再試行しましたが失敗しました: %w", retries, err)
+			}
+			return err
+		}
+		if waitErr := sleepContext(ctx, backoff); waitErr != nil {
+			if err != nil {
+				return fmt.Errorf("再試行の待機中に中断されました: %w", err)
+			}
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// sleepContextは、指定した時間だけ待機します。待機中にコンテキストがキャンセルされた場合は、待機を中断してコンテキストのエラーを返します。
+//
+// args:
+//
+//	ctx: 待機を中断するためのコンテキスト
+//	d: 待機する時間
+//
+// return:
+//
+//	error: コンテキストがキャンセルされた場合のエラー
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryConditionは、ステータスコードとエラーから、再試行できる一時的な失敗の種類を判定します。
+// レスポンスを受け取った場合は5xxのみを一時的な失敗とし、404・410などは再試行しても結果が変わらないものとして扱います。
+//
+// args:
+//
+//	status: 受け取ったステータスコード（レスポンスを受け取っていない場合は0）
+//	err: リクエストのエラー
+//
+// return:
+//
+//	config.RetryCondition: 失敗の種類
+//	bool: 一時的な失敗の場合はtrue
+func retryCondition(status int, err error) (config.RetryCondition, bool) {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return config.RetryOnServerError, true
+	case status != 0 || err == nil:
+		return "", false
+	case errors.Is(err, playwright.ErrTargetClosed):
+		// ページやブラウザが閉じられた場合は、再試行しても成功しない
+		return "", false
+	case errors.Is(err, playwright.ErrTimeout):
+		return config.RetryOnTimeout, true
+	default:
+		return config.RetryOnNetwork, true
+	}
+}
+
+// recycleIfNeededは、前回作り直してからのナビゲーションの回数、またはブラウザのメモリ使用量が設定の上限を超えている場合に、
+// ページとコンテキストを作り直します。長時間の実行でページに蓄積したメモリを解放するために使用します。
+// メモリ使用量は、ローカルで起動したブラウザをLinux上で実行している場合のみ確認します。
+//
+// return:
+//
+//	error: 作り直しに失敗した場合のエラー
+func (b *browserClient) recycleIfNeeded() error {
+	recycle := b.cfg.BrowserRecycle
+	byMemory := false
+	switch {
+	case recycle.EveryNavigations > 0 && b.navigations >= recycle.EveryNavigations:
+	case recycle.MaxRSSBytes() > 0 && b.cfg.RemoteBrowser.Endpoint == "" && b.navigations > 0:
+		// 確認できない環境では作り直さない
+		rss, err := childProcessRSS(os.Getpid())
+		if err != nil || rss <= recycle.MaxRSSBytes() {
+			return nil
+		}
+		byMemory = true
+	default:
+		return nil
+	}
+
+	// ログインなどのセッションを失わないよう、Cookieとローカルストレージを引き継ぐ
+	state, err := b.context.StorageState()
+	if err != nil {
+		return fmt.Errorf("ブラウザのコンテキストの状態の取得に失敗しました: %w", err)
+	}
+	context, page, err := newBrowserPage(b.browser, b.cfg, state.ToOptionalStorageState())
+	if err != nil {
+		return fmt.Errorf("ブラウザのコンテキストの作り直しに失敗しました: %w", err)
+	}
+	if err := b.context.Close(); err != nil {
+		context.Close()
+		return fmt.Errorf("古いブラウザのコンテキストのクローズに失敗しました: %w", err)
+	}
+
+	b.context = context
+	b.page = page
+	b.navigations = 0
+	if byMemory {
+		b.recycles.ByMemory++
+	} else {
+		b.recycles.ByNavigations++
 	}
 	return nil
 }
 
+// Recyclesは、ページとコンテキストを作り直した回数を返します。
+//
+// return:
+//
+//	BrowserRecycles: 条件ごとの作り直した回数
+func (b *browserClient) Recycles() BrowserRecycles {
+	return b.recycles
+}
+
+// Fetchは、ページを遷移せずに指定したURLへGETリクエストを送り、レスポンスのボディを返します。
+// ブラウザのコンテキストのCookieとヘッダーを使用するため、一覧ページと同じセッションでAPIを呼び出せます。
+// Navigateと同様に、一時的な失敗はretry_countの回数まで再試行します。
+//
+// args:
+//
+//	ctx: 再試行の待機を中断するためのコンテキスト
+//	url: リクエスト先のURL
+//
+// return:
+//
+//	[]byte: レスポンスのボディ
+//	error: 失敗時、またはステータスコードが2xx以外の場合のエラー
+func (b *browserClient) Fetch(ctx context.Context, url string) ([]byte, error) {
+	b.lastStatus = 0
+	humanDelay(b.cfg.Stealth)
+	if cookies := cookiesForURL(b.cookies, url); len(cookies) > 0 {
+		if err := b.context.AddCookies(cookies); err != nil {
+			return nil, fmt.Errorf("Cookieの設定に失敗しました: %w", err)
+		}
+	}
+
+	var body []byte
+	err := b.retry(ctx, func() error {
+		b.lastStatus = 0
+		response, err := b.context.Request().Get(url, playwright.APIRequestContextGetOptions{
+			Timeout: playwright.Float(float64(b.cfg.CrawlTimeoutSeconds * 1000)),
+		})
+		if err != nil {
+			return fmt.Errorf("リクエストに失敗しました: %w", err)
+		}
+		defer response.Dispose()
+
+		b.lastStatus = response.Status()
+		if !response.Ok() {
+			return fmt.Errorf("ステータスコード %d が返されました", response.Status())
+		}
+
+		body, err = response.Body()
+		if err != nil {
+			return fmt.Errorf("レスポンスの読み込みに失敗しました: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// CheckNotModifiedは、ETagとLast-Modifiedを指定した条件付きリクエストを送り、前回の取得からページが変更されていないかを確認します。
+// ページを遷移せずに確認するため、変更がない場合はページの描画を省略できます。
+//
+// args:
+//
+//	url: 確認するURL
+//	etag: 前回のレスポンスのETagヘッダー（空文字列の場合は指定しない）
+//	lastModified: 前回のレスポンスのLast-Modifiedヘッダー（空文字列の場合は指定しない）
+//
+// return:
+//
+//	bool: ステータスコード304（変更なし）が返された場合はtrue
+//	error: リクエストの失敗時のエラー
+func (b *browserClient) CheckNotModified(url, etag, lastModified string) (bool, error) {
+	b.lastStatus = 0
+	humanDelay(b.cfg.Stealth)
+	if cookies := cookiesForURL(b.cookies, url); len(cookies) > 0 {
+		if err := b.context.AddCookies(cookies); err != nil {
+			return false, fmt.Errorf("Cookieの設定に失敗しました: %w", err)
+		}
+	}
+
+	headers := make(map[string]string, 2)
+	if etag != "" {
+		headers["If-None-Match"] = etag
+	}
+	if lastModified != "" {
+		headers["If-Modified-Since"] = lastModified
+	}
+	response, err := b.context.Request().Get(url, playwright.APIRequestContextGetOptions{
+		Headers: headers,
+		Timeout: playwright.Float(float64(b.cfg.CrawlTimeoutSeconds * 1000)),
+	})
+	if err != nil {
+		return false, fmt.Errorf("条件付きリクエストに失敗しました: %w", err)
+	}
+	defer response.Dispose()
+
+	b.lastStatus = response.Status()
+	return response.Status() == http.StatusNotModified, nil
+}
+
+// LastHeaderは、直前のNavigateで受け取ったレスポンスのヘッダーの値を返します。ヘッダーがない場合は空文字列を返します。
+//
+// args:
+//
+//	name: ヘッダー名（大文字と小文字は区別しない）
+//
+// return:
+//
+//	string: ヘッダーの値
+func (b *browserClient) LastHeader(name string) string {
+	return b.lastHeaders[strings.ToLower(name)]
+}
+
+// LastRedirectsは、直前のNavigateでリダイレクトされたURLを、遷移を指定したURLから最終的なURLまで順に返します。
+// リダイレクトされなかった場合はnilを返します。
+//
+// return:
+//
+//	[]string: リダイレクトされたURLの一覧
+func (b *browserClient) LastRedirects() []string {
+	return slices.Clone(b.redirects)
+}
+
+// LastHeadersは、直前のNavigateで受け取ったレスポンスのヘッダーを返します。ヘッダー名は小文字です。
+// レスポンスを受け取っていない場合はnilを返します。
+//
+// return:
+//
+//	map[string]string: ヘッダー名と値
+func (b *browserClient) LastHeaders() map[string]string {
+	return maps.Clone(b.lastHeaders)
+}
+
+// LastStatusは、直前のNavigate・Fetch・CheckNotModifiedで受け取ったHTTPステータスコードを返します。
+// レスポンスを受け取っていない場合は0を返します。
+//
+// args: なし
+// return:
+//
+//	int: HTTPステータスコード
+func (b *browserClient) LastStatus() int {
+	return b.lastStatus
+}
+
 // Clickは、指定したセレクタの要素をクリックします。
 //
 // args:
@@ -121,13 +600,110 @@ func (b *browserClient) Click(selector string) error {
 	if err := locator.WaitFor(); err != nil {
 		return fmt.Errorf("セレクター '%s' の可視状態待機に失敗しました: %w", selector, err)
 	}
+	humanDelay(b.cfg.Stealth)
 	if err := locator.Click(); err != nil {
 		return fmt.Errorf("%sのクリックに失敗しました: %w", selector, err)
 	}
 	return nil
 }
 
+// ScrollToBottomは、ページの末尾までスクロールします。
+// スクロールによって遅延読み込みされる要素を表示させるために使用します。
+//
+// return:
+//
+//	error: 失敗時のエラー
+func (b *browserClient) ScrollToBottom() error {
+	humanDelay(b.cfg.Stealth)
+	if _, err := b.page.Evaluate("() => window.scrollTo(0, document.body.scrollHeight)"); err != nil {
+		return fmt.Errorf("ページの末尾へのスクロールに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Pressは、指定したキーを押します。
+//
+// args:
+//
+//	selector: キーを押す要素のCSSセレクタ（空文字列の場合はページ全体）
+//	key: 押すキー（例: End, Enter）
+//
+// return:
+//
+//	error: 失敗時のエラー
+func (b *browserClient) Press(selector, key string) error {
+	humanDelay(b.cfg.Stealth)
+	if selector == "" {
+		if err := b.page.Keyboard().Press(key); err != nil {
+			return fmt.Errorf("キー %s の入力に失敗しました: %w", key, err)
+		}
+		return nil
+	}
+	if err := b.page.Locator(selector).First().Press(key); err != nil {
+		return fmt.Errorf("%sでのキー %s の入力に失敗しました: %w", selector, key, err)
+	}
+	return nil
+}
+
+// FillAndSubmitは、検索フォームの項目を記載した順に入力して送信し、一覧が表示されるまで待機します。
+// 送信ボタンのセレクターが未指定の場合は、最後に入力した項目でEnterキーを押して送信します。
+// 送信後にページ遷移するフォームとXHRで一覧を書き換えるフォームのどちらにも対応するため、読み込みを待った後に指定した時間だけ待機します。
+//
+// args:
+//
+//	ctx: 一覧の表示の待機を中断するためのコンテキスト
+//	form: 入力・送信する検索フォーム
+//
+// return:
+//
+//	error: 失敗時、または待機中に中断された場合のエラー
+func (b *browserClient) FillAndSubmit(ctx context.Context, form config.SearchFormConfig) error {
+	for _, field := range form.Fields {
+		locator := b.page.Locator(field.Selector).First()
+		if err := locator.WaitFor(); err != nil {
+			return fmt.Errorf("セレクター '%s' の可視状態待機に失敗しました: %w", field.Selector, err)
+		}
+		humanDelay(b.cfg.Stealth)
+		switch field.TypeOrDefault() {
+		case config.FormFieldSelect:
+			if _, err := locator.SelectOption(playwright.SelectOptionValues{ValuesOrLabels: &[]string{field.Value}}); err != nil {
+				return fmt.Errorf("%sの選択肢 %s の選択に失敗しました: %w", field.Selector, field.Value, err)
+			}
+		case config.FormFieldCheck:
+			if err := locator.SetChecked(field.Value != "false"); err != nil {
+				return fmt.Errorf("%sのチェックの変更に失敗しました: %w", field.Selector, err)
+			}
+		default:
+			if err := locator.Fill(field.Value); err != nil {
+				return fmt.Errorf("%sへの入力に失敗しました: %w", field.Selector, err)
+			}
+		}
+	}
+
+	if form.SubmitSelector != "" {
+		if err := b.Click(form.SubmitSelector); err != nil {
+			return fmt.Errorf("検索フォームの送信に失敗しました: %w", err)
+		}
+	} else {
+		last := form.Fields[len(form.Fields)-1].Selector
+		if err := b.Press(last, "Enter"); err != nil {
+			return fmt.Errorf("検索フォームの送信に失敗しました: %w", err)
+		}
+	}
+
+	if err := b.page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+		State: playwright.LoadStateDomcontentloaded,
+	}); err != nil {
+		return fmt.Errorf("検索結果の読み込み待機に失敗しました: %w", err)
+	}
+	if err := sleepContext(ctx, form.Wait()); err != nil {
+		return fmt.Errorf("検索結果の表示の待機中に中断されました: %w", err)
+	}
+	return nil
+}
+
 // GetHTMLは、現在のページのHTMLを取得します。
+// pierceが有効な場合は、openなshadow rootと同一オリジンのiframeの内容を設定に応じて含めたHTMLを返します。
 //
 // args: なし
 // return:
@@ -140,6 +716,9 @@ func (b *browserClient) GetHTML() (string, error) {
 	}); err != nil {
 		return "", fmt.Errorf("ページ読み込み待機に失敗しました: %w", err)
 	}
+	if b.cfg.Pierce.Enabled() {
+		return b.piercingHTML()
+	}
 	html, err := b.page.Content()
 	if err != nil {
 		return "", fmt.Errorf("ページコンテンツの取得に失敗しました: %w", err)
@@ -147,6 +726,24 @@ func (b *browserClient) GetHTML() (string, error) {
 	return html, nil
 }
 
+// PDFは、現在のページを印刷用のレイアウトで描画し、A4サイズでページ分割したPDFを返します。
+// 背景色や画像も含めて、ブラウザで表示した状態に近い見た目で出力します。Chromiumのみが対応しています。
+//
+// return:
+//
+//	[]byte: PDFの内容
+//	error: 失敗時のエラー
+func (b *browserClient) PDF() ([]byte, error) {
+	pdf, err := b.page.PDF(playwright.PagePdfOptions{
+		Format:          playwright.String("A4"),
+		PrintBackground: playwright.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PDFの出力に失敗しました: %w", err)
+	}
+	return pdf, nil
+}
+
 // SaveHTMLは、HTMLをファイルに保存します。
 //
 // args:
@@ -158,7 +755,7 @@ func (b *browserClient) GetHTML() (string, error) {
 //
 //	error: 失敗時のエラー
 func (b *browserClient) SaveHTML(filename string, content string) error {
-	filePath := filepath.Join(b.cfg.OutputDir, filename)
+	filePath := filepath.Join(b.cfg.OutputDir, SafeFileName(filename))
 	if err := os.MkdirAll(b.cfg.OutputDir, os.ModePerm); err != nil {
 		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
 	}
@@ -170,6 +767,43 @@ func (b *browserClient) SaveHTML(filename string, content string) error {
 	return nil
 }
 
+// SavePDFは、詳細ページのPDFをファイルに保存します。
+//
+// args:
+//
+//	filename: 保存ファイル名
+//	content: PDFの内容
+//
+// return:
+//
+//	error: 失敗時のエラー
+func (b *browserClient) SavePDF(filename string, content []byte) error {
+	if err := os.MkdirAll(b.cfg.OutputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(b.cfg.OutputDir, SafeFileName(filename)), content, 0o644); err != nil {
+		return fmt.Errorf("PDFファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// SaveHTMLMetadataは、保存したHTMLファイルに対応するメタデータをサイドカーファイルとして保存します。
+//
+// args:
+//
+//	filename: 対応するHTMLファイル名
+//	meta: 保存するメタデータ
+//
+// return:
+//
+//	error: 失敗時のエラー
+func (b *browserClient) SaveHTMLMetadata(filename string, meta HTMLMetadata) error {
+	if err := os.MkdirAll(b.cfg.OutputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+	return writeHTMLMetadata(MetadataPath(filepath.Join(b.cfg.OutputDir, SafeFileName(filename))), meta)
+}
+
 // CurrentURLは、現在のページのURLを返します。
 //
 // args: なし
@@ -187,6 +821,7 @@ func (b *browserClient) CurrentURL() (*url.URL, error) {
 }
 
 // Closeは、ブラウザとPlaywrightインスタンスを閉じます。
+// リモートブラウザに接続している場合は、接続を切断します。
 //
 // args: なし
 // return:
@@ -208,23 +843,21 @@ func (b *browserClient) Close() error {
 }
 
 // ExtractTextは、指定したセレクタに一致する要素のテキストを抽出します。
+// pierce.iframesが有効な場合は、同一オリジンのiframeの中の要素も対象にします。
 //
 // args:
 //
+//	ctx: iframeの中の要素の待機を中断するためのコンテキスト
 //	selector: CSSセレクタ
 //
 // return:
 //
 //	[]string: テキストのリスト
 //	error: 失敗時のエラー
-func (b *browserClient) ExtractText(selector string) ([]string, error) {
-	locator := b.page.Locator(selector)
-	if err := locator.First().WaitFor(); err != nil {
-		return nil, fmt.Errorf("テキスト抽出前のセレクター待機に失敗しました: %w", err)
-	}
-	entries, err := locator.All()
+func (b *browserClient) ExtractText(ctx context.Context, selector string) ([]string, error) {
+	entries, err := b.locateAll(ctx, selector)
 	if err != nil {
-		return nil, fmt.Errorf("エントリの取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("テキスト抽出前の要素の取得に失敗しました: %w", err)
 	}
 
 	texts := make([]string, 0, len(entries))
@@ -241,9 +874,11 @@ func (b *browserClient) ExtractText(selector string) ([]string, error) {
 }
 
 // ExtractAttributeは、指定したセレクタに一致する要素から属性値を抽出します。
+// pierce.iframesが有効な場合は、同一オリジンのiframeの中の要素も対象にします。
 //
 // args:
 //
+//	ctx: iframeの中の要素の待機を中断するためのコンテキスト
 //	selector: CSSセレクタ
 //	attr: 属性名
 //
@@ -251,14 +886,10 @@ func (b *browserClient) ExtractText(selector string) ([]string, error) {
 //
 //	[]string: 属性値のリスト
 //	error: 失敗時のエラー
-func (b *browserClient) ExtractAttribute(selector string, attr string) ([]string, error) {
-	locator := b.page.Locator(selector)
-	if err := locator.First().WaitFor(); err != nil {
-		return nil, fmt.Errorf("属性抽出前のセレクター待機に失敗しました: %w", err)
-	}
-	entries, err := locator.All()
+func (b *browserClient) ExtractAttribute(ctx context.Context, selector string, attr string) ([]string, error) {
+	entries, err := b.locateAll(ctx, selector)
 	if err != nil {
-		return nil, fmt.Errorf("エントリの取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("属性抽出前の要素の取得に失敗しました: %w", err)
 	}
 
 	values := make([]string, 0, len(entries))
@@ -0,0 +1,162 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// framePollIntervalは、iframeの中の要素を含めて要素が表示されるのを待つ際に、要素を探し直す間隔です。
+const framePollInterval = 200 * time.Millisecond
+
+// piercingHTMLScriptは、openなshadow rootの内容と同一オリジンのiframeの内容を含めてページのHTMLを組み立てるスクリプトです。
+// shadow rootの内容はDeclarative Shadow DOMの<template shadowrootmode="open">として、
+// iframeの内容はiframe要素の直後の<div data-iframe-src="...">の中に、iframeのbodyの内容として出力します。
+// いずれもHTMLのパーサーで通常の要素として解釈できるため、スクレイパーのセレクターでそのまま抽出できます。
+const piercingHTMLScript = `(opts) => {
+	const voidTags = new Set(['area', 'base', 'br', 'col', 'embed', 'hr', 'img', 'input', 'link', 'meta', 'param', 'source', 'track', 'wbr']);
+	const rawTextTags = new Set(['script', 'style', 'textarea', 'title', 'xmp', 'iframe', 'noembed', 'noframes', 'noscript', 'plaintext']);
+	const escapeText = (s) => s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+	const escapeAttr = (s) => s.replace(/&/g, '&amp;').replace(/"/g, '&quot;');
+	const children = (node) => {
+		let html = '';
+		for (const child of node.childNodes) {
+			html += serialize(child, node);
+		}
+		return html;
+	};
+	const serialize = (node, parent) => {
+		switch (node.nodeType) {
+		case Node.TEXT_NODE:
+			return parent && rawTextTags.has(parent.localName) ? node.data : escapeText(node.data);
+		case Node.COMMENT_NODE:
+			return '<!--' + node.data + '-->';
+		case Node.ELEMENT_NODE:
+			break;
+		default:
+			return '';
+		}
+		const tag = node.localName;
+		let html = '<' + tag;
+		for (const attr of node.attributes) {
+			html += ' ' + attr.name + '="' + escapeAttr(attr.value) + '"';
+		}
+		html += '>';
+		if (voidTags.has(tag)) {
+			return html;
+		}
+		if (opts.shadowDOM && node.shadowRoot) {
+			html += '<template shadowrootmode="open">' + children(node.shadowRoot) + '</template>';
+		}
+		html += children(tag === 'template' ? node.content : node) + '</' + tag + '>';
+		// 別オリジンのiframeはcontentDocumentがnullになるため含めない
+		if (opts.iframes && tag === 'iframe' && node.contentDocument && node.contentDocument.documentElement) {
+			const doc = node.contentDocument;
+			html += '<div data-iframe-src="' + escapeAttr(node.src) + '">' + children(doc.body || doc.documentElement) + '</div>';
+		}
+		return html;
+	};
+	const doctype = document.doctype ? '<!DOCTYPE ' + document.doctype.name + '>' : '';
+	return doctype + serialize(document.documentElement, null);
+}`
+
+// locateAllは、セレクターに一致する要素が表示されるまで待機し、一致したすべての要素を返します。
+// pierce.iframesが有効な場合は、同一オリジンのiframeの中の要素も含めます。
+//
+// args:
+//
+//	ctx: iframeの中の要素の待機を中断するためのコンテキスト
+//	selector: CSSセレクタ
+//
+// return:
+//
+//	[]playwright.Locator: 一致した要素
+//	error: 待機または取得に失敗した場合のエラー
+func (b *browserClient) locateAll(ctx context.Context, selector string) ([]playwright.Locator, error) {
+	if b.cfg.Pierce.Iframes {
+		return b.locateAllInFrames(ctx, selector)
+	}
+
+	locator := b.page.Locator(selector)
+	if err := locator.First().WaitFor(); err != nil {
+		return nil, fmt.Errorf("セレクター '%s' の待機に失敗しました: %w", selector, err)
+	}
+	entries, err := locator.All()
+	if err != nil {
+		return nil, fmt.Errorf("エントリの取得に失敗しました: %w", err)
+	}
+	return entries, nil
+}
+
+// locateAllInFramesは、ページと同一オリジンのiframeのいずれかにセレクターに一致する要素が現れるまで待機し、
+// すべてのフレームで一致した要素をページ、iframeの順に返します。
+// iframeは遅れて読み込まれることが多いため、タイムアウトまでフレームを探し直します。コンテキストがキャンセルされた場合は探し直すのをやめます。
+func (b *browserClient) locateAllInFrames(ctx context.Context, selector string) ([]playwright.Locator, error) {
+	deadline := time.Now().Add(time.Duration(b.cfg.CrawlTimeoutSeconds) * time.Second)
+	for {
+		var entries []playwright.Locator
+		for _, frame := range b.sameOriginFrames() {
+			found, err := frame.Locator(selector).All()
+			if err != nil {
+				// 探している間に破棄されたiframeは読み飛ばす
+				continue
+			}
+			entries = append(entries, found...)
+		}
+		if len(entries) > 0 {
+			return entries, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("セレクター '%s' に一致する要素がページとiframeのいずれにも見つかりませんでした: %w", selector, playwright.ErrTimeout)
+		}
+		if err := sleepContext(ctx, framePollInterval); err != nil {
+			return nil, fmt.Errorf("セレクター '%s' に一致する要素の待機中に中断されました: %w", selector, err)
+		}
+	}
+}
+
+// sameOriginFramesは、メインフレームと、メインフレームと同一オリジンのiframe（about:blankなどを含む）を返します。
+func (b *browserClient) sameOriginFrames() []playwright.Frame {
+	main := b.page.MainFrame()
+	frames := []playwright.Frame{main}
+	origin := urlOrigin(main.URL())
+	for _, frame := range b.page.Frames() {
+		if frame == main {
+			continue
+		}
+		frameURL := frame.URL()
+		if strings.HasPrefix(frameURL, "about:") || (origin != "" && urlOrigin(frameURL) == origin) {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// urlOriginは、URLのスキームとホストからオリジンを返します。URLを解釈できない場合は空文字列を返します。
+func urlOrigin(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// piercingHTMLは、openなshadow rootと同一オリジンのiframeの内容を設定に応じて含めたページのHTMLを返します。
+func (b *browserClient) piercingHTML() (string, error) {
+	result, err := b.page.Evaluate(piercingHTMLScript, map[string]any{
+		"shadowDOM": b.cfg.Pierce.ShadowDOM,
+		"iframes":   b.cfg.Pierce.Iframes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("shadow DOMとiframeを含めたHTMLの組み立てに失敗しました: %w", err)
+	}
+	html, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("shadow DOMとiframeを含めたHTMLの組み立て結果が文字列ではありません: %T", result)
+	}
+	return html, nil
+}
@@ -0,0 +1,161 @@
+package infra
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// parseCacheは、解析結果を入力文字列ごとに保持するキャッシュです。
+// 保持する件数が上限に達した場合は、すべての結果を破棄してから新しい結果を保持します。
+//
+// フィールド:
+//
+//	entries    : 入力文字列ごとの解析結果
+//	maxEntries : 保持する解析結果の上限
+type parseCache[V any] struct {
+	mu         sync.Mutex
+	entries    map[string]parseResult[V]
+	maxEntries int
+}
+
+// parseResultは、キャッシュに保持する解析結果とエラーです。
+type parseResult[V any] struct {
+	value V
+	err   error
+}
+
+// newParseCacheは、parseCacheの新しいインスタンスを生成します。
+func newParseCache[V any](maxEntries int) *parseCache[V] {
+	return &parseCache[V]{
+		entries:    make(map[string]parseResult[V]),
+		maxEntries: maxEntries,
+	}
+}
+
+// getは、入力文字列に対応する解析結果を返します。キャッシュにない場合はparseで解析して保持します。
+// 解析はロックの外で行うため、同じ入力を複数のワーカーが同時に解析する場合があります。
+//
+// args:
+//
+//	input : 解析対象の文字列
+//	parse : 解析処理
+//
+// return:
+//
+//	V     : 解析結果
+//	error : 解析処理が返したエラー
+func (c *parseCache[V]) get(input string, parse func() (V, error)) (V, error) {
+	c.mu.Lock()
+	result, ok := c.entries[input]
+	c.mu.Unlock()
+	if ok {
+		return result.value, result.err
+	}
+
+	value, err := parse()
+
+	c.mu.Lock()
+	if len(c.entries) >= c.maxEntries {
+		clear(c.entries)
+	}
+	c.entries[input] = parseResult[V]{value: value, err: err}
+	c.mu.Unlock()
+
+	return value, err
+}
+
+// cachedJobPostingParserは、JobPostingParserをラップし、解析に時間のかかるメソッドの結果をキャッシュするJobPostingParserの実装です。
+// 同じ会社の求人では給与や勤務地などの文字列が繰り返し現れるため、大規模なサイトのスクレイプを高速化できます。
+// 解析結果には原文が含まれるため、キャッシュのキーには入力文字列をそのまま使用します。
+// キャッシュ対象外のメソッドはラップしたパーサーにそのまま委譲します。
+//
+// フィールド:
+//
+//	JobPostingParser : ラップするパーサー
+//	salary           : ParseSalaryDetailsの結果
+//	location         : ParseLocationの結果
+//	locations        : ParseLocationsの結果
+//	workHours        : ParseWorkHoursの結果
+//	benefits         : ParseBenefitsの結果
+//	nearestStation   : ParseNearestStationの結果
+type cachedJobPostingParser struct {
+	JobPostingParser
+	salary         *parseCache[model.Salary]
+	location       *parseCache[model.Location]
+	locations      *parseCache[[]model.Location]
+	workHours      *parseCache[[]model.WorkShift]
+	benefits       *parseCache[model.Benefits]
+	nearestStation *parseCache[model.NearestStation]
+}
+
+// NewCachedJobPostingParserは、cachedJobPostingParserの新しいインスタンスを生成します。
+//
+// args:
+//
+//	parser     : ラップするパーサー
+//	maxEntries : メソッドごとに保持する解析結果の上限
+//
+// return:
+//
+//	*cachedJobPostingParser : 生成されたパーサー
+func NewCachedJobPostingParser(parser JobPostingParser, maxEntries int) *cachedJobPostingParser {
+	return &cachedJobPostingParser{
+		JobPostingParser: parser,
+		salary:           newParseCache[model.Salary](maxEntries),
+		location:         newParseCache[model.Location](maxEntries),
+		locations:        newParseCache[[]model.Location](maxEntries),
+		workHours:        newParseCache[[]model.WorkShift](maxEntries),
+		benefits:         newParseCache[model.Benefits](maxEntries),
+		nearestStation:   newParseCache[model.NearestStation](maxEntries),
+	}
+}
+
+// ParseSalaryDetailsは、給与の解析結果をキャッシュから返します。
+func (c *cachedJobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, error) {
+	return c.salary.get(salaryStr, func() (model.Salary, error) {
+		return c.JobPostingParser.ParseSalaryDetails(salaryStr)
+	})
+}
+
+// ParseLocationは、勤務地の解析結果をキャッシュから返します。
+func (c *cachedJobPostingParser) ParseLocation(location string) (model.Location, error) {
+	return c.location.get(location, func() (model.Location, error) {
+		return c.JobPostingParser.ParseLocation(location)
+	})
+}
+
+// ParseLocationsは、複数の勤務地の解析結果をキャッシュから返します。
+// 呼び出し側での変更がキャッシュに影響しないよう、スライスは複製して返します。
+func (c *cachedJobPostingParser) ParseLocations(location string) ([]model.Location, error) {
+	locations, err := c.locations.get(location, func() ([]model.Location, error) {
+		return c.JobPostingParser.ParseLocations(location)
+	})
+	return slices.Clone(locations), err
+}
+
+// ParseWorkHoursは、勤務時間の解析結果をキャッシュから返します。
+// 呼び出し側での変更がキャッシュに影響しないよう、スライスは複製して返します。
+func (c *cachedJobPostingParser) ParseWorkHours(workHoursStr string) []model.WorkShift {
+	shifts, _ := c.workHours.get(workHoursStr, func() ([]model.WorkShift, error) {
+		return c.JobPostingParser.ParseWorkHours(workHoursStr), nil
+	})
+	return slices.Clone(shifts)
+}
+
+// ParseBenefitsは、福利厚生の解析結果をキャッシュから返します。
+func (c *cachedJobPostingParser) ParseBenefits(benefitsStr string) model.Benefits {
+	benefits, _ := c.benefits.get(benefitsStr, func() (model.Benefits, error) {
+		return c.JobPostingParser.ParseBenefits(benefitsStr), nil
+	})
+	return benefits
+}
+
+// ParseNearestStationは、最寄り駅の解析結果をキャッシュから返します。
+func (c *cachedJobPostingParser) ParseNearestStation(accessStr string) model.NearestStation {
+	station, _ := c.nearestStation.get(accessStr, func() (model.NearestStation, error) {
+		return c.JobPostingParser.ParseNearestStation(accessStr), nil
+	})
+	return station
+}
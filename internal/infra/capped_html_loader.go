@@ -0,0 +1,259 @@
+package infra
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// ErrHTMLTruncatedは、HTMLが上限サイズを超えたため途中までしか読み込まれなかったことを示すエラーです。
+// このエラーを返す場合も、上限までに読み込んだHTMLはあわせて返されます。
+var ErrHTMLTruncated = errors.New("HTMLが上限サイズを超えたため切り詰めました")
+
+// htmlOpenerは、HTMLをストリームとして開けるローダーが実装するインターフェースです。
+// 実装していないローダーの場合は、LoadHTMLFileで読み込んだ内容から圧縮します。
+type htmlOpener interface {
+	OpenHTMLFile(path string) (io.ReadCloser, error)
+}
+
+// skippedHTMLElementsは、求人情報の抽出に不要なため内容を読み飛ばす要素です。
+// インラインのJSONやCSSでページが巨大化している場合でも、ワーカーのメモリ使用量を抑えられます。
+var skippedHTMLElements = map[string]bool{
+	"script": true,
+	"style":  true,
+}
+
+// keptScriptTypeは、json_ldで求人情報を抽出するため、読み飛ばさずに内容を残すscriptのtype属性の値です。
+const keptScriptType = "application/ld+json"
+
+// cappedHTMLLoaderは、読み込むHTMLのサイズに上限を設けるHTMLLoaderのデコレーターです。
+// HTMLをストリームとして処理し、scriptとstyleの内容を除いたうえで上限サイズまで読み込みます。
+// 構造化データ（type="application/ld+json"のscript）の内容は残します。
+//
+// フィールド:
+//
+//	HTMLLoader : 実際にHTMLを読み込むローダー
+//	maxBytes   : 読み込むHTMLの最大バイト数
+type cappedHTMLLoader struct {
+	HTMLLoader
+	maxBytes int
+}
+
+// NewCappedHTMLLoaderは、cappedHTMLLoaderの新しいインスタンスを生成します。
+//
+// args:
+//
+//	loader   : 実際にHTMLを読み込むローダー
+//	maxBytes : 読み込むHTMLの最大バイト数
+//
+// return:
+//
+//	*cappedHTMLLoader : 生成されたローダー
+func NewCappedHTMLLoader(loader HTMLLoader, maxBytes int) *cappedHTMLLoader {
+	return &cappedHTMLLoader{
+		HTMLLoader: loader,
+		maxBytes:   maxBytes,
+	}
+}
+
+// HTMLModTimeは、実際にHTMLを読み込むローダーからHTMLの更新日時を取得します。
+// ローダーが更新日時の取得に対応していない場合はゼロ値を返します。
+func (l *cappedHTMLLoader) HTMLModTime(path string) (time.Time, error) {
+	return htmlModTime(l.HTMLLoader, path)
+}
+
+// LoadHTMLFileは、scriptとstyleの内容を除いたHTMLを上限サイズまで読み込みます。構造化データのscriptの内容は残します。
+// 上限を超えた場合は、読み込んだ部分までのHTMLとErrHTMLTruncatedを返します。
+//
+// args:
+//
+//	path : 読み込むHTMLのパス
+//
+// return:
+//
+//	string : 読み込んだHTML
+//	error  : 読み込みに失敗した場合、または切り詰めた場合のエラー
+func (l *cappedHTMLLoader) LoadHTMLFile(path string) (string, error) {
+	opener, ok := l.HTMLLoader.(htmlOpener)
+	if !ok {
+		content, err := l.HTMLLoader.LoadHTMLFile(path)
+		if err != nil {
+			return "", err
+		}
+		return compactHTML(strings.NewReader(content), l.maxBytes)
+	}
+
+	reader, err := opener.OpenHTMLFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	return compactHTML(reader, l.maxBytes)
+}
+
+// compactHTMLは、HTMLからscriptとstyleの内容（構造化データのscriptを除く）を除き、トークン単位で上限サイズまで書き出します。
+// トークナイザーのバッファも上限サイズに制限するため、巨大なHTMLでもメモリ使用量は上限の数倍に収まります。
+//
+// args:
+//
+//	r        : HTMLの読み込み元
+//	maxBytes : 書き出す最大バイト数
+//
+// return:
+//
+//	string : 書き出したHTML
+//	error  : 読み込みに失敗した場合、または切り詰めた場合のエラー
+func compactHTML(r io.Reader, maxBytes int) (string, error) {
+	tokenizer := html.NewTokenizer(newRawTextStripper(r))
+	tokenizer.SetMaxBuf(maxBytes)
+
+	var builder strings.Builder
+	for {
+		if tokenizer.Next() == html.ErrorToken {
+			err := tokenizer.Err()
+			if errors.Is(err, io.EOF) {
+				return builder.String(), nil
+			}
+			if errors.Is(err, html.ErrBufferExceeded) {
+				return builder.String(), ErrHTMLTruncated
+			}
+			return "", fmt.Errorf("HTMLの読み込みに失敗しました: %w", err)
+		}
+
+		raw := tokenizer.Raw()
+		if builder.Len()+len(raw) > maxBytes {
+			return builder.String(), ErrHTMLTruncated
+		}
+		builder.Write(raw)
+	}
+}
+
+// rawTextStripperの状態
+const (
+	stripperText    = iota // 通常のテキストを読み込み中
+	stripperOpenTag        // 読み飛ばす要素の開始タグを読み込み中
+	stripperSkip           // 読み飛ばす要素の内容を読み込み中
+)
+
+// rawTextStripperは、読み飛ばす要素の内容を取り除きながらHTMLを読み込むio.Readerです。
+// 内容をバッファに溜めずに1バイトずつ読み捨てるため、巨大なインライン要素があってもメモリを消費しません。
+// 開始タグのtype属性がapplication/ld+jsonのscriptは、内容を読み飛ばさずに残します。
+//
+// フィールド:
+//
+//	src     : HTMLの読み込み元
+//	state   : 現在の状態
+//	element : 読み飛ばし中の要素名
+//	tag     : 読み込み中の開始タグ（type属性の判定に使用する）
+type rawTextStripper struct {
+	src     *bufio.Reader
+	state   int
+	element string
+	tag     []byte
+}
+
+// newRawTextStripperは、rawTextStripperの新しいインスタンスを生成します。
+//
+// args:
+//
+//	r : HTMLの読み込み元
+//
+// return:
+//
+//	*rawTextStripper : 生成されたReader
+func newRawTextStripper(r io.Reader) *rawTextStripper {
+	return &rawTextStripper{src: bufio.NewReader(r)}
+}
+
+// Readは、読み飛ばす要素の内容を除いたHTMLをpに読み込みます。
+func (s *rawTextStripper) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		b, err := s.src.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		switch s.state {
+		case stripperText:
+			p[n] = b
+			n++
+			if b == '<' {
+				if element, ok := s.peekElement(""); ok {
+					s.state = stripperOpenTag
+					s.element = element
+					s.tag = s.tag[:0]
+				}
+			}
+		case stripperOpenTag:
+			p[n] = b
+			n++
+			if b != '>' {
+				s.tag = append(s.tag, b)
+				continue
+			}
+			s.state = stripperSkip
+			if s.element == "script" && s.isKeptScript() {
+				s.state = stripperText
+			}
+		case stripperSkip:
+			// 終了タグが現れるまで読み捨てる
+			if b == '<' {
+				if _, ok := s.peekElement("/"); ok {
+					s.state = stripperText
+					p[n] = b
+					n++
+				}
+			}
+		}
+	}
+	return n, nil
+}
+
+// isKeptScriptは、読み込んだ開始タグが内容を残す構造化データのscriptかどうかを判定します。
+//
+// return:
+//
+//	bool : type属性がapplication/ld+jsonの場合はtrue
+func (s *rawTextStripper) isKeptScript() bool {
+	return strings.Contains(strings.ToLower(string(s.tag)), keptScriptType)
+}
+
+// peekElementは、次に続くバイト列が読み飛ばす要素のタグ名かどうかを、読み込み位置を進めずに判定します。
+// 読み飛ばし中は、その要素の終了タグかどうかのみを判定します。
+//
+// args:
+//
+//	prefix : タグ名の前に置かれる文字列（終了タグの場合は"/"）
+//
+// return:
+//
+//	string : 一致した要素名
+//	bool   : 一致した場合はtrue
+func (s *rawTextStripper) peekElement(prefix string) (string, bool) {
+	for element := range skippedHTMLElements {
+		if s.state == stripperSkip && element != s.element {
+			continue
+		}
+		name := prefix + element
+		peeked, _ := s.src.Peek(len(name) + 1)
+		if len(peeked) <= len(name) || !strings.EqualFold(string(peeked[:len(name)]), name) {
+			continue
+		}
+		// "<scripts"のような別のタグ名と区別するため、タグ名の直後の文字を確認する
+		switch peeked[len(name)] {
+		case ' ', '\t', '\n', '\r', '\f', '/', '>':
+			return element, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,124 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"golang.org/x/text/width"
+)
+
+// CategoryRuleは、職種カテゴリーの1つの分類と、その分類に該当すると判定するキーワードです。
+//
+// フィールド:
+//
+//	Name     : 出力するカテゴリー名（例: "エンジニア"）
+//	Keywords : 分類に該当すると判定するキーワード（同義語）の一覧
+type CategoryRule struct {
+	Name     string   `yaml:"name"`
+	Keywords []string `yaml:"keywords"`
+}
+
+// CategoryTaxonomyは、職種カテゴリーの分類体系です。
+//
+// フィールド:
+//
+//	Categories : カテゴリーの一覧（先に記載したカテゴリーから判定します）
+//	Default    : いずれのカテゴリーにも該当しない場合のカテゴリー名（省略時は空）
+type CategoryTaxonomy struct {
+	Categories []CategoryRule `yaml:"categories"`
+	Default    string         `yaml:"default"`
+}
+
+// LoadCategoryTaxonomyは、職種カテゴリーの分類体系のファイル（YAMLまたはJSON）を読み込みます。
+//
+// args:
+//
+//	path : 分類体系のファイルのパス
+//
+// return:
+//
+//	CategoryTaxonomy : 読み込んだ分類体系
+//	error            : 読み込みや解析に失敗した場合、またはカテゴリーの定義が不正な場合のエラー
+func LoadCategoryTaxonomy(path string) (CategoryTaxonomy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CategoryTaxonomy{}, fmt.Errorf("職種カテゴリーのファイルを読み込めませんでした: %w", err)
+	}
+
+	var taxonomy CategoryTaxonomy
+	if err := yaml.UnmarshalWithOptions(data, &taxonomy, yaml.Strict()); err != nil {
+		return CategoryTaxonomy{}, fmt.Errorf("職種カテゴリーのファイルの解析に失敗しました: %w", err)
+	}
+
+	names := make(map[string]bool, len(taxonomy.Categories))
+	for i, category := range taxonomy.Categories {
+		if category.Name == "" {
+			return CategoryTaxonomy{}, fmt.Errorf("categories[%d]のnameが空です", i)
+		}
+		if names[category.Name] {
+			return CategoryTaxonomy{}, fmt.Errorf("カテゴリー %s が重複して定義されています", category.Name)
+		}
+		if len(category.Keywords) == 0 {
+			return CategoryTaxonomy{}, fmt.Errorf("カテゴリー %s のkeywordsが空です", category.Name)
+		}
+		names[category.Name] = true
+	}
+	return taxonomy, nil
+}
+
+// categoryClassifierは、求人のタイトル・職務内容・業務内容詳細に含まれるキーワードから職種カテゴリーを判定するJobPostingEnricherの実装です。
+// タイトルはもっとも職種を表すため、タイトルで判定できない場合にのみ職務内容・業務内容詳細の順に判定します。
+//
+// フィールド:
+//
+//	classifier  : 正規化済みのキーワードによる分類器（分類体系に記載した順）
+//	defaultName : いずれのカテゴリーにも該当しない場合のカテゴリー名
+type categoryClassifier struct {
+	classifier  keywordClassifier[string]
+	defaultName string
+}
+
+// NewCategoryClassifierは、categoryClassifierの新しいインスタンスを生成します。
+//
+// args:
+//
+//	taxonomy : 職種カテゴリーの分類体系
+//
+// return:
+//
+//	*categoryClassifier : 生成された分類器
+func NewCategoryClassifier(taxonomy CategoryTaxonomy) *categoryClassifier {
+	classifier := make(keywordClassifier[string], 0, len(taxonomy.Categories))
+	for _, category := range taxonomy.Categories {
+		class := keywordClass[string]{value: category.Name}
+		for _, keyword := range category.Keywords {
+			if keyword = normalizeCategoryText(keyword); keyword != "" {
+				class.keywords = append(class.keywords, keyword)
+			}
+		}
+		classifier = append(classifier, class)
+	}
+	return &categoryClassifier{
+		classifier:  classifier,
+		defaultName: taxonomy.Default,
+	}
+}
+
+// Enrichは、職種カテゴリーを設定した求人情報を返します。
+func (c *categoryClassifier) Enrich(posting model.JobPosting) model.JobPosting {
+	details := posting.Details()
+	for _, text := range []string{posting.Title(), details.JobName(), details.Description()} {
+		if category := c.classifier.classify(normalizeCategoryText(text), ""); category != "" {
+			return posting.WithCategory(category)
+		}
+	}
+	return posting.WithCategory(c.defaultName)
+}
+
+// normalizeCategoryTextは、キーワードと判定対象の文字列の全角・半角と英字の大文字・小文字を揃えます。
+func normalizeCategoryText(s string) string {
+	return strings.ToLower(strings.TrimSpace(width.Fold.String(s)))
+}
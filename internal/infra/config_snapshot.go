@@ -0,0 +1,61 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// configSnapshotHashLengthは、設定のスナップショットのファイル名に含めるハッシュ値の長さです。
+const configSnapshotHashLength = 12
+
+// WriteConfigSnapshotは、実行に使用した設定ファイルの内容を、設定のハッシュ値を含むファイル名でdirにコピーします。
+// 出力物に記録したハッシュ値から、出力を生成したセレクターや設定をいつでも辿れるようにします。
+// 同じハッシュ値のスナップショットがすでに存在する場合は書き込みません。
+// 環境変数の参照は展開せずにコピーするため、環境変数で渡した秘密の値はスナップショットに含まれません。
+//
+// args:
+//
+//	dir        : スナップショットの出力先のディレクトリ
+//	kind       : 設定の種類（"crawler" または "scraper"）
+//	sourcePath : 実行に使用した設定ファイルのパス
+//	configHash : 実効的な設定のハッシュ値
+//
+// return:
+//
+//	string : スナップショットのファイル名（dirからの相対パス）
+//	error  : 設定ファイルの読み込みやスナップショットの書き込みに失敗した場合のエラー
+func WriteConfigSnapshot(dir, kind, sourcePath, configHash string) (string, error) {
+	name := fmt.Sprintf("%s_config_%s.yaml", kind, configHash[:min(len(configHash), configSnapshotHashLength)])
+	path := filepath.Join(dir, name)
+
+	if _, err := os.Stat(path); err == nil {
+		return name, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("スナップショットの確認に失敗しました: %w", err)
+	}
+
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("設定ファイルを読み込めませんでした: %w", err)
+	}
+
+	file, err := createAtomicFile(path, false)
+	if err != nil {
+		return "", err
+	}
+	header := fmt.Sprintf("# source: %s\n# config_hash: %s\n", sourcePath, configHash)
+	if _, err := file.WriteString(header); err != nil {
+		file.Abort()
+		return "", fmt.Errorf("スナップショットの書き込みに失敗しました: %w", err)
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Abort()
+		return "", fmt.Errorf("スナップショットの書き込みに失敗しました: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,119 @@
+package infra
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// contentHashKeyは、URLごとの前回のクロール時のページの内容のハッシュ値を保持するRedisハッシュのキーです。
+const contentHashKey = "crawl_content_hash"
+
+// ContentHashは、ページの表示されるテキストのハッシュ値（SHA-256の16進数表現）を返します。
+// アクセスごとに変わるスクリプトやCSRFトークンなどで変更と判定しないよう、script・style・noscriptを除いたbodyのテキストを
+// 空白を正規化した上でハッシュ化します。HTMLとして解釈できない場合はHTML全体をハッシュ化します。
+//
+// args:
+//
+//	html : ページのHTML
+//
+// return:
+//
+//	string : ページの内容のハッシュ値
+func ContentHash(html string) string {
+	text := html
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(html)); err == nil {
+		doc.Find("script, style, noscript").Remove()
+		text = doc.Find("body").Text()
+	}
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ContentHashStoreは、URLごとに前回のクロール時のページの内容のハッシュ値を保存するストアです。
+// 再クロールしたページの内容が前回から変更されたかの判定に使用します。
+type ContentHashStore interface {
+	// Getは、URLの前回のハッシュ値を返します。記録がない場合はfalseを返します。
+	Get(ctx context.Context, rawURL string) (string, bool, error)
+	// Setは、URLのハッシュ値を保存します。
+	Set(ctx context.Context, rawURL, hash string) error
+}
+
+// contentHashClientは、Redisを用いたContentHashStoreの実装です。
+// ハッシュ値は正規化したURLをフィールドとして1つのRedisハッシュに保存するため、クロールジョブを削除しても残ります。
+type contentHashClient struct {
+	redis *redis.Client
+}
+
+// NewContentHashClientは、contentHashClientの新しいインスタンスを作成します。
+//
+// args:
+//
+//	rds: Redisクライアント
+//
+// return:
+//
+//	*contentHashClient: 生成されたストア
+func NewContentHashClient(rds *redis.Client) *contentHashClient {
+	return &contentHashClient{
+		redis: rds,
+	}
+}
+
+// Getは、URLの前回のハッシュ値をRedisから取得します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	rawURL: ページのURL
+//
+// return:
+//
+//	string: 前回のハッシュ値
+//	bool: 記録が存在する場合はtrue
+//	error: URLの正規化や取得に失敗した場合のエラー
+func (c *contentHashClient) Get(ctx context.Context, rawURL string) (string, bool, error) {
+	field, err := model.CanonicalizeURL(rawURL)
+	if err != nil {
+		return "", false, fmt.Errorf("URLの正規化に失敗しました: %w", err)
+	}
+
+	hash, err := c.redis.HGet(ctx, contentHashKey, field).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("ページの内容のハッシュ値をRedisから取得できませんでした: %w", err)
+	}
+	return hash, true, nil
+}
+
+// Setは、URLのハッシュ値をRedisに保存します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	rawURL: ページのURL
+//	hash: ページの内容のハッシュ値
+//
+// return:
+//
+//	error: URLの正規化や保存に失敗した場合のエラー
+func (c *contentHashClient) Set(ctx context.Context, rawURL, hash string) error {
+	field, err := model.CanonicalizeURL(rawURL)
+	if err != nil {
+		return fmt.Errorf("URLの正規化に失敗しました: %w", err)
+	}
+
+	if err := c.redis.HSet(ctx, contentHashKey, field, hash).Err(); err != nil {
+		return fmt.Errorf("ページの内容のハッシュ値をRedisに保存できませんでした: %w", err)
+	}
+	return nil
+}
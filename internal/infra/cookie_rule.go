@@ -0,0 +1,88 @@
+package infra
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/playwright-community/playwright-go"
+)
+
+// cookieRuleは、正規表現をコンパイル済みのCookieの設定ルールです。
+//
+// フィールド:
+//
+//	pattern : 対象とするURLの正規表現
+//	cookies : 対象のURLへ遷移する前に設定するCookie
+type cookieRule struct {
+	pattern *regexp.Regexp
+	cookies []config.CookieConfig
+}
+
+// compileCookieRulesは、設定ファイルのCookieの設定ルールの正規表現をコンパイルします。
+//
+// args:
+//
+//	rules : 設定ファイルのCookieの設定ルール
+//
+// return:
+//
+//	[]cookieRule : コンパイル済みのルール
+//	error        : 正規表現のコンパイルに失敗した場合のエラー
+func compileCookieRules(rules []config.CookieRule) ([]cookieRule, error) {
+	compiled := make([]cookieRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.URLPattern)
+		if err != nil {
+			return nil, fmt.Errorf("cookiesのurl_pattern %q のコンパイルに失敗しました: %w", rule.URLPattern, err)
+		}
+		compiled = append(compiled, cookieRule{pattern: pattern, cookies: rule.Cookies})
+	}
+	return compiled, nil
+}
+
+// cookiesForURLは、指定したURLに一致するルールのCookieを、ブラウザに設定する形式で返します。
+// ドメインを省略したCookieは遷移先のURLのホストに、パスを省略したCookieは「/」に設定されます。
+//
+// args:
+//
+//	rules     : コンパイル済みのルール
+//	targetURL : 遷移先のURL
+//
+// return:
+//
+//	[]playwright.OptionalCookie : 設定するCookie（一致するルールがない場合は空）
+func cookiesForURL(rules []cookieRule, targetURL string) []playwright.OptionalCookie {
+	var host string
+	if parsed, err := url.Parse(targetURL); err == nil {
+		host = parsed.Hostname()
+	}
+
+	var cookies []playwright.OptionalCookie
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(targetURL) {
+			continue
+		}
+
+		for _, c := range rule.cookies {
+			domain := c.Domain
+			if domain == "" {
+				domain = host
+			}
+			path := c.Path
+			if path == "" {
+				path = "/"
+			}
+			cookies = append(cookies, playwright.OptionalCookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   playwright.String(domain),
+				Path:     playwright.String(path),
+				Secure:   playwright.Bool(c.Secure),
+				HttpOnly: playwright.Bool(c.HTTPOnly),
+			})
+		}
+	}
+	return cookies
+}
@@ -0,0 +1,272 @@
+package infra
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"golang.org/x/text/width"
+)
+
+// defaultCorporateNumberEndpointは、国税庁の法人番号システムWeb-APIの法人名による検索のURLです。
+const defaultCorporateNumberEndpoint = "https://api.houjin-bangou.nta.go.jp/4/name"
+
+// corporateFormsReplacerは、会社名の比較のために法人格の表記を取り除く置換です。
+// 求人サイトでは「(株)」のような略記や、法人格を省略した会社名が多いため、法人格を除いた名称で比較します。
+// 「医療法人社団」が「医療法人」より先に取り除かれるよう、長い表記から並べます。
+var corporateFormsReplacer = strings.NewReplacer(
+	"特定非営利活動法人", "",
+	"一般社団法人", "",
+	"一般財団法人", "",
+	"公益社団法人", "",
+	"公益財団法人", "",
+	"医療法人社団", "",
+	"医療法人財団", "",
+	"社会福祉法人", "",
+	"医療法人", "",
+	"学校法人", "",
+	"株式会社", "",
+	"有限会社", "",
+	"合同会社", "",
+	"合資会社", "",
+	"合名会社", "",
+	"(株)", "",
+	"(有)", "",
+	"(同)", "",
+	"㈱", "",
+	"㈲", "",
+)
+
+// CorporateNumberClientは、会社名から法人番号を取得するインターフェースです。
+// 国税庁のWeb-API以外の情報源（社内のマスターデータなど）を使用する場合は、このインターフェースを実装します。
+type CorporateNumberClient interface {
+	// LookupCorporateNumberは、会社名と都道府県から法人番号（13桁）を返します。
+	// 該当する法人がない場合や、1件に特定できない場合は空文字列を返します。
+	// 都道府県が空の場合は、所在地で絞り込まずに検索します。
+	LookupCorporateNumber(ctx context.Context, name string, prefecture model.PrefectureCode) (string, error)
+}
+
+// ntaCorporationsは、法人番号システムWeb-APIのXML形式の応答です。
+type ntaCorporations struct {
+	Corporations []ntaCorporation `xml:"corporation"`
+}
+
+// ntaCorporationは、法人番号システムWeb-APIの応答に含まれる1件の法人です。
+type ntaCorporation struct {
+	CorporateNumber string `xml:"corporateNumber"`
+	Name            string `xml:"name"`
+}
+
+// ntaCorporateNumberClientは、国税庁の法人番号システムWeb-APIを使用するCorporateNumberClientの実装です。
+// 法人格を除いた会社名で部分一致の検索を行い、法人格を除いた名称が一致する法人が1件の場合に、その法人番号を返します。
+//
+// フィールド:
+//
+//	appID    : Web-APIのアプリケーションID
+//	endpoint : 法人名で検索するAPIのURL
+//	timeout  : 1回の問い合わせのタイムアウト
+type ntaCorporateNumberClient struct {
+	appID    string
+	endpoint string
+	timeout  time.Duration
+}
+
+// NewNTACorporateNumberClientは、ntaCorporateNumberClientの新しいインスタンスを生成します。
+//
+// args:
+//
+//	appID    : Web-APIのアプリケーションID
+//	endpoint : 法人名で検索するAPIのURL（空の場合は国税庁のWeb-API）
+//	timeout  : 1回の問い合わせのタイムアウト
+//
+// return:
+//
+//	*ntaCorporateNumberClient : 生成されたクライアント
+func NewNTACorporateNumberClient(appID, endpoint string, timeout time.Duration) *ntaCorporateNumberClient {
+	if endpoint == "" {
+		endpoint = defaultCorporateNumberEndpoint
+	}
+	return &ntaCorporateNumberClient{
+		appID:    appID,
+		endpoint: endpoint,
+		timeout:  timeout,
+	}
+}
+
+// LookupCorporateNumberは、法人番号システムWeb-APIで会社名を検索し、法人番号を返します。
+//
+// args:
+//
+//	ctx        : コンテキスト
+//	name       : 会社名
+//	prefecture : 本社の都道府県コード（空の場合は絞り込まない）
+//
+// return:
+//
+//	string : 法人番号（1件に特定できない場合は空文字列）
+//	error  : 問い合わせに失敗した場合や、応答を解析できない場合のエラー
+func (c *ntaCorporateNumberClient) LookupCorporateNumber(ctx context.Context, name string, prefecture model.PrefectureCode) (string, error) {
+	key := normalizeCompanyName(name)
+	if key == "" {
+		return "", nil
+	}
+
+	// type=12はXML形式、mode=2は部分一致、target=1はあいまい検索、close=0は閉鎖した法人を除く指定です
+	query := url.Values{
+		"id":     {c.appID},
+		"name":   {key},
+		"type":   {"12"},
+		"mode":   {"2"},
+		"target": {"1"},
+		"close":  {"0"},
+	}
+	if prefecture != "" {
+		query.Set("address", string(prefecture))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("リクエストの作成に失敗しました: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("法人番号の問い合わせに失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(io.Discard, resp.Body)
+		return "", fmt.Errorf("法人番号のAPIがステータスコード %d を返しました", resp.StatusCode)
+	}
+
+	var result ntaCorporations
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("法人番号のAPIの応答の解析に失敗しました: %w", err)
+	}
+
+	var number string
+	for _, corporation := range result.Corporations {
+		if normalizeCompanyName(corporation.Name) != key {
+			continue
+		}
+		if number != "" && number != corporation.CorporateNumber {
+			// 同じ名称の法人が複数ある場合は、誤った法人番号を付けないよう特定できないものとして扱う
+			return "", nil
+		}
+		number = corporation.CorporateNumber
+	}
+	return number, nil
+}
+
+// normalizeCompanyNameは、会社名を比較できるよう、全角・半角をそろえ、法人格と空白を取り除きます。
+func normalizeCompanyName(name string) string {
+	name = corporateFormsReplacer.Replace(width.Fold.String(name))
+	return strings.Join(strings.Fields(name), "")
+}
+
+// cachedCorporateNumberClientは、CorporateNumberClientをラップし、会社名と都道府県ごとの結果をキャッシュするCorporateNumberClientの実装です。
+// 同じ会社の求人は繰り返し現れるため、APIへの問い合わせを会社ごとに1回に抑えます。
+// 該当する法人がなかった結果もキャッシュし、問い合わせに失敗した場合は次回に再度問い合わせます。
+//
+// フィールド:
+//
+//	inner   : ラップするクライアント
+//	mu      : entriesへのアクセスを保護するミューテックス
+//	entries : 会社名と都道府県ごとの法人番号
+type cachedCorporateNumberClient struct {
+	inner   CorporateNumberClient
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// NewCachedCorporateNumberClientは、cachedCorporateNumberClientの新しいインスタンスを生成します。
+//
+// args:
+//
+//	inner : ラップするクライアント
+//
+// return:
+//
+//	*cachedCorporateNumberClient : 生成されたクライアント
+func NewCachedCorporateNumberClient(inner CorporateNumberClient) *cachedCorporateNumberClient {
+	return &cachedCorporateNumberClient{
+		inner:   inner,
+		entries: make(map[string]string),
+	}
+}
+
+// LookupCorporateNumberは、キャッシュにある場合はその法人番号を、ない場合はinnerに問い合わせた結果を返します。
+func (c *cachedCorporateNumberClient) LookupCorporateNumber(ctx context.Context, name string, prefecture model.PrefectureCode) (string, error) {
+	key := string(prefecture) + "\x00" + normalizeCompanyName(name)
+
+	c.mu.Lock()
+	number, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		return number, nil
+	}
+
+	number, err := c.inner.LookupCorporateNumber(ctx, name, prefecture)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = number
+	c.mu.Unlock()
+	return number, nil
+}
+
+// corporateNumberEnricherは、会社名と本社の都道府県から法人番号を取得して付加するJobPostingEnricherの実装です。
+// 問い合わせに失敗した場合は法人番号を空のまま出力し、失敗した件数を記録します。
+//
+// フィールド:
+//
+//	client   : 法人番号を取得するクライアント
+//	failures : 問い合わせに失敗した件数
+type corporateNumberEnricher struct {
+	client   CorporateNumberClient
+	failures atomic.Int64
+}
+
+// NewCorporateNumberEnricherは、corporateNumberEnricherの新しいインスタンスを生成します。
+//
+// args:
+//
+//	client : 法人番号を取得するクライアント
+//
+// return:
+//
+//	*corporateNumberEnricher : 生成されたエンリッチャー
+func NewCorporateNumberEnricher(client CorporateNumberClient) *corporateNumberEnricher {
+	return &corporateNumberEnricher{client: client}
+}
+
+// Failuresは、法人番号の問い合わせに失敗した件数を返します。
+func (e *corporateNumberEnricher) Failures() int {
+	return int(e.failures.Load())
+}
+
+// Enrichは、法人番号を設定した求人情報を返します。会社名が空の場合や、問い合わせに失敗した場合は設定しません。
+func (e *corporateNumberEnricher) Enrich(posting model.JobPosting) model.JobPosting {
+	if posting.CompanyName() == "" {
+		return posting
+	}
+	number, err := e.client.LookupCorporateNumber(context.Background(), posting.CompanyName(), posting.Headquarters().PrefectureCode())
+	if err != nil {
+		e.failures.Add(1)
+		return posting
+	}
+	return posting.WithCorporateNumber(number)
+}
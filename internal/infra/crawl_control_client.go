@@ -0,0 +1,106 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// crawlControlKeyは、クローラーへの操作指示を保存するRedisキーです。
+const crawlControlKey = "crawl_control"
+
+// crawlControlClientは、Redisを用いたCrawlControlRepositoryの実装です。
+// 実行中のクローラーはこのキーをポーリングし、別プロセスから書き込まれた指示に従います。
+type crawlControlClient struct {
+	redis *redis.Client
+}
+
+// NewCrawlControlClientは、crawlControlClientの新しいインスタンスを作成します。
+//
+// args:
+//
+//	rds: Redisクライアント
+//
+// return:
+//
+//	*crawlControlClient: 生成されたリポジトリ実装
+func NewCrawlControlClient(rds *redis.Client) *crawlControlClient {
+	return &crawlControlClient{
+		redis: rds,
+	}
+}
+
+// Getは、現在の操作指示を取得します。指示が保存されていない場合はRUNを返します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//
+// return:
+//
+//	model.CrawlControlCommand: 現在の操作指示
+//	error: 取得または変換に失敗した場合のエラー
+func (c *crawlControlClient) Get(ctx context.Context) (model.CrawlControlCommand, error) {
+	value, err := c.redis.Get(ctx, crawlControlKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return model.CrawlControlRun, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("操作指示をRedisから取得できませんでした: %w", err)
+	}
+
+	command, err := model.ParseCrawlControlCommand(value)
+	if err != nil {
+		return "", fmt.Errorf("保存されている操作指示 %q が不正です: %w", value, err)
+	}
+	return command, nil
+}
+
+// Setは、操作指示を保存します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	command: 保存する操作指示
+//
+// return:
+//
+//	error: 保存に失敗した場合のエラー
+func (c *crawlControlClient) Set(ctx context.Context, command model.CrawlControlCommand) error {
+	if err := c.redis.Set(ctx, crawlControlKey, string(command), 0).Err(); err != nil {
+		return fmt.Errorf("操作指示をRedisに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// resetControlScriptは、操作指示がARGV[1]の場合にのみRUNに戻すLuaスクリプトです。
+// 確認と書き込みの間に別プロセスが書き込んだ指示を上書きしないよう、Redis上で不可分に実行します。
+var resetControlScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  redis.call('SET', KEYS[1], ARGV[2])
+  return 1
+end
+return 0
+`)
+
+// Resetは、操作指示が指定した指示の場合にのみRUNに戻します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	command: RUNに戻す対象の操作指示
+//
+// return:
+//
+//	bool: RUNに戻した場合はtrue
+//	error: 取得または保存に失敗した場合のエラー
+func (c *crawlControlClient) Reset(ctx context.Context, command model.CrawlControlCommand) (bool, error) {
+	reset, err := resetControlScript.Run(ctx, c.redis, []string{crawlControlKey}, string(command), string(model.CrawlControlRun)).Int()
+	if err != nil {
+		return false, fmt.Errorf("操作指示 %s をRUNに戻せませんでした: %w", command, err)
+	}
+	return reset == 1, nil
+}
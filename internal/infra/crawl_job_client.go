@@ -3,15 +3,43 @@ package infra
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/nrad-K/go-crawler/internal/domain/model"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// jobQueueKeyは、PENDINGのジョブのURLを登録順に保持するRedisリストのキーです。
+	jobQueueKey = "crawl_job_queue"
+	// batchScanSizeは、インデックスの再構築時に1回のSCANで取得するキーの数です。
+	batchScanSize = 1000
+	// saveBatchSizeは、SaveAllで1回のパイプラインにまとめるジョブの数です。
+	saveBatchSize = 500
+	// jobIndexKeyPrefixは、ステータスごとのジョブのURLを保持するRedisセットのキーの接頭辞です。
+	jobIndexKeyPrefix = "crawl_job_index:"
+	// jobProcessingKeyPrefixは、ワーカーがキューから取り出して獲得を終えていないURLを保持するRedisリストのキーの接頭辞です。
+	jobProcessingKeyPrefix = "crawl_job_processing:"
+)
+
 // crawlJobClientは、Redisを用いたCrawlJobRepositoryの実装です。
+// ジョブ本体はステータスとURLごとのキーに保存し、あわせて次の構造を更新します。
+//
+//   - crawl_job_queue: PENDINGのジョブのURLのリスト。実行時は先頭から取り出して処理します。
+//   - crawl_job_index:<ステータス>: ステータスごとのジョブのURLのセット。一覧の取得に使用します。
+//
+// キー全体をSCANせずに済むため、ジョブ数が多くても取得の速度が落ちず、複数のプロセスで1つのキューを共有できます。
+//
+// フィールド:
+//
+//	redis: Redisクライアント
+//	pendingTTL: PENDINGのジョブの有効期限（0は無期限）
 type crawlJobClient struct {
-	redis *redis.Client
+	redis      *redis.Client
+	pendingTTL time.Duration
 }
 
 // NewCrawlJobClientは、crawlJobClientの新しいインスタンスを作成します。
@@ -19,13 +47,15 @@ type crawlJobClient struct {
 // args:
 //
 //	rds: Redisクライアント
+//	pendingTTL: PENDINGのジョブの有効期限。期限を過ぎた未処理のジョブはRedisから自動的に削除されます（0は無期限）
 //
 // return:
 //
 //	repository.CrawlJobRepository: 生成されたリポジトリ実装
-func NewCrawlJobClient(rds *redis.Client) *crawlJobClient {
+func NewCrawlJobClient(rds *redis.Client, pendingTTL time.Duration) *crawlJobClient {
 	return &crawlJobClient{
-		redis: rds,
+		redis:      rds,
+		pendingTTL: pendingTTL,
 	}
 }
 
@@ -48,18 +78,144 @@ func (r *crawlJobClient) Save(ctx context.Context, job model.CrawlJob) error {
 		return fmt.Errorf("クローリングジョブのマーシャルに失敗しました: %w", err)
 	}
 
-	key, err := r.generateJobKey(job)
+	jobURL, err := model.CanonicalizeURL(job.URL())
+	if err != nil {
+		return fmt.Errorf("URLの正規化に失敗しました: %w", err)
+	}
+
+	key, err := r.generateJobKeyForURL(job.Status(), jobURL)
 	if err != nil {
 		return fmt.Errorf("ジョブキーの生成に失敗しました: %w", err)
 	}
 
-	if err := r.redis.Set(ctx, key, data, 0).Err(); err != nil {
+	pipe := r.redis.TxPipeline()
+	r.queueSave(ctx, pipe, job.Status(), key, jobURL, data)
+
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("クローリングジョブをRedisに保存できませんでした: %w", err)
 	}
 
 	return nil
 }
 
+// SaveAllは、複数のCrawlJobをまとめてRedisに保存します。
+// 既に同じステータスで保存されているジョブは上書きせずに読み飛ばします。
+// ジョブごとに存在確認と保存の往復が発生しないよう、saveBatchSize件ごとに存在確認と保存をそれぞれ1回のパイプラインで行います。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	jobs: 保存するCrawlJob
+//
+// return:
+//
+//	int: 保存したジョブ数（読み飛ばしたジョブを除く）
+//	error: 保存に失敗した場合のエラー
+func (r *crawlJobClient) SaveAll(ctx context.Context, jobs []model.CrawlJob) (int, error) {
+	saved := 0
+	for start := 0; start < len(jobs); start += saveBatchSize {
+		count, err := r.saveBatch(ctx, jobs[start:min(start+saveBatchSize, len(jobs))])
+		saved += count
+		if err != nil {
+			return saved, err
+		}
+	}
+	return saved, nil
+}
+
+// saveBatchは、存在しないジョブのみを1回のトランザクションで保存します。
+// 同じURLのジョブが複数含まれる場合は、最初のジョブのみを保存します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	jobs: 保存するCrawlJob
+//
+// return:
+//
+//	int: 保存したジョブ数
+//	error: 存在確認や保存に失敗した場合のエラー
+func (r *crawlJobClient) saveBatch(ctx context.Context, jobs []model.CrawlJob) (int, error) {
+	type pendingSave struct {
+		status model.CrawlJobStatus
+		key    string
+		jobURL string
+		data   []byte
+	}
+
+	saves := make([]pendingSave, 0, len(jobs))
+	seen := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		data, err := json.Marshal(ToRecord(job))
+		if err != nil {
+			return 0, fmt.Errorf("クローリングジョブのマーシャルに失敗しました: %w", err)
+		}
+
+		jobURL, err := model.CanonicalizeURL(job.URL())
+		if err != nil {
+			return 0, fmt.Errorf("URLの正規化に失敗しました: %w", err)
+		}
+
+		key, err := r.generateJobKeyForURL(job.Status(), jobURL)
+		if err != nil {
+			return 0, fmt.Errorf("ジョブキーの生成に失敗しました: %w", err)
+		}
+
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		saves = append(saves, pendingSave{status: job.Status(), key: key, jobURL: jobURL, data: data})
+	}
+
+	existsPipe := r.redis.Pipeline()
+	exists := make([]*redis.IntCmd, len(saves))
+	for i, save := range saves {
+		exists[i] = existsPipe.Exists(ctx, save.key)
+	}
+	if _, err := existsPipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("redisの存在確認に失敗しました: %w", err)
+	}
+
+	pipe := r.redis.TxPipeline()
+	saved := 0
+	for i, save := range saves {
+		if exists[i].Val() > 0 {
+			continue
+		}
+		r.queueSave(ctx, pipe, save.status, save.key, save.jobURL, save.data)
+		saved++
+	}
+	if saved == 0 {
+		return 0, nil
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("クローリングジョブをRedisに保存できませんでした: %w", err)
+	}
+	return saved, nil
+}
+
+// queueSaveは、ジョブ本体の保存と、キューおよびインデックスへの登録をパイプラインに追加します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	pipe: コマンドを追加するパイプライン
+//	status: ジョブのステータス
+//	key: ジョブ本体のキー
+//	jobURL: 正規化したURL
+//	data: ジョブのJSON
+func (r *crawlJobClient) queueSave(ctx context.Context, pipe redis.Pipeliner, status model.CrawlJobStatus, key, jobURL string, data []byte) {
+	if status == model.CrawlJobStatusPending {
+		pipe.Set(ctx, key, data, r.pendingTTL)
+		pipe.RPush(ctx, jobQueueKey, jobURL)
+	} else {
+		pipe.Set(ctx, key, data, 0)
+	}
+	pipe.SAdd(ctx, r.generateIndexKey(status), jobURL)
+}
+
 // Deleteは、指定したCrawlJobをRedisから削除します。
 //
 // args:
@@ -71,43 +227,250 @@ func (r *crawlJobClient) Save(ctx context.Context, job model.CrawlJob) error {
 //
 //	error: 削除に失敗した場合のエラー
 func (r *crawlJobClient) Delete(ctx context.Context, job model.CrawlJob) error {
-	key, err := r.generateJobKey(job)
+	keys, err := r.generateJobKeys(job)
 	if err != nil {
 		return fmt.Errorf("削除用のジョブキーの生成に失敗しました: %w", err)
 	}
-	if err := r.redis.Del(ctx, key).Err(); err != nil {
+	jobURL, err := model.CanonicalizeURL(job.URL())
+	if err != nil {
+		return fmt.Errorf("URLの正規化に失敗しました: %w", err)
+	}
+
+	// キューに残ったURLは、取り出した時点でジョブが存在しなければ読み飛ばされる
+	pipe := r.redis.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.SRem(ctx, r.generateIndexKey(job.Status()), jobURL, job.URL())
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("保留中のジョブをRedisから削除できませんでした: %w", err)
 	}
 	return nil
 }
 
+// claimScriptは、PENDINGのジョブのキーを削除できた場合にのみIN_PROGRESSのジョブを保存するLuaスクリプトです。
+// KEYS[1]はIN_PROGRESSのキー、KEYS[2]とKEYS[3]はPENDINGとIN_PROGRESSのインデックス、KEYS[4:]はPENDINGのキーです。
+// ARGV[1]は保存するジョブのJSON、ARGV[2]は正規化したURLです。
+// Redis上で不可分に実行されるため、同じジョブを獲得できるのは1つのプロセスだけです。
+var claimScript = redis.NewScript(`
+if redis.call('DEL', unpack(KEYS, 4)) > 0 then
+  redis.call('SET', KEYS[1], ARGV[1])
+  redis.call('SREM', KEYS[2], ARGV[2])
+  redis.call('SADD', KEYS[3], ARGV[2])
+  return 1
+end
+return 0
+`)
+
+// Claimは、PENDINGのジョブを不可分な操作でIN_PROGRESSに変更し、獲得します。
+// 複数のプロセスが同じキューを共有している場合でも、1つのジョブを獲得できるのは1つのプロセスだけです。
+// 獲得したジョブには、どのワーカーが処理しているかを辿れるようワーカーのIDを記録します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	job: 獲得するPENDINGのCrawlJob
+//	workerID: ジョブを獲得するワーカーのID
+//
+// return:
+//
+//	model.CrawlJob: IN_PROGRESSに変更されたCrawlJob
+//	bool: 獲得できた場合はtrue、他のプロセスが既に獲得していた場合はfalse
+//	error: 獲得処理に失敗した場合のエラー
+func (r *crawlJobClient) Claim(ctx context.Context, job model.CrawlJob, workerID string) (model.CrawlJob, bool, error) {
+	pendingKeys, err := r.generateJobKeys(job)
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("ジョブキーの生成に失敗しました: %w", err)
+	}
+
+	claimedJob, err := job.ChangeStatus(model.CrawlJobStatusInProgress)
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("ジョブのステータス変更に失敗しました: %w", err)
+	}
+	claimedJob = claimedJob.WithWorkerID(workerID)
+
+	inProgressKey, err := r.generateJobKey(claimedJob)
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("ジョブキーの生成に失敗しました: %w", err)
+	}
+
+	data, err := json.Marshal(ToRecord(claimedJob))
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("クローリングジョブのマーシャルに失敗しました: %w", err)
+	}
+
+	jobURL, err := model.CanonicalizeURL(job.URL())
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("URLの正規化に失敗しました: %w", err)
+	}
+
+	keys := append([]string{
+		inProgressKey,
+		r.generateIndexKey(model.CrawlJobStatusPending),
+		r.generateIndexKey(model.CrawlJobStatusInProgress),
+	}, pendingKeys...)
+	claimed, err := claimScript.Run(ctx, r.redis, keys, data, jobURL).Int()
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("ジョブの獲得に失敗しました: %w", err)
+	}
+
+	return claimedJob, claimed == 1, nil
+}
+
+// Dequeueは、キューの先頭からPENDINGのジョブを取り出し、IN_PROGRESSに変更して獲得します。
+// 有効期限切れや他のプロセスによる獲得で既に存在しないジョブは読み飛ばします。
+// 取り出したURLは獲得を終えるまでワーカーごとの処理中リストに移しておくため、取り出した直後にプロセスが停止しても失われず、
+// RequeueProcessingでキューに戻せます（少なくとも1回の受け渡し）。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	workerID: ジョブを獲得するワーカーのID
+//
+// return:
+//
+//	model.CrawlJob: IN_PROGRESSに変更されたCrawlJob
+//	bool: ジョブを獲得できた場合はtrue、キューが空の場合はfalse
+//	error: 取得や獲得に失敗した場合のエラー
+func (r *crawlJobClient) Dequeue(ctx context.Context, workerID string) (model.CrawlJob, bool, error) {
+	processingKey := jobProcessingKeyPrefix + workerID
+	for {
+		jobURL, err := r.redis.LMove(ctx, jobQueueKey, processingKey, "LEFT", "RIGHT").Result()
+		if errors.Is(err, redis.Nil) {
+			return model.CrawlJob{}, false, nil
+		}
+		if err != nil {
+			return model.CrawlJob{}, false, fmt.Errorf("キューからのジョブの取り出しに失敗しました: %w", err)
+		}
+
+		claimedJob, claimed, err := r.claimURL(ctx, jobURL, workerID)
+		if err != nil {
+			// 獲得できなかったジョブを失わないよう、処理中リストからキューの先頭に戻す
+			if requeueErr := r.requeueURL(context.WithoutCancel(ctx), processingKey, jobURL); requeueErr != nil {
+				return model.CrawlJob{}, false, fmt.Errorf("%w（キューへの再登録にも失敗しました: %v）", err, requeueErr)
+			}
+			return model.CrawlJob{}, false, err
+		}
+
+		// 獲得したジョブはIN_PROGRESSとして保存済みのため、処理中リストから外す。
+		// 外す前に停止した場合はキューに戻されるが、PENDINGのジョブが存在しないため読み飛ばされる
+		if err := r.redis.LRem(context.WithoutCancel(ctx), processingKey, 1, jobURL).Err(); err != nil {
+			return model.CrawlJob{}, false, fmt.Errorf("処理中リストからのジョブの削除に失敗しました: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+		return claimedJob, true, nil
+	}
+}
+
+// claimURLは、キューから取り出したURLのPENDINGのジョブを獲得します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	jobURL: キューから取り出した正規化済みのURL
+//	workerID: ジョブを獲得するワーカーのID
+//
+// return:
+//
+//	model.CrawlJob: IN_PROGRESSに変更されたCrawlJob
+//	bool: 獲得できた場合はtrue、ジョブが存在しないか他のプロセスが獲得していた場合はfalse
+//	error: 取得や獲得に失敗した場合のエラー
+func (r *crawlJobClient) claimURL(ctx context.Context, jobURL, workerID string) (model.CrawlJob, bool, error) {
+	job, found, err := r.findByKey(ctx, r.generatePendingJobKey(jobURL))
+	if err != nil {
+		return model.CrawlJob{}, false, err
+	}
+	if !found {
+		return model.CrawlJob{}, false, nil
+	}
+	return r.Claim(ctx, job, workerID)
+}
+
+// requeueURLは、処理中リストのURLを不可分な操作でキューの先頭に戻します。
+func (r *crawlJobClient) requeueURL(ctx context.Context, processingKey, jobURL string) error {
+	pipe := r.redis.TxPipeline()
+	pipe.LRem(ctx, processingKey, 1, jobURL)
+	pipe.LPush(ctx, jobQueueKey, jobURL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("ジョブをキューに戻せませんでした: %w", err)
+	}
+	return nil
+}
+
+// requeueProcessingScriptは、処理中リストのURLをすべてキューの先頭に移すLuaスクリプトです。
+// KEYS[1]は処理中リスト、KEYS[2]はキューです。移したURLの数を返します。
+var requeueProcessingScript = redis.NewScript(`
+local moved = 0
+while redis.call('LMOVE', KEYS[1], KEYS[2], 'RIGHT', 'LEFT') do
+  moved = moved + 1
+end
+return moved
+`)
+
+// ProcessingWorkerIDsは、キューから取り出して獲得を終えていないURLが処理中リストに残っているワーカーのIDを返します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//
+// return:
+//
+//	[]string: 処理中リストが残っているワーカーのID
+//	error: 取得に失敗した場合のエラー
+func (r *crawlJobClient) ProcessingWorkerIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	iter := r.redis.Scan(ctx, 0, jobProcessingKeyPrefix+"*", batchScanSize).Iterator()
+	for iter.Next(ctx) {
+		ids = append(ids, strings.TrimPrefix(iter.Val(), jobProcessingKeyPrefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("処理中リストのキーの取得に失敗しました: %w", err)
+	}
+	return ids, nil
+}
+
+// RequeueProcessingは、停止したワーカーの処理中リストに残ったURLをキューの先頭に戻します。
+// 実行中のワーカーの処理中リストを戻すと同じジョブを獲得しようとするため、停止したワーカーにのみ使用してください。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	workerID: 停止したワーカーのID
+//
+// return:
+//
+//	int: キューに戻したURLの数
+//	error: 戻すのに失敗した場合のエラー
+func (r *crawlJobClient) RequeueProcessing(ctx context.Context, workerID string) (int, error) {
+	moved, err := requeueProcessingScript.Run(ctx, r.redis, []string{jobProcessingKeyPrefix + workerID, jobQueueKey}).Int()
+	if err != nil {
+		return 0, fmt.Errorf("ワーカー %s の処理中リストをキューに戻せませんでした: %w", workerID, err)
+	}
+	return moved, nil
+}
+
 // FindListByStatusStreamは、指定したステータスのCrawlJobをRedisからストリーム形式で取得します。
+// ステータスごとのインデックスを走査するため、取得にかかる時間は対象のジョブ数にのみ比例します。
+// ジョブ本体は走査したURLごとにMGETでまとめて取得し、パーティションを指定した場合は担当するURLのジョブのみを取得します。
 //
 // args:
 //
 //	ctx: コンテキスト
-//	size: 1回のSCANで取得するキーの数
 //	status: 対象のジョブステータス
+//	opts: 1回の走査で取得する数、先行して取得する数、担当するパーティション
 //
 // return:
 //
 //	<-chan model.CrawlJobStream: 取得したジョブのストリーム
-func (r *crawlJobClient) FindListByStatusStream(ctx context.Context, size int, status model.CrawlJobStatus) <-chan model.CrawlJobStream {
-	batchSize := int64(size)
-	resultCh := make(chan model.CrawlJobStream, batchSize)
+func (r *crawlJobClient) FindListByStatusStream(ctx context.Context, status model.CrawlJobStatus, opts model.CrawlJobStreamOptions) <-chan model.CrawlJobStream {
+	batchSize := int64(opts.BatchSizeOrDefault())
+	resultCh := make(chan model.CrawlJobStream, opts.PrefetchOrDefault())
 
 	go func() {
 		defer close(resultCh)
 
+		indexKey := r.generateIndexKey(status)
 		var cursor uint64 = 0
-		pattern, err := r.getJobKeyPattern(status)
-		if err != nil {
-			resultCh <- model.CrawlJobStream{
-				Err: fmt.Errorf("ジョブキーのパターンの取得に失敗しました: %w", err),
-			}
-			return
-		}
-
 		for {
 			select {
 			case <-ctx.Done():
@@ -115,50 +478,53 @@ func (r *crawlJobClient) FindListByStatusStream(ctx context.Context, size int, s
 			default:
 			}
 
-			// SCANでキーを取得
-			keys, nextCursor, err := r.redis.Scan(ctx, cursor, pattern, batchSize).Result()
+			// SSCANでインデックスからURLを取得
+			urls, nextCursor, err := r.redis.SScan(ctx, indexKey, cursor, "", batchSize).Result()
 			if err != nil {
-				resultCh <- model.CrawlJobStream{
-					Err: fmt.Errorf("Redis SCANエラー: %w", err),
-				}
+				sendStream(ctx, resultCh, model.CrawlJobStream{
+					Err: fmt.Errorf("Redis SSCANエラー: %w", err),
+				})
 				return
 			}
 
-			for _, key := range keys {
-				select {
-				case <-ctx.Done():
-					return
-				default:
+			jobURLs := make([]string, 0, len(urls))
+			keys := make([]string, 0, len(urls))
+			for _, jobURL := range urls {
+				if !opts.Includes(jobURL) {
+					continue
 				}
-
-				value, err := r.redis.Get(ctx, key).Result()
+				key, err := r.generateJobKeyForURL(status, jobURL)
 				if err != nil {
-					resultCh <- model.CrawlJobStream{
-						Err: fmt.Errorf("キー %s のRedis取得エラー: %w", key, err),
-					}
-					continue
+					sendStream(ctx, resultCh, model.CrawlJobStream{
+						Err: fmt.Errorf("ジョブキーの生成に失敗しました: %w", err),
+					})
+					return
 				}
+				jobURLs = append(jobURLs, jobURL)
+				keys = append(keys, key)
+			}
 
-				jobRecord := CrawlJobRecord{}
-				err = json.Unmarshal([]byte(value), &jobRecord)
+			if len(keys) > 0 {
+				values, err := r.redis.MGet(ctx, keys...).Result()
 				if err != nil {
-					resultCh <- model.CrawlJobStream{
-						Err: fmt.Errorf("キー %s のJSONデシリアライズに失敗しました: %w", key, err),
-					}
-					continue
+					sendStream(ctx, resultCh, model.CrawlJobStream{
+						Err: fmt.Errorf("Redis MGETエラー: %w", err),
+					})
+					return
 				}
 
-				job, err := jobRecord.ToDomain()
-				if err != nil {
-					resultCh <- model.CrawlJobStream{
-						Err: fmt.Errorf("ジョブデータのドメイン変換に失敗しました（キー: %s, エラー: %v）", key, err),
+				for i, value := range values {
+					data, ok := value.(string)
+					if !ok {
+						// 有効期限切れなどでジョブが存在しない場合は、インデックスからも取り除く
+						r.redis.SRem(ctx, indexKey, jobURLs[i])
+						continue
 					}
-					continue
-				}
 
-				resultCh <- model.CrawlJobStream{
-					Job: job,
-					Err: nil,
+					job, err := decodeJob(keys[i], data)
+					if !sendStream(ctx, resultCh, model.CrawlJobStream{Job: job, Err: err}) {
+						return
+					}
 				}
 			}
 
@@ -173,6 +539,146 @@ func (r *crawlJobClient) FindListByStatusStream(ctx context.Context, size int, s
 	return resultCh
 }
 
+// findByKeyは、指定したキーのCrawlJobをRedisから取得します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	key: 取得するジョブのキー
+//
+// return:
+//
+//	model.CrawlJob: 取得したCrawlJob
+//	bool: ジョブが存在する場合はtrue
+//	error: 取得や変換に失敗した場合のエラー
+func (r *crawlJobClient) findByKey(ctx context.Context, key string) (model.CrawlJob, bool, error) {
+	value, err := r.redis.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return model.CrawlJob{}, false, nil
+	}
+	if err != nil {
+		return model.CrawlJob{}, false, fmt.Errorf("キー %s のRedis取得エラー: %w", key, err)
+	}
+
+	job, err := decodeJob(key, value)
+	if err != nil {
+		return model.CrawlJob{}, false, err
+	}
+	return job, true, nil
+}
+
+// decodeJobは、Redisに保存されたJSONをCrawlJobに変換します。
+//
+// args:
+//
+//	key: ジョブのキー（エラーメッセージに使用）
+//	value: 保存されたJSON
+//
+// return:
+//
+//	model.CrawlJob: 変換したCrawlJob
+//	error: デシリアライズや変換に失敗した場合のエラー
+func decodeJob(key, value string) (model.CrawlJob, error) {
+	jobRecord := CrawlJobRecord{}
+	if err := json.Unmarshal([]byte(value), &jobRecord); err != nil {
+		return model.CrawlJob{}, fmt.Errorf("キー %s のJSONデシリアライズに失敗しました: %w", key, err)
+	}
+
+	job, err := jobRecord.ToDomain()
+	if err != nil {
+		return model.CrawlJob{}, fmt.Errorf("ジョブデータのドメイン変換に失敗しました（キー: %s, エラー: %v）", key, err)
+	}
+	return job, nil
+}
+
+// Reindexは、保存済みのジョブのキーを走査し、キューとステータスごとのインデックスを再構築します。
+// キューやインデックスの導入前に保存されたジョブや、クラッシュなどでキューから外れたジョブを処理対象に戻せます。
+// 正規化前のURLをキーに持つジョブは、正規化したURLのキーに移行します。
+// 再構築中にジョブが更新されると不整合が生じるため、クローラーを停止した状態で実行してください。
+//
+// args:
+//
+//	ctx: コンテキスト
+//
+// return:
+//
+//	int: インデックスに登録したジョブ数
+//	error: 走査や登録に失敗した場合のエラー
+func (r *crawlJobClient) Reindex(ctx context.Context) (int, error) {
+	statuses := []model.CrawlJobStatus{
+		model.CrawlJobStatusPending,
+		model.CrawlJobStatusInProgress,
+		model.CrawlJobStatusSuccess,
+		model.CrawlJobStatusFailed,
+		model.CrawlJobStatusBlocked,
+	}
+
+	staleKeys := []string{jobQueueKey}
+	for _, status := range statuses {
+		staleKeys = append(staleKeys, r.generateIndexKey(status))
+	}
+	if err := r.redis.Del(ctx, staleKeys...).Err(); err != nil {
+		return 0, fmt.Errorf("既存のキューとインデックスの削除に失敗しました: %w", err)
+	}
+
+	indexed := 0
+	for _, status := range statuses {
+		pattern, err := r.getJobKeyPattern(status)
+		if err != nil {
+			return indexed, fmt.Errorf("ジョブキーのパターンの取得に失敗しました: %w", err)
+		}
+
+		iter := r.redis.Scan(ctx, 0, pattern, batchScanSize).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			job, found, err := r.findByKey(ctx, key)
+			if err != nil {
+				return indexed, err
+			}
+			if !found {
+				continue
+			}
+
+			// Saveでキューとインデックスに登録し、正規化前のキーは削除する
+			if err := r.Save(ctx, job); err != nil {
+				return indexed, err
+			}
+			if canonicalKey, err := r.generateJobKey(job); err == nil && canonicalKey != key {
+				if err := r.redis.Del(ctx, key).Err(); err != nil {
+					return indexed, fmt.Errorf("正規化前のキー %s の削除に失敗しました: %w", key, err)
+				}
+			}
+			indexed++
+		}
+		if err := iter.Err(); err != nil {
+			return indexed, fmt.Errorf("Redis SCANエラー: %w", err)
+		}
+	}
+
+	return indexed, nil
+}
+
+// sendStreamは、コンテキストがキャンセルされるまでストリームへの送信を試みます。
+// 受信側が読み込みを止めた場合でも送信側のゴルーチンがブロックし続けないようにします。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	ch: 送信先のチャネル
+//	item: 送信する値
+//
+// return:
+//
+//	bool: 送信できた場合はtrue、コンテキストがキャンセルされた場合はfalse
+func sendStream(ctx context.Context, ch chan<- model.CrawlJobStream, item model.CrawlJobStream) bool {
+	select {
+	case ch <- item:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // Existsは、指定したCrawlJobがRedisに存在するか確認します。
 //
 // args:
@@ -213,8 +719,12 @@ func (r *crawlJobClient) getJobKeyPattern(status model.CrawlJobStatus) (string,
 		pattern = "success_job:*"
 	case model.CrawlJobStatusFailed:
 		pattern = "failed_job:*"
+	case model.CrawlJobStatusBlocked:
+		pattern = "blocked_job:*"
 	case model.CrawlJobStatusPending:
 		pattern = "pending_job:*"
+	case model.CrawlJobStatusInProgress:
+		pattern = "in_progress_job:*"
 	default:
 		return pattern, fmt.Errorf("サポートされていないジョブステータスです: %s", status)
 	}
@@ -223,6 +733,7 @@ func (r *crawlJobClient) getJobKeyPattern(status model.CrawlJobStatus) (string,
 }
 
 // generateJobKeyは、ジョブのステータスに応じたRedisキーを生成します。
+// 同じ求人を指すURLが同じキーになるよう、キーには正規化したURLを用います。
 //
 // args:
 //
@@ -233,26 +744,89 @@ func (r *crawlJobClient) getJobKeyPattern(status model.CrawlJobStatus) (string,
 //	string: 生成されたキー
 //	error: 生成に失敗した場合のエラー
 func (r *crawlJobClient) generateJobKey(job model.CrawlJob) (string, error) {
+	jobURL, err := model.CanonicalizeURL(job.URL())
+	if err != nil {
+		return "", fmt.Errorf("URLの正規化に失敗しました: %w", err)
+	}
+
+	return r.generateJobKeyForURL(job.Status(), jobURL)
+}
+
+// generateJobKeysは、ジョブのRedisキーを、正規化の導入前に保存された可能性のあるキーとあわせて生成します。
+//
+// args:
+//
+//	job: 対象のCrawlJob
+//
+// return:
+//
+//	[]string: 生成されたキー（先頭が正規化したURLのキー）
+//	error: 生成に失敗した場合のエラー
+func (r *crawlJobClient) generateJobKeys(job model.CrawlJob) ([]string, error) {
+	key, err := r.generateJobKey(job)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{key}
+	// 正規化の導入前に保存されたジョブは正規化前のURLをキーに持つ
+	if legacyKey, err := r.generateJobKeyForURL(job.Status(), job.URL()); err == nil && legacyKey != key {
+		keys = append(keys, legacyKey)
+	}
+	return keys, nil
+}
+
+// generateJobKeyForURLは、ステータスとURLからRedisキーを生成します。
+//
+// args:
+//
+//	status: 対象のジョブステータス
+//	url: キーに含めるURL
+//
+// return:
+//
+//	string: 生成されたキー
+//	error: サポートされていないステータスが指定された場合のエラー
+func (r *crawlJobClient) generateJobKeyForURL(status model.CrawlJobStatus, url string) (string, error) {
 	var key string
 
-	switch job.Status() {
+	switch status {
 
 	case model.CrawlJobStatusPending:
-		key = r.generatePendingJobKey(job.URL())
+		key = r.generatePendingJobKey(url)
+
+	case model.CrawlJobStatusInProgress:
+		key = r.generateInProgressJobKey(url)
 
 	case model.CrawlJobStatusSuccess:
-		key = r.generateSuccessJobKey(job.URL())
+		key = r.generateSuccessJobKey(url)
 
 	case model.CrawlJobStatusFailed:
-		key = r.generateFailedJobKey(job.URL())
+		key = r.generateFailedJobKey(url)
+
+	case model.CrawlJobStatusBlocked:
+		key = r.generateBlockedJobKey(url)
 
 	default:
-		return "", fmt.Errorf("キー生成にサポートされていないジョブステータスです: %s", job.Status)
+		return "", fmt.Errorf("キー生成にサポートされていないジョブステータスです: %s", status)
 	}
 
 	return key, nil
 }
 
+// generateIndexKeyは、ステータスごとのインデックスのRedisキーを生成します。
+//
+// args:
+//
+//	status: 対象のジョブステータス
+//
+// return:
+//
+//	string: 生成されたキー
+func (r *crawlJobClient) generateIndexKey(status model.CrawlJobStatus) string {
+	return jobIndexKeyPrefix + string(status)
+}
+
 // generateSuccessJobKeyは、成功ジョブ用のRedisキーを生成します。
 //
 // args:
@@ -279,6 +853,19 @@ func (r *crawlJobClient) generateFailedJobKey(url string) string {
 	return fmt.Sprintf("failed_job: %s", url)
 }
 
+// generateBlockedJobKeyは、アクセスを拒否されたジョブ用のRedisキーを生成します。
+//
+// args:
+//
+//	url: 対象URL
+//
+// return:
+//
+//	string: 生成されたキー
+func (r *crawlJobClient) generateBlockedJobKey(url string) string {
+	return fmt.Sprintf("blocked_job: %s", url)
+}
+
 // generatePendingJobKeyは、保留ジョブ用のRedisキーを生成します。
 //
 // args:
@@ -291,3 +878,16 @@ func (r *crawlJobClient) generateFailedJobKey(url string) string {
 func (r *crawlJobClient) generatePendingJobKey(url string) string {
 	return fmt.Sprintf("pending_job:%s", url)
 }
+
+// generateInProgressJobKeyは、処理中ジョブ用のRedisキーを生成します。
+//
+// args:
+//
+//	url: 対象URL
+//
+// return:
+//
+//	string: 生成されたキー
+func (r *crawlJobClient) generateInProgressJobKey(url string) string {
+	return fmt.Sprintf("in_progress_job:%s", url)
+}
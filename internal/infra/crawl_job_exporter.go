@@ -0,0 +1,187 @@
+package infra
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// CrawlJobExporterは、クロールジョブをファイルにエクスポートするためのインターフェースです。
+type CrawlJobExporter interface {
+	// Writeは、単一のクロールジョブを書き込みます。
+	Write(job model.CrawlJob) error
+	// Closeは、エクスポーターをクローズし、出力を確定します。
+	Close() error
+	// Abortは、出力を確定せずにエクスポーターを破棄します。
+	Abort() error
+}
+
+// crawlJobCSVHeadersは、クロールジョブのCSVのヘッダーです。CrawlJobRecordのJSONのキーと同じ名前を使用します。
+var crawlJobCSVHeaders = []string{
+	"id", "url", "status", "created_at", "updated_at",
+	"started_at", "finished_at", "duration_ms", "http_status", "retry_count", "last_error",
+	"worker_id", "list_page", "list_position",
+}
+
+// CrawlJobExportFormatは、出力先のパスの拡張子からクロールジョブの出力形式（csvまたはjson）を判定します。
+// .json以外の拡張子の場合はcsvを返します。
+func CrawlJobExportFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+	return "csv"
+}
+
+// NewCrawlJobExporterは、指定した形式のCrawlJobExporterを生成します。
+// 書き込みは一時ファイルに対して行われ、Closeが成功した時点で出力先へアトミックにリネームされます。
+//
+// args:
+//
+//	filePath : 出力するファイルのパス
+//	format   : 出力形式（csv、json）
+//
+// return:
+//
+//	CrawlJobExporter : 生成されたエクスポーター
+//	error            : 出力形式が不明な場合や、ファイルの作成に失敗した場合のエラー
+func NewCrawlJobExporter(filePath, format string) (CrawlJobExporter, error) {
+	if format != "csv" && format != "json" {
+		return nil, fmt.Errorf("不明な出力形式です: %q（csv, json のいずれかを指定してください）", format)
+	}
+
+	file, err := createAtomicFile(filePath, false)
+	if err != nil {
+		return nil, fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+	}
+
+	if format == "json" {
+		writer := bufio.NewWriter(file)
+		if _, err := writer.WriteString("["); err != nil {
+			file.Abort()
+			return nil, fmt.Errorf("JSONの書き込みに失敗しました: %w", err)
+		}
+		return &crawlJobJSONExporter{file: file, writer: writer}, nil
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(crawlJobCSVHeaders); err != nil {
+		file.Abort()
+		return nil, fmt.Errorf("CSVヘッダーの書き込みに失敗しました: %w", err)
+	}
+	return &crawlJobCSVExporter{file: file, writer: writer}, nil
+}
+
+// crawlJobCSVExporterは、クロールジョブを1行に1件のCSVとしてエクスポートするCrawlJobExporterの実装です。
+//
+// フィールド:
+//
+//	file   : 書き込み対象の一時ファイル
+//	writer : CSV書き込みを行う*csv.Writer
+type crawlJobCSVExporter struct {
+	file   *atomicFile
+	writer *csv.Writer
+}
+
+// Writeは、1件のクロールジョブをCSVの1行として書き込みます。日時はRFC 3339形式で出力し、値がない列は空にします。
+func (e *crawlJobCSVExporter) Write(job model.CrawlJob) error {
+	record := ToRecord(job)
+	return e.writer.Write([]string{
+		record.ID,
+		record.URL,
+		record.Status,
+		formatTime(record.CreatedAt, time.RFC3339),
+		formatTime(record.UpdatedAt, time.RFC3339),
+		formatTime(record.StartedAt, time.RFC3339),
+		formatTime(record.FinishedAt, time.RFC3339),
+		formatDurationMS(record),
+		formatPositiveInt(record.HTTPStatus),
+		strconv.Itoa(record.RetryCount),
+		record.LastError,
+		record.WorkerID,
+		formatPositiveInt(record.ListPage),
+		formatPositiveInt(record.ListPosition),
+	})
+}
+
+// formatDurationMSは、クロールの所要時間（ミリ秒）をフォーマットします。クロールしていないジョブの場合は空文字列を返します。
+func formatDurationMS(record CrawlJobRecord) string {
+	if record.StartedAt.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(record.DurationMS, 10)
+}
+
+// Closeは、CSVライターをフラッシュし、一時ファイルを出力先へリネームして確定します。
+func (e *crawlJobCSVExporter) Close() error {
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("CSVのフラッシュに失敗しました: %w", err)
+	}
+	return e.file.Commit()
+}
+
+// Abortは、出力を確定せずに一時ファイルを削除します。
+func (e *crawlJobCSVExporter) Abort() error {
+	return e.file.Abort()
+}
+
+// crawlJobJSONExporterは、クロールジョブをCrawlJobRecordの配列のJSONとしてエクスポートするCrawlJobExporterの実装です。
+// ジョブ数が多くてもメモリに溜めないよう、1件ずつ配列の要素として書き込みます。
+//
+// フィールド:
+//
+//	file   : 書き込み対象の一時ファイル
+//	writer : バッファ付きの書き込み先
+//	count  : 書き込んだジョブ数（要素の区切りの判定に使用）
+type crawlJobJSONExporter struct {
+	file   *atomicFile
+	writer *bufio.Writer
+	count  int
+}
+
+// Writeは、1件のクロールジョブを配列の要素として1行で書き込みます。
+func (e *crawlJobJSONExporter) Write(job model.CrawlJob) error {
+	data, err := json.Marshal(ToRecord(job))
+	if err != nil {
+		return fmt.Errorf("ジョブのJSONへの変換に失敗しました: %w", err)
+	}
+
+	separator := ",\n"
+	if e.count == 0 {
+		separator = "\n"
+	}
+	if _, err := e.writer.WriteString(separator); err != nil {
+		return err
+	}
+	if _, err := e.writer.Write(data); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// Closeは、配列を閉じてバッファを書き出し、一時ファイルを出力先へリネームして確定します。
+func (e *crawlJobJSONExporter) Close() error {
+	if _, err := e.writer.WriteString("\n]\n"); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("JSONの書き込みに失敗しました: %w", err)
+	}
+	if err := e.writer.Flush(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("JSONのフラッシュに失敗しました: %w", err)
+	}
+	return e.file.Commit()
+}
+
+// Abortは、出力を確定せずに一時ファイルを削除します。
+func (e *crawlJobJSONExporter) Abort() error {
+	return e.file.Abort()
+}
@@ -0,0 +1,174 @@
+package infra
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// CrawlJobImportEntryは、取り込むファイルから読み込んだ1件のクロールジョブです。
+//
+// フィールド:
+//
+//	Line    : ファイル内の行番号（1始まり）
+//	URL     : クロール対象のURL
+//	Listing : 一覧ページでの掲載位置（CSVに列がない場合はゼロ値）
+type CrawlJobImportEntry struct {
+	Line    int
+	URL     string
+	Listing model.ListingPosition
+}
+
+// CrawlJobImportReaderは、クロールジョブを取り込むファイルを1件ずつ読み込むリーダーです。
+// 1行に1つのURLを記述したテキストと、ヘッダーにurl列を含むCSVを読み込めます。
+// CSVの列名は `crawler jobs export` の出力と同じで、url以外にはlist_page・list_positionを読み込みます。
+//
+// フィールド:
+//
+//	text    : テキストの場合の行単位のスキャナー（CSVの場合はnil）
+//	csv     : CSVの場合のリーダー（テキストの場合はnil）
+//	columns : CSVの列名ごとの位置
+//	line    : テキストの場合に最後に読み込んだ行番号
+type CrawlJobImportReader struct {
+	text    *bufio.Scanner
+	csv     *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+// NewCrawlJobImportReaderは、CrawlJobImportReaderの新しいインスタンスを生成します。
+// 先頭の行をCSVとして解析し、url列が含まれる場合はCSV、含まれない場合はテキストとして読み込みます。
+//
+// args:
+//
+//	r : 取り込むファイルの内容
+//
+// return:
+//
+//	*CrawlJobImportReader : 生成されたリーダー
+//	error                 : 先頭の行の読み込みに失敗した場合のエラー
+func NewCrawlJobImportReader(r io.Reader) (*CrawlJobImportReader, error) {
+	br := bufio.NewReader(r)
+	first, err := br.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("ファイルの読み込みに失敗しました: %w", err)
+	}
+	// 表計算ソフトで保存したCSVの先頭に付くBOMを取り除く
+	first = strings.TrimPrefix(first, "\ufeff")
+	content := io.MultiReader(strings.NewReader(first), br)
+
+	header, err := csv.NewReader(strings.NewReader(first)).Read()
+	if err != nil || !slices.Contains(normalizeImportColumns(header), "url") {
+		return &CrawlJobImportReader{text: bufio.NewScanner(content)}, nil
+	}
+
+	reader := csv.NewReader(content)
+	reader.FieldsPerRecord = -1
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("CSVヘッダーの読み込みに失敗しました: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range normalizeImportColumns(header) {
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	return &CrawlJobImportReader{csv: reader, columns: columns}, nil
+}
+
+// normalizeImportColumnsは、CSVの列名を比較できるよう、前後の空白を除いて小文字にします。
+func normalizeImportColumns(header []string) []string {
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return columns
+}
+
+// Nextは、次のクロールジョブを読み込みます。
+// テキストの場合は空行と「#」で始まる行を、CSVの場合はurl列が空の行を読み飛ばします。
+//
+// return:
+//
+//	CrawlJobImportEntry : 読み込んだクロールジョブ
+//	error               : すべて読み込んだ場合はio.EOF、読み込みや解析に失敗した場合はそのエラー
+func (r *CrawlJobImportReader) Next() (CrawlJobImportEntry, error) {
+	if r.csv != nil {
+		return r.nextCSV()
+	}
+
+	for r.text.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.text.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return CrawlJobImportEntry{Line: r.line, URL: line}, nil
+	}
+	if err := r.text.Err(); err != nil {
+		return CrawlJobImportEntry{}, fmt.Errorf("%d行目の読み込みに失敗しました: %w", r.line+1, err)
+	}
+	return CrawlJobImportEntry{}, io.EOF
+}
+
+// nextCSVは、CSVの次の行からクロールジョブを読み込みます。
+func (r *CrawlJobImportReader) nextCSV() (CrawlJobImportEntry, error) {
+	for {
+		record, err := r.csv.Read()
+		if errors.Is(err, io.EOF) {
+			return CrawlJobImportEntry{}, io.EOF
+		}
+		if err != nil {
+			return CrawlJobImportEntry{}, fmt.Errorf("CSVの読み込みに失敗しました: %w", err)
+		}
+		line, _ := r.csv.FieldPos(0)
+
+		rawURL := strings.TrimSpace(r.column(record, "url"))
+		if rawURL == "" {
+			continue
+		}
+
+		page, err := r.intColumn(record, "list_page")
+		if err != nil {
+			return CrawlJobImportEntry{}, fmt.Errorf("%d行目: %w", line, err)
+		}
+		position, err := r.intColumn(record, "list_position")
+		if err != nil {
+			return CrawlJobImportEntry{}, fmt.Errorf("%d行目: %w", line, err)
+		}
+		return CrawlJobImportEntry{
+			Line:    line,
+			URL:     rawURL,
+			Listing: model.ListingPosition{Page: page, Position: position},
+		}, nil
+	}
+}
+
+// columnは、CSVの行から指定した列の値を返します。列がない場合は空文字列を返します。
+func (r *CrawlJobImportReader) column(record []string, name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+// intColumnは、CSVの行から指定した列の値を0以上の整数として返します。列がない場合や値が空の場合は0を返します。
+func (r *CrawlJobImportReader) intColumn(record []string, name string) (int, error) {
+	value := strings.TrimSpace(r.column(record, name))
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%sの値が不正です: %q", name, value)
+	}
+	return n, nil
+}
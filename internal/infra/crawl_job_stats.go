@@ -0,0 +1,123 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// CrawlJobStatsは、Redisに保存されたクロールジョブの集計結果です。
+//
+// フィールド:
+//
+//	Queued                  : キューに積まれているURLの数
+//	Statuses                : ステータスごとの集計
+//	OldestPendingAt         : 最も古いPENDINGのジョブの作成または更新日時（PENDINGのジョブがない場合はnil）
+//	OldestPendingAgeSeconds : 最も古いPENDINGのジョブの経過時間（秒）
+type CrawlJobStats struct {
+	Queued                  int64                 `json:"queued"`
+	Statuses                []CrawlJobStatusStats `json:"statuses"`
+	OldestPendingAt         *time.Time            `json:"oldest_pending_at,omitempty"`
+	OldestPendingAgeSeconds int64                 `json:"oldest_pending_age_seconds,omitempty"`
+}
+
+// CrawlJobStatusStatsは、1つのステータスのクロールジョブの集計結果です。
+//
+// フィールド:
+//
+//	Status     : ジョブのステータス
+//	Count      : ジョブ数
+//	SampleURLs : ジョブのURLの例
+//	SampleJobs : URLの例のジョブ（最後のクロールの結果を含む。集計中にステータスが変わったジョブは含まない）
+type CrawlJobStatusStats struct {
+	Status     model.CrawlJobStatus `json:"status"`
+	Count      int64                `json:"count"`
+	SampleURLs []string             `json:"sample_urls"`
+	SampleJobs []CrawlJobRecord     `json:"sample_jobs"`
+}
+
+// Statsは、ステータスごとのジョブ数とURLの例（最後のクロールの結果を含む）、キューの長さ、最も古いPENDINGのジョブの日時を集計します。
+// ジョブ数はインデックスから取得しますが、最も古いPENDINGのジョブを求めるためにPENDINGのジョブはすべて読み込みます。
+//
+// args:
+//
+//	ctx        : コンテキスト
+//	sampleSize : ステータスごとに取得するURLの例の数
+//
+// return:
+//
+//	CrawlJobStats : 集計結果
+//	error         : Redisからの取得に失敗した場合のエラー
+func (r *crawlJobClient) Stats(ctx context.Context, sampleSize int) (CrawlJobStats, error) {
+	statuses := []model.CrawlJobStatus{
+		model.CrawlJobStatusPending,
+		model.CrawlJobStatusInProgress,
+		model.CrawlJobStatusSuccess,
+		model.CrawlJobStatusFailed,
+		model.CrawlJobStatusBlocked,
+	}
+
+	queued, err := r.redis.LLen(ctx, jobQueueKey).Result()
+	if err != nil {
+		return CrawlJobStats{}, fmt.Errorf("キューの長さの取得に失敗しました: %w", err)
+	}
+	stats := CrawlJobStats{Queued: queued}
+
+	for _, status := range statuses {
+		indexKey := r.generateIndexKey(status)
+		count, err := r.redis.SCard(ctx, indexKey).Result()
+		if err != nil {
+			return CrawlJobStats{}, fmt.Errorf("%sのジョブ数の取得に失敗しました: %w", status, err)
+		}
+
+		samples := []string{}
+		if sampleSize > 0 && count > 0 {
+			samples, err = r.redis.SRandMemberN(ctx, indexKey, int64(sampleSize)).Result()
+			if err != nil {
+				return CrawlJobStats{}, fmt.Errorf("%sのジョブのURLの取得に失敗しました: %w", status, err)
+			}
+		}
+
+		// 失敗の原因を確認できるよう、URLの例のジョブを読み込んで最後のクロールの結果を添える
+		sampleJobs := []CrawlJobRecord{}
+		for _, sampleURL := range samples {
+			key, err := r.generateJobKeyForURL(status, sampleURL)
+			if err != nil {
+				return CrawlJobStats{}, err
+			}
+			job, found, err := r.findByKey(ctx, key)
+			if err != nil {
+				return CrawlJobStats{}, fmt.Errorf("%sのジョブの取得に失敗しました: %w", status, err)
+			}
+			if found {
+				sampleJobs = append(sampleJobs, ToRecord(job))
+			}
+		}
+
+		stats.Statuses = append(stats.Statuses, CrawlJobStatusStats{Status: status, Count: count, SampleURLs: samples, SampleJobs: sampleJobs})
+	}
+
+	// 途中で終了した場合にストリームの送信側を止めるため、キャンセル可能なコンテキストで取得する
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var oldest time.Time
+	for item := range r.FindListByStatusStream(streamCtx, model.CrawlJobStatusPending, model.CrawlJobStreamOptions{BatchSize: batchScanSize}) {
+		if item.Err != nil {
+			return CrawlJobStats{}, item.Err
+		}
+		// 更新日時が記録されていない古いジョブは比較できないため除外する
+		updatedAt := item.Job.UpdatedAt()
+		if !updatedAt.IsZero() && (oldest.IsZero() || updatedAt.Before(oldest)) {
+			oldest = updatedAt
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestPendingAt = &oldest
+		stats.OldestPendingAgeSeconds = int64(time.Since(oldest).Seconds())
+	}
+
+	return stats, ctx.Err()
+}
@@ -0,0 +1,130 @@
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// crawlRunLockKeyは、スケジュール実行中のクローラーを表すロックのRedisキーです。
+const crawlRunLockKey = "crawl_run_lock"
+
+// releaseLockScriptは、ロックの所有者が一致する場合にのみロックを削除するLuaスクリプトです。
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendLockScriptは、ロックの所有者が一致する場合にのみロックの有効期限を延長するLuaスクリプトです。
+var extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// CrawlRunLockは、スケジュールされたクロールの実行が複数のプロセスで重ならないようにするためのロックです。
+// ロックには有効期限を設定するため、ロックを取得したプロセスがクラッシュしてもロックが残り続けることはありません。
+//
+// フィールド:
+//
+//	redis : Redisクライアント
+//	owner : このプロセスを識別するロックの値
+//	ttl   : ロックの有効期限
+type CrawlRunLock struct {
+	redis *redis.Client
+	owner string
+	ttl   time.Duration
+}
+
+// NewCrawlRunLockは、CrawlRunLockの新しいインスタンスを作成します。
+//
+// args:
+//
+//	rds : Redisクライアント
+//	ttl : ロックの有効期限。実行中はExtendで延長します
+//
+// return:
+//
+//	*CrawlRunLock : 生成されたロック
+func NewCrawlRunLock(rds *redis.Client, ttl time.Duration) *CrawlRunLock {
+	hostname, _ := os.Hostname()
+	token := make([]byte, 8)
+	_, _ = rand.Read(token)
+	return &CrawlRunLock{
+		redis: rds,
+		owner: fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), hex.EncodeToString(token)),
+		ttl:   ttl,
+	}
+}
+
+// TryAcquireは、ロックの取得を試みます。他のプロセスがロックを保持している場合はfalseを返します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	bool   : ロックを取得できた場合はtrue
+//	string : ロックを取得できなかった場合は、ロックを保持しているプロセスの値
+//	error  : Redisの操作に失敗した場合のエラー
+func (l *CrawlRunLock) TryAcquire(ctx context.Context) (bool, string, error) {
+	acquired, err := l.redis.SetNX(ctx, crawlRunLockKey, l.owner, l.ttl).Result()
+	if err != nil {
+		return false, "", fmt.Errorf("実行中のロックを取得できませんでした: %w", err)
+	}
+	if acquired {
+		return true, "", nil
+	}
+
+	holder, err := l.redis.Get(ctx, crawlRunLockKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, "", fmt.Errorf("実行中のロックの保持者を取得できませんでした: %w", err)
+	}
+	return false, holder, nil
+}
+
+// Extendは、保持しているロックの有効期限を延長します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	error : ロックを保持していない場合や、Redisの操作に失敗した場合のエラー
+func (l *CrawlRunLock) Extend(ctx context.Context) error {
+	extended, err := extendLockScript.Run(ctx, l.redis, []string{crawlRunLockKey}, l.owner, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("実行中のロックを延長できませんでした: %w", err)
+	}
+	if extended == 0 {
+		return errors.New("実行中のロックを保持していません")
+	}
+	return nil
+}
+
+// Releaseは、保持しているロックを解放します。ロックを保持していない場合は何もしません。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	error : Redisの操作に失敗した場合のエラー
+func (l *CrawlRunLock) Release(ctx context.Context) error {
+	if err := releaseLockScript.Run(ctx, l.redis, []string{crawlRunLockKey}, l.owner).Err(); err != nil {
+		return fmt.Errorf("実行中のロックを解放できませんでした: %w", err)
+	}
+	return nil
+}
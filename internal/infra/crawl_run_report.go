@@ -0,0 +1,132 @@
+package infra
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// topCrawlErrorsは、実行結果のレポートに出力するエラーメッセージの数です。
+const topCrawlErrors = 10
+
+// CrawlRunReportは、クローラーの1回の実行の結果を、パイプラインの制御や監査のために機械可読な形式でまとめたレポートです。
+//
+// フィールド:
+//
+//	ConfigHash      : 実行に使用した設定のハッシュ値
+//	StartedAt       : 実行を開始した日時
+//	FinishedAt      : 実行を終了した日時
+//	Operations      : 実行した処理（refresh、generate、execute）
+//	Generated       : 生成したクロールジョブ数
+//	Success         : 成功したジョブ数
+//	Failed          : 失敗したジョブ数
+//	Blocked         : 失敗したジョブのうち、アクセスを拒否されたジョブ数
+//	Drained         : DRAINの指示を受けてキューを処理し終えた場合はtrue
+//	TopErrors       : 失敗したジョブの件数が多いエラーメッセージ（件数の多い順に最大10件）
+//	AvgFetchMillis  : 処理したジョブの取得と保存にかかった時間の平均（ミリ秒）
+//	Pages           : ナビゲーションしたページ数
+//	Bytes           : 取得したHTMLの合計バイト数
+//	BrowserSeconds  : ブラウザ操作に要した時間の合計（秒）
+//	BrowserRecycles : ブラウザのページとコンテキストを作り直した回数
+//	Outputs         : 実行で書き込んだ出力先
+//	Error           : 実行に失敗した場合のエラーメッセージ
+type CrawlRunReport struct {
+	ConfigHash      string            `json:"config_hash"`
+	StartedAt       time.Time         `json:"started_at"`
+	FinishedAt      time.Time         `json:"finished_at"`
+	Operations      []string          `json:"operations"`
+	Generated       int               `json:"generated"`
+	Success         int               `json:"success"`
+	Failed          int               `json:"failed"`
+	Blocked         int               `json:"blocked"`
+	Drained         bool              `json:"drained,omitempty"`
+	TopErrors       []CrawlErrorCount `json:"top_errors"`
+	AvgFetchMillis  int64             `json:"avg_fetch_ms"`
+	Pages           int               `json:"pages"`
+	Bytes           int64             `json:"bytes"`
+	BrowserSeconds  int               `json:"browser_seconds"`
+	BrowserRecycles int               `json:"browser_recycles"`
+	Outputs         CrawlRunOutputs   `json:"outputs"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// CrawlErrorCountは、1つのエラーメッセージと、そのエラーで失敗したジョブ数です。
+//
+// フィールド:
+//
+//	Message : エラーメッセージ
+//	Count   : 失敗したジョブ数
+type CrawlErrorCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// CrawlRunOutputsは、クローラーの実行で書き込んだ出力先です。
+//
+// フィールド:
+//
+//	Storage          : HTMLの保存先の種類（local、redis、s3、gcs）
+//	HTMLDir          : HTMLを保存したディレクトリ（local以外の場合はキーの名前空間）
+//	ListPageDir      : 一覧ページのHTMLを保存したディレクトリ（保存しない場合は空）
+//	PolitenessReport : ドメインごとのリクエストの集計結果のパス（出力しない場合は空）
+//	ConfigSnapshots  : 設定のスナップショットのパス
+type CrawlRunOutputs struct {
+	Storage          string   `json:"storage"`
+	HTMLDir          string   `json:"html_dir"`
+	ListPageDir      string   `json:"list_page_dir,omitempty"`
+	PolitenessReport string   `json:"politeness_report,omitempty"`
+	ConfigSnapshots  []string `json:"config_snapshots,omitempty"`
+}
+
+// TopCrawlErrorsは、エラーメッセージごとの件数から、件数の多い順に最大10件のエラーメッセージを返します。
+// 件数が同じ場合はメッセージの順に並べます。
+//
+// args:
+//
+//	counts : エラーメッセージごとの失敗したジョブ数
+//
+// return:
+//
+//	[]CrawlErrorCount : 件数の多いエラーメッセージ
+func TopCrawlErrors(counts map[string]int) []CrawlErrorCount {
+	errors := make([]CrawlErrorCount, 0, len(counts))
+	for message, count := range counts {
+		errors = append(errors, CrawlErrorCount{Message: message, Count: count})
+	}
+	slices.SortFunc(errors, func(a, b CrawlErrorCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Message, b.Message))
+	})
+	if len(errors) > topCrawlErrors {
+		errors = errors[:topCrawlErrors]
+	}
+	return errors
+}
+
+// WriteCrawlRunReportは、実行結果のレポートをJSONファイルとして書き出します。
+// 書き込み途中のファイルが残らないよう、一時ファイルに書き込んでからリネームします。
+//
+// args:
+//
+//	path   : 出力先のパス
+//	report : 書き出すレポート
+//
+// return:
+//
+//	error : 書き込みに失敗した場合のエラー
+func WriteCrawlRunReport(path string, report CrawlRunReport) error {
+	file, err := createAtomicFile(path, false)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		file.Abort()
+		return fmt.Errorf("レポートの書き込みに失敗しました: %w", err)
+	}
+
+	return file.Commit()
+}
@@ -0,0 +1,166 @@
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// crawlWorkerKeyPrefixは、ワーカーの状態を保存するRedisキーの接頭辞です。
+	crawlWorkerKeyPrefix = "crawl_worker:"
+	// CrawlWorkerHeartbeatIntervalは、実行中のワーカーが状態を報告する間隔です。
+	CrawlWorkerHeartbeatInterval = 10 * time.Second
+	// crawlWorkerTTLは、ワーカーの状態の有効期限です。報告が3回続けて途絶えたワーカーは停止したものとみなします。
+	crawlWorkerTTL = 3 * CrawlWorkerHeartbeatInterval
+)
+
+// CrawlWorkerRecordは、Redisに保存するワーカーの状態です。
+type CrawlWorkerRecord struct {
+	ID         string                `json:"id"`
+	Role       model.CrawlWorkerRole `json:"role"`
+	Hostname   string                `json:"hostname"`
+	StartedAt  time.Time             `json:"started_at"`
+	LastSeen   time.Time             `json:"last_seen"`
+	Generated  int                   `json:"generated"`
+	Success    int                   `json:"success"`
+	Failed     int                   `json:"failed"`
+	Blocked    int                   `json:"blocked"`
+	CurrentURL string                `json:"current_url,omitempty"`
+}
+
+// ToDomainは、保存されたワーカーの状態をmodel.CrawlWorkerに変換します。
+func (r CrawlWorkerRecord) ToDomain() model.CrawlWorker {
+	return model.CrawlWorker(r)
+}
+
+// ToCrawlWorkerRecordは、model.CrawlWorkerを保存する形式に変換します。
+func ToCrawlWorkerRecord(worker model.CrawlWorker) CrawlWorkerRecord {
+	return CrawlWorkerRecord(worker)
+}
+
+// NewCrawlWorkerIDは、ホスト名・プロセスID・乱数からワーカーを識別する値を生成します。
+func NewCrawlWorkerID() string {
+	hostname, _ := os.Hostname()
+	token := make([]byte, 4)
+	_, _ = rand.Read(token)
+	return fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), hex.EncodeToString(token))
+}
+
+// crawlWorkerClientは、Redisを用いたCrawlWorkerRepositoryの実装です。
+// ワーカーごとに有効期限付きのキー `crawl_worker:<ワーカーID>` に状態を保存するため、
+// クラッシュなどで報告が途絶えたワーカーのキーは自動的に削除されます。
+type crawlWorkerClient struct {
+	redis *redis.Client
+}
+
+// NewCrawlWorkerClientは、crawlWorkerClientの新しいインスタンスを作成します。
+//
+// args:
+//
+//	rds: Redisクライアント
+//
+// return:
+//
+//	*crawlWorkerClient: 生成されたリポジトリ実装
+func NewCrawlWorkerClient(rds *redis.Client) *crawlWorkerClient {
+	return &crawlWorkerClient{
+		redis: rds,
+	}
+}
+
+// Heartbeatは、ワーカーの状態を保存し、有効期限を延長します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	worker: 保存するワーカーの状態
+//
+// return:
+//
+//	error: 保存に失敗した場合のエラー
+func (c *crawlWorkerClient) Heartbeat(ctx context.Context, worker model.CrawlWorker) error {
+	data, err := json.Marshal(ToCrawlWorkerRecord(worker))
+	if err != nil {
+		return fmt.Errorf("ワーカーの状態のマーシャルに失敗しました: %w", err)
+	}
+	if err := c.redis.Set(ctx, crawlWorkerKeyPrefix+worker.ID, data, crawlWorkerTTL).Err(); err != nil {
+		return fmt.Errorf("ワーカーの状態をRedisに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// Removeは、停止したワーカーの状態を削除します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//	id: 削除するワーカーのID
+//
+// return:
+//
+//	error: 削除に失敗した場合のエラー
+func (c *crawlWorkerClient) Remove(ctx context.Context, id string) error {
+	if err := c.redis.Del(ctx, crawlWorkerKeyPrefix+id).Err(); err != nil {
+		return fmt.Errorf("ワーカーの状態をRedisから削除できませんでした: %w", err)
+	}
+	return nil
+}
+
+// FindAllは、実行中のワーカー（有効期限内に状態を報告したワーカー）の状態をID順に取得します。
+// ワーカーの数は少ないため、キーはSCANで取得します。
+//
+// args:
+//
+//	ctx: コンテキスト
+//
+// return:
+//
+//	[]model.CrawlWorker: 実行中のワーカーの状態
+//	error: 取得に失敗した場合のエラー
+func (c *crawlWorkerClient) FindAll(ctx context.Context) ([]model.CrawlWorker, error) {
+	var keys []string
+	iter := c.redis.Scan(ctx, 0, crawlWorkerKeyPrefix+"*", batchScanSize).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("ワーカーのキーの取得に失敗しました: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	values, err := c.redis.MGet(ctx, keys...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("ワーカーの状態の取得に失敗しました: %w", err)
+	}
+
+	workers := make([]model.CrawlWorker, 0, len(values))
+	for i, value := range values {
+		// SCANの後に有効期限が切れたワーカーは読み飛ばす
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var record CrawlWorkerRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("ワーカーの状態 %s のアンマーシャルに失敗しました: %w", keys[i], err)
+		}
+		workers = append(workers, record.ToDomain())
+	}
+	slices.SortFunc(workers, func(a, b model.CrawlWorker) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return workers, nil
+}
@@ -3,8 +3,8 @@ package infra
 import (
 	"encoding/csv"
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/nrad-K/go-crawler/internal/domain/model"
 )
@@ -13,19 +13,39 @@ import (
 type FileExporter interface {
 	// Writeは、単一の求人情報を書き込みます。
 	Write(jobPosting model.JobPosting) error
-	// Closeは、エクスポーターをクローズし、リソースを解放します。
+	// Closeは、エクスポーターをクローズし、出力を確定します。
 	Close() error
+	// Abortは、出力を確定せずにエクスポーターを破棄します。
+	Abort() error
+}
+
+// ExportFlusherは、バッファに溜めた書き込みを出力先へ書き出せるエクスポーターが実装するインターフェースです。
+// スクレイプ中に定期的に書き出すことで、異常終了した場合も書き出し済みの行が一時ファイルに残ります。
+type ExportFlusher interface {
+	Flush() error
+}
+
+// flushExporterは、エクスポーターがExportFlusherを実装している場合に、バッファを出力先へ書き出します。
+// 実装していない場合は何もしません。
+func flushExporter(exporter FileExporter) error {
+	if flusher, ok := exporter.(ExportFlusher); ok {
+		return flusher.Flush()
+	}
+	return nil
 }
 
 // CSVExporterは、求人情報をCSVファイルにエクスポートするFileExporterの実装です。
+// 書き込みは一時ファイルに対して行われ、Closeが成功した時点で出力先へアトミックにリネームされます。
 //
 // フィールド:
 //
-//	file   : 書き込み対象の*os.File
+//	file   : 書き込み対象の一時ファイル
 //	writer : CSV書き込みを行う*csv.Writer
+//	fields : 出力する列の定義
 type CSVExporter struct {
-	file   *os.File
+	file   *atomicFile
 	writer *csv.Writer
+	fields []ExportField
 }
 
 // formatUintは、*uint型の値をフォーマットします。ポインタがnilの場合は空文字列を返します。
@@ -44,38 +64,89 @@ func formatUint64(p *uint64) string {
 	return fmt.Sprintf("%d", *p)
 }
 
+// formatBoolは、*bool型の値をフォーマットします。ポインタがnilの場合は空文字列を返します。
+func formatBool(p *bool) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("%t", *p)
+}
+
+// formatPositiveIntは、1以上の整数をフォーマットします。0以下（値が不明）の場合は空文字列を返します。
+func formatPositiveInt(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// formatTimeは、時刻を指定された書式でフォーマットします。ゼロ値の場合は空文字列を返します。
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// multiValueSeparatorは、1つのセルに複数の値を出力する際の区切り文字です。
+const multiValueSeparator = ";"
+
+// joinShiftsは、複数の勤務パターンから取り出した値を区切り文字で連結します。
+func joinShifts(shifts []model.WorkShift, value func(model.WorkShift) string) string {
+	values := make([]string, 0, len(shifts))
+	for _, shift := range shifts {
+		values = append(values, value(shift))
+	}
+	return strings.Join(values, multiValueSeparator)
+}
+
+// joinLocationsは、複数の勤務地から取り出した値を区切り文字で連結します。
+func joinLocations(locations []model.Location, value func(model.Location) string) string {
+	values := make([]string, 0, len(locations))
+	for _, location := range locations {
+		values = append(values, value(location))
+	}
+	return strings.Join(values, multiValueSeparator)
+}
+
+// firstLocationRawは、勤務地の原文を返します。原文はすべての勤務地で共通のため、先頭の値を使用します。
+func firstLocationRaw(locations []model.Location) string {
+	if len(locations) == 0 {
+		return ""
+	}
+	return locations[0].Raw()
+}
+
 // NewCSVExporterは、CSVExporterの新しいインスタンスを生成します。
-// 指定されたファイルパスにCSVファイルを作成し、ヘッダーを書き込みます。
+// 指定されたファイルパスと同じディレクトリに一時ファイルを作成し、列定義から生成したヘッダーを書き込みます。
 //
 // args:
 //
-//	filePath : 出力するCSVファイルのパス
-//	headers  : CSVファイルのヘッダー行
+//	filePath    : 出力するCSVファイルのパス
+//	fields      : 出力する列の定義
+//	keepPartial : 失敗時に書き込み途中のファイルを.partialとして残すかどうか
 //
 // return:
 //
 //	*CSVExporter : 生成されたCSVExporterのインスタンス
 //	error        : ディレクトリやファイルの作成、ヘッダーの書き込みに失敗した場合のエラー
-func NewCSVExporter(filePath string, headers []string) (*CSVExporter, error) {
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return nil, fmt.Errorf("出力ディレクトリの作成に失敗しました: %w", err)
-	}
-
-	file, err := os.Create(filePath)
+func NewCSVExporter(filePath string, fields []ExportField, keepPartial bool) (*CSVExporter, error) {
+	file, err := createAtomicFile(filePath, keepPartial)
 	if err != nil {
 		return nil, fmt.Errorf("CSVファイルの作成に失敗しました: %w", err)
 	}
 
 	writer := csv.NewWriter(file)
 
-	if err := writer.Write(headers); err != nil {
+	if err := writer.Write(ExportHeaders(fields)); err != nil {
+		file.Abort()
 		return nil, fmt.Errorf("CSVヘッダーの書き込みに失敗しました: %w", err)
 	}
 
 	return &CSVExporter{
 		file:   file,
 		writer: writer,
+		fields: fields,
 	}, nil
 }
 
@@ -89,47 +160,49 @@ func NewCSVExporter(filePath string, headers []string) (*CSVExporter, error) {
 //
 //	error : CSV行の書き込みに失敗した場合のエラー
 func (c *CSVExporter) Write(job model.JobPosting) error {
-	maxAmount := job.Salary().MaxAmount()
-	minAmount := job.Salary().MinAmount()
-
-	row := []string{
-		job.CompanyName(),
-		job.Title(),
-		job.SummaryURL(),
-		string(job.Location().PrefectureCode()),
-		job.Location().PrefectureName(),
-		job.Location().City(),
-		job.Location().Raw(),
-		string(job.Headquarters().PrefectureCode()),
-		job.Headquarters().PrefectureName(),
-		job.Headquarters().City(),
-		job.Headquarters().Raw(),
-		string(job.JobType()),
-		minAmount.Format(),
-		maxAmount.Format(),
-		string(job.Salary().Unit()),
-		job.PostedAt().Format("2006-01-02"),
-		job.Details().JobName(),
-		formatUint(job.Details().Raise()),
-		formatUint(job.Details().Bonus()),
-		job.Details().Description(),
-		job.Details().Requirements(),
-		string(job.Details().WorkplaceType()),
-		formatUint(job.Details().HolidaysPerYear()),
-		string(job.Details().HolidayPolicy()),
-		job.Details().WorkHours(),
-		job.Details().Benefits().RawBenefits(),
+	row := make([]string, 0, len(c.fields))
+	for _, field := range c.fields {
+		row = append(row, field.Value(job))
 	}
 
 	return c.writer.Write(row)
 }
 
-// Closeは、CSVライターをフラッシュし、ファイルをクローズします。
+// Flushは、CSVライターのバッファを一時ファイルへ書き出します。
+//
+// return:
+//
+//	error : 書き出しに失敗した場合のエラー
+func (c *CSVExporter) Flush() error {
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		return fmt.Errorf("CSVのフラッシュに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Closeは、CSVライターをフラッシュし、一時ファイルを出力先へリネームして確定します。
+// フラッシュに失敗した場合は出力を確定せず、Abortと同様に後始末します。
 //
 // return:
 //
-//	error : ファイルのクローズに失敗した場合のエラー
+//	error : フラッシュやファイルの確定に失敗した場合のエラー
 func (c *CSVExporter) Close() error {
 	c.writer.Flush()
-	return c.file.Close()
+	if err := c.writer.Error(); err != nil {
+		c.file.Abort()
+		return fmt.Errorf("CSVのフラッシュに失敗しました: %w", err)
+	}
+	return c.file.Commit()
+}
+
+// Abortは、CSVライターをフラッシュした上で出力を確定せずに破棄します。
+// keepPartialが有効な場合、書き込み済みの内容は.partialサフィックス付きのファイルとして残ります。
+//
+// return:
+//
+//	error : 一時ファイルの後始末に失敗した場合のエラー
+func (c *CSVExporter) Abort() error {
+	c.writer.Flush()
+	return c.file.Abort()
 }
@@ -0,0 +1,104 @@
+package infra
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// ErrDuplicatePostingは、同じ実行ですでに書き込んだ求人情報と重複するため、書き込まなかったことを表すエラーです。
+var ErrDuplicatePosting = errors.New("すでに書き込んだ求人情報と重複しています")
+
+// dedupExporterは、会社名・タイトル・勤務地が同じ求人情報を1回の実行で1件だけ書き込むFileExporterの実装です。
+// 求人サイトでは同じ求人が複数の一覧ページに掲載されることが多く、そのまま出力すると重複した行になります。
+// 重複の判定には各項目のハッシュ値のみを保持するため、件数が多くてもメモリの使用量を抑えられます。
+//
+// フィールド:
+//
+//	inner      : ラップするエクスポーター
+//	seen       : 書き込んだ求人情報のキーのハッシュ値
+//	duplicates : 重複のため書き込まなかった件数
+type dedupExporter struct {
+	inner      FileExporter
+	seen       map[[sha256.Size]byte]struct{}
+	duplicates int
+}
+
+// NewDedupExporterは、dedupExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	inner : ラップするエクスポーター
+//
+// return:
+//
+//	*dedupExporter : 生成されたエクスポーター
+func NewDedupExporter(inner FileExporter) *dedupExporter {
+	return &dedupExporter{
+		inner: inner,
+		seen:  make(map[[sha256.Size]byte]struct{}),
+	}
+}
+
+// Duplicatesは、重複のため書き込まなかった求人情報の件数を返します。
+func (d *dedupExporter) Duplicates() int {
+	return d.duplicates
+}
+
+// Writeは、書き込み済みの求人情報と重複しない場合のみ、innerに書き込みます。
+// innerへの書き込みに失敗した場合は、再度書き込めるよう書き込み済みとして扱いません。
+//
+// args:
+//
+//	job : 書き込む求人情報
+//
+// return:
+//
+//	error : 重複している場合はErrDuplicatePosting、書き込みに失敗した場合はそのエラー
+func (d *dedupExporter) Write(job model.JobPosting) error {
+	key := dedupKey(job)
+	if _, ok := d.seen[key]; ok {
+		d.duplicates++
+		return ErrDuplicatePosting
+	}
+	if err := d.inner.Write(job); err != nil {
+		return err
+	}
+	d.seen[key] = struct{}{}
+	return nil
+}
+
+// Closeは、innerをクローズして出力を確定します。
+func (d *dedupExporter) Close() error {
+	return d.inner.Close()
+}
+
+// Abortは、innerの出力を確定せずに破棄します。
+func (d *dedupExporter) Abort() error {
+	return d.inner.Abort()
+}
+
+// Flushは、innerのバッファを書き出します。
+func (d *dedupExporter) Flush() error {
+	return flushExporter(d.inner)
+}
+
+// dedupKeyは、会社名・タイトル・勤務地から重複の判定に使用するキーを生成します。
+// 勤務地は都道府県と市区町村を、解析できなかった場合は原文を使用します。
+// 表記の揺れで別の求人と判定されないよう、空白の連続は1つにまとめ、英字は小文字にそろえます。
+func dedupKey(job model.JobPosting) [sha256.Size]byte {
+	parts := []string{job.CompanyName(), job.Title()}
+	for _, location := range job.Locations() {
+		if location.PrefectureName() != "" {
+			parts = append(parts, location.PrefectureName()+location.City())
+		} else {
+			parts = append(parts, location.Raw())
+		}
+	}
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(part), " "))
+	}
+	return sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+}
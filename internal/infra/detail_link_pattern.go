@@ -0,0 +1,67 @@
+package infra
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+)
+
+// DetailLinkPatternは、一覧ページのHTMLから正規表現で詳細ページのリンクを抽出します。
+// JavaScriptでリンクを組み立てるページや、data属性にURLを持つページなど、CSSセレクターでリンクを指定しにくいページで使用します。
+//
+// フィールド:
+//
+//	pattern : 詳細ページのリンクを1番目のキャプチャグループで取り出す正規表現
+type DetailLinkPattern struct {
+	pattern *regexp.Regexp
+}
+
+// NewDetailLinkPatternは、正規表現からDetailLinkPatternを生成します。正規表現が指定されていない場合はnilを返します。
+//
+// args:
+//
+//	pattern : 詳細ページのリンクを1番目のキャプチャグループで取り出す正規表現
+//
+// return:
+//
+//	*DetailLinkPattern : 生成された抽出器（正規表現がない場合はnil）
+//	error              : 正規表現の解釈に失敗した場合、またはキャプチャグループがない場合のエラー
+func NewDetailLinkPattern(pattern string) (*DetailLinkPattern, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("detail_links_patternの正規表現 %q を解釈できません: %w", pattern, err)
+	}
+	if re.NumSubexp() == 0 {
+		return nil, fmt.Errorf("detail_links_patternの正規表現 %q にリンクを取り出すキャプチャグループがありません", pattern)
+	}
+	return &DetailLinkPattern{pattern: re}, nil
+}
+
+// Extractは、HTMLから正規表現に一致したリンクを出現順に返します。
+// HTMLの属性値やスクリプトに含まれるリンクは同じページで何度も出現することが多いため、重複したリンクは最初の1件のみを返します。
+// 属性値に含まれる &amp; などの文字参照は元の文字に戻します。
+//
+// args:
+//
+//	content : 一覧ページのHTML
+//
+// return:
+//
+//	[]string : 抽出したリンク
+func (p *DetailLinkPattern) Extract(content string) []string {
+	seen := make(map[string]bool)
+	links := make([]string, 0)
+	for _, match := range p.pattern.FindAllStringSubmatch(content, -1) {
+		link := html.UnescapeString(match[1])
+		if link == "" || seen[link] {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	return links
+}
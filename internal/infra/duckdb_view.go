@@ -0,0 +1,86 @@
+package infra
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildDuckDBViewは、列定義から、CSV出力を型付きで参照するDuckDBのビューを作成するSQLを生成します。
+// CSVをすべて文字列として読み込んだうえで列ごとに型を変換するため、分析者は変換の手順を踏まずに最新の出力を問い合わせられます。
+// 空文字列はNULL、`;` 区切りの列はリストとして扱います。
+//
+// args:
+//
+//	fields        : 出力する列の定義
+//	schemaVersion : エクスポートのスキーマバージョン
+//	viewName      : 作成するビューの名前
+//	source        : 読み込むCSVのパス（DuckDBのglobパターンを使用できます）
+//
+// return:
+//
+//	string : 生成されたSQL
+func BuildDuckDBView(fields []ExportField, schemaVersion SchemaVersion, viewName, source string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "-- go-crawler export schema %s\n", schemaVersion.String())
+	fmt.Fprintf(&builder, "CREATE OR REPLACE VIEW %s AS\nSELECT\n", quoteDuckDBIdentifier(viewName))
+
+	for i, field := range fields {
+		separator := ","
+		if i == len(fields)-1 {
+			separator = ""
+		}
+		fmt.Fprintf(&builder, "  %s AS %s%s\n", duckDBColumnExpr(field), quoteDuckDBIdentifier(field.Key), separator)
+	}
+
+	fmt.Fprintf(&builder, "FROM read_csv(%s, header = true, all_varchar = true);\n", quoteDuckDBString(source))
+	return builder.String()
+}
+
+// duckDBColumnExprは、CSVの列を列定義の型に変換するDuckDBの式を返します。
+func duckDBColumnExpr(field ExportField) string {
+	column := fmt.Sprintf("NULLIF(%s, '')", quoteDuckDBIdentifier(field.Header))
+	if !field.Nullable && field.Type == FieldTypeString && !field.Multi {
+		// 空文字列も値として扱う列は、そのまま参照する
+		column = quoteDuckDBIdentifier(field.Header)
+	}
+
+	sqlType := duckDBType(field.Type)
+	if !field.Multi {
+		if sqlType == "VARCHAR" {
+			return column
+		}
+		return fmt.Sprintf("TRY_CAST(%s AS %s)", column, sqlType)
+	}
+
+	list := fmt.Sprintf("string_split(%s, %s)", column, quoteDuckDBString(multiValueSeparator))
+	if sqlType == "VARCHAR" {
+		return list
+	}
+	return fmt.Sprintf("list_transform(%s, x -> TRY_CAST(NULLIF(x, '') AS %s))", list, sqlType)
+}
+
+// duckDBTypeは、列の型に対応するDuckDBの型を返します。
+func duckDBType(fieldType ExportFieldType) string {
+	switch fieldType {
+	case FieldTypeInteger:
+		return "BIGINT"
+	case FieldTypeNumber:
+		return "DOUBLE"
+	case FieldTypeDate:
+		return "DATE"
+	case FieldTypeDateTime:
+		return "TIMESTAMPTZ"
+	default:
+		return "VARCHAR"
+	}
+}
+
+// quoteDuckDBIdentifierは、識別子をダブルクォートで囲みます。
+func quoteDuckDBIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteDuckDBStringは、文字列をシングルクォートで囲んだ文字列リテラルにします。
+func quoteDuckDBString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
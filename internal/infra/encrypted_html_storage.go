@@ -0,0 +1,238 @@
+package infra
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// encryptedHTMLPrefixは、暗号化して保存したHTMLとメタデータの先頭に付与する識別子です。
+// 読み込み時にこの識別子の有無で暗号化されているかを判定するため、暗号化前に保存したHTMLもそのまま読み込めます。
+const encryptedHTMLPrefix = "GOCRAWLER-AESGCM1:"
+
+// ErrHTMLKeyRequiredは、暗号化されたHTMLを復号する鍵が設定されていない場合のエラーです。
+var ErrHTMLKeyRequired = errors.New("HTMLが暗号化されていますが、復号する鍵が設定されていません。HTML_ENCRYPTION_KEYを設定してください")
+
+// NewHTMLCipherは、Base64でエンコードされた256ビットの鍵から、HTMLの暗号化に使用するAES-GCMを生成します。
+//
+// args:
+//
+//	encodedKey : Base64でエンコードされた32バイトの鍵
+//
+// return:
+//
+//	cipher.AEAD : 生成されたAES-GCM
+//	error       : 鍵の形式が不正な場合のエラー
+func NewHTMLCipher(encodedKey string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("鍵のBase64デコードに失敗しました: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("鍵の長さが不正です。32バイトの鍵を指定してください（現在: %dバイト）", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("AESの初期化に失敗しました: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptedHTMLWriterは、HTMLとメタデータをAES-GCMで暗号化してから保存するHTMLWriterのデコレーターです。
+// 第三者のページの内容を平文で保存できない場合に使用します。
+//
+// フィールド:
+//
+//	HTMLWriter : 実際に保存を行うライター
+//	aead       : 暗号化に使用するAES-GCM
+type encryptedHTMLWriter struct {
+	HTMLWriter
+	aead cipher.AEAD
+}
+
+// NewEncryptedHTMLWriterは、encryptedHTMLWriterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	writer : 実際に保存を行うライター
+//	aead   : 暗号化に使用するAES-GCM
+//
+// return:
+//
+//	*encryptedHTMLWriter : 生成されたライター
+func NewEncryptedHTMLWriter(writer HTMLWriter, aead cipher.AEAD) *encryptedHTMLWriter {
+	return &encryptedHTMLWriter{
+		HTMLWriter: writer,
+		aead:       aead,
+	}
+}
+
+// SaveHTMLは、HTMLを暗号化して保存します。
+//
+// args:
+//
+//	filename : ファイル名
+//	content  : HTMLの内容
+//
+// return:
+//
+//	error : 暗号化や保存に失敗した場合のエラー
+func (w *encryptedHTMLWriter) SaveHTML(filename string, content string) error {
+	sealed, err := sealHTML(w.aead, []byte(content))
+	if err != nil {
+		return err
+	}
+	return w.HTMLWriter.SaveHTML(filename, sealed)
+}
+
+// SaveHTMLMetadataは、メタデータ全体を暗号化し、Encryptedのみを設定したメタデータとして保存します。
+//
+// args:
+//
+//	filename : HTMLのファイル名
+//	meta     : 保存するメタデータ
+//
+// return:
+//
+//	error : 暗号化や保存に失敗した場合のエラー
+func (w *encryptedHTMLWriter) SaveHTMLMetadata(filename string, meta HTMLMetadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("メタデータのマーシャルに失敗しました: %w", err)
+	}
+	sealed, err := sealHTML(w.aead, data)
+	if err != nil {
+		return err
+	}
+	return w.HTMLWriter.SaveHTMLMetadata(filename, HTMLMetadata{Encrypted: sealed})
+}
+
+// encryptedHTMLLoaderは、暗号化して保存されたHTMLとメタデータを復号して読み込むHTMLLoaderのデコレーターです。
+// 暗号化されていないHTMLはそのまま返すため、暗号化の導入前に保存したHTMLと混在していても読み込めます。
+//
+// フィールド:
+//
+//	HTMLLoader : 実際に読み込みを行うローダー
+//	aead       : 復号に使用するAES-GCM（鍵が設定されていない場合はnil）
+type encryptedHTMLLoader struct {
+	HTMLLoader
+	aead cipher.AEAD
+}
+
+// NewEncryptedHTMLLoaderは、encryptedHTMLLoaderの新しいインスタンスを生成します。
+// aeadがnilの場合、暗号化されたHTMLの読み込みはErrHTMLKeyRequiredで失敗します。
+//
+// args:
+//
+//	loader : 実際に読み込みを行うローダー
+//	aead   : 復号に使用するAES-GCM
+//
+// return:
+//
+//	*encryptedHTMLLoader : 生成されたローダー
+func NewEncryptedHTMLLoader(loader HTMLLoader, aead cipher.AEAD) *encryptedHTMLLoader {
+	return &encryptedHTMLLoader{
+		HTMLLoader: loader,
+		aead:       aead,
+	}
+}
+
+// HTMLModTimeは、実際にHTMLを読み込むローダーからHTMLの更新日時を取得します。
+// ローダーが更新日時の取得に対応していない場合はゼロ値を返します。
+func (l *encryptedHTMLLoader) HTMLModTime(path string) (time.Time, error) {
+	return htmlModTime(l.HTMLLoader, path)
+}
+
+// LoadHTMLFileは、HTMLを読み込み、暗号化されている場合は復号して返します。
+//
+// args:
+//
+//	path : HTMLのパス
+//
+// return:
+//
+//	string : HTMLの内容
+//	error  : 読み込みや復号に失敗した場合のエラー
+func (l *encryptedHTMLLoader) LoadHTMLFile(path string) (string, error) {
+	content, err := l.HTMLLoader.LoadHTMLFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(content, encryptedHTMLPrefix) {
+		return content, nil
+	}
+
+	plain, err := openHTML(l.aead, content)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return string(plain), nil
+}
+
+// LoadHTMLMetadataは、メタデータを読み込み、暗号化されている場合は復号して返します。
+//
+// args:
+//
+//	htmlPath : HTMLのパス
+//
+// return:
+//
+//	HTMLMetadata : 読み込んだメタデータ
+//	error        : 読み込みや復号に失敗した場合のエラー
+func (l *encryptedHTMLLoader) LoadHTMLMetadata(htmlPath string) (HTMLMetadata, error) {
+	meta, err := l.HTMLLoader.LoadHTMLMetadata(htmlPath)
+	if err != nil || meta.Encrypted == "" {
+		return meta, err
+	}
+
+	plain, err := openHTML(l.aead, meta.Encrypted)
+	if err != nil {
+		return HTMLMetadata{}, fmt.Errorf("%s のメタデータ: %w", htmlPath, err)
+	}
+
+	var decrypted HTMLMetadata
+	if err := json.Unmarshal(plain, &decrypted); err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータのデシリアライズに失敗しました: %w", err)
+	}
+	return decrypted, nil
+}
+
+// sealHTMLは、平文をAES-GCMで暗号化し、識別子とBase64でエンコードしたnonce・暗号文を連結した文字列を返します。
+// HTMLとメタデータはいずれも文字列として保存されるため、バイナリではなくテキストで表現します。
+func sealHTML(aead cipher.AEAD, plain []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("nonceの生成に失敗しました: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plain, []byte(encryptedHTMLPrefix))
+	return encryptedHTMLPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openHTMLは、sealHTMLで暗号化した文字列を復号します。
+func openHTML(aead cipher.AEAD, sealed string) ([]byte, error) {
+	if aead == nil {
+		return nil, ErrHTMLKeyRequired
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, encryptedHTMLPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("暗号文のBase64デコードに失敗しました: %w", err)
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("暗号文が短すぎます")
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(encryptedHTMLPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("復号に失敗しました。鍵が暗号化に使用したものと一致するか確認してください: %w", err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,21 @@
+package infra
+
+import "github.com/nrad-K/go-crawler/internal/domain/model"
+
+// JobPostingEnricherは、抽出した求人情報に、抽出した値から導出した項目（職種カテゴリーなど）を付加する処理です。
+// 抽出の後、フックと出力の前に呼び出されます。
+// スクレイパーのワーカーは並列に動作するため、実装は並行呼び出しに対して安全である必要があります。
+type JobPostingEnricher interface {
+	// Enrichは、項目を付加した求人情報を返します。元の求人情報は変更しません。
+	Enrich(posting model.JobPosting) model.JobPosting
+}
+
+// EnricherChainは、複数のJobPostingEnricherを登録順に呼び出すJobPostingEnricherの実装です。
+type EnricherChain []JobPostingEnricher
+
+func (c EnricherChain) Enrich(posting model.JobPosting) model.JobPosting {
+	for _, enricher := range c {
+		posting = enricher.Enrich(posting)
+	}
+	return posting
+}
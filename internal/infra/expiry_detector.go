@@ -0,0 +1,105 @@
+package infra
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// ExpiryDetectorは、掲載が終了した求人ページ（ソフト404）を判定します。
+// 多くの求人サイトは掲載終了後もHTTPステータス200で「掲載を終了しました」と表示したり、一覧ページへリダイレクトしたりするため、
+// ページの内容とリダイレクト先から判定します。
+//
+// フィールド:
+//
+//	textPatterns     : ページのテキストに含まれる場合に掲載終了と判定する正規表現
+//	minContentBytes  : ページのテキストがこのバイト数未満の場合に掲載終了と判定する（0は判定しない）
+//	redirectPatterns : リダイレクト先がこの正規表現に一致する場合に掲載終了と判定する
+type ExpiryDetector struct {
+	textPatterns     []*regexp.Regexp
+	minContentBytes  int
+	redirectPatterns []*regexp.Regexp
+}
+
+// NewExpiryDetectorは、設定からExpiryDetectorを生成します。判定条件が指定されていない場合はnilを返します。
+//
+// args:
+//
+//	cfg : 掲載終了の判定条件
+//
+// return:
+//
+//	*ExpiryDetector : 生成された判定器（判定条件がない場合はnil）
+//	error           : 正規表現の解釈に失敗した場合のエラー
+func NewExpiryDetector(cfg config.ExpiryConfig) (*ExpiryDetector, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	d := &ExpiryDetector{minContentBytes: cfg.MinContentBytes}
+	for _, pattern := range cfg.TextPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("text_patternsの正規表現 %q を解釈できません: %w", pattern, err)
+		}
+		d.textPatterns = append(d.textPatterns, re)
+	}
+	for _, pattern := range cfg.RedirectURLPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redirect_url_patternsの正規表現 %q を解釈できません: %w", pattern, err)
+		}
+		d.redirectPatterns = append(d.redirectPatterns, re)
+	}
+	return d, nil
+}
+
+// Detectは、ページが掲載終了かどうかを判定し、掲載終了の場合はその理由を返します。
+//
+// args:
+//
+//	html         : ページのHTML
+//	requestedURL : 要求したURL
+//	finalURL     : リダイレクト後のURL（不明な場合は空文字列）
+//
+// return:
+//
+//	string : 掲載終了と判定した理由（掲載終了でない場合は空文字列）
+//	bool   : 掲載終了と判定した場合はtrue
+func (d *ExpiryDetector) Detect(html, requestedURL, finalURL string) (string, bool) {
+	if finalURL != "" && finalURL != requestedURL {
+		for _, re := range d.redirectPatterns {
+			if re.MatchString(finalURL) {
+				return fmt.Sprintf("リダイレクト先 %s が redirect_url_patterns に一致しました", finalURL), true
+			}
+		}
+	}
+
+	if len(d.textPatterns) == 0 && d.minContentBytes == 0 {
+		return "", false
+	}
+
+	// スクリプトなどに含まれる文字列に反応しないよう、表示されるテキストのみを判定に使用する
+	text := html
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(html)); err == nil {
+		doc.Find("script, style, noscript").Remove()
+		text = doc.Find("body").Text()
+	}
+
+	for _, re := range d.textPatterns {
+		if match := re.FindString(text); match != "" {
+			return fmt.Sprintf("ページに %q が含まれています", match), true
+		}
+	}
+
+	if d.minContentBytes > 0 {
+		if size := len(strings.Join(strings.Fields(text), " ")); size < d.minContentBytes {
+			return fmt.Sprintf("ページのテキストが %dバイトしかありません", size), true
+		}
+	}
+
+	return "", false
+}
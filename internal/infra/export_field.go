@@ -0,0 +1,302 @@
+package infra
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// ExportFieldTypeは、エクスポートされる列の値の型です。JSON Schemaの型と形式に対応します。
+type ExportFieldType string
+
+const (
+	FieldTypeString   ExportFieldType = "string"    // 文字列
+	FieldTypeInteger  ExportFieldType = "integer"   // 整数
+	FieldTypeNumber   ExportFieldType = "number"    // 数値
+	FieldTypeDate     ExportFieldType = "date"      // 日付（YYYY-MM-DD）
+	FieldTypeDateTime ExportFieldType = "date-time" // 日時（RFC3339）
+)
+
+// ExportFieldは、エクスポートされる1列の定義です。
+// CSVのヘッダーと値、JSON Schemaの生成はすべてこの定義から行うため、列を追加・変更する場合はここを更新します。
+//
+// フィールド:
+//
+//	Key         : 機械可読なキー（JSON Schemaのプロパティ名）
+//	Header      : CSVのヘッダー
+//	Type        : 値の型
+//	Nullable    : 値が不明な場合に空になるかどうか
+//	Multi       : 複数の値を区切り文字（;）で連結した列かどうか
+//	Enum        : 取り得る値の一覧（列挙型の場合）
+//	Description : 列の説明
+//	Value       : 求人情報から列の値（CSVのセル）を取り出す関数
+type ExportField struct {
+	Key         string
+	Header      string
+	Type        ExportFieldType
+	Nullable    bool
+	Multi       bool
+	Enum        []string
+	Description string
+	Value       func(job model.JobPosting) string
+}
+
+// ExportHeadersは、列定義からCSVのヘッダー行を生成します。
+//
+// args:
+//
+//	fields : 列定義
+//
+// return:
+//
+//	[]string : ヘッダー行
+func ExportHeaders(fields []ExportField) []string {
+	headers := make([]string, 0, len(fields))
+	for _, field := range fields {
+		headers = append(headers, field.Header)
+	}
+	return headers
+}
+
+// SelectExportFieldsは、列定義から指定されたキーの列を、指定された順に選択した列定義を返します。
+// 業務内容詳細などの長い列を出力から除いたり、下流のシステムに合わせて列を並べ替えたりするために使用します。
+// キーを指定しない場合は、元の列定義をそのまま返します。
+//
+// args:
+//
+//	fields : 元の列定義
+//	keys   : 出力する列のキー（JSON Schemaのプロパティ名）
+//
+// return:
+//
+//	[]ExportField : 選択した列定義
+//	error         : 存在しない列や重複した列が指定された場合のエラー
+func SelectExportFields(fields []ExportField, keys []string) ([]ExportField, error) {
+	if len(keys) == 0 {
+		return fields, nil
+	}
+
+	byKey := make(map[string]ExportField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	selected := make([]ExportField, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("列 %s は存在しません", key)
+		}
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("列 %s が重複して指定されています", key)
+		}
+		seen[key] = struct{}{}
+		selected = append(selected, field)
+	}
+	return selected, nil
+}
+
+// AppendExtraExportFieldsは、設定ファイルのextra_fieldsで定義した追加の項目の列を、列定義の末尾に追加した列定義を返します。
+// 追加の列のキーとCSVのヘッダーはどちらも項目名で、値は抽出した文字列をそのまま出力します。
+//
+// args:
+//
+//	fields : 元の列定義
+//	names  : 追加の項目名（この順に追加します）
+//
+// return:
+//
+//	[]ExportField : 追加の列を含む列定義
+//	error         : 項目名が空の場合、または既存の列のキーまたはヘッダーと重複する場合のエラー
+func AppendExtraExportFields(fields []ExportField, names []string) ([]ExportField, error) {
+	if len(names) == 0 {
+		return fields, nil
+	}
+
+	extended := make([]ExportField, 0, len(fields)+len(names))
+	extended = append(extended, fields...)
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("追加の項目名が空です")
+		}
+		for _, field := range fields {
+			if field.Key == name || field.Header == name {
+				return nil, fmt.Errorf("追加の項目 %s は既存の列と重複しています", name)
+			}
+		}
+		extended = append(extended, ExportField{
+			Key:         name,
+			Header:      name,
+			Type:        FieldTypeString,
+			Nullable:    true,
+			Description: "extra_fieldsで定義した追加の項目",
+			Value:       func(j model.JobPosting) string { return j.Extra(name) },
+		})
+	}
+	return extended, nil
+}
+
+// DefaultExportFieldsは、スクレイパーが出力する列の定義を出力順に返します。
+func DefaultExportFields() []ExportField {
+	return []ExportField{
+		{Key: "company_name", Header: "会社名", Type: FieldTypeString, Description: "会社名",
+			Value: func(j model.JobPosting) string { return j.CompanyName() }},
+		{Key: "corporate_number", Header: "法人番号", Type: FieldTypeString, Nullable: true, Description: "会社の法人番号（国税庁の法人番号システムから取得、13桁）",
+			Value: func(j model.JobPosting) string { return j.CorporateNumber() }},
+		{Key: "title", Header: "タイトル", Type: FieldTypeString, Description: "求人のタイトル",
+			Value: func(j model.JobPosting) string { return j.Title() }},
+		{Key: "url", Header: "URL", Type: FieldTypeString, Description: "求人ページのURL",
+			Value: func(j model.JobPosting) string { return j.SummaryURL() }},
+		{Key: "location_prefecture_code", Header: "勤務地(都道府県コード)", Type: FieldTypeString, Nullable: true, Multi: true, Description: "勤務地の都道府県コード（JIS X 0401）",
+			Value: func(j model.JobPosting) string {
+				return joinLocations(j.Locations(), func(l model.Location) string { return string(l.PrefectureCode()) })
+			}},
+		{Key: "location_prefecture", Header: "勤務地(都道府県)", Type: FieldTypeString, Nullable: true, Multi: true, Description: "勤務地の都道府県名",
+			Value: func(j model.JobPosting) string {
+				return joinLocations(j.Locations(), func(l model.Location) string { return l.PrefectureName() })
+			}},
+		{Key: "location_city", Header: "勤務地(市区町村)", Type: FieldTypeString, Nullable: true, Multi: true, Description: "勤務地の市区町村",
+			Value: func(j model.JobPosting) string {
+				return joinLocations(j.Locations(), func(l model.Location) string { return l.City() })
+			}},
+		{Key: "location_municipality_code", Header: "勤務地(市区町村コード)", Type: FieldTypeString, Nullable: true, Multi: true, Description: "勤務地の市区町村コード（JIS X 0402）",
+			Value: func(j model.JobPosting) string {
+				return joinLocations(j.Locations(), func(l model.Location) string { return l.MunicipalityCode() })
+			}},
+		{Key: "location_raw", Header: "勤務地(原文)", Type: FieldTypeString, Nullable: true, Description: "勤務地の原文",
+			Value: func(j model.JobPosting) string { return firstLocationRaw(j.Locations()) }},
+		{Key: "headquarters_prefecture_code", Header: "本社(都道府県コード)", Type: FieldTypeString, Nullable: true, Description: "本社の都道府県コード（JIS X 0401）",
+			Value: func(j model.JobPosting) string { return string(j.Headquarters().PrefectureCode()) }},
+		{Key: "headquarters_prefecture", Header: "本社(都道府県)", Type: FieldTypeString, Nullable: true, Description: "本社の都道府県名",
+			Value: func(j model.JobPosting) string { return j.Headquarters().PrefectureName() }},
+		{Key: "headquarters_city", Header: "本社(市区町村)", Type: FieldTypeString, Nullable: true, Description: "本社の市区町村",
+			Value: func(j model.JobPosting) string { return j.Headquarters().City() }},
+		{Key: "headquarters_municipality_code", Header: "本社(市区町村コード)", Type: FieldTypeString, Nullable: true, Description: "本社の市区町村コード（JIS X 0402）",
+			Value: func(j model.JobPosting) string { return j.Headquarters().MunicipalityCode() }},
+		{Key: "headquarters_raw", Header: "本社(原文)", Type: FieldTypeString, Nullable: true, Description: "本社所在地の原文",
+			Value: func(j model.JobPosting) string { return j.Headquarters().Raw() }},
+		{Key: "job_type", Header: "雇用形態", Type: FieldTypeString, Description: "雇用形態",
+			Enum: []string{
+				string(model.FullTime), string(model.PartTime), string(model.Contract), string(model.Temporary),
+				string(model.Freelance), string(model.Internship), string(model.Other), string(model.Unknown),
+			},
+			Value: func(j model.JobPosting) string { return string(j.JobType()) }},
+		{Key: "category", Header: "職種カテゴリー", Type: FieldTypeString, Nullable: true, Description: "categoriesの分類体系で判定した職種カテゴリー",
+			Value: func(j model.JobPosting) string { return j.Category() }},
+		{Key: "salary_min", Header: "給与(下限)", Type: FieldTypeInteger, Nullable: true, Description: "給与の下限（円）",
+			Value: func(j model.JobPosting) string {
+				amount := j.Salary().MinAmount()
+				return amount.Format()
+			}},
+		{Key: "salary_max", Header: "給与(上限)", Type: FieldTypeInteger, Nullable: true, Description: "給与の上限（円）",
+			Value: func(j model.JobPosting) string {
+				amount := j.Salary().MaxAmount()
+				return amount.Format()
+			}},
+		{Key: "salary_unit", Header: "給与(単位)", Type: FieldTypeString, Description: "給与の単位",
+			Enum: []string{
+				string(model.Hourly), string(model.Daily), string(model.Monthly), string(model.Yearly), string(model.UnknownSalaryType),
+			},
+			Value: func(j model.JobPosting) string { return string(j.Salary().Unit()) }},
+		{Key: "salary_currency", Header: "給与(通貨)", Type: FieldTypeString, Nullable: true, Description: "給与の通貨（ISO 4217の通貨コード）",
+			Value: func(j model.JobPosting) string { return string(j.Salary().Currency()) }},
+		{Key: "salary_negotiable", Header: "給与(応相談)", Type: FieldTypeString, Enum: []string{"true", "false"}, Description: "給与が応相談と記載されているかどうか",
+			Value: func(j model.JobPosting) string { return strconv.FormatBool(j.Salary().Negotiable()) }},
+		{Key: "salary_fixed_overtime", Header: "固定残業代", Type: FieldTypeString, Enum: []string{"true", "false"}, Description: "給与に固定残業代（みなし残業）が含まれるかどうか",
+			Value: func(j model.JobPosting) string { return strconv.FormatBool(j.Salary().FixedOvertime().Included()) }},
+		{Key: "salary_fixed_overtime_hours", Header: "固定残業時間", Type: FieldTypeInteger, Nullable: true, Description: "固定残業代に含まれる月あたりの残業時間",
+			Value: func(j model.JobPosting) string { return formatUint(j.Salary().FixedOvertime().Hours()) }},
+		{Key: "salary_fixed_overtime_pay", Header: "固定残業代(金額)", Type: FieldTypeInteger, Nullable: true, Description: "固定残業代の金額（円）",
+			Value: func(j model.JobPosting) string {
+				amount := j.Salary().FixedOvertime().Amount()
+				return amount.Format()
+			}},
+		{Key: "income_band", Header: "年収帯", Type: FieldTypeString, Nullable: true, Description: "年収に換算した給与のincome_bandsによる年収帯",
+			Value: func(j model.JobPosting) string { return j.IncomeBand() }},
+		{Key: "posted_at", Header: "投稿日", Type: FieldTypeDate, Description: "求人の投稿日",
+			Value: func(j model.JobPosting) string { return j.PostedAt().Format("2006-01-02") }},
+		{Key: "expires_at", Header: "応募締切日", Type: FieldTypeDate, Nullable: true, Description: "応募締切日（掲載終了日）",
+			Value: func(j model.JobPosting) string { return formatTime(j.ExpiresAt(), "2006-01-02") }},
+		{Key: "crawled_at", Header: "クロール日時", Type: FieldTypeDateTime, Nullable: true, Description: "求人ページを取得した日時",
+			Value: func(j model.JobPosting) string { return formatTime(j.CrawledAt(), time.RFC3339) }},
+		{Key: "expired", Header: "掲載終了", Type: FieldTypeString, Enum: []string{"true", "false"}, Description: "求人ページが掲載終了と判定されたかどうか",
+			Value: func(j model.JobPosting) string { return strconv.FormatBool(j.Expired()) }},
+		{Key: "list_page", Header: "掲載ページ", Type: FieldTypeInteger, Nullable: true, Description: "求人が見つかった一覧ページのページ番号",
+			Value: func(j model.JobPosting) string { return formatPositiveInt(j.Listing().Page) }},
+		{Key: "list_position", Header: "掲載位置", Type: FieldTypeInteger, Nullable: true, Description: "求人が見つかった一覧ページ内の掲載位置（1始まり）",
+			Value: func(j model.JobPosting) string { return formatPositiveInt(j.Listing().Position) }},
+		{Key: "job_name", Header: "職務内容", Type: FieldTypeString, Description: "職務内容",
+			Value: func(j model.JobPosting) string { return j.Details().JobName() }},
+		{Key: "raise", Header: "昇給", Type: FieldTypeInteger, Nullable: true, Description: "年間の昇給回数",
+			Value: func(j model.JobPosting) string { return formatUint(j.Details().Raise()) }},
+		{Key: "bonus", Header: "賞与", Type: FieldTypeInteger, Nullable: true, Description: "年間の賞与回数",
+			Value: func(j model.JobPosting) string { return formatUint(j.Details().Bonus()) }},
+		{Key: "description", Header: "業務内容詳細", Type: FieldTypeString, Description: "業務内容の詳細",
+			Value: func(j model.JobPosting) string { return j.Details().Description() }},
+		{Key: "requirements", Header: "応募要件", Type: FieldTypeString, Description: "応募要件",
+			Value: func(j model.JobPosting) string { return j.Details().Requirements() }},
+		{Key: "workplace_type", Header: "勤務形態", Type: FieldTypeString, Description: "勤務形態",
+			Enum: []string{
+				string(model.Onsite), string(model.Remote), string(model.Hybrid), string(model.FullRemote), string(model.UnknownWorkplace),
+			},
+			Value: func(j model.JobPosting) string { return string(j.Details().WorkplaceType()) }},
+		{Key: "holidays_per_year", Header: "年間休日", Type: FieldTypeInteger, Nullable: true, Description: "年間休日数",
+			Value: func(j model.JobPosting) string { return formatUint(j.Details().HolidaysPerYear()) }},
+		{Key: "holiday_policy", Header: "休日・休暇", Type: FieldTypeString, Description: "休日制度",
+			Enum: []string{
+				string(model.CompleteTwoDaysAWeek), string(model.TwoDaysAWeek), string(model.OneDayAWeek), string(model.ShiftSystem), string(model.UnknownHoliday),
+			},
+			Value: func(j model.JobPosting) string { return string(j.Details().HolidayPolicy()) }},
+		{Key: "work_hours_raw", Header: "勤務時間", Type: FieldTypeString, Description: "勤務時間の原文",
+			Value: func(j model.JobPosting) string { return j.Details().WorkHours() }},
+		{Key: "shift_start", Header: "勤務開始時刻", Type: FieldTypeString, Nullable: true, Multi: true, Description: "勤務パターンごとの開始時刻（HH:MM）",
+			Value: func(j model.JobPosting) string {
+				return joinShifts(j.Details().WorkShifts(), func(w model.WorkShift) string { return w.Start() })
+			}},
+		{Key: "shift_end", Header: "勤務終了時刻", Type: FieldTypeString, Nullable: true, Multi: true, Description: "勤務パターンごとの終了時刻（HH:MM）",
+			Value: func(j model.JobPosting) string {
+				return joinShifts(j.Details().WorkShifts(), func(w model.WorkShift) string { return w.End() })
+			}},
+		{Key: "shift_break_minutes", Header: "休憩時間(分)", Type: FieldTypeInteger, Nullable: true, Multi: true, Description: "勤務パターンごとの休憩時間（分）",
+			Value: func(j model.JobPosting) string {
+				return joinShifts(j.Details().WorkShifts(), func(w model.WorkShift) string { return formatUint(w.BreakMinutes()) })
+			}},
+		{Key: "shift_working_hours", Header: "実働時間", Type: FieldTypeNumber, Nullable: true, Multi: true, Description: "勤務パターンごとの実働時間（時間）",
+			Value: func(j model.JobPosting) string {
+				return joinShifts(j.Details().WorkShifts(), func(w model.WorkShift) string {
+					return strconv.FormatFloat(w.WorkingHours(), 'f', -1, 64)
+				})
+			}},
+		{Key: "station_line", Header: "最寄り駅(路線)", Type: FieldTypeString, Nullable: true, Description: "最寄り駅の路線名",
+			Value: func(j model.JobPosting) string { return j.Details().NearestStation().Line() }},
+		{Key: "station_name", Header: "最寄り駅", Type: FieldTypeString, Nullable: true, Description: "最寄り駅名",
+			Value: func(j model.JobPosting) string { return j.Details().NearestStation().Name() }},
+		{Key: "station_walk_minutes", Header: "最寄り駅(徒歩分)", Type: FieldTypeInteger, Nullable: true, Description: "最寄り駅からの徒歩分数",
+			Value: func(j model.JobPosting) string { return formatUint(j.Details().NearestStation().WalkMinutes()) }},
+		{Key: "contract_months", Header: "契約期間(月)", Type: FieldTypeInteger, Nullable: true, Description: "契約期間の月数",
+			Value: func(j model.JobPosting) string { return formatUint(j.Details().ContractPeriod().Months()) }},
+		{Key: "contract_long_term", Header: "契約期間(長期)", Type: FieldTypeString, Enum: []string{"true", "false"}, Description: "契約期間が長期と記載されているかどうか",
+			Value: func(j model.JobPosting) string { return strconv.FormatBool(j.Details().ContractPeriod().LongTerm()) }},
+		{Key: "contract_renewable", Header: "契約更新", Type: FieldTypeString, Nullable: true, Description: "契約の更新の有無（true・false、記載がない場合は空）",
+			Value: func(j model.JobPosting) string { return formatBool(j.Details().ContractPeriod().Renewable()) }},
+		{Key: "probation_months", Header: "試用期間(月)", Type: FieldTypeInteger, Nullable: true, Description: "試用期間の月数（試用期間がない場合は0）",
+			Value: func(j model.JobPosting) string { return formatUint(j.Details().ProbationPeriod().Months()) }},
+		{Key: "probation_condition_changed", Header: "試用期間の条件変更", Type: FieldTypeString, Nullable: true, Description: "試用期間中に労働条件が変わるかどうか（true・false、記載がない場合は空）",
+			Value: func(j model.JobPosting) string { return formatBool(j.Details().ProbationPeriod().ConditionChanged()) }},
+		{Key: "smoking_policy", Header: "受動喫煙対策", Type: FieldTypeString, Description: "受動喫煙対策（抽出しない場合は不明）",
+			Enum: []string{
+				string(model.NoSmokingPremises), string(model.NoSmokingIndoors), string(model.SmokingRoom), string(model.SmokingAllowed), string(model.UnknownSmoking),
+			},
+			Value: func(j model.JobPosting) string {
+				if j.Details().SmokingPolicy() == "" {
+					return string(model.UnknownSmoking)
+				}
+				return string(j.Details().SmokingPolicy())
+			}},
+		{Key: "benefits_raw", Header: "福利厚生(原文)", Type: FieldTypeString, Description: "福利厚生の原文",
+			Value: func(j model.JobPosting) string { return j.Details().Benefits().RawBenefits() }},
+	}
+}
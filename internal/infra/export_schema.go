@@ -0,0 +1,66 @@
+package infra
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersionは、エクスポートデータのスキーマバージョンを表します。
+//
+// 互換性ポリシー:
+//
+//	Major : 列の削除・名称変更・値の意味や書式の変更など、既存の利用者が壊れる変更で増やします。
+//	Minor : 列の追加など、既存の列をそのまま読めば動作し続ける変更で増やします。
+//
+// 利用者は、Majorが一致し、かつMinorが自身の想定以上であれば読み込みを継続できます。
+type SchemaVersion struct {
+	Major int
+	Minor int
+}
+
+// Stringは、スキーマバージョンを"MAJOR.MINOR"形式で返します。
+func (v SchemaVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// IsCompatibleWithは、このバージョンのデータを、指定されたバージョンを想定した利用者が読み込めるかを判定します。
+//
+// args:
+//
+//	expected : 利用者が想定しているスキーマバージョン
+//
+// return:
+//
+//	bool : 互換性がある場合はtrue
+func (v SchemaVersion) IsCompatibleWith(expected SchemaVersion) bool {
+	return v.Major == expected.Major && v.Minor >= expected.Minor
+}
+
+// ParseSchemaVersionは、"MAJOR.MINOR"形式の文字列をSchemaVersionに変換します。
+//
+// args:
+//
+//	s : 変換対象の文字列 (例: "1.2")
+//
+// return:
+//
+//	SchemaVersion : 変換されたスキーマバージョン
+//	error         : 形式が不正な場合のエラー
+func ParseSchemaVersion(s string) (SchemaVersion, error) {
+	majorStr, minorStr, ok := strings.Cut(strings.TrimSpace(s), ".")
+	if !ok {
+		return SchemaVersion{}, fmt.Errorf("スキーマバージョンの形式が不正です: %s", s)
+	}
+
+	major, err := strconv.Atoi(majorStr)
+	if err != nil || major < 0 {
+		return SchemaVersion{}, fmt.Errorf("スキーマバージョンのメジャー番号が不正です: %s", s)
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil || minor < 0 {
+		return SchemaVersion{}, fmt.Errorf("スキーマバージョンのマイナー番号が不正です: %s", s)
+	}
+
+	return SchemaVersion{Major: major, Minor: minor}, nil
+}
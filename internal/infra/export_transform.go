@@ -0,0 +1,130 @@
+package infra
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// ApplyExportTransformsは、列定義に列ごとの変換（ハッシュ化・範囲への丸め・削除）を適用した列定義を返します。
+// 出力先ごとに異なる変換を適用することで、1回のスクレイプから社内向けの完全なデータと共有用の匿名化したデータを同時に出力できます。
+// 元の列定義は変更しません。
+//
+// args:
+//
+//	fields     : 元の列定義
+//	transforms : 列ごとの変換
+//
+// return:
+//
+//	[]ExportField : 変換を適用した列定義
+//	error         : 存在しない列や変換できない型の列が指定された場合のエラー
+func ApplyExportTransforms(fields []ExportField, transforms []config.ColumnTransform) ([]ExportField, error) {
+	if len(transforms) == 0 {
+		return fields, nil
+	}
+
+	byKey := make(map[string]config.ColumnTransform, len(transforms))
+	for _, transform := range transforms {
+		if _, ok := byKey[transform.Column]; ok {
+			return nil, fmt.Errorf("列 %s の変換が重複して指定されています", transform.Column)
+		}
+		byKey[transform.Column] = transform
+	}
+
+	transformed := make([]ExportField, 0, len(fields))
+	for _, field := range fields {
+		transform, ok := byKey[field.Key]
+		if !ok {
+			transformed = append(transformed, field)
+			continue
+		}
+		delete(byKey, field.Key)
+
+		switch transform.Type {
+		case config.TransformDrop:
+			continue
+		case config.TransformHash:
+			transformed = append(transformed, hashedField(field, transform.Salt))
+		case config.TransformBucket:
+			if field.Type != FieldTypeInteger {
+				return nil, fmt.Errorf("列 %s は整数の列ではないため、範囲に丸めることはできません", field.Key)
+			}
+			transformed = append(transformed, bucketedField(field, transform.BucketSize))
+		default:
+			return nil, fmt.Errorf("列 %s の変換の種類 %s はサポートされていません", field.Key, transform.Type)
+		}
+	}
+
+	if len(byKey) > 0 {
+		unknown := make([]string, 0, len(byKey))
+		for key := range byKey {
+			unknown = append(unknown, key)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("変換を指定した列が存在しません: %s", strings.Join(unknown, ", "))
+	}
+	return transformed, nil
+}
+
+// hashedFieldは、値をHMAC-SHA256のハッシュ値（16進数）に置き換える列定義を返します。
+// 同じ値は同じハッシュ値になるため、元の値を含めずに同じ会社の求人を集計できます。
+// saltを指定しない場合、ありふれた値は総当たりで元の値を推測できることに注意してください。
+func hashedField(field ExportField, salt string) ExportField {
+	value := field.Value
+	field.Type = FieldTypeString
+	field.Enum = nil
+	field.Description += "（ハッシュ値）"
+	field.Value = func(j model.JobPosting) string {
+		return mapExportValue(field, value(j), func(raw string) string {
+			mac := hmac.New(sha256.New, []byte(salt))
+			mac.Write([]byte(raw))
+			return hex.EncodeToString(mac.Sum(nil))
+		})
+	}
+	return field
+}
+
+// bucketedFieldは、整数の値をsize単位の範囲（例: 3000000-3999999）に丸める列定義を返します。
+func bucketedField(field ExportField, size uint64) ExportField {
+	value := field.Value
+	field.Type = FieldTypeString
+	field.Description += fmt.Sprintf("（%d単位の範囲）", size)
+	field.Value = func(j model.JobPosting) string {
+		return mapExportValue(field, value(j), func(raw string) string {
+			v, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				// 元の値を漏らさないよう、整数として解釈できない値は出力しない
+				return ""
+			}
+			lower := v / size * size
+			return fmt.Sprintf("%d-%d", lower, lower+size-1)
+		})
+	}
+	return field
+}
+
+// mapExportValueは、列の値に変換を適用します。複数の値を持つ列は値ごとに変換し、空の値は空のまま返します。
+func mapExportValue(field ExportField, raw string, convert func(string) string) string {
+	if raw == "" {
+		return ""
+	}
+	if !field.Multi {
+		return convert(raw)
+	}
+
+	parts := strings.Split(raw, multiValueSeparator)
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = convert(part)
+		}
+	}
+	return strings.Join(parts, multiValueSeparator)
+}
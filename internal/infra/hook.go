@@ -0,0 +1,436 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// ErrHookVetoは、フックがレコードの出力を拒否したことを示すエラーです。
+var ErrHookVeto = errors.New("フックによって拒否されました")
+
+// Hookは、スクレイピングとクロールの各段階で呼び出される拡張ポイントです。
+// スクレイパーのワーカーは並列に動作するため、実装は並行呼び出しに対して安全である必要があります。
+type Hook interface {
+	// OnPostingParsedは、求人情報を抽出した直後に呼び出されます。
+	// 戻り値の求人情報が以降の処理で使用されるため、情報の補完に利用できます。
+	// ErrHookVetoをラップしたエラーを返すと、その求人情報は出力されません。
+	OnPostingParsed(ctx context.Context, posting model.JobPosting) (model.JobPosting, error)
+	// OnRowExportedは、求人情報を出力した直後に呼び出されます。
+	OnRowExported(ctx context.Context, posting model.JobPosting) error
+	// OnJobCompletedは、クロールジョブの処理が完了した直後に呼び出されます。失敗した場合はcrawlErrに原因が渡されます。
+	OnJobCompleted(ctx context.Context, job model.CrawlJob, crawlErr error) error
+}
+
+// NopHookは、何もしないHookの実装です。埋め込むことで、必要なメソッドだけを実装できます。
+type NopHook struct{}
+
+// OnPostingParsedは、求人情報をそのまま返します。
+//
+// args:
+//
+//	ctx     : コンテキスト
+//	posting : 抽出した求人情報
+//
+// return:
+//
+//	model.JobPosting : 受け取った求人情報
+//	error            : 常にnil
+func (NopHook) OnPostingParsed(ctx context.Context, posting model.JobPosting) (model.JobPosting, error) {
+	return posting, nil
+}
+
+// OnRowExportedは、何もしません。
+//
+// args:
+//
+//	ctx     : コンテキスト
+//	posting : 出力した求人情報
+//
+// return:
+//
+//	error : 常にnil
+func (NopHook) OnRowExported(ctx context.Context, posting model.JobPosting) error {
+	return nil
+}
+
+// OnJobCompletedは、何もしません。
+//
+// args:
+//
+//	ctx      : コンテキスト
+//	job      : 処理が完了したクロールジョブ
+//	crawlErr : クロールに失敗した場合の原因
+//
+// return:
+//
+//	error : 常にnil
+func (NopHook) OnJobCompleted(ctx context.Context, job model.CrawlJob, crawlErr error) error {
+	return nil
+}
+
+// HookChainは、複数のフックを登録順に呼び出すHookの実装です。
+// OnPostingParsedでいずれかのフックがエラーを返した場合、以降のフックは呼び出されません。
+type HookChain []Hook
+
+// OnPostingParsedは、各フックのOnPostingParsedを登録順に呼び出し、前のフックが返した求人情報を次のフックに渡します。
+//
+// args:
+//
+//	ctx     : コンテキスト
+//	posting : 抽出した求人情報
+//
+// return:
+//
+//	model.JobPosting : 最後のフックが返した求人情報
+//	error            : いずれかのフックが返したエラー（以降のフックは呼び出さない）
+func (c HookChain) OnPostingParsed(ctx context.Context, posting model.JobPosting) (model.JobPosting, error) {
+	for _, hook := range c {
+		var err error
+		posting, err = hook.OnPostingParsed(ctx, posting)
+		if err != nil {
+			return posting, err
+		}
+	}
+	return posting, nil
+}
+
+// OnRowExportedは、すべてのフックのOnRowExportedを登録順に呼び出します。
+//
+// args:
+//
+//	ctx     : コンテキスト
+//	posting : 出力した求人情報
+//
+// return:
+//
+//	error : 各フックが返したエラーをまとめたエラー
+func (c HookChain) OnRowExported(ctx context.Context, posting model.JobPosting) error {
+	var errs []error
+	for _, hook := range c {
+		errs = append(errs, hook.OnRowExported(ctx, posting))
+	}
+	return errors.Join(errs...)
+}
+
+// OnJobCompletedは、すべてのフックのOnJobCompletedを登録順に呼び出します。
+//
+// args:
+//
+//	ctx      : コンテキスト
+//	job      : 処理が完了したクロールジョブ
+//	crawlErr : クロールに失敗した場合の原因
+//
+// return:
+//
+//	error : 各フックが返したエラーをまとめたエラー
+func (c HookChain) OnJobCompleted(ctx context.Context, job model.CrawlJob, crawlErr error) error {
+	var errs []error
+	for _, hook := range c {
+		errs = append(errs, hook.OnJobCompleted(ctx, job, crawlErr))
+	}
+	return errors.Join(errs...)
+}
+
+// defaultHookTimeoutは、timeout_secondsが未指定の場合のフック呼び出しのタイムアウトです。
+const defaultHookTimeout = 10 * time.Second
+
+// hookPayloadは、Webhookとスクリプトに渡すJSONです。
+//
+// フィールド:
+//
+//	Event   : 呼び出されたイベント
+//	Posting : 求人情報（列定義のキーと値）。posting_parsed、row_exportedの場合のみ
+//	Job     : クロールジョブ。job_completedの場合のみ
+//	Error   : クロールジョブが失敗した場合の原因
+type hookPayload struct {
+	Event   config.HookEvent  `json:"event"`
+	Posting map[string]string `json:"posting,omitempty"`
+	Job     *CrawlJobRecord   `json:"job,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// hookResponseは、Webhookのレスポンスボディやスクリプトの標準出力として受け付けるJSONです。
+// 空の場合は受理されたものとして扱います。
+//
+// フィールド:
+//
+//	Veto    : 求人情報の出力を拒否する場合はtrue
+//	Reason  : 拒否した理由
+//	Posting : 求人情報に設定する値（列定義のキーと値）。posting_parsedの場合のみ適用する
+type hookResponse struct {
+	Veto    bool              `json:"veto"`
+	Reason  string            `json:"reason"`
+	Posting map[string]string `json:"posting,omitempty"`
+}
+
+// hookInvokerは、ペイロードを外部（Webhookまたはスクリプト）に渡し、レスポンスを受け取る関数です。
+type hookInvoker func(ctx context.Context, payload []byte) ([]byte, error)
+
+// externalHookは、設定ファイルから登録されたWebhookまたはスクリプトを呼び出すHookの実装です。
+//
+// フィールド:
+//
+//	name    : ログやエラーに表示するフックの名前
+//	events  : 呼び出すイベント
+//	fields  : 求人情報をJSONに変換する際の列定義
+//	timeout : 1回の呼び出しのタイムアウト
+//	invoke  : 外部への呼び出し
+//	extras  : 追加の項目（extra_fields）の列のキー
+type externalHook struct {
+	name    string
+	events  []config.HookEvent
+	fields  []ExportField
+	timeout time.Duration
+	invoke  hookInvoker
+	extras  map[string]bool
+}
+
+// NewHooksFromConfigは、設定ファイルのフック定義からHookを生成します。
+//
+// args:
+//
+//	cfgs   : フックの設定
+//	fields : 求人情報をJSONに変換する際の列定義
+//
+// return:
+//
+//	Hook  : 登録順に呼び出すHookChain
+//	error : 定義が不正な場合のエラー
+func NewHooksFromConfig(cfgs []config.HookConfig, fields []ExportField) (Hook, error) {
+	// 既定の列にないキーは、extra_fieldsで定義した追加の項目の列
+	defaults := make(map[string]bool)
+	for _, field := range DefaultExportFields() {
+		defaults[field.Key] = true
+	}
+	extras := make(map[string]bool)
+	for _, field := range fields {
+		if !defaults[field.Key] {
+			extras[field.Key] = true
+		}
+	}
+
+	chain := make(HookChain, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		timeout := defaultHookTimeout
+		if cfg.TimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+		}
+
+		hook := &externalHook{
+			events:  cfg.Events,
+			fields:  fields,
+			timeout: timeout,
+			extras:  extras,
+		}
+		switch cfg.Type {
+		case config.HookScript:
+			if len(cfg.Command) == 0 {
+				return nil, fmt.Errorf("scriptフックにはcommandが必要です")
+			}
+			hook.name = cfg.Command[0]
+			hook.invoke = scriptInvoker(cfg.Command)
+		default:
+			hook.name = cfg.URL
+			hook.invoke = webhookInvoker(cfg.URL)
+		}
+		chain = append(chain, hook)
+	}
+	return chain, nil
+}
+
+// OnPostingParsedは、posting_parsedが対象のイベントであれば求人情報を外部に渡し、
+// レスポンスのpostingに含まれる値を設定した求人情報を返します。
+//
+// args:
+//
+//	ctx     : コンテキスト
+//	posting : 抽出した求人情報
+//
+// return:
+//
+//	model.JobPosting : レスポンスの値を設定した求人情報
+//	error            : 呼び出しに失敗した場合、レスポンスの値を設定できない場合、または拒否された場合（ErrHookVetoをラップ）のエラー
+func (h *externalHook) OnPostingParsed(ctx context.Context, posting model.JobPosting) (model.JobPosting, error) {
+	response, err := h.call(ctx, hookPayload{
+		Event:   config.HookEventPostingParsed,
+		Posting: h.record(posting),
+	})
+	if err != nil {
+		return posting, err
+	}
+	return h.apply(posting, response.Posting)
+}
+
+// OnRowExportedは、row_exportedが対象のイベントであれば出力した求人情報を外部に渡します。
+//
+// args:
+//
+//	ctx     : コンテキスト
+//	posting : 出力した求人情報
+//
+// return:
+//
+//	error : 呼び出しに失敗した場合のエラー
+func (h *externalHook) OnRowExported(ctx context.Context, posting model.JobPosting) error {
+	_, err := h.call(ctx, hookPayload{
+		Event:   config.HookEventRowExported,
+		Posting: h.record(posting),
+	})
+	return err
+}
+
+// OnJobCompletedは、job_completedが対象のイベントであれば処理が完了したクロールジョブを外部に渡します。
+//
+// args:
+//
+//	ctx      : コンテキスト
+//	job      : 処理が完了したクロールジョブ
+//	crawlErr : クロールに失敗した場合の原因
+//
+// return:
+//
+//	error : 呼び出しに失敗した場合のエラー
+func (h *externalHook) OnJobCompleted(ctx context.Context, job model.CrawlJob, crawlErr error) error {
+	record := ToRecord(job)
+	payload := hookPayload{
+		Event: config.HookEventJobCompleted,
+		Job:   &record,
+	}
+	if crawlErr != nil {
+		payload.Error = crawlErr.Error()
+	}
+	_, err := h.call(ctx, payload)
+	return err
+}
+
+// applyは、フックのレスポンスの値を求人情報に設定します。
+// 抽出した値を補完する用途のため、設定できるのは職種カテゴリー・年収帯・法人番号と、extra_fieldsで定義した追加の項目です。
+// 1つでも設定できないキーがある場合は、求人情報を変更せずにエラーを返します。
+//
+// args:
+//
+//	posting : 抽出した求人情報
+//	values  : 設定する値（列定義のキーと値）
+//
+// return:
+//
+//	model.JobPosting : 値を設定した求人情報
+//	error            : 設定できないキーが含まれている場合のエラー
+func (h *externalHook) apply(posting model.JobPosting, values map[string]string) (model.JobPosting, error) {
+	enriched := posting
+	for key, value := range values {
+		switch {
+		case key == "category":
+			enriched = enriched.WithCategory(value)
+		case key == "income_band":
+			enriched = enriched.WithIncomeBand(value)
+		case key == "corporate_number":
+			enriched = enriched.WithCorporateNumber(value)
+		case h.extras[key]:
+			enriched = enriched.WithExtra(key, value)
+		default:
+			return posting, fmt.Errorf("フック %s のレスポンスの %s はフックから設定できない項目です", h.name, key)
+		}
+	}
+	return enriched, nil
+}
+
+// recordは、求人情報を列定義のキーと値のマップに変換します。
+func (h *externalHook) record(posting model.JobPosting) map[string]string {
+	record := make(map[string]string, len(h.fields))
+	for _, field := range h.fields {
+		record[field.Key] = field.Value(posting)
+	}
+	return record
+}
+
+// callは、対象のイベントであればペイロードを外部に渡してレスポンスを返し、レスポンスが拒否を示していればErrHookVetoを返します。
+// 対象のイベントでない場合やレスポンスが空の場合は、空のレスポンスを返します。
+func (h *externalHook) call(ctx context.Context, payload hookPayload) (hookResponse, error) {
+	var response hookResponse
+	if !slices.Contains(h.events, payload.Event) {
+		return response, nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return response, fmt.Errorf("フック %s のペイロードのマーシャルに失敗しました: %w", h.name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	output, err := h.invoke(ctx, body)
+	if err != nil {
+		return response, fmt.Errorf("フック %s の呼び出しに失敗しました: %w", h.name, err)
+	}
+
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return response, nil
+	}
+
+	if err := json.Unmarshal(output, &response); err != nil {
+		return hookResponse{}, fmt.Errorf("フック %s のレスポンスの解析に失敗しました: %w", h.name, err)
+	}
+	if response.Veto {
+		return response, fmt.Errorf("%w: %s (%s)", ErrHookVeto, h.name, response.Reason)
+	}
+	return response, nil
+}
+
+// webhookInvokerは、ペイロードをJSONとしてURLにPOSTし、レスポンスボディを返すhookInvokerを生成します。
+// 2xx以外のステータスコードはエラーとして扱います。
+func webhookInvoker(url string) hookInvoker {
+	return func(ctx context.Context, payload []byte) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("リクエストの作成に失敗しました: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("リクエストの送信に失敗しました: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("レスポンスの読み込みに失敗しました: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("ステータスコード %d が返されました", resp.StatusCode)
+		}
+		return body, nil
+	}
+}
+
+// scriptInvokerは、コマンドの標準入力にペイロードを渡して実行し、標準出力を返すhookInvokerを生成します。
+// 終了コードが0以外の場合はエラーとして扱います。
+func scriptInvoker(command []string) hookInvoker {
+	return func(ctx context.Context, payload []byte) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdin = bytes.NewReader(payload)
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("コマンドの実行に失敗しました: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return output, nil
+	}
+}
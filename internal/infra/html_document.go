@@ -7,50 +7,82 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// HTMLParserは、HTMLを解析してHTMLDocumentを生成するインターフェースです。
+// 1つのHTMLから多くの項目を抽出する場合に、HTMLの解析を1回で済ませるために使用します。
+type HTMLParser interface {
+	Parse(html string) (HTMLDocument, error)
+}
+
+// HTMLDocumentは、解析済みの1つのHTMLから、セレクターなどで値を取り出すインターフェースです。
 type HTMLDocument interface {
-	ExtractText(html string, selector string) ([]string, error)
-	ExtractAttribute(html string, selector, attr string) ([]string, error)
-	ExtractTextByRegex(html, selector, pattern string) ([]string, error)
+	ExtractText(selector string) ([]string, error)
+	ExtractAttribute(selector, attr string) ([]string, error)
+	ExtractTextByRegex(selector, pattern string) ([]string, error)
+	ExtractJobPostingJSONLD() (JSONLDJobPosting, bool)
+	ExtractPageMeta() PageMeta
 }
 
-type htmlDocument struct {
+// htmlParserは、goqueryを用いたHTMLParserの実装です。
+type htmlParser struct {
 }
 
-func NewHTMLDocument() *htmlDocument {
-	return &htmlDocument{}
+func NewHTMLParser() *htmlParser {
+	return &htmlParser{}
+}
+
+// Parseは、HTMLを解析してHTMLDocumentを返します。
+//
+// args:
+//
+//	html : 解析対象のHTML文字列
+//
+// return:
+//
+//	HTMLDocument : 解析済みのHTML
+//	error        : HTMLの解析に失敗した場合のエラー
+func (p *htmlParser) Parse(html string) (HTMLDocument, error) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+	return &htmlDocument{document: document}, nil
+}
+
+// htmlDocumentは、goqueryで解析済みのHTMLを保持するHTMLDocumentの実装です。
+// 抽出ではドキュメントを変更しないため、同じHTMLに対して繰り返しセレクターを適用できます。
+//
+// フィールド:
+//
+//	document : 解析済みのHTML
+type htmlDocument struct {
+	document *goquery.Document
 }
 
 // ExtractText はHTMLから特定のセレクタにマッチする要素のテキストを抽出します。
 //
 // 使用例:
 //
-//   - 段落テキストの抽出: ExtractText(html, "p")
+//   - 段落テキストの抽出: ExtractText("p")
 //     入力: <p>これは段落です</p>
 //     出力: ["これは段落です"]
 //
-//   - リスト項目の抽出: ExtractText(html, "li")
+//   - リスト項目の抽出: ExtractText("li")
 //     入力: <ul><li>項目1</li><li>項目2</li></ul>
 //     出力: ["項目1", "項目2"]
 //
-//   - クラス指定での抽出: ExtractText(html, ".title")
+//   - クラス指定での抽出: ExtractText(".title")
 //     入力: <h1 class="title">メインタイトル</h1>
 //     出力: ["メインタイトル"]
 //
 // パラメータ:
-//   - html: 解析対象のHTML文字列
 //   - selector: 要素を選択するためのCSSセレクタ
 //
 // 戻り値:
 //   - []string: 抽出されたテキストの配列
 //   - error: エラーが発生した場合のエラー情報
-func (h *htmlDocument) ExtractText(html string, selector string) ([]string, error) {
-	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return nil, err
-	}
-
+func (h *htmlDocument) ExtractText(selector string) ([]string, error) {
 	var texts []string
-	document.Find(selector).Each(func(_ int, s *goquery.Selection) {
+	h.document.Find(selector).Each(func(_ int, s *goquery.Selection) {
 		texts = append(texts, s.Text())
 	})
 
@@ -61,34 +93,28 @@ func (h *htmlDocument) ExtractText(html string, selector string) ([]string, erro
 //
 // 使用例:
 //
-//   - リンクのhref属性抽出: ExtractAttribute(html, "a", "href")
+//   - リンクのhref属性抽出: ExtractAttribute("a", "href")
 //     入力: <a href="https://example.com">リンク</a>
 //     出力: ["https://example.com"]
 //
-//   - 画像のsrc属性抽出: ExtractAttribute(html, "img", "src")
+//   - 画像のsrc属性抽出: ExtractAttribute("img", "src")
 //     入力: <img src="image.jpg" alt="画像">
 //     出力: ["image.jpg"]
 //
-//   - カスタムデータ属性の抽出: ExtractAttribute(html, "div", "data-id")
+//   - カスタムデータ属性の抽出: ExtractAttribute("div", "data-id")
 //     入力: <div data-id="12345">コンテンツ</div>
 //     出力: ["12345"]
 //
 // パラメータ:
-//   - html: 解析対象のHTML文字列
 //   - selector: 要素を選択するためのCSSセレクタ
 //   - attr: 抽出する属性名
 //
 // 戻り値:
 //   - []string: 抽出された属性値の配列
 //   - error: エラーが発生した場合のエラー情報
-func (h *htmlDocument) ExtractAttribute(html string, selector, attr string) ([]string, error) {
-	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return nil, err
-	}
-
+func (h *htmlDocument) ExtractAttribute(selector, attr string) ([]string, error) {
 	var attributes []string
-	document.Find(selector).Each(func(_ int, s *goquery.Selection) {
+	h.document.Find(selector).Each(func(_ int, s *goquery.Selection) {
 		if value, exists := s.Attr(attr); exists {
 			attributes = append(attributes, value)
 		}
@@ -102,47 +128,41 @@ func (h *htmlDocument) ExtractAttribute(html string, selector, attr string) ([]s
 //
 // 使用例:
 //
-//   - 価格の抽出: ExtractTextByRegex(html, ".price", `¥[\d,]+`)
+//   - 価格の抽出: ExtractTextByRegex(".price", `¥[\d,]+`)
 //     入力: <div class="price">¥1,980</div>
 //     出力: ["¥1,980"]
 //
-//   - 電話番号の抽出: ExtractTextByRegex(html, ".contact", `\d{2,4}-\d{2,4}-\d{4}`)
+//   - 電話番号の抽出: ExtractTextByRegex(".contact", `\d{2,4}-\d{2,4}-\d{4}`)
 //     入力: <span class="contact">TEL: 03-1234-5678</span>
 //     出力: ["03-1234-5678"]
 //
-//   - カッコで囲まれた文字列: ExtractTextByRegex(html, "div", `\((.*?)\)`)
+//   - カッコで囲まれた文字列: ExtractTextByRegex("div", `\((.*?)\)`)
 //     入力: <div>これは(重要)な情報です</div>
 //     出力: ["(重要)"]
 //
-//   - 日付の抽出: ExtractTextByRegex(html, "time", `\d{4}/\d{2}/\d{2}`)
+//   - 日付の抽出: ExtractTextByRegex("time", `\d{4}/\d{2}/\d{2}`)
 //     入力: <time>2024/03/15</time>
 //     出力: ["2024/03/15"]
 //
-//   - メールアドレスの抽出: ExtractTextByRegex(html, "a", `[^@]+@[^@]+\.[^@]+`)
+//   - メールアドレスの抽出: ExtractTextByRegex("a", `[^@]+@[^@]+\.[^@]+`)
 //     入力: <a>contact@example.com</a>
 //     出力: ["contact@example.com"]
 //
 // パラメータ:
-//   - html: 解析対象のHTML文字列
 //   - selector: 要素を選択するためのCSSセレクタ
 //   - pattern: テキストから抽出するための正規表現パターン
 //
 // 戻り値:
 //   - []string: マッチした文字列の配列
 //   - error: エラーが発生した場合のエラー情報
-func (h *htmlDocument) ExtractTextByRegex(html, selector, pattern string) ([]string, error) {
-	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return nil, err
-	}
-
+func (h *htmlDocument) ExtractTextByRegex(selector, pattern string) ([]string, error) {
 	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, err
 	}
 
 	var matches []string
-	document.Find(selector).Each(func(_ int, s *goquery.Selection) {
+	h.document.Find(selector).Each(func(_ int, s *goquery.Selection) {
 		text := s.Text()
 		found := re.FindAllString(text, -1)
 		if found != nil {
@@ -1,9 +1,14 @@
 package infra
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // HTMLFileLoaderは、ローカルファイルシステムからHTMLファイルの読み込みに関連する操作を提供します。
@@ -32,7 +37,65 @@ func (f *HTMLFileLoader) LoadHTMLFile(path string) (string, error) {
 	return string(html), nil
 }
 
+// OpenHTMLFileは、指定されたパスのHTMLファイルをストリームとして開きます。
+//
+// args:
+//
+//	path : 開くHTMLファイルのパス
+//
+// return:
+//
+//	io.ReadCloser : 開いたファイル
+//	error         : ファイルを開けなかった場合のエラー
+func (f *HTMLFileLoader) OpenHTMLFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open HTML file: %w", err)
+	}
+	return file, nil
+}
+
+// HTMLModTimeは、HTMLファイルの更新日時を返します。
+// メタデータがない古いクロール結果で、取得日時の代わりに使用します。
+//
+// args:
+//
+//	path : HTMLファイルのパス
+//
+// return:
+//
+//	time.Time : ファイルの更新日時
+//	error     : ファイルの情報を取得できなかった場合のエラー
+func (f *HTMLFileLoader) HTMLModTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to stat HTML file: %w", err)
+	}
+	return info.ModTime(), nil
+}
+
+// LoadHTMLMetadataは、HTMLファイルに対応するメタデータ（サイドカー）ファイルを読み込みます。
+// サイドカーが存在しない場合は、os.ErrNotExistをラップしたエラーを返します。
+//
+// args:
+//
+//	htmlPath : HTMLファイルのパス
+//
+// return:
+//
+//	HTMLMetadata : 読み込んだメタデータ
+//	error        : 読み込みやデシリアライズに失敗した場合のエラー
+func (f *HTMLFileLoader) LoadHTMLMetadata(htmlPath string) (HTMLMetadata, error) {
+	return readHTMLMetadata(MetadataPath(htmlPath))
+}
+
+// readDirBatchSizeは、ディレクトリのエントリを一度に読み込む件数です。
+const readDirBatchSize = 1024
+
 // ListHTMLFilePathsは、指定されたディレクトリ配下のすべての.htmlファイルのパスを再帰的に検索して返します。
+// 数百万件のファイルを含むディレクトリでもメモリとファイルハンドルを使い切らないよう、
+// エントリを一定件数ずつ読み込み、ファイルごとの情報の取得（lstat）は行いません。ディレクトリは読み終えた時点で閉じます。
+// 拡張子は大文字と小文字を区別せずに判定します。パスは辞書順に並べて返します。
 //
 // args:
 //
@@ -46,18 +109,58 @@ func (f *HTMLFileLoader) ListHTMLFilePaths(dir string) ([]string, error) {
 	// 指定ディレクトリ配下の全ての.htmlファイルを再帰的に取得する
 	paths := make([]string, 0, 10000)
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	// 深い階層でもスタックを消費しないよう、未走査のディレクトリを積んで順に処理する
+	pending := []string{dir}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		subdirs, err := appendHTMLFilePaths(current, &paths)
 		if err != nil {
-			return err
+			return paths, fmt.Errorf("ディレクトリの走査に失敗しました: %w", err)
 		}
-		if !info.IsDir() && filepath.Ext(path) == ".html" {
-			paths = append(paths, path)
-		}
-		return nil
-	})
-	if err != nil {
-		return paths, fmt.Errorf("ディレクトリの走査に失敗しました: %w", err)
+		pending = append(pending, subdirs...)
 	}
 
+	sort.Strings(paths)
 	return paths, nil
 }
+
+// appendHTMLFilePathsは、1つのディレクトリのエントリを一定件数ずつ読み込み、.htmlファイルのパスをpathsに追加します。
+//
+// args:
+//
+//	dir   : 読み込むディレクトリのパス
+//	paths : .htmlファイルのパスの追加先
+//
+// return:
+//
+//	[]string : ディレクトリ内のサブディレクトリのパス
+//	error    : ディレクトリの読み込みに失敗した場合のエラー
+func appendHTMLFilePaths(dir string, paths *[]string) ([]string, error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	var subdirs []string
+	for {
+		entries, err := d.ReadDir(readDirBatchSize)
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case entry.IsDir():
+				subdirs = append(subdirs, path)
+			case strings.EqualFold(filepath.Ext(entry.Name()), ".html"):
+				*paths = append(*paths, path)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return subdirs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s の読み込みに失敗しました: %w", dir, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// htmlFileWriterは、指定されたディレクトリにHTMLとメタデータを保存するHTMLWriterの実装です。
+// クロールした詳細ページとは別のディレクトリに、一覧ページのHTMLを保存する場合に使用します。
+//
+// フィールド:
+//
+//	dir : HTMLを保存するディレクトリ
+type htmlFileWriter struct {
+	dir string
+}
+
+// NewHTMLFileWriterは、htmlFileWriterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	dir : HTMLを保存するディレクトリ
+//
+// return:
+//
+//	*htmlFileWriter : 生成されたライター
+func NewHTMLFileWriter(dir string) *htmlFileWriter {
+	return &htmlFileWriter{dir: dir}
+}
+
+// SaveHTMLは、HTMLをファイルに保存します。ファイル名はSafeFileNameで保存できる名前に置き換えます。
+//
+// args:
+//
+//	filename : ファイル名
+//	content  : HTMLの内容
+//
+// return:
+//
+//	error : ディレクトリの作成やファイルの書き込みに失敗した場合のエラー
+func (w *htmlFileWriter) SaveHTML(filename string, content string) error {
+	if err := os.MkdirAll(w.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(w.dir, SafeFileName(filename)), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("HTMLファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// SavePDFは、PDFをファイルに保存します。ファイル名はSafeFileNameで保存できる名前に置き換えます。
+//
+// args:
+//
+//	filename : ファイル名
+//	content  : PDFの内容
+//
+// return:
+//
+//	error : ディレクトリの作成やファイルの書き込みに失敗した場合のエラー
+func (w *htmlFileWriter) SavePDF(filename string, content []byte) error {
+	if err := os.MkdirAll(w.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(w.dir, SafeFileName(filename)), content, 0o644); err != nil {
+		return fmt.Errorf("PDFファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// SaveHTMLMetadataは、HTMLに対応するメタデータをサイドカーファイルとして保存します。
+//
+// args:
+//
+//	filename : 対応するHTMLのファイル名
+//	meta     : 保存するメタデータ
+//
+// return:
+//
+//	error : ディレクトリの作成やファイルの書き込みに失敗した場合のエラー
+func (w *htmlFileWriter) SaveHTMLMetadata(filename string, meta HTMLMetadata) error {
+	if err := os.MkdirAll(w.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+	return writeHTMLMetadata(MetadataPath(filepath.Join(w.dir, SafeFileName(filename))), meta)
+}
@@ -0,0 +1,86 @@
+package infra
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metadataSuffixは、HTMLファイルに対応するメタデータ（サイドカー）ファイルのサフィックスです。
+const metadataSuffix = ".meta.json"
+
+// HTMLMetadataは、保存したHTMLの取得元や取得日時を記録するサイドカーファイルの内容です。
+// スクレイパーはこの情報から求人の取得元URLやクロール日時を復元します。
+// ConfigHashには、HTMLを取得したクローラーの設定のハッシュ値を、ExpiredReasonには掲載終了と判定した理由を記録します。
+// リダイレクトされた場合は、Redirectsに遷移を指定したURLからFinalURLまでのURLを順に記録します。
+// ページを取得し直さずにキャッシュのHTMLを再利用した場合は、FromCacheをtrueにします。
+// ContentHashにはページの内容のハッシュ値を記録し、前回のクロールから内容が変わっていない場合はUnchangedをtrueにします。
+// HTMLを暗号化して保存する場合は、メタデータ全体を暗号化した文字列をEncryptedにのみ設定します。
+// ListPageには一覧ページのページ番号を、ListPositionには詳細ページのリンクが並んでいた一覧ページ内の位置を記録します。
+// 一覧ページのHTMLを保存する場合は、ListPageのみを記録します。
+// 詳細ページのPDFも保存した場合は、PDFにHTMLと同じ保存先に保存したPDFのファイル名を記録します。
+type HTMLMetadata struct {
+	JobID         string    `json:"job_id"`
+	URL           string    `json:"url"`
+	FinalURL      string    `json:"final_url"`
+	Redirects     []string  `json:"redirects,omitempty"`
+	Status        int       `json:"status"`
+	FetchedAt     time.Time `json:"fetched_at"`
+	ConfigHash    string    `json:"config_hash,omitempty"`
+	ExpiredReason string    `json:"expired_reason,omitempty"`
+	FromCache     bool      `json:"from_cache,omitempty"`
+	ContentHash   string    `json:"content_hash,omitempty"`
+	Unchanged     bool      `json:"unchanged,omitempty"`
+	ListPage      int       `json:"list_page,omitempty"`
+	ListPosition  int       `json:"list_position,omitempty"`
+	Encrypted     string    `json:"encrypted,omitempty"`
+	PDF           string    `json:"pdf,omitempty"`
+}
+
+// MetadataPathは、HTMLファイルのパスから対応するメタデータファイルのパスを返します。
+// 例: "tmp/html/xxxx.html" -> "tmp/html/xxxx.meta.json"
+//
+// args:
+//
+//	htmlPath : HTMLファイルのパス
+//
+// return:
+//
+//	string : メタデータファイルのパス
+func MetadataPath(htmlPath string) string {
+	// 大文字と小文字を区別しないファイルシステムから移した .HTML のファイルも同じ規則で対応付ける
+	if ext := filepath.Ext(htmlPath); strings.EqualFold(ext, ".html") {
+		htmlPath = strings.TrimSuffix(htmlPath, ext)
+	}
+	return htmlPath + metadataSuffix
+}
+
+// writeHTMLMetadataは、メタデータをJSONとしてファイルに書き込みます。
+func writeHTMLMetadata(path string, meta HTMLMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("メタデータのマーシャルに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("メタデータファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// readHTMLMetadataは、メタデータファイルを読み込みます。
+// ファイルが存在しない場合は、os.ErrNotExistをラップしたエラーを返します。
+func readHTMLMetadata(path string) (HTMLMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータファイルの読み込みに失敗しました: %w", err)
+	}
+
+	var meta HTMLMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータのデシリアライズに失敗しました: %w", err)
+	}
+	return meta, nil
+}
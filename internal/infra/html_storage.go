@@ -0,0 +1,205 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// HTMLWriterは、クロールしたHTMLとそのメタデータを保存するインターフェースです。
+type HTMLWriter interface {
+	SaveHTML(filename string, content string) error
+	SaveHTMLMetadata(filename string, meta HTMLMetadata) error
+}
+
+// PDFWriterは、HTMLと並べて詳細ページのPDFを保存できる保存先が実装するインターフェースです。
+type PDFWriter interface {
+	SavePDF(filename string, content []byte) error
+}
+
+// HTMLLoaderは、保存済みのHTMLとそのメタデータを列挙・読み込むインターフェースです。
+// メタデータが存在しない場合、LoadHTMLMetadataはos.ErrNotExistをラップしたエラーを返します。
+type HTMLLoader interface {
+	ListHTMLFilePaths(dir string) ([]string, error)
+	LoadHTMLFile(path string) (string, error)
+	LoadHTMLMetadata(htmlPath string) (HTMLMetadata, error)
+}
+
+// HTMLModTimerは、HTMLの更新日時を取得できるローダーが実装するインターフェースです。
+// メタデータがない古いクロール結果で、取得日時の代わりに使用します。
+type HTMLModTimer interface {
+	HTMLModTime(path string) (time.Time, error)
+}
+
+// htmlModTimeは、ローダーがHTMLModTimerを実装している場合に、HTMLの更新日時を返します。
+// 実装していない場合はゼロ値を返します。
+func htmlModTime(loader HTMLLoader, path string) (time.Time, error) {
+	if timer, ok := loader.(HTMLModTimer); ok {
+		return timer.HTMLModTime(path)
+	}
+	return time.Time{}, nil
+}
+
+// HTMLStorageは、HTMLの保存と読み込みの両方を提供する保存先のインターフェースです。
+type HTMLStorage interface {
+	HTMLWriter
+	HTMLLoader
+}
+
+const (
+	htmlKeyPrefix     = "html:"
+	htmlMetaKeyPrefix = "html_meta:"
+	htmlScanBatchSize = 1000
+)
+
+// redisHTMLStorageは、Redisを用いたHTMLWriterとHTMLLoaderの実装です。
+// クロールとスクレイプを別のマシンで実行する場合に、HTMLをファイルとしてやり取りせずに共有できます。
+// キーは "html:<ディレクトリ>/<ファイル名>" の形式で、ディレクトリは名前空間として扱います。
+//
+// フィールド:
+//
+//	redis : Redisクライアント
+//	dir   : 保存時に使用する名前空間（クローラーのoutput_dir）
+type redisHTMLStorage struct {
+	redis *redis.Client
+	dir   string
+}
+
+// NewRedisHTMLStorageは、redisHTMLStorageの新しいインスタンスを生成します。
+//
+// args:
+//
+//	rds : Redisクライアント
+//	dir : 保存時に使用する名前空間
+//
+// return:
+//
+//	*redisHTMLStorage : 生成されたストレージ
+func NewRedisHTMLStorage(rds *redis.Client, dir string) *redisHTMLStorage {
+	return &redisHTMLStorage{
+		redis: rds,
+		dir:   dir,
+	}
+}
+
+// SaveHTMLは、HTMLをRedisに保存します。
+//
+// args:
+//
+//	filename : ファイル名
+//	content  : HTMLの内容
+//
+// return:
+//
+//	error : 保存に失敗した場合のエラー
+func (s *redisHTMLStorage) SaveHTML(filename string, content string) error {
+	key := htmlKeyPrefix + path.Join(s.dir, filename)
+	if err := s.redis.Set(context.Background(), key, content, 0).Err(); err != nil {
+		return fmt.Errorf("HTMLをRedisに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// SaveHTMLMetadataは、HTMLに対応するメタデータをRedisに保存します。
+//
+// args:
+//
+//	filename : HTMLのファイル名
+//	meta     : 保存するメタデータ
+//
+// return:
+//
+//	error : 保存に失敗した場合のエラー
+func (s *redisHTMLStorage) SaveHTMLMetadata(filename string, meta HTMLMetadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("メタデータのマーシャルに失敗しました: %w", err)
+	}
+
+	key := htmlMetaKeyPrefix + path.Join(s.dir, filename)
+	if err := s.redis.Set(context.Background(), key, data, 0).Err(); err != nil {
+		return fmt.Errorf("メタデータをRedisに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// ListHTMLFilePathsは、指定された名前空間に保存されたHTMLのパスを列挙します。
+//
+// args:
+//
+//	dir : 列挙する名前空間
+//
+// return:
+//
+//	[]string : 見つかったHTMLのパス（"<ディレクトリ>/<ファイル名>"）
+//	error    : 走査中にエラーが発生した場合
+func (s *redisHTMLStorage) ListHTMLFilePaths(dir string) ([]string, error) {
+	ctx := context.Background()
+	pattern := htmlKeyPrefix + path.Clean(dir) + "/*"
+
+	paths := make([]string, 0, 10000)
+	iter := s.redis.Scan(ctx, 0, pattern, htmlScanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		paths = append(paths, strings.TrimPrefix(iter.Val(), htmlKeyPrefix))
+	}
+	if err := iter.Err(); err != nil {
+		return paths, fmt.Errorf("RedisのHTMLキーの走査に失敗しました: %w", err)
+	}
+
+	return paths, nil
+}
+
+// LoadHTMLFileは、指定されたパスのHTMLをRedisから読み込みます。
+//
+// args:
+//
+//	path : HTMLのパス
+//
+// return:
+//
+//	string : HTMLの内容
+//	error  : 読み込みに失敗した場合のエラー
+func (s *redisHTMLStorage) LoadHTMLFile(path string) (string, error) {
+	html, err := s.redis.Get(context.Background(), htmlKeyPrefix+path).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", fmt.Errorf("HTMLが見つかりません %s: %w", path, os.ErrNotExist)
+	}
+	if err != nil {
+		return "", fmt.Errorf("HTMLをRedisから読み込めませんでした: %w", err)
+	}
+	return html, nil
+}
+
+// LoadHTMLMetadataは、HTMLに対応するメタデータをRedisから読み込みます。
+// メタデータが存在しない場合は、os.ErrNotExistをラップしたエラーを返します。
+//
+// args:
+//
+//	htmlPath : HTMLのパス
+//
+// return:
+//
+//	HTMLMetadata : 読み込んだメタデータ
+//	error        : 読み込みやデシリアライズに失敗した場合のエラー
+func (s *redisHTMLStorage) LoadHTMLMetadata(htmlPath string) (HTMLMetadata, error) {
+	data, err := s.redis.Get(context.Background(), htmlMetaKeyPrefix+htmlPath).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return HTMLMetadata{}, fmt.Errorf("メタデータが見つかりません %s: %w", htmlPath, os.ErrNotExist)
+	}
+	if err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータをRedisから読み込めませんでした: %w", err)
+	}
+
+	var meta HTMLMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータのデシリアライズに失敗しました: %w", err)
+	}
+	return meta, nil
+}
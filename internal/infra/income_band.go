@@ -0,0 +1,87 @@
+package infra
+
+import (
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+const (
+	// annualWorkingDaysは、日給を年収に換算する際の年間の労働日数（月20日×12か月）です。
+	annualWorkingDays = 240
+	// annualWorkingHoursは、時給を年収に換算する際の年間の労働時間（1日8時間×240日）です。
+	annualWorkingHours = 1920
+)
+
+// AnnualSalaryは、給与を年収に換算した金額（円）を返します。
+// 下限と上限がある場合はその中央の金額を、下限のみの場合は下限を換算します。
+// 月給は12か月、日給は年240日、時給は年1920時間として換算します。
+//
+// args:
+//
+//	salary : 換算する給与
+//
+// return:
+//
+//	uint64 : 年収に換算した金額
+//	bool   : 換算できた場合はtrue（金額や単位が不明な場合、日本円以外の場合はfalse）
+func AnnualSalary(salary model.Salary) (uint64, bool) {
+	if currency := salary.Currency(); currency != model.JPY && currency != model.UnknownCurrency {
+		return 0, false
+	}
+	minAmount, maxAmount := salary.MinAmount(), salary.MaxAmount()
+	amount, ok := minAmount.Value()
+	if !ok || amount == 0 {
+		return 0, false
+	}
+	if maxValue, ok := maxAmount.Value(); ok && maxValue > amount {
+		amount = (amount + maxValue) / 2
+	}
+
+	switch salary.Unit() {
+	case model.Yearly:
+		return amount, true
+	case model.Monthly:
+		return amount * 12, true
+	case model.Daily:
+		return amount * annualWorkingDays, true
+	case model.Hourly:
+		return amount * annualWorkingHours, true
+	}
+	return 0, false
+}
+
+// incomeBandClassifierは、年収に換算した給与を設定ファイルの年収帯に分類するJobPostingEnricherの実装です。
+//
+// フィールド:
+//
+//	bands : 金額の小さい順に並べた年収帯
+type incomeBandClassifier struct {
+	bands []config.IncomeBand
+}
+
+// NewIncomeBandClassifierは、incomeBandClassifierの新しいインスタンスを生成します。
+//
+// args:
+//
+//	bands : 金額の小さい順に並べた年収帯
+//
+// return:
+//
+//	*incomeBandClassifier : 生成された分類器
+func NewIncomeBandClassifier(bands []config.IncomeBand) *incomeBandClassifier {
+	return &incomeBandClassifier{bands: bands}
+}
+
+// Enrichは、年収帯を設定した求人情報を返します。給与を年収に換算できない場合や、いずれの年収帯にも該当しない場合は設定しません。
+func (c *incomeBandClassifier) Enrich(posting model.JobPosting) model.JobPosting {
+	annual, ok := AnnualSalary(posting.Salary())
+	if !ok {
+		return posting
+	}
+	for _, band := range c.bands {
+		if band.Below == 0 || annual < band.Below {
+			return posting.WithIncomeBand(band.Label)
+		}
+	}
+	return posting
+}
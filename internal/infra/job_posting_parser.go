@@ -3,27 +3,38 @@ package infra
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/nrad-K/go-crawler/internal/config"
 	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"golang.org/x/text/unicode/norm"
 	"golang.org/x/text/width"
 )
 
 // JobPostingParserは、求人情報の様々な要素を文字列から解析するためのインターフェースです。
 type JobPostingParser interface {
 	ParseJobType(jobTypeStr string) model.JobType
-	ParsePostedAt(postedAtStr string) (time.Time, error)
+	ParsePostedAt(postedAtStr string, reference time.Time) (time.Time, error)
+	ParseExpiresAt(expiresAtStr string) (time.Time, error)
 	ParseRaise(raiseStr string) *uint
 	ParseBonus(bonusStr string) *uint
 	ParseSalaryDetails(salaryStr string) (model.Salary, error)
 	ParseHolidayPolicy(policyStr string) model.HolidayPolicy
 	ParseWorkplaceType(workplaceTypeStr string) model.WorkplaceType
+	ParseSmokingPolicy(smokingStr string) model.SmokingPolicy
 	ParseBenefits(benefitsStr string) model.Benefits
 	ParseOptionalUint(optionalStr string) (*uint, error)
 	ParseLocation(location string) (model.Location, error)
+	ParseLocations(location string) ([]model.Location, error)
+	ParseWorkHours(workHoursStr string) []model.WorkShift
+	ParseNearestStation(accessStr string) model.NearestStation
+	ParseContractPeriod(contractStr string) model.ContractPeriod
+	ParseProbationPeriod(probationStr string) model.ProbationPeriod
 }
 
 // CompiledPatternsは、解析処理で使用されるコンパイル済みの正規表現を保持します。
@@ -34,34 +45,101 @@ type CompiledPatterns struct {
 	AmountPattern       *regexp.Regexp
 	SalaryRangePattern  *regexp.Regexp
 	SalarySinglePattern *regexp.Regexp
+	SalaryUnitPattern   *regexp.Regexp
+	OvertimePattern     *regexp.Regexp
+	OvertimeHourPattern *regexp.Regexp
+	YenPattern          *regexp.Regexp
+	DatePattern         *regexp.Regexp
+	RelativeDatePattern *regexp.Regexp
 	LocationPattern     *regexp.Regexp
+	WorkHoursPattern    *regexp.Regexp
+	BreakTimePattern    *regexp.Regexp
+	StationPattern      *regexp.Regexp
+	PeriodPattern       *regexp.Regexp
 }
 
 // jobPostingParserは、JobPostingParserインターフェースの実装です。
 //
 // フィールド:
 //
-//	patterns: コンパイル済みの正規表現パターン
+//	patterns            : コンパイル済みの正規表現パターン
+//	benefitKeywords     : 福利厚生の項目ごとの正規化済みのキーワード（benefitFlagsと同じ順）
+//	jobTypes            : 雇用形態の分類器
+//	holidayPolicies     : 休日制度の分類器
+//	workplaceTypes      : 勤務形態の分類器
+//	smokingPolicies     : 受動喫煙対策の分類器
+//	municipalityCodes   : 市区町村名と全国地方公共団体コードの対応表
+//	preferredSalaryUnit : 複数の単位の給与が併記されている場合に優先する単位（UnknownSalaryTypeの場合は先に記載された単位）
 type jobPostingParser struct {
-	patterns CompiledPatterns
+	patterns            CompiledPatterns
+	benefitKeywords     [][]string
+	jobTypes            keywordClassifier[model.JobType]
+	holidayPolicies     keywordClassifier[model.HolidayPolicy]
+	workplaceTypes      keywordClassifier[model.WorkplaceType]
+	smokingPolicies     keywordClassifier[model.SmokingPolicy]
+	municipalityCodes   MunicipalityCodes
+	preferredSalaryUnit model.SalaryType
 }
 
 // NewJobPostingParserは、jobPostingParserの新しいインスタンスを生成します。
 //
 // args:
 //
-//	patterns: 解析に使用するコンパイル済み正規表現
+//	patterns            : 解析に使用するコンパイル済み正規表現
+//	dictionary          : キーワードで判定する項目の辞書
+//	municipalityCodes   : 市区町村名と全国地方公共団体コードの対応表
+//	preferredSalaryUnit : 複数の単位の給与が併記されている場合に優先する単位（UnknownSalaryTypeの場合は先に記載された単位）
 //
 // return:
 //
 //	*jobPostingParser: 新しいパーサーのインスタンス
-func NewJobPostingParser(patterns CompiledPatterns) *jobPostingParser {
-	return &jobPostingParser{
-		patterns: patterns,
+func NewJobPostingParser(patterns CompiledPatterns, dictionary ParserDictionary, municipalityCodes MunicipalityCodes, preferredSalaryUnit model.SalaryType) *jobPostingParser {
+	p := &jobPostingParser{
+		patterns:            patterns,
+		municipalityCodes:   municipalityCodes,
+		preferredSalaryUnit: preferredSalaryUnit,
 	}
+	// 解析対象の文字列と同じ正規化を行い、全角・半角の違いに関わらず一致させる
+	p.benefitKeywords = make([][]string, len(benefitFlags))
+	for i, flag := range benefitFlags {
+		for _, keyword := range dictionary.Benefits[flag.name] {
+			if keyword = p.normalizeString(keyword); keyword != "" {
+				p.benefitKeywords[i] = append(p.benefitKeywords[i], keyword)
+			}
+		}
+	}
+	p.jobTypes = newKeywordClassifier(jobTypeValues, dictionary.JobType, p.normalizeString)
+	p.holidayPolicies = newKeywordClassifier(holidayPolicyValues, dictionary.HolidayPolicy, p.normalizeString)
+	p.workplaceTypes = newKeywordClassifier(workplaceTypeValues, dictionary.WorkplaceType, p.normalizeString)
+	p.smokingPolicies = newKeywordClassifier(smokingPolicyValues, dictionary.SmokingPolicy, p.normalizeString)
+	return p
+}
+
+// configSalaryUnitsは、設定ファイルの給与の単位と給与単位の対応です。
+var configSalaryUnits = map[config.SalaryUnit]model.SalaryType{
+	config.SalaryUnitHourly:  model.Hourly,
+	config.SalaryUnitDaily:   model.Daily,
+	config.SalaryUnitMonthly: model.Monthly,
+	config.SalaryUnitYearly:  model.Yearly,
+}
+
+// SalaryTypeOfは、設定ファイルの給与の単位に対応する給与単位を返します。未指定の場合はUnknownSalaryTypeを返します。
+//
+// args:
+//
+//	unit: 設定ファイルの給与の単位
+//
+// return:
+//
+//	model.SalaryType: 対応する給与単位
+func SalaryTypeOf(unit config.SalaryUnit) model.SalaryType {
+	if salaryType, ok := configSalaryUnits[unit]; ok {
+		return salaryType
+	}
+	return model.UnknownSalaryType
 }
 
-// ParseJobTypeは、与えられた雇用形態の文字列を解析し、対応するmodel.JobType定数を返します。
+// ParseJobTypeは、与えられた雇用形態の文字列を辞書のキーワードで解析し、対応するmodel.JobType定数を返します。
 //
 // args:
 //
@@ -72,57 +150,184 @@ func NewJobPostingParser(patterns CompiledPatterns) *jobPostingParser {
 //	model.JobType: 解析結果の雇用形態
 func (p *jobPostingParser) ParseJobType(jobTypeStr string) model.JobType {
 	jobTypeStr = p.normalizeString(jobTypeStr)
-	if strings.Contains(jobTypeStr, "正社員") {
-		return model.FullTime
+	return p.jobTypes.classify(jobTypeStr, model.Unknown)
+}
+
+// relativeDayKeywordsは、基準日からの日数で表す掲載日の表記です（例: "本日掲載"）。
+// 長い表記から順に判定します。
+var relativeDayKeywords = []struct {
+	keyword string
+	days    int
+}{
+	{"一昨日", -2},
+	{"昨日", -1},
+	{"本日", 0},
+	{"今日", 0},
+	{"yesterday", -1},
+	{"today", 0},
+	{"just posted", 0},
+}
+
+// ParsePostedAtは、様々な形式の投稿日の文字列を解析し、time.Timeオブジェクトに変換します。
+// "掲載開始日: 2024/05/01" のように前後に文字列を含む日付や、"3日前"・"本日掲載"・"1週間以内" のような
+// 相対的な表記にも対応します。相対的な表記は基準日時（クロール日時）からの日付に変換し、"以内" は範囲の最も古い日付とします。
+//
+// args:
+//
+//	postedAtStr: 解析対象の日付文字列 (例: "2023年03月15日", "2023/03/15", "3日前")
+//	reference  : 相対的な表記の基準とする日時（不明な場合はゼロ値）
+//
+// return:
+//
+//	time.Time: 解析された時刻
+//	error    : いずれの形式にもマッチしない場合、または基準日時が不明な相対的な表記の場合のエラー
+func (p *jobPostingParser) ParsePostedAt(postedAtStr string, reference time.Time) (time.Time, error) {
+	postedAtStr = p.normalizeString(postedAtStr)
+	if parsedTime, ok := p.parseDate(postedAtStr); ok {
+		return parsedTime, nil
 	}
-	if strings.Contains(jobTypeStr, "アルバイト") || strings.Contains(jobTypeStr, "パート") || strings.Contains(jobTypeStr, "バイト") {
-		return model.PartTime
+	if parsedTime, ok, err := p.parseRelativePostedAt(postedAtStr, reference); ok {
+		return parsedTime, err
 	}
-	if strings.Contains(jobTypeStr, "契約社員") {
-		return model.Contract
+	return time.Time{}, fmt.Errorf("日付のパースに失敗しました: %s", postedAtStr)
+}
+
+// openEndedDeadlineKeywordsは、応募締切日が定められていないことを表す表記です。
+var openEndedDeadlineKeywords = []string{"随時", "定めなし", "なし", "充足次第", "決まり次第", "決定次第"}
+
+// ParseExpiresAtは、応募締切日・掲載終了日の文字列を解析し、time.Timeオブジェクトに変換します。
+// ParsePostedAtと同じ形式の日付に対応し、"2024年5月31日まで" のように前後に文字列を含む日付も解析します。
+//
+// args:
+//
+//	expiresAtStr: 解析対象の日付文字列 (例: "応募締切: 2024/05/31", "2024年5月31日まで")
+//
+// return:
+//
+//	time.Time: 解析された時刻（"随時" や "採用者が決まり次第終了" のように締切日がない場合はゼロ値）
+//	error    : いずれの形式にもマッチしない場合のエラー
+func (p *jobPostingParser) ParseExpiresAt(expiresAtStr string) (time.Time, error) {
+	expiresAtStr = p.normalizeString(expiresAtStr)
+	if parsedTime, ok := p.parseDate(expiresAtStr); ok {
+		return parsedTime, nil
 	}
-	if strings.Contains(jobTypeStr, "派遣社員") {
-		return model.Temporary
+	for _, keyword := range openEndedDeadlineKeywords {
+		if strings.Contains(expiresAtStr, keyword) {
+			return time.Time{}, nil
+		}
 	}
-	if strings.Contains(jobTypeStr, "業務委託") || strings.Contains(jobTypeStr, "フリーランス") {
-		return model.Freelance
+	return time.Time{}, fmt.Errorf("日付のパースに失敗しました: %s", expiresAtStr)
+}
+
+// dateFormatsは、掲載日や応募締切日として解釈する日付の形式です。
+var dateFormats = []string{
+	"2006年01月02日",     // 例: 2023年03月15日
+	"2006/01/02",      // 例: 2023/03/15
+	"2006-01-02",      // 例: 2023-03-15
+	"2006.01.02",      // 例: 2025.06.17
+	"January 2, 2006", // 例: March 15, 2023
+	"Jan 2, 2006",     // 例: Mar 15, 2023
+}
+
+// parseDateは、dateFormatsのいずれかの形式の日付、または前後に文字列を含む年月日の日付を解析します。
+//
+// args:
+//
+//	s: 正規化済みの日付文字列
+//
+// return:
+//
+//	time.Time: 解析された日付
+//	bool     : 日付を解析できた場合はtrue
+func (p *jobPostingParser) parseDate(s string) (time.Time, bool) {
+	for _, format := range dateFormats {
+		parsedTime, err := time.Parse(format, s)
+		if err == nil {
+			return parsedTime, true
+		}
 	}
-	if strings.Contains(jobTypeStr, "インターン") {
-		return model.Internship
+
+	// 前後に文字列を含む日付やゼロ埋めのない日付（例: 掲載開始日: 2024/5/1）
+	if matches := p.patterns.DatePattern.FindStringSubmatch(s); len(matches) == 4 {
+		year, _ := strconv.Atoi(matches[1])
+		month, _ := strconv.Atoi(matches[2])
+		day, _ := strconv.Atoi(matches[3])
+		parsedTime := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if parsedTime.Month() == time.Month(month) && parsedTime.Day() == day {
+			return parsedTime, true
+		}
 	}
-	return model.Unknown
+	return time.Time{}, false
 }
 
-// ParsePostedAtは、様々な形式の投稿日の文字列を解析し、time.Timeオブジェクトに変換します。
+// parseRelativePostedAtは、"3日前"・"本日掲載"・"1週間以内" のような相対的な掲載日の表記を、基準日時からの日付に変換します。
 //
 // args:
 //
-//	postedAtStr: 解析対象の日付文字列 (例: "2023年03月15日", "2023/03/15")
+//	postedAtStr: 正規化済みの日付文字列
+//	reference  : 基準とする日時
 //
 // return:
 //
-//	time.Time: 解析された時刻
-//	error    : いずれの形式にもマッチしない場合のエラー
-func (p *jobPostingParser) ParsePostedAt(postedAtStr string) (time.Time, error) {
-	postedAtStr = p.normalizeString(postedAtStr)
-	formats := []string{
-		"2006年01月02日",     // 例: 2023年03月15日
-		"2006/01/02",      // 例: 2023/03/15
-		"2006-01-02",      // 例: 2023-03-15
-		"2006.01.02",      // 例: 2025.06.17
-		"January 2, 2006", // 例: March 15, 2023
-		"Jan 2, 2006",     // 例: Mar 15, 2023
+//	time.Time: 変換した日付（基準日時のタイムゾーンの0時）
+//	bool     : 相対的な表記の場合はtrue
+//	error    : 基準日時が不明な場合のエラー
+func (p *jobPostingParser) parseRelativePostedAt(postedAtStr string, reference time.Time) (time.Time, bool, error) {
+	offset := func(years, months, days int, d time.Duration) (time.Time, bool, error) {
+		if reference.IsZero() {
+			return time.Time{}, true, fmt.Errorf("クロール日時が不明なため相対的な日付を解析できません: %s", postedAtStr)
+		}
+		t := reference.Add(-d).AddDate(years, months, days)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), true, nil
 	}
 
-	for _, format := range formats {
-		parsedTime, err := time.Parse(format, postedAtStr)
+	if matches := p.patterns.RelativeDatePattern.FindStringSubmatch(postedAtStr); len(matches) == 4 {
+		n, err := strconv.Atoi(matches[1])
 		if err == nil {
-			return parsedTime, nil
+			switch matches[2] {
+			case "分":
+				return offset(0, 0, 0, time.Duration(n)*time.Minute)
+			case "時間":
+				return offset(0, 0, 0, time.Duration(n)*time.Hour)
+			case "日":
+				return offset(0, 0, -n, 0)
+			case "週間", "週":
+				return offset(0, 0, -7*n, 0)
+			default:
+				return offset(0, -n, 0, 0)
+			}
 		}
 	}
-	return time.Time{}, fmt.Errorf("日付のパースに失敗しました: %s", postedAtStr)
+
+	lower := strings.ToLower(postedAtStr)
+	if matches := englishRelativeDatePattern.FindStringSubmatch(lower); len(matches) == 3 {
+		n, err := strconv.Atoi(matches[1])
+		if err == nil {
+			switch matches[2] {
+			case "minute":
+				return offset(0, 0, 0, time.Duration(n)*time.Minute)
+			case "hour":
+				return offset(0, 0, 0, time.Duration(n)*time.Hour)
+			case "day":
+				return offset(0, 0, -n, 0)
+			case "week":
+				return offset(0, 0, -7*n, 0)
+			default:
+				return offset(0, -n, 0, 0)
+			}
+		}
+	}
+	for _, k := range relativeDayKeywords {
+		if strings.Contains(lower, k.keyword) {
+			return offset(0, 0, k.days, 0)
+		}
+	}
+	return time.Time{}, false, nil
 }
 
+// englishRelativeDatePatternは、英語の相対的な掲載日の表記（例: "3 days ago"）です。
+var englishRelativeDatePattern = regexp.MustCompile(`(\d+)\+?\s*(minute|hour|day|week|month)s?\s+ago`)
+
 // ParseAmountは、"100万円"や"500,000"のような金額を表す文字列から、数値を抽出しuint64型で返します。
 //
 // args:
@@ -145,6 +350,9 @@ func (p *jobPostingParser) ParseAmount(amountStr string) (uint64, error) {
 		"千": 1e3,
 	}
 
+	// 桁区切りのカンマを除去（例: 1,200万 -> 1200万）
+	amountStr = strings.ReplaceAll(amountStr, ",", "")
+
 	for unit, multiplier := range unitMap {
 		if strings.Contains(amountStr, unit) {
 			// re := regexp.MustCompile(`(\d+(?:\.\d+)?)`)
@@ -238,11 +446,16 @@ func (p *jobPostingParser) ParseBonus(text string) *uint {
 	return nil
 }
 
+// salaryNegotiableKeywordsは、給与が「応相談」であることを示すキーワードです。
+var salaryNegotiableKeywords = []string{"応相談", "要相談"}
+
 // ParseSalaryDetailsは、給与情報の文字列を解析し、給与の範囲、単位などを含むmodel.Salaryオブジェクトを返します。
+// 「応相談」と記載されている場合は、金額の有無に関わらず応相談として扱います。
+// 月給と年収などが併記されている場合は、優先する単位の金額を、指定がない場合は先に記載された金額を使用します。
 //
 // args:
 //
-//	salaryStr: 解析対象の給与情報文字列 (例: "月給25万円～", "年収400万円～800万円")
+//	salaryStr: 解析対象の給与情報文字列 (例: "月給25万円～", "年収400万円～800万円", "給与応相談")
 //
 // return:
 //
@@ -256,8 +469,203 @@ func (p *jobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, e
 		return model.NewSalary(minAmount, maxAmount, model.UnknownSalaryType), fmt.Errorf("給与文字列が空です")
 	}
 
-	unit := p.ParseSalaryType(salaryStr)
+	negotiable := false
+	for _, keyword := range salaryNegotiableKeywords {
+		if strings.Contains(salaryStr, keyword) {
+			negotiable = true
+			break
+		}
+	}
+
+	// 固定残業代の金額や時間数を給与の金額として解析しないよう、固定残業代の記載を取り除いてから解析する
+	overtime, salaryStr := p.parseFixedOvertime(salaryStr)
+
+	segment := p.selectSalarySegment(salaryStr)
+	salary, err := p.parseSalaryAmounts(segment, p.ParseSalaryType(segment))
+	if err != nil && negotiable {
+		// 金額の記載がない「応相談」は、金額が不明な給与として扱う
+		return model.NewSalary(model.NewNullAmount(), model.NewNullAmount(), p.ParseSalaryType(segment)).WithNegotiable(true).WithFixedOvertime(overtime), nil
+	}
+	if err != nil {
+		return salary, err
+	}
+
+	// 通貨の記載がない場合は、国内の求人サイトの表記として日本円とみなす
+	currency := p.ParseCurrency(segment)
+	if currency == model.UnknownCurrency {
+		currency = model.JPY
+	}
+	return salary.WithNegotiable(negotiable).WithCurrency(currency).WithFixedOvertime(overtime), nil
+}
+
+// fixedOvertimeClauseEndsは、固定残業代の記載の終わりとみなす文字です。
+var fixedOvertimeClauseEnds = "。※\n"
+
+// parseFixedOvertimeは、給与情報の文字列から固定残業代（みなし残業）の記載を探し、残業時間と金額を抽出します。
+// 固定残業代の記載は、キーワードから文末（「。」「※」または改行）または次の給与の単位の前まで、括弧内に記載されている場合は閉じ括弧までとします。
+//
+// args:
+//
+//	salaryStr: 正規化済みの給与情報文字列 (例: "月給30万円(固定残業代45時間分5万円を含む)")
+//
+// return:
+//
+//	model.FixedOvertime: 抽出された固定残業代。記載がない場合、または「固定残業代なし」の場合はゼロ値。
+//	string             : 固定残業代の記載を取り除いた給与情報文字列
+func (p *jobPostingParser) parseFixedOvertime(salaryStr string) (model.FixedOvertime, string) {
+	if p.patterns.OvertimePattern == nil {
+		return model.FixedOvertime{}, salaryStr
+	}
+	loc := p.patterns.OvertimePattern.FindStringIndex(salaryStr)
+	if loc == nil {
+		return model.FixedOvertime{}, salaryStr
+	}
+
+	end := len(salaryStr)
+	if i := strings.IndexAny(salaryStr[loc[1]:], fixedOvertimeClauseEnds); i >= 0 {
+		end = loc[1] + i
+	}
+	// 固定残業代の記載の後に給与の単位が続く場合（例: "固定残業代45時間分を含む 月給25万円~"）は、単位の前までとする
+	if unitLoc := p.patterns.SalaryUnitPattern.FindStringIndex(salaryStr[loc[1]:end]); unitLoc != nil {
+		end = loc[1] + unitLoc[0]
+	}
+	// 括弧内の記載（例: "月給30万円(固定残業代5万円を含む)、年収450万円~"）は、閉じ括弧までとする
+	if depth := strings.Count(salaryStr[:loc[0]], "(") - strings.Count(salaryStr[:loc[0]], ")"); depth > 0 {
+		for i, r := range salaryStr[loc[1]:end] {
+			if r == '(' {
+				depth++
+			} else if r == ')' {
+				if depth--; depth == 0 {
+					end = loc[1] + i
+					break
+				}
+			}
+		}
+	}
+	clause := salaryStr[loc[1]:end]
+	rest := salaryStr[:loc[0]] + salaryStr[end:]
+
+	trimmed := strings.TrimLeft(clause, ":: ")
+	if strings.HasPrefix(trimmed, "なし") || strings.HasPrefix(trimmed, "無") {
+		return model.FixedOvertime{}, rest
+	}
+
+	var hours *uint
+	if match := p.patterns.OvertimeHourPattern.FindStringSubmatch(clause); len(match) == 2 {
+		if n, err := strconv.ParseUint(match[1], 10, 64); err == nil {
+			val := uint(n)
+			hours = &val
+		}
+	}
+	amount := model.NewNullAmount()
+	if match := p.patterns.YenPattern.FindStringSubmatch(clause); len(match) == 2 {
+		if n, err := p.ParseAmount(match[1]); err == nil {
+			amount = model.NewAmount(n)
+		}
+	}
+	return model.NewFixedOvertime(hours, amount), rest
+}
+
+// salaryCurrencyMarkersは、給与の文字列に含まれる通貨の記号・表記と通貨の対応です。
+// "US$" は "$" より先に判定するため、長い表記から順に並べます。
+var salaryCurrencyMarkers = []struct {
+	marker   string
+	currency model.Currency
+}{
+	{"US$", model.USD},
+	{"USD", model.USD},
+	{"ドル", model.USD},
+	{"$", model.USD},
+	{"JPY", model.JPY},
+	{"¥", model.JPY},
+	{"円", model.JPY},
+}
+
+// ParseCurrencyは、給与情報の文字列から通貨を特定します。
+// 複数の通貨が記載されている場合（例: "¥8,000,000 (US$55,000)"）は、先に記載された通貨を返します。
+//
+// args:
+//
+//	salaryStr: 正規化済みの給与情報文字列
+//
+// return:
+//
+//	model.Currency: 特定された通貨。記載がない場合はUnknownCurrency
+func (p *jobPostingParser) ParseCurrency(salaryStr string) model.Currency {
+	currency, first := model.UnknownCurrency, -1
+	for _, m := range salaryCurrencyMarkers {
+		if i := strings.Index(salaryStr, m.marker); i >= 0 && (first < 0 || i < first) {
+			currency, first = m.currency, i
+		}
+	}
+	return currency
+}
+
+// selectSalarySegmentは、複数の単位の給与が併記された文字列から、解析に使用する部分を選択します。
+// 文字列は単位（月給・年収など）の記載位置で区切り、優先する単位の部分を、ない場合は最初に金額を含む部分を返します。
+//
+// args:
+//
+//	salaryStr: 正規化済みの給与情報文字列 (例: "月給25万円~(年収300万円~)")
+//
+// return:
+//
+//	string: 解析に使用する部分 (例: "月給25万円~(")
+func (p *jobPostingParser) selectSalarySegment(salaryStr string) string {
+	if p.patterns.SalaryUnitPattern == nil {
+		return salaryStr
+	}
+	locs := p.patterns.SalaryUnitPattern.FindAllStringIndex(salaryStr, -1)
+	if len(locs) < 2 {
+		return salaryStr
+	}
+
+	segments := make([]string, 0, len(locs))
+	for i, loc := range locs {
+		start, end := loc[0], len(salaryStr)
+		if i == 0 {
+			// 最初の単位より前の文字列（例: "【正社員】"）は最初の部分に含める
+			start = 0
+		}
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		segments = append(segments, salaryStr[start:end])
+	}
+
+	var first string
+	for _, segment := range segments {
+		if !p.patterns.SalarySinglePattern.MatchString(segment) {
+			continue
+		}
+		if p.preferredSalaryUnit != model.UnknownSalaryType && p.ParseSalaryType(segment) == p.preferredSalaryUnit {
+			return segment
+		}
+		if first == "" {
+			first = segment
+		}
+	}
+	if first == "" {
+		return salaryStr
+	}
+	return first
+}
+
+// salaryAmountUnitPatternは、金額の単位（万・千・億）に一致する正規表現です。
+var salaryAmountUnitPattern = regexp.MustCompile(`(万|千|億)`)
 
+// parseSalaryAmountsは、給与情報の文字列から金額の範囲または単一の金額を解析します。
+//
+// args:
+//
+//	salaryStr: 正規化済みの給与情報文字列
+//	unit     : 給与の単位
+//
+// return:
+//
+//	model.Salary: 解析された給与情報
+//	error       : 解析に失敗した場合のエラー
+func (p *jobPostingParser) parseSalaryAmounts(salaryStr string, unit model.SalaryType) (model.Salary, error) {
 	// 範囲表現の処理
 	if matches := p.patterns.SalaryRangePattern.FindStringSubmatch(salaryStr); len(matches) >= 3 {
 		minStr := matches[1]
@@ -265,11 +673,11 @@ func (p *jobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, e
 
 		// 下限に単位がなく上限にある場合、上限の単位を下限に付与する
 		// 例: 400〜500万円 -> 400万円〜500万円
-		unitRegex := regexp.MustCompile(`(万|千|億)`)
-		minUnitMatch := unitRegex.FindString(minStr)
-		maxUnitMatch := unitRegex.FindString(maxStr)
+		// 下限に「円」「台」が付いている場合（例: 1000円〜1万円）は、下限の金額が記載どおりのため付与しない
+		minUnitMatch := salaryAmountUnitPattern.FindString(minStr)
+		maxUnitMatch := salaryAmountUnitPattern.FindString(maxStr)
 
-		if minUnitMatch == "" && maxUnitMatch != "" {
+		if minUnitMatch == "" && maxUnitMatch != "" && !strings.ContainsAny(minStr, "円台") {
 			minStr += maxUnitMatch
 		}
 
@@ -288,7 +696,7 @@ func (p *jobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, e
 		}
 
 		minAmount := model.NewAmount(pMinAmount)
-		maxAmount := model.NewAmount(pMaxAmount)
+		maxAmount := model.NewAmount(salaryBandUpperBound(pMaxAmount, maxStr))
 
 		return model.NewSalary(minAmount, maxAmount, unit), nil
 	}
@@ -303,6 +711,13 @@ func (p *jobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, e
 			return model.NewSalary(minAmount, maxAmount, model.UnknownSalaryType), fmt.Errorf("給与のパースに失敗しました: %w", err)
 		}
 
+		// 「年収400万円台」のような金額の帯は、帯の上限を上限とする
+		if len(singleMatch) >= 3 && singleMatch[2] != "" {
+			if upper := salaryBandUpperBound(amount, singleMatch[1]+singleMatch[2]); upper != amount {
+				maxAmount = model.NewAmount(upper)
+			}
+		}
+
 		minAmount := model.NewAmount(amount)
 		return model.NewSalary(minAmount, maxAmount, unit), nil
 	}
@@ -312,6 +727,27 @@ func (p *jobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, e
 	return model.NewSalary(minAmount, maxAmount, model.UnknownSalaryType), fmt.Errorf("給与の金額を抽出できませんでした: %s", salaryStr)
 }
 
+// salaryBandWidthは、「X万円台」と記載された金額の帯の幅（10万円）です。
+const salaryBandWidth = 100_000
+
+// salaryBandUpperBoundは、「X万円台」と記載された金額の帯の上限を返します。
+// X万円台はX万円から(X+10)万円未満とみなし、(X+10)万円-1円を返します。帯の記載でない場合は金額をそのまま返します。
+//
+// args:
+//
+//	amount   : 解析した金額 (例: 4000000)
+//	amountStr: 金額の文字列 (例: "400万円台")
+//
+// return:
+//
+//	uint64: 帯の上限 (例: 4099999)
+func salaryBandUpperBound(amount uint64, amountStr string) uint64 {
+	if !strings.HasSuffix(amountStr, "台") || !strings.Contains(amountStr, "万") {
+		return amount
+	}
+	return amount + salaryBandWidth - 1
+}
+
 // ParseSalaryTypeは、給与情報の文字列から給与の単位（年収、月給など）を特定します。
 //
 // args:
@@ -323,17 +759,37 @@ func (p *jobPostingParser) ParseSalaryDetails(salaryStr string) (model.Salary, e
 //	model.SalaryType: 特定された給与単位
 func (p *jobPostingParser) ParseSalaryType(salaryStr string) model.SalaryType {
 	switch {
-	case strings.Contains(salaryStr, "年収"), strings.Contains(salaryStr, "年給"):
+	case strings.Contains(salaryStr, "年収"), strings.Contains(salaryStr, "年給"), strings.Contains(salaryStr, "年俸"):
 		return model.Yearly
-	case strings.Contains(salaryStr, "月給"):
+	case strings.Contains(salaryStr, "月給"), strings.Contains(salaryStr, "月収"):
 		return model.Monthly
 	case strings.Contains(salaryStr, "日給"):
 		return model.Daily
 	case strings.Contains(salaryStr, "時給"):
 		return model.Hourly
-	default:
-		return model.UnknownSalaryType
 	}
+
+	// 英語の求人サイトの表記（例: "$120,000 per year", "$25/hr"）
+	lower := strings.ToLower(salaryStr)
+	for _, u := range englishSalaryUnits {
+		for _, keyword := range u.keywords {
+			if strings.Contains(lower, keyword) {
+				return u.unit
+			}
+		}
+	}
+	return model.UnknownSalaryType
+}
+
+// englishSalaryUnitsは、英語で記載された給与の単位の表記と給与単位の対応です。
+var englishSalaryUnits = []struct {
+	unit     model.SalaryType
+	keywords []string
+}{
+	{model.Yearly, []string{"per year", "per annum", "/year", "/yr", "annual"}},
+	{model.Monthly, []string{"per month", "/month", "/mo", "monthly"}},
+	{model.Daily, []string{"per day", "/day", "daily"}},
+	{model.Hourly, []string{"per hour", "/hour", "/hr", "hourly"}},
 }
 
 // ParseOptionalUintは、オプションの数値を含む文字列（例: 年間休日数）を解析し、*uint型で返します。
@@ -370,7 +826,7 @@ func (p *jobPostingParser) ParseOptionalUint(optionalStr string) (*uint, error)
 	return &val, nil
 }
 
-// ParseHolidayPolicyは、休日・休暇に関する文字列を解析し、対応するmodel.HolidayPolicyを返します。
+// ParseHolidayPolicyは、休日・休暇に関する文字列を辞書のキーワードで解析し、対応するmodel.HolidayPolicyを返します。
 //
 // args:
 //
@@ -381,23 +837,24 @@ func (p *jobPostingParser) ParseOptionalUint(optionalStr string) (*uint, error)
 //	model.HolidayPolicy: 解析された休日ポリシー
 func (p *jobPostingParser) ParseHolidayPolicy(policyStr string) model.HolidayPolicy {
 	policyStr = p.normalizeString(policyStr)
-	if strings.Contains(policyStr, "完全週休二日制") {
-		return model.CompleteTwoDaysAWeek
-	}
-	if strings.Contains(policyStr, "週休二日制") {
-		return model.TwoDaysAWeek
-	}
-	if strings.Contains(policyStr, "週休制") {
-		return model.OneDayAWeek
-	}
-	if strings.Contains(policyStr, "シフト制") {
-		return model.ShiftSystem
-	}
+	return p.holidayPolicies.classify(policyStr, model.UnknownHoliday)
+}
 
-	return model.UnknownHoliday
+// ParseSmokingPolicyは、受動喫煙対策に関する文字列を辞書のキーワードで解析し、対応するmodel.SmokingPolicyを返します。
+//
+// args:
+//
+//	smokingStr: 解析対象の受動喫煙対策の文字列 (例: "屋内原則禁煙（喫煙専用室あり）")
+//
+// return:
+//
+//	model.SmokingPolicy: 解析された受動喫煙対策
+func (p *jobPostingParser) ParseSmokingPolicy(smokingStr string) model.SmokingPolicy {
+	smokingStr = p.normalizeString(smokingStr)
+	return p.smokingPolicies.classify(smokingStr, model.UnknownSmoking)
 }
 
-// ParseWorkplaceTypeは、勤務形態に関する文字列を解析し、対応するmodel.WorkplaceTypeを返します。
+// ParseWorkplaceTypeは、勤務形態に関する文字列を辞書のキーワードで解析し、対応するmodel.WorkplaceTypeを返します。
 //
 // args:
 //
@@ -408,19 +865,10 @@ func (p *jobPostingParser) ParseHolidayPolicy(policyStr string) model.HolidayPol
 //	model.WorkplaceType: 解析された勤務形態
 func (p *jobPostingParser) ParseWorkplaceType(workplaceTypeStr string) model.WorkplaceType {
 	workplaceTypeStr = p.normalizeString(workplaceTypeStr)
-	if strings.Contains(workplaceTypeStr, "出社") {
-		return model.Onsite
-	}
-	if strings.Contains(workplaceTypeStr, "在宅") || strings.Contains(workplaceTypeStr, "リモート") || strings.Contains(workplaceTypeStr, "フルリモート") {
-		return model.Remote
-	}
-	if strings.Contains(workplaceTypeStr, "ハイブリッド") {
-		return model.Hybrid
-	}
-	return model.UnknownWorkplace
+	return p.workplaceTypes.classify(workplaceTypeStr, model.UnknownWorkplace)
 }
 
-// ParseBenefitsは、福利厚生に関する文字列を解析し、キーワードに基づいてmodel.Benefits構造体に変換します。
+// ParseBenefitsは、福利厚生に関する文字列を解析し、辞書のキーワードに基づいてmodel.Benefits構造体に変換します。
 //
 // args:
 //
@@ -434,60 +882,14 @@ func (p *jobPostingParser) ParseBenefits(benefitsStr string) model.Benefits {
 	benefits.RawBenefits = benefitsStr // 元の文字列を保存
 	normalizedBenefitsStr := p.normalizeString(benefitsStr)
 
-	// キーワードに基づいて各フィールドを設定
-	if strings.Contains(normalizedBenefitsStr, "社会保険完備") {
-		benefits.SocialInsurance = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "交通費支給") {
-		benefits.TransportAllowance = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "住宅手当") {
-		benefits.HousingAllowance = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "社宅・寮") {
-		benefits.CompanyHousing = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "家賃補助") {
-		benefits.RentSubsidy = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "食事手当") {
-		benefits.MealAllowance = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "社員食堂") {
-		benefits.CafeteriaProvided = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "研修制度") {
-		benefits.TrainingSupport = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "資格取得支援") {
-		benefits.CertificationSupport = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "有給休暇") {
-		benefits.PaidLeave = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "特別休暇") {
-		benefits.SpecialLeave = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "フレックスタイム") {
-		benefits.FlexTime = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "時短勤務") {
-		benefits.ShortWorkingHours = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "育児支援") {
-		benefits.ChildcareSupport = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "産前産後休暇") {
-		benefits.MaternityLeave = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "育児休暇") {
-		benefits.ParentalLeave = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "介護支援") {
-		benefits.ElderCareSupport = true
-	}
-	if strings.Contains(normalizedBenefitsStr, "退職金制度") {
-		benefits.RetirementPlan = true
+	// 辞書のキーワードに基づいて各フィールドを設定
+	for i, flag := range benefitFlags {
+		for _, keyword := range p.benefitKeywords[i] {
+			if strings.Contains(normalizedBenefitsStr, keyword) {
+				flag.set(&benefits)
+				break
+			}
+		}
 	}
 	return model.NewBenefits(benefits)
 }
@@ -558,6 +960,7 @@ var (
 )
 
 // ParseLocationは、所在地の文字列を解析し、都道府県コード、市区町村などを含むmodel.Locationオブジェクトを返します。
+// 複数の都道府県が含まれる場合は、最初に記載された都道府県を返します。
 //
 // args:
 //
@@ -568,41 +971,405 @@ var (
 //	model.Location: 解析された所在地情報
 //	error         : 都道府県名の特定に失敗した場合などのエラー
 func (p *jobPostingParser) ParseLocation(locationStr string) (model.Location, error) {
+	locations, err := p.ParseLocations(locationStr)
+	if err != nil {
+		return model.Location{}, err
+	}
+	return locations[0], nil
+}
+
+// ParseLocationsは、所在地の文字列に含まれるすべての都道府県を記載順に解析します。
+// 例: "東京都・大阪府・福岡県" -> [東京都, 大阪府, 福岡県]
+// 市区町村は、各都道府県の記載位置から次の都道府県の記載位置までの区間から抽出します。
+//
+// args:
+//
+//	locationStr: 解析対象の所在地の文字列
+//
+// return:
+//
+//	[]model.Location: 解析された所在地情報のリスト（同じ都道府県は1件にまとめる）
+//	error           : 都道府県名の特定に失敗した場合などのエラー
+func (p *jobPostingParser) ParseLocations(locationStr string) ([]model.Location, error) {
 	locationStr = p.normalizeString(locationStr)
 	if locationStr == "" {
-		return model.Location{}, fmt.Errorf("位置情報文字列が空です")
+		return nil, fmt.Errorf("位置情報文字列が空です")
+	}
+
+	matches := p.findPrefectures(locationStr)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("都道府県名が特定できませんでした: %s", locationStr)
+	}
+
+	locations := make([]model.Location, 0, len(matches))
+	for i, match := range matches {
+		segmentEnd := len(locationStr)
+		if i+1 < len(matches) {
+			segmentEnd = matches[i+1].start
+		}
+
+		// 都道府県名の末尾（都・道・府・県）から市区町村を探す
+		// 例: "京都府京都市" の "京都" 部分の "都" にマッチしないよう、名称の先頭からは探さない
+		segmentStart := match.start
+		if match.end-match.start == len(match.name) {
+			_, size := utf8.DecodeLastRuneInString(match.name)
+			segmentStart = match.end - size
+		}
+
+		var city string
+		// 市区町村の抽出（例: 東京都渋谷区 → 渋谷区）
+		cityMatch := p.patterns.LocationPattern.FindStringSubmatch(locationStr[segmentStart:segmentEnd])
+		if len(cityMatch) >= 2 {
+			city = p.trimPunctuation(cityMatch[1])
+		}
+
+		location := model.NewLocation(match.code, match.name, city, locationStr)
+		locations = append(locations, location.WithMunicipalityCode(p.municipalityCodes.Lookup(match.code, city)))
 	}
 
-	var name string
-	var code model.PrefectureCode
+	return locations, nil
+}
+
+// prefectureMatchは、文字列中で見つかった都道府県名の位置を表します。
+type prefectureMatch struct {
+	start int
+	end   int
+	name  string
+	code  model.PrefectureCode
+}
+
+// findPrefecturesは、文字列中の都道府県名をすべて探し、記載位置の順に返します。
+// 正式名称（例: "東京都"）を優先し、正式名称で見つからなかった都道府県についてのみ、
+// 正式名称と重ならない位置で略称（例: "東京"）を探します。
+// これにより、"東京都"に含まれる"京都"や"大阪府大阪市"の"大阪"を別の記載と誤認することを防ぎます。
+// 同じ都道府県が複数回記載されている場合は、最初の記載のみを返します。
+//
+// args:
+//
+//	s: 検索対象の文字列
+//
+// return:
+//
+//	[]prefectureMatch: 見つかった都道府県名の位置（記載順）
+func (p *jobPostingParser) findPrefectures(s string) []prefectureMatch {
+	var matches []prefectureMatch
+	found := make(map[model.PrefectureCode]bool)
+
+	overlaps := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	collect := func(useShortName bool) {
+		for name, code := range prefMap {
+			if found[code] {
+				continue
+			}
+
+			target := name
+			if useShortName {
+				// "東京都" -> "東京" のように末尾の文字を削除
+				if !strings.HasSuffix(name, "都") && !strings.HasSuffix(name, "府") && !strings.HasSuffix(name, "県") {
+					continue
+				}
+				target = string([]rune(name)[:len([]rune(name))-1])
+			}
+
+			for offset := 0; offset < len(s); {
+				idx := strings.Index(s[offset:], target)
+				if idx < 0 {
+					break
+				}
+				start := offset + idx
+				end := start + len(target)
+				if !overlaps(start, end) {
+					matches = append(matches, prefectureMatch{start: start, end: end, name: name, code: code})
+					break
+				}
+				offset = end
+			}
+		}
+
+		for _, m := range matches {
+			found[m.code] = true
+		}
+	}
+
+	collect(false)
+	collect(true)
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+	return matches
+}
+
+// ParseWorkHoursは、勤務時間の文字列を解析し、開始・終了時刻と休憩時間を持つ勤務パターンのリストを返します。
+// シフト制など複数の時間帯が記載されている場合は、記載順にすべての勤務パターンを返します。
+// 休憩時間は各時間帯の直後の記載を優先し、見つからない場合は全体で1つだけ記載された休憩時間を適用します。
+//
+// args:
+//
+//	workHoursStr: 解析対象の勤務時間の文字列 (例: "9:00～18:00（休憩60分）")
+//
+// return:
+//
+//	[]model.WorkShift: 解析された勤務パターン。時間帯が見つからない場合はnil。
+func (p *jobPostingParser) ParseWorkHours(workHoursStr string) []model.WorkShift {
+	workHoursStr = p.normalizeString(workHoursStr)
+	ranges := p.patterns.WorkHoursPattern.FindAllStringSubmatchIndex(workHoursStr, -1)
+	if len(ranges) == 0 {
+		return nil
+	}
 
-	// 都道府県名の特定
-	for k, v := range prefMap {
-		// "東京都" -> "東京" のように末尾の文字を削除
-		shortName := k
-		if strings.HasSuffix(k, "都") || strings.HasSuffix(k, "府") || strings.HasSuffix(k, "県") {
-			shortName = string([]rune(k)[:len([]rune(k))-1])
+	// 全体で休憩時間の記載が1つだけの場合、全ての時間帯に共通で適用する
+	var commonBreak *uint
+	if breaks := p.patterns.BreakTimePattern.FindAllStringSubmatch(workHoursStr, -1); len(breaks) == 1 {
+		commonBreak = p.parseBreakMinutes(breaks[0])
+	}
+
+	shifts := make([]model.WorkShift, 0, len(ranges))
+	for i, loc := range ranges {
+		start, ok := p.parseClock(workHoursStr, loc[2:6])
+		if !ok {
+			continue
+		}
+		end, ok := p.parseClock(workHoursStr, loc[6:10])
+		if !ok {
+			continue
+		}
+
+		// 次の時間帯までの区間から、この時間帯の休憩時間を探す
+		segmentEnd := len(workHoursStr)
+		if i+1 < len(ranges) {
+			segmentEnd = ranges[i+1][0]
+		}
+		breakMinutes := commonBreak
+		if match := p.patterns.BreakTimePattern.FindStringSubmatch(workHoursStr[loc[1]:segmentEnd]); match != nil {
+			breakMinutes = p.parseBreakMinutes(match)
 		}
 
-		if strings.Contains(locationStr, k) || strings.Contains(locationStr, shortName) {
-			name = k
-			code = v
+		shifts = append(shifts, model.NewWorkShift(start, end, breakMinutes))
+	}
+
+	if len(shifts) == 0 {
+		return nil
+	}
+	return shifts
+}
+
+// parseClockは、時・分のサブマッチ位置から0時からの経過分を算出します。
+//
+// args:
+//
+//	s   : 元の文字列
+//	loc : 時・分のサブマッチの開始・終了位置（分は省略可能）
+//
+// return:
+//
+//	uint : 0時からの経過分
+//	bool : 時刻として妥当な場合はtrue
+func (p *jobPostingParser) parseClock(s string, loc []int) (uint, bool) {
+	hour, err := strconv.ParseUint(s[loc[0]:loc[1]], 10, 64)
+	if err != nil || hour > 30 {
+		return 0, false
+	}
+
+	var minute uint64
+	if loc[2] >= 0 {
+		minute, err = strconv.ParseUint(s[loc[2]:loc[3]], 10, 64)
+		if err != nil || minute >= 60 {
+			return 0, false
+		}
+	}
+
+	return uint(hour*60 + minute), true
+}
+
+// parseBreakMinutesは、休憩時間のマッチ結果（数値と単位）を分に換算します。
+//
+// args:
+//
+//	match: BreakTimePatternのサブマッチ
+//
+// return:
+//
+//	*uint: 休憩時間（分）。換算できない場合はnil。
+func (p *jobPostingParser) parseBreakMinutes(match []string) *uint {
+	if len(match) < 3 {
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return nil
+	}
+
+	if match[2] != "分" {
+		value *= 60
+	}
+
+	minutes := uint(value)
+	return &minutes
+}
+
+// stationLabelReplacerは、"最寄り駅"などの見出しを駅名と誤認しないよう除去するためのリプレーサーです。
+var stationLabelReplacer = strings.NewReplacer(
+	"最寄り駅", " ",
+	"最寄駅", " ",
+)
+
+// ParseNearestStationは、アクセス情報の文字列から最寄り駅の路線名・駅名・徒歩分数を抽出します。
+// 複数の駅が記載されている場合は、最初に記載された駅を返します。
+//
+// args:
+//
+//	accessStr: 解析対象のアクセス情報の文字列 (例: "JR渋谷駅より徒歩5分")
+//
+// return:
+//
+//	model.NearestStation: 抽出された最寄り駅情報。見つからない場合はゼロ値。
+func (p *jobPostingParser) ParseNearestStation(accessStr string) model.NearestStation {
+	accessStr = stationLabelReplacer.Replace(p.normalizeString(accessStr))
+
+	match := p.patterns.StationPattern.FindStringSubmatch(accessStr)
+	if len(match) < 4 {
+		return model.NearestStation{}
+	}
+
+	var walkMinutes *uint
+	if match[3] != "" {
+		if minutes, err := strconv.ParseUint(match[3], 10, 64); err == nil {
+			val := uint(minutes)
+			walkMinutes = &val
+		}
+	}
+
+	return model.NewNearestStation(strings.TrimSpace(match[1]), match[2], walkMinutes)
+}
+
+// contractRenewalKeywordsは、契約の更新の有無を表す表記です。「更新なし」を「更新の可能性あり」などより先に判定します。
+var contractRenewalKeywords = []struct {
+	keyword   string
+	renewable bool
+}{
+	{"更新なし", false},
+	{"更新無", false},
+	{"更新不可", false},
+	{"更新しない", false},
+	{"更新の可能性なし", false},
+	{"更新あり", true},
+	{"更新有", true},
+	{"更新可", true},
+	{"更新の可能性あり", true},
+}
+
+// ParseContractPeriodは、契約期間の文字列から、期間の月数・長期かどうか・更新の有無を抽出します。
+// 期間が年で記載されている場合は月数に換算します。
+//
+// args:
+//
+//	contractStr: 解析対象の契約期間の文字列 (例: "契約期間: 6ヶ月（更新あり）", "長期")
+//
+// return:
+//
+//	model.ContractPeriod: 抽出された契約期間。記載がない項目はゼロ値。
+func (p *jobPostingParser) ParseContractPeriod(contractStr string) model.ContractPeriod {
+	contractStr = p.normalizeString(contractStr)
+
+	months := p.parsePeriodMonths(contractStr)
+
+	var renewable *bool
+	for _, k := range contractRenewalKeywords {
+		if strings.Contains(contractStr, k.keyword) {
+			val := k.renewable
+			renewable = &val
 			break
 		}
 	}
 
-	if name == "" {
-		return model.Location{}, fmt.Errorf("都道府県名が特定できませんでした: %s", locationStr)
+	return model.NewContractPeriod(months, strings.Contains(contractStr, "長期"), renewable)
+}
+
+// probationConditionKeywordsは、試用期間中の労働条件の変更の有無を表す表記です。「変更なし」を「変更あり」より先に判定します。
+var probationConditionKeywords = []struct {
+	keyword string
+	changed bool
+}{
+	{"条件変更なし", false},
+	{"条件変更無", false},
+	{"待遇変更なし", false},
+	{"待遇変更無", false},
+	{"条件に変更なし", false},
+	{"待遇に変更なし", false},
+	{"同条件", false},
+	{"条件変更あり", true},
+	{"条件変更有", true},
+	{"待遇変更あり", true},
+	{"待遇変更有", true},
+	{"条件が異な", true},
+	{"待遇が異な", true},
+}
+
+// ParseProbationPeriodは、試用期間の文字列から、期間の月数と試用期間中の労働条件の変更の有無を抽出します。
+// 「試用期間なし」と記載されている場合は、期間を0ヶ月とします。
+//
+// args:
+//
+//	probationStr: 解析対象の試用期間の文字列 (例: "試用期間3ヶ月（条件変更なし）")
+//
+// return:
+//
+//	model.ProbationPeriod: 抽出された試用期間。記載がない項目はゼロ値。
+func (p *jobPostingParser) ParseProbationPeriod(probationStr string) model.ProbationPeriod {
+	probationStr = p.normalizeString(probationStr)
+
+	months := p.parsePeriodMonths(probationStr)
+	if months == nil && (strings.Contains(probationStr, "試用期間なし") || strings.Contains(probationStr, "試用期間無")) {
+		val := uint(0)
+		months = &val
 	}
 
-	var city string
-	// 市区町村の抽出（例: 東京都渋谷区 → 渋谷区）
-	match := p.patterns.LocationPattern.FindStringSubmatch(locationStr)
-	if len(match) >= 2 {
-		city = p.trimPunctuation(match[1])
+	var conditionChanged *bool
+	for _, k := range probationConditionKeywords {
+		if strings.Contains(probationStr, k.keyword) {
+			val := k.changed
+			conditionChanged = &val
+			break
+		}
 	}
 
-	return model.NewLocation(code, name, city, locationStr), nil
+	return model.NewProbationPeriod(months, conditionChanged)
+}
+
+// parsePeriodMonthsは、正規化済みの文字列から期間（例: "6ヶ月"・"1年"・"半年"）を抽出し、月数に換算します。
+//
+// args:
+//
+//	s: 正規化済みの文字列
+//
+// return:
+//
+//	*uint: 期間の月数。記載がない場合はnil。
+func (p *jobPostingParser) parsePeriodMonths(s string) *uint {
+	if match := p.patterns.PeriodPattern.FindStringSubmatch(s); len(match) == 3 {
+		if n, err := strconv.ParseUint(match[1], 10, 64); err == nil {
+			val := uint(n)
+			if match[2] == "年" {
+				val *= 12
+			}
+			return &val
+		}
+	}
+	if strings.Contains(s, "半年") {
+		val := uint(6)
+		return &val
+	}
+	return nil
 }
 
 // normalizeStringは、文字列の正規化（全角記号・数字の半角化、トリムなど）を行います。
@@ -615,8 +1382,11 @@ func (p *jobPostingParser) ParseLocation(locationStr string) (model.Location, er
 //
 //	string: 正規化後の文字列
 func (p *jobPostingParser) normalizeString(s string) string {
-	// 全角英数字・記号・カタカナなどを半角に変換
-	s = width.Narrow.String(s)
+	// 全角英数字・記号を半角に、半角カタカナを全角に変換
+	// (width.Narrowではカタカナまで半角になり、"パート"などのキーワードにマッチしなくなるためFoldを使用する)
+	s = width.Fold.String(s)
+	// 半角カタカナの濁点・半濁点は結合文字に変換されるため、直前の文字と合成する（例: "ﾊﾟｰﾄ" -> "パート"）
+	s = norm.NFC.String(s)
 
 	// 全角記号を半角に変換
 	s = symbolReplacer.Replace(s)
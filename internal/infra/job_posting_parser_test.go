@@ -0,0 +1,107 @@
+package infra_test
+
+import (
+	"testing"
+
+	"github.com/nrad-K/go-crawler/internal/constants"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/infra"
+)
+
+// newTestParserは、既定の正規表現を使用するパーサーを生成します。
+func newTestParser(preferredSalaryUnit model.SalaryType) infra.JobPostingParser {
+	return infra.NewJobPostingParser(constants.GetScraperCompiledPatterns(), infra.ParserDictionary{}, nil, preferredSalaryUnit)
+}
+
+func TestParseSalaryDetails(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		preferred  model.SalaryType
+		min        string
+		max        string
+		unit       model.SalaryType
+		negotiable bool
+	}{
+		{name: "以上", input: "月給25万円以上", min: "250000", unit: model.Monthly},
+		{name: "年俸制", input: "年俸制600万円～", min: "6000000", unit: model.Yearly},
+		{name: "桁区切りの範囲と交通費", input: "時給1,200円〜1,500円+交通費", min: "1200", max: "1500", unit: model.Hourly},
+		{name: "桁区切りの万円", input: "年収1,200万円", min: "12000000", unit: model.Yearly},
+		{name: "下限の単位を上限から補う", input: "400〜500万円", min: "4000000", max: "5000000", unit: model.UnknownSalaryType},
+		{name: "下限が円の場合は単位を補わない", input: "時給1000円〜1万円", min: "1000", max: "10000", unit: model.Hourly},
+		{name: "全角の数字", input: "月給２５万円〜", min: "250000", unit: model.Monthly},
+		{name: "応相談のみ", input: "給与応相談", unit: model.UnknownSalaryType, negotiable: true},
+		{name: "金額と応相談", input: "年収400万円～600万円(応相談)", min: "4000000", max: "6000000", unit: model.Yearly, negotiable: true},
+		{name: "万円台", input: "年収400万円台", min: "4000000", max: "4099999", unit: model.Yearly},
+		{name: "万円台の範囲", input: "月給25万円台～30万円台", min: "250000", max: "399999", unit: model.Monthly},
+		{name: "万円台からの範囲", input: "年収400万円台〜600万円", min: "4000000", max: "6000000", unit: model.Yearly},
+		{name: "月給と年収の併記（最初の単位）", input: "月給25万円~(年収300万円~)", min: "250000", unit: model.Monthly},
+		{name: "月給と年収の併記（優先する単位）", input: "月給25万円~(年収300万円~)", preferred: model.Yearly, min: "3000000", unit: model.Yearly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preferred := tt.preferred
+			if preferred == "" {
+				preferred = model.UnknownSalaryType
+			}
+			salary, err := newTestParser(preferred).ParseSalaryDetails(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSalaryDetails(%q): %v", tt.input, err)
+			}
+			minAmount, maxAmount := salary.MinAmount(), salary.MaxAmount()
+			if got := minAmount.Format(); got != tt.min {
+				t.Errorf("下限 = %q, want %q", got, tt.min)
+			}
+			if got := maxAmount.Format(); got != tt.max {
+				t.Errorf("上限 = %q, want %q", got, tt.max)
+			}
+			if got := salary.Unit(); got != tt.unit {
+				t.Errorf("単位 = %s, want %s", got, tt.unit)
+			}
+			if got := salary.Negotiable(); got != tt.negotiable {
+				t.Errorf("応相談 = %v, want %v", got, tt.negotiable)
+			}
+		})
+	}
+}
+
+func TestParseNearestStation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		line    string
+		station string
+		walk    int // 徒歩分数の記載がない場合は-1
+	}{
+		{name: "路線の略称", input: "JR渋谷駅より徒歩5分", line: "JR", station: "渋谷", walk: 5},
+		{name: "括弧内の駅名", input: "JR山手線「渋谷」駅 徒歩5分", line: "JR山手線", station: "渋谷", walk: 5},
+		{name: "駅を含む括弧", input: "JR山手線「渋谷駅」徒歩5分", line: "JR山手線", station: "渋谷", walk: 5},
+		{name: "事業者名付きの路線", input: "東京メトロ銀座線 銀座駅 徒歩2分", line: "東京メトロ銀座線", station: "銀座", walk: 2},
+		{name: "路線なし・約", input: "渋谷駅から徒歩約3分", station: "渋谷", walk: 3},
+		{name: "見出しのみで徒歩分数なし", input: "最寄駅:新宿駅", station: "新宿", walk: -1},
+		{name: "全角の英数字と記号", input: "ＪＲ山手線「渋谷駅」徒歩５分", line: "JR山手線", station: "渋谷", walk: 5},
+		{name: "半角カタカナ", input: "ｼﾌﾞﾔ駅 徒歩３分", station: "シブヤ", walk: 3},
+		{name: "駅の記載なし", input: "リモート勤務", walk: -1},
+	}
+
+	parser := newTestParser(model.UnknownSalaryType)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			station := parser.ParseNearestStation(tt.input)
+			if got := station.Line(); got != tt.line {
+				t.Errorf("路線 = %q, want %q", got, tt.line)
+			}
+			if got := station.Name(); got != tt.station {
+				t.Errorf("駅名 = %q, want %q", got, tt.station)
+			}
+			walk := -1
+			if minutes := station.WalkMinutes(); minutes != nil {
+				walk = int(*minutes)
+			}
+			if walk != tt.walk {
+				t.Errorf("徒歩分数 = %d, want %d", walk, tt.walk)
+			}
+		})
+	}
+}
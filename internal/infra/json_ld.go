@@ -0,0 +1,254 @@
+package infra
+
+import (
+	"encoding/json"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// jsonLDSalaryUnitsは、schema.orgのQuantitativeValueのunitTextと給与の単位の対応です。
+var jsonLDSalaryUnits = map[string]model.SalaryType{
+	"HOUR":  model.Hourly,
+	"DAY":   model.Daily,
+	"MONTH": model.Monthly,
+	"YEAR":  model.Yearly,
+}
+
+// jsonLDDateFormatsは、datePosted・validThroughやarticle:published_timeとして解釈する日付の形式です。
+var jsonLDDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// JSONLDJobPostingは、ページに埋め込まれたJSON-LDのschema.org JobPostingから取り出した求人情報です。
+// JSON-LDに含まれていない項目はゼロ値になります。
+//
+// フィールド:
+//
+//	Title        : 求人のタイトル（title）
+//	CompanyName  : 募集している会社名（hiringOrganization.name）
+//	Locations    : 勤務地の住所（jobLocation.address）を記載順に並べたもの
+//	Salary       : 給与（baseSalary）
+//	HasSalary    : baseSalaryから給与を取り出せた場合はtrue
+//	DatePosted   : 掲載日（datePosted）
+//	ValidThrough : 応募締切日（validThrough）
+type JSONLDJobPosting struct {
+	Title        string
+	CompanyName  string
+	Locations    []string
+	Salary       model.Salary
+	HasSalary    bool
+	DatePosted   time.Time
+	ValidThrough time.Time
+}
+
+// ExtractJobPostingJSONLDは、HTMLに埋め込まれた <script type="application/ld+json"> から、最初のJobPostingを取り出します。
+// 配列や@graphにまとめて記述されたJSON-LDにも対応します。解釈できないJSON-LDは無視します。
+//
+// return:
+//
+//	JSONLDJobPosting : 取り出した求人情報
+//	bool             : JobPostingが見つかった場合はtrue
+func (h *htmlDocument) ExtractJobPostingJSONLD() (JSONLDJobPosting, bool) {
+	var posting map[string]any
+	h.document.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		// CMSによってはHTMLコメントやCDATAで囲まれているため取り除く
+		text := strings.TrimSpace(s.Text())
+		for _, wrapper := range []string{"<!--", "-->", "<![CDATA[", "]]>"} {
+			text = strings.ReplaceAll(text, wrapper, "")
+		}
+
+		var data any
+		if err := json.Unmarshal([]byte(text), &data); err != nil {
+			return true
+		}
+		posting = findJSONLDJobPosting(data)
+		return posting == nil
+	})
+	if posting == nil {
+		return JSONLDJobPosting{}, false
+	}
+
+	result := JSONLDJobPosting{
+		Title:       jsonLDText(posting["title"]),
+		CompanyName: jsonLDText(posting["hiringOrganization"]),
+		Locations:   jsonLDLocations(posting["jobLocation"]),
+	}
+	result.Salary, result.HasSalary = jsonLDSalary(posting["baseSalary"])
+	result.DatePosted = jsonLDDate(posting["datePosted"])
+	result.ValidThrough = jsonLDDate(posting["validThrough"])
+	return result, true
+}
+
+// jsonLDDateは、JSON-LDの日付（datePostedやvalidThrough）を解釈します。解釈できない場合はゼロ値を返します。
+func jsonLDDate(value any) time.Time {
+	if text := jsonLDText(value); text != "" {
+		for _, format := range jsonLDDateFormats {
+			if t, err := time.Parse(format, text); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// findJSONLDJobPostingは、JSON-LDのデータから@typeがJobPostingのオブジェクトを探します。
+func findJSONLDJobPosting(data any) map[string]any {
+	switch v := data.(type) {
+	case []any:
+		for _, item := range v {
+			if posting := findJSONLDJobPosting(item); posting != nil {
+				return posting
+			}
+		}
+	case map[string]any:
+		if isJSONLDType(v["@type"], "JobPosting") {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findJSONLDJobPosting(graph)
+		}
+	}
+	return nil
+}
+
+// isJSONLDTypeは、@typeの値（文字列または文字列の配列）が指定された型を含むかを判定します。
+func isJSONLDType(value any, typeName string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == typeName || strings.HasSuffix(v, "/"+typeName)
+	case []any:
+		for _, item := range v {
+			if isJSONLDType(item, typeName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDTextは、JSON-LDの値を文字列として取り出します。
+// オブジェクトの場合はnameを、配列の場合は最初の空でない値を使用します。
+func jsonLDText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(v))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		if name := jsonLDText(v["name"]); name != "" {
+			return name
+		}
+		return jsonLDText(v["@value"])
+	case []any:
+		for _, item := range v {
+			if text := jsonLDText(item); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// jsonLDLocationsは、jobLocation（Placeまたはその配列）から勤務地の住所を取り出します。
+// PostalAddressの場合は、都道府県・市区町村・番地の順につなげて1つの住所にします。
+func jsonLDLocations(value any) []string {
+	var places []any
+	switch v := value.(type) {
+	case []any:
+		places = v
+	case nil:
+		return nil
+	default:
+		places = []any{v}
+	}
+
+	var locations []string
+	for _, place := range places {
+		p, ok := place.(map[string]any)
+		if !ok {
+			if text := jsonLDText(place); text != "" {
+				locations = append(locations, text)
+			}
+			continue
+		}
+
+		var address string
+		switch a := p["address"].(type) {
+		case map[string]any:
+			address = jsonLDText(a["addressRegion"]) + jsonLDText(a["addressLocality"]) + jsonLDText(a["streetAddress"])
+		default:
+			address = jsonLDText(a)
+		}
+		if address == "" {
+			address = jsonLDText(p["name"])
+		}
+		if address != "" {
+			locations = append(locations, address)
+		}
+	}
+	return locations
+}
+
+// jsonLDSalaryは、baseSalary（MonetaryAmount）から給与を取り出します。
+// valueが数値の場合は単一の金額、QuantitativeValueの場合はminValue・maxValueを範囲として扱います。
+// 通貨はcurrency（ISO 4217の通貨コード）から設定します。
+func jsonLDSalary(value any) (model.Salary, bool) {
+	amount, ok := value.(map[string]any)
+	if !ok {
+		return model.Salary{}, false
+	}
+
+	unit := model.UnknownSalaryType
+	var minValue, maxValue float64
+	var hasMin, hasMax bool
+	switch v := amount["value"].(type) {
+	case map[string]any:
+		if u, ok := jsonLDSalaryUnits[strings.ToUpper(jsonLDText(v["unitText"]))]; ok {
+			unit = u
+		}
+		minValue, hasMin = jsonLDNumber(v["minValue"])
+		maxValue, hasMax = jsonLDNumber(v["maxValue"])
+		if !hasMin {
+			minValue, hasMin = jsonLDNumber(v["value"])
+		}
+	default:
+		minValue, hasMin = jsonLDNumber(v)
+	}
+	if !hasMin && hasMax {
+		minValue, hasMin = maxValue, true
+		hasMax = false
+	}
+	if !hasMin {
+		return model.Salary{}, false
+	}
+
+	maxAmount := model.NewNullAmount()
+	if hasMax {
+		maxAmount = model.NewAmount(uint64(maxValue))
+	}
+	salary := model.NewSalary(model.NewAmount(uint64(minValue)), maxAmount, unit)
+	if currency := strings.ToUpper(jsonLDText(amount["currency"])); currency != "" {
+		salary = salary.WithCurrency(model.Currency(currency))
+	}
+	return salary, true
+}
+
+// jsonLDNumberは、JSON-LDの数値（数値またはカンマ区切りの文字列）を取り出します。負の値は無視します。
+func jsonLDNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, v >= 0
+	case string:
+		n, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+		return n, err == nil && n >= 0
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package infra
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LookupJSONPathは、JSONからパスで指定した値を取り出します。
+// パスは $ から始まる .キー・['キー']・[添字] の連結（例: $.meta.total, $.results[0]['count']）をサポートします。
+//
+// args:
+//
+//	data : JSON
+//	path : 取り出す値のパス
+//
+// return:
+//
+//	any   : 取り出した値（数値はjson.Number）
+//	error : JSONやパスを解釈できない場合、またはパスに一致する値がない場合のエラー
+func LookupJSONPath(data []byte, path string) (any, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("JSONの解析に失敗しました: %w", err)
+	}
+
+	current := "$"
+	for _, segment := range segments {
+		switch node := value.(type) {
+		case map[string]any:
+			child, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("%s にキー %q がありません", current, segment)
+			}
+			value = child
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("%s に添字 %s の要素がありません（要素数: %d）", current, segment, len(node))
+			}
+			value = node[index]
+		default:
+			return nil, fmt.Errorf("%s はオブジェクトでも配列でもないため、%s を取り出せません", current, segment)
+		}
+		current += "." + segment
+	}
+	return value, nil
+}
+
+// parseJSONPathは、パスをキーまたは添字の列に分解します。
+func parseJSONPath(path string) ([]string, error) {
+	rest := strings.TrimPrefix(path, "$")
+	var segments []string
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSONPath %q に空のキーがあります", path)
+			}
+			segments = append(segments, rest[1:end+1])
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q の括弧が閉じられていません", path)
+			}
+			segments = append(segments, rest[2:2+end])
+			rest = rest[2+end+2:]
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q の括弧が閉じられていません", path)
+			}
+			if _, err := strconv.Atoi(rest[1:end]); err != nil {
+				return nil, fmt.Errorf("JSONPath %q の添字 %q は整数ではありません", path, rest[1:end])
+			}
+			segments = append(segments, rest[1:end])
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %q を解釈できません。$.キー・['キー']・[添字] を連結して指定してください", path)
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,96 @@
+package infra
+
+// jsonSchemaDraftは、生成するJSON Schemaのドラフトバージョンです。
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchemaは、エクスポートされる求人情報1件の構造を表すJSON Schemaです。
+// 下流のシステムが出力の検証やコード生成に使用できるよう、列定義から生成されます。
+type JSONSchema struct {
+	Schema               string                        `json:"$schema"`
+	Title                string                        `json:"title"`
+	Description          string                        `json:"description"`
+	SchemaVersion        string                        `json:"x-schema-version"`
+	Type                 string                        `json:"type"`
+	Properties           map[string]JSONSchemaProperty `json:"properties"`
+	Required             []string                      `json:"required"`
+	AdditionalProperties bool                          `json:"additionalProperties"`
+}
+
+// JSONSchemaPropertyは、JSON Schemaの1プロパティ（エクスポートされる1列）の定義です。
+// x-csv-headerとx-column-indexは、CSV出力における列名と列の位置を表します。
+type JSONSchemaProperty struct {
+	Type        any                 `json:"type"`
+	Format      string              `json:"format,omitempty"`
+	Enum        []string            `json:"enum,omitempty"`
+	Items       *JSONSchemaProperty `json:"items,omitempty"`
+	Description string              `json:"description,omitempty"`
+	CSVHeader   string              `json:"x-csv-header,omitempty"`
+	ColumnIndex *int                `json:"x-column-index,omitempty"`
+}
+
+// BuildJSONSchemaは、列定義からエクスポートされる求人情報のJSON Schemaを生成します。
+// 複数の値を持つ列は配列、値が不明な場合に空になる列はnullを許容する型として表現します。
+//
+// args:
+//
+//	fields        : 出力する列の定義
+//	schemaVersion : エクスポートのスキーマバージョン
+//
+// return:
+//
+//	JSONSchema : 生成されたJSON Schema
+func BuildJSONSchema(fields []ExportField, schemaVersion SchemaVersion) JSONSchema {
+	schema := JSONSchema{
+		Schema:               jsonSchemaDraft,
+		Title:                "JobPosting",
+		Description:          "go-crawlerのスクレイパーが出力する求人情報1件の構造",
+		SchemaVersion:        schemaVersion.String(),
+		Type:                 "object",
+		Properties:           make(map[string]JSONSchemaProperty, len(fields)),
+		Required:             make([]string, 0, len(fields)),
+		AdditionalProperties: false,
+	}
+
+	for i, field := range fields {
+		value := JSONSchemaProperty{
+			Type: jsonSchemaType(field.Type),
+			Enum: field.Enum,
+		}
+		if field.Type == FieldTypeDate || field.Type == FieldTypeDateTime {
+			value.Format = string(field.Type)
+		}
+
+		property := value
+		if field.Multi {
+			property = JSONSchemaProperty{
+				Type:  "array",
+				Items: &value,
+			}
+		}
+		if field.Nullable {
+			property.Type = []any{property.Type, "null"}
+		}
+
+		index := i
+		property.Description = field.Description
+		property.CSVHeader = field.Header
+		property.ColumnIndex = &index
+
+		schema.Properties[field.Key] = property
+		schema.Required = append(schema.Required, field.Key)
+	}
+
+	return schema
+}
+
+// jsonSchemaTypeは、列の型に対応するJSON Schemaの型を返します。
+func jsonSchemaType(fieldType ExportFieldType) string {
+	switch fieldType {
+	case FieldTypeInteger:
+		return "integer"
+	case FieldTypeNumber:
+		return "number"
+	default:
+		return "string"
+	}
+}
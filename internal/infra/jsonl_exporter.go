@@ -0,0 +1,213 @@
+package infra
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// JSONLExporterは、求人情報を1行に1件のJSON（JSON Lines）としてエクスポートするFileExporterの実装です。
+// 各行はJSON Schemaに従い、キーは列定義のキー、値は列の型に応じた数値・文字列・配列・nullになります。
+// 書き込みは一時ファイルに対して行われ、Closeが成功した時点で出力先へアトミックにリネームされます。
+//
+// フィールド:
+//
+//	file   : 書き込み対象の一時ファイル
+//	writer : バッファ付きの書き込み先
+//	fields : 出力する列の定義
+type JSONLExporter struct {
+	file   *atomicFile
+	writer *bufio.Writer
+	fields []ExportField
+}
+
+// NewJSONLExporterは、JSONLExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	filePath    : 出力するJSON Linesファイルのパス
+//	fields      : 出力する列の定義
+//	keepPartial : 失敗時に書き込み途中のファイルを.partialとして残すかどうか
+//
+// return:
+//
+//	*JSONLExporter : 生成されたJSONLExporterのインスタンス
+//	error          : ディレクトリやファイルの作成に失敗した場合のエラー
+func NewJSONLExporter(filePath string, fields []ExportField, keepPartial bool) (*JSONLExporter, error) {
+	file, err := createAtomicFile(filePath, keepPartial)
+	if err != nil {
+		return nil, fmt.Errorf("JSON Linesファイルの作成に失敗しました: %w", err)
+	}
+
+	return &JSONLExporter{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		fields: fields,
+	}, nil
+}
+
+// Writeは、1件の求人情報をJSONの1行として書き込みます。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : JSONの生成や書き込みに失敗した場合のエラー
+func (e *JSONLExporter) Write(job model.JobPosting) error {
+	record, err := MarshalExportRecord(e.fields, job)
+	if err != nil {
+		return err
+	}
+	return e.WriteRecords([]json.RawMessage{record})
+}
+
+// WriteRecordsは、JSONに変換済みの求人情報をそれぞれ1行として書き込みます。
+//
+// args:
+//
+//	records : 書き込むJSON
+//
+// return:
+//
+//	error : 書き込みに失敗した場合のエラー
+func (e *JSONLExporter) WriteRecords(records []json.RawMessage) error {
+	for _, record := range records {
+		if _, err := e.writer.Write(record); err != nil {
+			return err
+		}
+		if err := e.writer.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flushは、バッファを一時ファイルへ書き出します。
+//
+// return:
+//
+//	error : 書き出しに失敗した場合のエラー
+func (e *JSONLExporter) Flush() error {
+	if err := e.writer.Flush(); err != nil {
+		return fmt.Errorf("JSON Linesのフラッシュに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Closeは、バッファをフラッシュし、一時ファイルを出力先へリネームして確定します。
+//
+// return:
+//
+//	error : フラッシュやファイルの確定に失敗した場合のエラー
+func (e *JSONLExporter) Close() error {
+	if err := e.writer.Flush(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("JSON Linesのフラッシュに失敗しました: %w", err)
+	}
+	return e.file.Commit()
+}
+
+// Abortは、バッファをフラッシュした上で出力を確定せずに破棄します。
+//
+// return:
+//
+//	error : 一時ファイルの後始末に失敗した場合のエラー
+func (e *JSONLExporter) Abort() error {
+	e.writer.Flush()
+	return e.file.Abort()
+}
+
+// MarshalExportRecordは、求人情報を1件のJSONオブジェクトに変換します。
+// キーは列定義のキーで、順序は列定義の順序と同じです。
+//
+// args:
+//
+//	fields : 出力する列の定義
+//	job    : 変換する求人情報
+//
+// return:
+//
+//	json.RawMessage : 変換されたJSON
+//	error           : JSONの生成に失敗した場合のエラー
+func MarshalExportRecord(fields []ExportField, job model.JobPosting) (json.RawMessage, error) {
+	var record bytes.Buffer
+	record.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			record.WriteByte(',')
+		}
+
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, fmt.Errorf("キー %s のJSON変換に失敗しました: %w", field.Key, err)
+		}
+		value, err := json.Marshal(ExportValue(field, field.Value(job)))
+		if err != nil {
+			return nil, fmt.Errorf("列 %s のJSON変換に失敗しました: %w", field.Key, err)
+		}
+
+		record.Write(key)
+		record.WriteByte(':')
+		record.Write(value)
+	}
+	record.WriteByte('}')
+
+	return record.Bytes(), nil
+}
+
+// ExportValueは、CSVのセルと同じ文字列の値を、列の型に応じたJSONの値に変換します。
+// 空文字列はnull、複数の値を持つ列は配列になります。数値として解釈できない値は文字列のまま返します。
+//
+// args:
+//
+//	field : 列の定義
+//	raw   : CSVのセルと同じ文字列の値
+//
+// return:
+//
+//	any : JSONに変換できる値
+func ExportValue(field ExportField, raw string) any {
+	if !field.Multi {
+		return exportScalarValue(field, raw)
+	}
+
+	if raw == "" {
+		if field.Nullable {
+			return nil
+		}
+		return []any{}
+	}
+
+	parts := strings.Split(raw, multiValueSeparator)
+	values := make([]any, 0, len(parts))
+	for _, part := range parts {
+		values = append(values, exportScalarValue(field, part))
+	}
+	return values
+}
+
+// exportScalarValueは、単一の値を列の型に応じたJSONの値に変換します。
+func exportScalarValue(field ExportField, raw string) any {
+	if raw == "" && (field.Nullable || field.Type != FieldTypeString) {
+		return nil
+	}
+
+	switch field.Type {
+	case FieldTypeInteger:
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return v
+		}
+	case FieldTypeNumber:
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return v
+		}
+	}
+	return raw
+}
@@ -0,0 +1,172 @@
+package infra
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// manifestSuffixは、マニフェストファイルに付与するサフィックスです。
+const manifestSuffix = ".manifest.json"
+
+// ExportManifestは、エクスポートファイルの完全性を検証するためのマニフェストです。
+// 下流のパイプラインは行数とハッシュ値を照合することで、欠損や途中切れを検出できます。
+type ExportManifest struct {
+	File          string    `json:"file"`
+	RowCount      int       `json:"row_count"`
+	SHA256        string    `json:"sha256"`
+	SchemaVersion string    `json:"schema_version"`
+	Columns       []string  `json:"columns"`
+	ConfigHash    string    `json:"config_hash"`
+	ConfigFile    string    `json:"config_file,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// manifestExporterは、他のFileExporterをラップし、Close時にマニフェストを出力するFileExporterの実装です。
+//
+// フィールド:
+//
+//	inner         : 実際に書き込みを行うエクスポーター
+//	path          : innerが出力するファイルのパス
+//	schemaVersion : 出力データのスキーマバージョン
+//	columns       : 出力データの列名
+//	configHash    : 出力を生成した設定のハッシュ値
+//	configFile    : 出力を生成した設定のスナップショットのファイル名
+//	rowCount      : 書き込みに成功した行数
+type manifestExporter struct {
+	inner         FileExporter
+	path          string
+	schemaVersion SchemaVersion
+	columns       []string
+	configHash    string
+	configFile    string
+	rowCount      int
+}
+
+// NewManifestExporterは、manifestExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	inner         : ラップするエクスポーター
+//	path          : innerが出力するファイルのパス
+//	schemaVersion : 出力データのスキーマバージョン
+//	columns       : 出力データの列名
+//	configHash    : 出力を生成した設定のハッシュ値
+//	configFile    : 出力を生成した設定のスナップショットのファイル名（スナップショットがない場合は空文字列）
+//
+// return:
+//
+//	*manifestExporter : 生成されたエクスポーター
+func NewManifestExporter(inner FileExporter, path string, schemaVersion SchemaVersion, columns []string, configHash, configFile string) *manifestExporter {
+	return &manifestExporter{
+		inner:         inner,
+		path:          path,
+		schemaVersion: schemaVersion,
+		columns:       columns,
+		configHash:    configHash,
+		configFile:    configFile,
+	}
+}
+
+// Writeは、innerに書き込み、成功した行数を記録します。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : innerの書き込みに失敗した場合のエラー
+func (m *manifestExporter) Write(job model.JobPosting) error {
+	if err := m.inner.Write(job); err != nil {
+		return err
+	}
+	m.rowCount++
+	return nil
+}
+
+// Flushは、innerのバッファを書き出します。
+func (m *manifestExporter) Flush() error {
+	return flushExporter(m.inner)
+}
+
+// Closeは、innerをクローズして出力を確定した後、出力ファイルのハッシュを計算してマニフェストを書き込みます。
+//
+// return:
+//
+//	error : innerのクローズ、ハッシュ計算、マニフェストの書き込みに失敗した場合のエラー
+func (m *manifestExporter) Close() error {
+	if err := m.inner.Close(); err != nil {
+		return err
+	}
+
+	checksum, err := sha256File(m.path)
+	if err != nil {
+		return fmt.Errorf("出力ファイルのハッシュ計算に失敗しました: %w", err)
+	}
+
+	manifest := ExportManifest{
+		File:          filepath.Base(m.path),
+		RowCount:      m.rowCount,
+		SHA256:        checksum,
+		SchemaVersion: m.schemaVersion.String(),
+		Columns:       m.columns,
+		ConfigHash:    m.configHash,
+		ConfigFile:    m.configFile,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := writeJSONFile(m.path+manifestSuffix, manifest); err != nil {
+		return fmt.Errorf("マニフェストの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Abortは、マニフェストを出力せずにinnerを破棄します。
+//
+// return:
+//
+//	error : innerの破棄に失敗した場合のエラー
+func (m *manifestExporter) Abort() error {
+	return m.inner.Abort()
+}
+
+// sha256Fileは、指定されたファイルのSHA-256ハッシュを16進数表記で返します。
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeJSONFileは、値をインデント付きのJSONとしてアトミックに書き込みます。
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := createAtomicFile(path, false)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Abort()
+		return err
+	}
+	return file.Commit()
+}
@@ -0,0 +1,238 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// ErrQuotaExceededは、実行あたりのリソース使用量が上限に達したことを示すエラーです。
+var ErrQuotaExceeded = errors.New("リソース使用量の上限に達しました")
+
+// Usageは、1回の実行で消費したリソース量です。
+//
+// フィールド:
+//
+//	Pages       : ナビゲーションしたページ数
+//	Bytes       : 取得したHTMLのバイト数
+//	BrowserTime : ブラウザ操作に要した時間の合計
+type Usage struct {
+	Pages       int
+	Bytes       int64
+	BrowserTime time.Duration
+}
+
+// meteredBrowserClientは、BrowserClientをラップしてリソース使用量を計測し、上限を超えた場合に処理を止めるBrowserClientの実装です。
+// 計測対象外のメソッドはラップしたクライアントにそのまま委譲します。
+//
+// フィールド:
+//
+//	BrowserClient : ラップするクライアント
+//	quota         : リソース使用量の上限（0は無制限）
+//	usage         : 現在までのリソース使用量
+type meteredBrowserClient struct {
+	BrowserClient
+	quota config.QuotaConfig
+	mu    sync.Mutex
+	usage Usage
+}
+
+// NewMeteredBrowserClientは、meteredBrowserClientの新しいインスタンスを生成します。
+//
+// args:
+//
+//	client : ラップするクライアント
+//	quota  : リソース使用量の上限
+//
+// return:
+//
+//	*meteredBrowserClient : 生成されたクライアント
+func NewMeteredBrowserClient(client BrowserClient, quota config.QuotaConfig) *meteredBrowserClient {
+	return &meteredBrowserClient{
+		BrowserClient: client,
+		quota:         quota,
+	}
+}
+
+// Usageは、現在までのリソース使用量を返します。
+func (m *meteredBrowserClient) Usage() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+// Navigateは、上限を確認した上でナビゲーションし、ページ数と所要時間を記録します。
+//
+// args:
+//
+//	ctx: 再試行の待機を中断するためのコンテキスト
+//	url: 遷移先のURL
+//
+// return:
+//
+//	error: 上限に達している場合はErrQuotaExceeded、ナビゲーションの失敗時はそのエラー
+func (m *meteredBrowserClient) Navigate(ctx context.Context, url string) error {
+	if err := m.checkQuota(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err := m.BrowserClient.Navigate(ctx, url)
+
+	m.mu.Lock()
+	m.usage.Pages++
+	m.usage.BrowserTime += time.Since(start)
+	m.mu.Unlock()
+
+	return err
+}
+
+// Fetchは、上限を確認した上でリクエストを送り、ページ数・バイト数・所要時間を記録します。
+//
+// args:
+//
+//	ctx: 再試行の待機を中断するためのコンテキスト
+//	url: リクエスト先のURL
+//
+// return:
+//
+//	[]byte: レスポンスのボディ
+//	error: 上限に達している場合はErrQuotaExceeded、リクエストの失敗時はそのエラー
+func (m *meteredBrowserClient) Fetch(ctx context.Context, url string) ([]byte, error) {
+	if err := m.checkQuota(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	body, err := m.BrowserClient.Fetch(ctx, url)
+
+	m.mu.Lock()
+	m.usage.Pages++
+	m.usage.Bytes += int64(len(body))
+	m.usage.BrowserTime += time.Since(start)
+	m.mu.Unlock()
+
+	return body, err
+}
+
+// CheckNotModifiedは、上限を確認した上で条件付きリクエストを送り、ページ数と所要時間を記録します。
+//
+// args:
+//
+//	url: 確認するURL
+//	etag: 前回のレスポンスのETagヘッダー
+//	lastModified: 前回のレスポンスのLast-Modifiedヘッダー
+//
+// return:
+//
+//	bool: 変更がない場合はtrue
+//	error: 上限に達している場合はErrQuotaExceeded、リクエストの失敗時はそのエラー
+func (m *meteredBrowserClient) CheckNotModified(url, etag, lastModified string) (bool, error) {
+	if err := m.checkQuota(); err != nil {
+		return false, err
+	}
+
+	start := time.Now()
+	notModified, err := m.BrowserClient.CheckNotModified(url, etag, lastModified)
+
+	m.mu.Lock()
+	m.usage.Pages++
+	m.usage.BrowserTime += time.Since(start)
+	m.mu.Unlock()
+
+	return notModified, err
+}
+
+// Clickは、クリックを実行し、所要時間を記録します。
+func (m *meteredBrowserClient) Click(selector string) error {
+	start := time.Now()
+	err := m.BrowserClient.Click(selector)
+	m.addBrowserTime(time.Since(start))
+	return err
+}
+
+// ScrollToBottomは、スクロールを実行し、所要時間を記録します。
+func (m *meteredBrowserClient) ScrollToBottom() error {
+	start := time.Now()
+	err := m.BrowserClient.ScrollToBottom()
+	m.addBrowserTime(time.Since(start))
+	return err
+}
+
+// Pressは、キーの入力を実行し、所要時間を記録します。
+func (m *meteredBrowserClient) Press(selector, key string) error {
+	start := time.Now()
+	err := m.BrowserClient.Press(selector, key)
+	m.addBrowserTime(time.Since(start))
+	return err
+}
+
+// FillAndSubmitは、上限を確認した上で検索フォームを送信し、送信をページ数として所要時間とともに記録します。
+func (m *meteredBrowserClient) FillAndSubmit(ctx context.Context, form config.SearchFormConfig) error {
+	if err := m.checkQuota(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err := m.BrowserClient.FillAndSubmit(ctx, form)
+
+	m.mu.Lock()
+	m.usage.Pages++
+	m.usage.BrowserTime += time.Since(start)
+	m.mu.Unlock()
+
+	return err
+}
+
+// GetHTMLは、HTMLを取得し、取得したバイト数と所要時間を記録します。
+func (m *meteredBrowserClient) GetHTML() (string, error) {
+	start := time.Now()
+	html, err := m.BrowserClient.GetHTML()
+
+	m.mu.Lock()
+	m.usage.Bytes += int64(len(html))
+	m.usage.BrowserTime += time.Since(start)
+	m.mu.Unlock()
+
+	return html, err
+}
+
+// PDFは、PDFを出力し、所要時間を記録します。
+func (m *meteredBrowserClient) PDF() ([]byte, error) {
+	start := time.Now()
+	pdf, err := m.BrowserClient.PDF()
+	m.addBrowserTime(time.Since(start))
+	return pdf, err
+}
+
+// addBrowserTimeは、ブラウザ操作の所要時間を加算します。
+func (m *meteredBrowserClient) addBrowserTime(d time.Duration) {
+	m.mu.Lock()
+	m.usage.BrowserTime += d
+	m.mu.Unlock()
+}
+
+// checkQuotaは、現在の使用量がいずれかの上限に達しているかを確認します。
+//
+// return:
+//
+//	error: 上限に達している場合はErrQuotaExceededをラップしたエラー
+func (m *meteredBrowserClient) checkQuota() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.quota.MaxPages > 0 && m.usage.Pages >= m.quota.MaxPages {
+		return fmt.Errorf("%w: ページ数 %d/%d", ErrQuotaExceeded, m.usage.Pages, m.quota.MaxPages)
+	}
+	if m.quota.MaxBytes > 0 && m.usage.Bytes >= m.quota.MaxBytes {
+		return fmt.Errorf("%w: 取得バイト数 %d/%d", ErrQuotaExceeded, m.usage.Bytes, m.quota.MaxBytes)
+	}
+	if m.quota.MaxBrowserSeconds > 0 && m.usage.BrowserTime >= time.Duration(m.quota.MaxBrowserSeconds)*time.Second {
+		return fmt.Errorf("%w: ブラウザ時間 %s/%ds", ErrQuotaExceeded, m.usage.BrowserTime.Round(time.Second), m.quota.MaxBrowserSeconds)
+	}
+	return nil
+}
@@ -1,28 +1,63 @@
 package infra
 
 import (
+	"time"
+
 	"github.com/nrad-K/go-crawler/internal/domain/model"
 )
 
 type CrawlJobRecord struct {
-	ID     string `json:"id"`
-	URL    string `json:"url"`
-	Status string `json:"status"`
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at,omitzero"`
+	UpdatedAt    time.Time `json:"updated_at,omitzero"`
+	ListPage     int       `json:"list_page,omitempty"`
+	ListPosition int       `json:"list_position,omitempty"`
+	StartedAt    time.Time `json:"started_at,omitzero"`
+	FinishedAt   time.Time `json:"finished_at,omitzero"`
+	DurationMS   int64     `json:"duration_ms,omitempty"`
+	HTTPStatus   int       `json:"http_status,omitempty"`
+	RetryCount   int       `json:"retry_count,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	WorkerID     string    `json:"worker_id,omitempty"`
 }
 
 func (c *CrawlJobRecord) ToDomain() (model.CrawlJob, error) {
-	crawlJob, err := model.Reconstruct(c.ID, c.URL, c.Status)
+	crawlJob, err := model.Reconstruct(c.ID, c.URL, c.Status, c.UpdatedAt)
 	if err != nil {
 		return model.CrawlJob{}, err
 	}
 
-	return crawlJob, nil
+	crawlJob = crawlJob.WithListingPosition(model.ListingPosition{Page: c.ListPage, Position: c.ListPosition})
+	crawlJob = crawlJob.WithCreatedAt(c.CreatedAt)
+	crawlJob = crawlJob.WithLastAttempt(model.CrawlAttempt{
+		StartedAt:  c.StartedAt,
+		FinishedAt: c.FinishedAt,
+		Duration:   time.Duration(c.DurationMS) * time.Millisecond,
+		HTTPStatus: c.HTTPStatus,
+		Error:      c.LastError,
+	})
+	crawlJob = crawlJob.WithRetryCount(c.RetryCount)
+	return crawlJob.WithWorkerID(c.WorkerID), nil
 }
 
 func ToRecord(crawlJob model.CrawlJob) CrawlJobRecord {
+	attempt := crawlJob.LastAttempt()
 	return CrawlJobRecord{
-		ID:     crawlJob.ID(),
-		URL:    crawlJob.URL(),
-		Status: string(crawlJob.Status()),
+		ID:           crawlJob.ID(),
+		URL:          crawlJob.URL(),
+		Status:       string(crawlJob.Status()),
+		CreatedAt:    crawlJob.CreatedAt(),
+		UpdatedAt:    crawlJob.UpdatedAt(),
+		ListPage:     crawlJob.ListingPosition().Page,
+		ListPosition: crawlJob.ListingPosition().Position,
+		StartedAt:    attempt.StartedAt,
+		FinishedAt:   attempt.FinishedAt,
+		DurationMS:   attempt.Duration.Milliseconds(),
+		HTTPStatus:   attempt.HTTPStatus,
+		RetryCount:   crawlJob.RetryCount(),
+		LastError:    attempt.Error,
+		WorkerID:     crawlJob.WorkerID(),
 	}
 }
@@ -0,0 +1,160 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// ExportSinkは、multiExporterの書き込み先の1つです。
+//
+// フィールド:
+//
+//	Name     : ログやエラーに表示する出力先の名前
+//	Exporter : 書き込み先のエクスポーター
+type ExportSink struct {
+	Name     string
+	Exporter FileExporter
+}
+
+// multiExporterは、複数のFileExporterに同じ求人情報を書き込むFileExporterの実装です。
+// 1回のスクレイプでCSVとJSON Lines、Parquetなど、複数の形式へ同時に出力できます。
+// 出力先ごとに失敗を扱い、書き込みに失敗した出力先はその時点で破棄して以降の書き込みから外すため、
+// 1つの出力先の失敗が他の出力先の出力に影響しません。
+//
+// フィールド:
+//
+//	sinks     : 書き込み先
+//	failures  : 書き込みに失敗した出力先のエラー（sinksと同じ順序、失敗していない出力先はnil）
+//	onFailure : 出力先が書き込みに失敗した時に呼び出す関数（nilの場合は呼び出さない）
+type multiExporter struct {
+	sinks     []ExportSink
+	failures  []error
+	onFailure func(name string, err error)
+}
+
+// NewMultiExporterは、multiExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	onFailure : 出力先が書き込みに失敗し、以降の書き込みから外した時に呼び出す関数（nilの場合は呼び出さない）
+//	sinks     : 書き込み先
+//
+// return:
+//
+//	*multiExporter : 生成されたエクスポーター
+func NewMultiExporter(onFailure func(name string, err error), sinks ...ExportSink) *multiExporter {
+	return &multiExporter{
+		sinks:     sinks,
+		failures:  make([]error, len(sinks)),
+		onFailure: onFailure,
+	}
+}
+
+// Writeは、失敗していないすべての出力先に求人情報を書き込みます。
+// 書き込みに失敗した出力先は、出力を確定せずに破棄して以降の書き込みから外します。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : すべての出力先が失敗している場合のエラー
+func (m *multiExporter) Write(job model.JobPosting) error {
+	for i, sink := range m.sinks {
+		if m.failures[i] != nil {
+			continue
+		}
+		if err := sink.Exporter.Write(job); err != nil {
+			m.fail(i, err)
+		}
+	}
+
+	if m.healthy() == 0 {
+		return fmt.Errorf("すべての出力先への書き込みに失敗しました: %w", errors.Join(m.failures...))
+	}
+	return nil
+}
+
+// Flushは、失敗していない出力先のバッファを書き出します。
+// 書き出しに失敗した出力先は、書き込みに失敗した場合と同様に以降の書き込みから外します。
+//
+// return:
+//
+//	error : すべての出力先が失敗している場合のエラー
+func (m *multiExporter) Flush() error {
+	for i, sink := range m.sinks {
+		if m.failures[i] != nil {
+			continue
+		}
+		if err := flushExporter(sink.Exporter); err != nil {
+			m.fail(i, err)
+		}
+	}
+
+	if m.healthy() == 0 {
+		return fmt.Errorf("すべての出力先への書き込みに失敗しました: %w", errors.Join(m.failures...))
+	}
+	return nil
+}
+
+// Closeは、失敗していない出力先をクローズして出力を確定します。
+// 途中で失敗した出力先がある場合は、他の出力先を確定したうえでその失敗を返します。
+//
+// return:
+//
+//	error : いずれかの出力先が途中で失敗した場合、またはクローズに失敗した場合のエラー
+func (m *multiExporter) Close() error {
+	for i, sink := range m.sinks {
+		if m.failures[i] != nil {
+			continue
+		}
+		if err := sink.Exporter.Close(); err != nil {
+			m.failures[i] = fmt.Errorf("%s: %w", sink.Name, err)
+		}
+	}
+	return errors.Join(m.failures...)
+}
+
+// Abortは、失敗していない出力先を、出力を確定せずに破棄します。
+//
+// return:
+//
+//	error : いずれかの出力先の破棄に失敗した場合のエラー
+func (m *multiExporter) Abort() error {
+	var errs []error
+	for i, sink := range m.sinks {
+		if m.failures[i] != nil {
+			continue
+		}
+		if err := sink.Exporter.Abort(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// failは、出力先を失敗として記録し、出力を確定せずに破棄します。
+func (m *multiExporter) fail(i int, err error) {
+	sink := m.sinks[i]
+	m.failures[i] = fmt.Errorf("%s: %w", sink.Name, err)
+	if abortErr := sink.Exporter.Abort(); abortErr != nil {
+		m.failures[i] = errors.Join(m.failures[i], fmt.Errorf("%s の後始末に失敗しました: %w", sink.Name, abortErr))
+	}
+	if m.onFailure != nil {
+		m.onFailure(sink.Name, err)
+	}
+}
+
+// healthyは、失敗していない出力先の数を返します。
+func (m *multiExporter) healthy() int {
+	count := 0
+	for _, err := range m.failures {
+		if err == nil {
+			count++
+		}
+	}
+	return count
+}
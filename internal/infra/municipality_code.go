@@ -0,0 +1,201 @@
+package infra
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// MunicipalityCodesは、都道府県ごとの市区町村名と全国地方公共団体コード（JIS X 0402の5桁）の対応表です。
+// 政府統計など、市区町村コードで集計されたデータと結合するために使用します。
+type MunicipalityCodes map[model.PrefectureCode]map[string]string
+
+// defaultMunicipalityCodesは、既定の対応表に含める市区町村です。
+// 求人の多い東京都の特別区と政令指定都市のみを収録し、それ以外は対応表のファイルで補います。
+var defaultMunicipalityCodes = []struct {
+	prefecture model.PrefectureCode
+	city       string
+	code       string
+}{
+	{model.Hokkaido, "札幌市", "01100"},
+	{model.Miyagi, "仙台市", "04100"},
+	{model.Saitama, "さいたま市", "11100"},
+	{model.Chiba, "千葉市", "12100"},
+	{model.Tokyo, "千代田区", "13101"},
+	{model.Tokyo, "中央区", "13102"},
+	{model.Tokyo, "港区", "13103"},
+	{model.Tokyo, "新宿区", "13104"},
+	{model.Tokyo, "文京区", "13105"},
+	{model.Tokyo, "台東区", "13106"},
+	{model.Tokyo, "墨田区", "13107"},
+	{model.Tokyo, "江東区", "13108"},
+	{model.Tokyo, "品川区", "13109"},
+	{model.Tokyo, "目黒区", "13110"},
+	{model.Tokyo, "大田区", "13111"},
+	{model.Tokyo, "世田谷区", "13112"},
+	{model.Tokyo, "渋谷区", "13113"},
+	{model.Tokyo, "中野区", "13114"},
+	{model.Tokyo, "杉並区", "13115"},
+	{model.Tokyo, "豊島区", "13116"},
+	{model.Tokyo, "北区", "13117"},
+	{model.Tokyo, "荒川区", "13118"},
+	{model.Tokyo, "板橋区", "13119"},
+	{model.Tokyo, "練馬区", "13120"},
+	{model.Tokyo, "足立区", "13121"},
+	{model.Tokyo, "葛飾区", "13122"},
+	{model.Tokyo, "江戸川区", "13123"},
+	{model.Kanagawa, "横浜市", "14100"},
+	{model.Kanagawa, "川崎市", "14130"},
+	{model.Kanagawa, "相模原市", "14150"},
+	{model.Niigata, "新潟市", "15100"},
+	{model.Shizuoka, "静岡市", "22100"},
+	{model.Shizuoka, "浜松市", "22130"},
+	{model.Aichi, "名古屋市", "23100"},
+	{model.Kyoto, "京都市", "26100"},
+	{model.Osaka, "大阪市", "27100"},
+	{model.Osaka, "堺市", "27140"},
+	{model.Hyogo, "神戸市", "28100"},
+	{model.Okayama, "岡山市", "33100"},
+	{model.Hiroshima, "広島市", "34100"},
+	{model.Fukuoka, "北九州市", "40100"},
+	{model.Fukuoka, "福岡市", "40130"},
+	{model.Kumamoto, "熊本市", "43100"},
+}
+
+// DefaultMunicipalityCodesは、東京都の特別区と政令指定都市を収録した既定の対応表を返します。
+//
+// return:
+//
+//	MunicipalityCodes : 既定の対応表
+func DefaultMunicipalityCodes() MunicipalityCodes {
+	codes := make(MunicipalityCodes)
+	for _, m := range defaultMunicipalityCodes {
+		codes.add(m.prefecture, m.city, m.code)
+	}
+	return codes
+}
+
+// LoadMunicipalityCodesは、総務省の全国地方公共団体コードの一覧をCSVとして保存したファイルを読み込み、既定の対応表に追加します。
+// ヘッダーの「団体コード」「都道府県名」「市区町村名」で始まる列を使用し、市区町村名が空の行（都道府県の行）は無視します。
+// 団体コードは6桁（検査数字付き）と5桁のどちらでも指定できます。
+//
+// args:
+//
+//	path : 全国地方公共団体コードの一覧のCSVのパス
+//
+// return:
+//
+//	MunicipalityCodes : 既定の対応表にファイルの内容を追加した対応表
+//	error             : 読み込みや解析に失敗した場合、または必要な列がない場合のエラー
+func LoadMunicipalityCodes(path string) (MunicipalityCodes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("市区町村コードのファイルを読み込めませんでした: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("市区町村コードのファイルのヘッダーの読み込みに失敗しました: %w", err)
+	}
+
+	columns := []string{"団体コード", "都道府県名", "市区町村名"}
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		indexes[i] = -1
+		for j, header := range headers {
+			// Excelから保存したCSVの先頭に付くBOMを取り除く
+			if strings.HasPrefix(strings.TrimPrefix(strings.TrimSpace(header), "\ufeff"), column) {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("市区町村コードのファイルに%s列がありません", column)
+		}
+	}
+
+	codes := DefaultMunicipalityCodes()
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("市区町村コードのファイルの読み込みに失敗しました: %w", err)
+		}
+
+		field := func(i int) string {
+			if indexes[i] >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[indexes[i]])
+		}
+		code, prefectureName, city := field(0), field(1), field(2)
+		if city == "" {
+			continue
+		}
+		prefecture, ok := prefMap[prefectureName]
+		if !ok {
+			return nil, fmt.Errorf("市区町村コードのファイルの%d行目の都道府県名が不正です: %s", line, prefectureName)
+		}
+		if !isMunicipalityCode(code) {
+			return nil, fmt.Errorf("市区町村コードのファイルの%d行目の団体コードが不正です: %s", line, code)
+		}
+		codes.add(prefecture, city, code[:5])
+	}
+	return codes, nil
+}
+
+// Lookupは、都道府県と市区町村名から全国地方公共団体コードを返します。
+// 郡部の町村（例: "虻田郡ニセコ町"）は、郡名を除いた町村名でも探します。
+//
+// args:
+//
+//	prefecture : 都道府県コード
+//	city       : 市区町村名
+//
+// return:
+//
+//	string : 全国地方公共団体コード（対応表にない場合は空）
+func (m MunicipalityCodes) Lookup(prefecture model.PrefectureCode, city string) string {
+	cities := m[prefecture]
+	if cities == nil || city == "" {
+		return ""
+	}
+	if code, ok := cities[city]; ok {
+		return code
+	}
+	if _, town, ok := strings.Cut(city, "郡"); ok {
+		return cities[town]
+	}
+	return ""
+}
+
+// addは、市区町村名と全国地方公共団体コードの対応を追加します。
+func (m MunicipalityCodes) add(prefecture model.PrefectureCode, city, code string) {
+	if m[prefecture] == nil {
+		m[prefecture] = make(map[string]string)
+	}
+	m[prefecture][city] = code
+}
+
+// isMunicipalityCodeは、文字列が5桁または6桁の数字の団体コードかを判定します。
+func isMunicipalityCode(s string) bool {
+	if len(s) != 5 && len(s) != 6 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// RunStatusは、クローラー・スクレイパーの1回の実行の終了状態です。
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "success" // 正常に完了した
+	RunFailed    RunStatus = "failed"  // エラーにより失敗した
+	RunAborted   RunStatus = "aborted" // シグナルにより中断された
+)
+
+// RunSummaryは、クローラー・スクレイパーの1回の実行結果で、通知のペイロードとして送信します。
+//
+// フィールド:
+//
+//	Command         : 実行したコマンド（"crawler" または "scrape"）
+//	Status          : 実行の終了状態
+//	Host            : 実行したマシンのホスト名
+//	StartedAt       : 実行の開始日時
+//	FinishedAt      : 実行の終了日時
+//	DurationSeconds : 実行の所要時間（秒）
+//	Generated       : 生成したクロールジョブ数（crawlerのみ）
+//	Executed        : 処理したクロールジョブ数（scrapeの場合は処理したHTMLの数）
+//	Failed          : 失敗したクロールジョブ数（scrapeの場合は処理に失敗したHTMLの数）
+//	Written         : 出力した求人情報の件数（scrapeのみ）
+//	ConfigHash      : 実行に使用した設定のハッシュ値
+//	Error           : 失敗または中断した場合の原因
+type RunSummary struct {
+	Command         string    `json:"command"`
+	Status          RunStatus `json:"status"`
+	Host            string    `json:"host"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Generated       int       `json:"generated"`
+	Executed        int       `json:"executed"`
+	Failed          int       `json:"failed"`
+	Written         int       `json:"written"`
+	ConfigHash      string    `json:"config_hash,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Finishは、実行の終了日時と所要時間、終了状態を設定したRunSummaryを返します。
+// runErrがnilでも、ctxが中断されている場合は中断として扱います。
+//
+// args:
+//
+//	ctx    : 実行に使用したコンテキスト
+//	runErr : 実行が失敗した場合の原因
+//
+// return:
+//
+//	RunSummary : 終了状態を設定した実行結果
+func (s RunSummary) Finish(ctx context.Context, runErr error) RunSummary {
+	s.FinishedAt = time.Now()
+	s.DurationSeconds = s.FinishedAt.Sub(s.StartedAt).Seconds()
+	if s.Host == "" {
+		s.Host, _ = os.Hostname()
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		s.Status = RunAborted
+		if runErr == nil {
+			runErr = ctx.Err()
+		}
+	case runErr != nil:
+		s.Status = RunFailed
+	default:
+		s.Status = RunSucceeded
+	}
+	if runErr != nil {
+		s.Error = runErr.Error()
+	}
+	return s
+}
+
+// Notifierは、実行結果を外部に通知します。
+type Notifier interface {
+	Notify(ctx context.Context, summary RunSummary) error
+}
+
+// NotifierChainは、複数の通知先に順に通知するNotifierの実装です。
+// いずれかの通知先への通知が失敗しても、残りの通知先には通知します。
+type NotifierChain []Notifier
+
+func (c NotifierChain) Notify(ctx context.Context, summary RunSummary) error {
+	var errs []error
+	for _, notifier := range c {
+		errs = append(errs, notifier.Notify(ctx, summary))
+	}
+	return errors.Join(errs...)
+}
+
+// webhookNotifierは、実行結果をWebhookにPOSTするNotifierの実装です。
+//
+// フィールド:
+//
+//	cfg     : 通知先の設定
+//	timeout : 1回の通知のタイムアウト
+//	invoke  : Webhookへの呼び出し
+type webhookNotifier struct {
+	cfg     config.NotificationConfig
+	timeout time.Duration
+	invoke  hookInvoker
+}
+
+// NewNotifiersFromConfigは、設定ファイルの通知先の定義からNotifierを生成します。
+//
+// args:
+//
+//	cfgs : 通知先の設定
+//
+// return:
+//
+//	Notifier : 定義順に通知するNotifierChain
+func NewNotifiersFromConfig(cfgs []config.NotificationConfig) Notifier {
+	chain := make(NotifierChain, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		timeout := defaultHookTimeout
+		if cfg.TimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+		}
+		chain = append(chain, &webhookNotifier{
+			cfg:     cfg,
+			timeout: timeout,
+			invoke:  webhookInvoker(cfg.URL),
+		})
+	}
+	return chain
+}
+
+// Notifyは、通知する契機に該当する場合に、実行結果を設定の形式でWebhookにPOSTします。
+func (n *webhookNotifier) Notify(ctx context.Context, summary RunSummary) error {
+	event := config.NotifyOnFailure
+	if summary.Status == RunSucceeded {
+		event = config.NotifyOnSuccess
+	}
+	if !n.cfg.Notifies(event) {
+		return nil
+	}
+
+	var payload any = summary
+	if n.cfg.FormatOrDefault() == config.NotificationSlack {
+		payload = map[string]string{"text": slackMessage(summary)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("通知 %s のペイロードのマーシャルに失敗しました: %w", n.cfg.URL, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	if _, err := n.invoke(ctx, body); err != nil {
+		return fmt.Errorf("通知 %s の送信に失敗しました: %w", n.cfg.URL, err)
+	}
+	return nil
+}
+
+// slackMessageは、実行結果をSlackに投稿するメッセージに変換します。
+func slackMessage(summary RunSummary) string {
+	var title string
+	switch summary.Status {
+	case RunSucceeded:
+		title = fmt.Sprintf(":white_check_mark: %s の実行が完了しました", summary.Command)
+	case RunAborted:
+		title = fmt.Sprintf(":warning: %s の実行が中断されました", summary.Command)
+	default:
+		title = fmt.Sprintf(":x: %s の実行が失敗しました", summary.Command)
+	}
+
+	lines := []string{title}
+	if summary.Command == "scrape" {
+		lines = append(lines, fmt.Sprintf("処理: %d件 / 失敗: %d件 / 出力: %d件", summary.Executed, summary.Failed, summary.Written))
+	} else {
+		lines = append(lines, fmt.Sprintf("生成: %d件 / 実行: %d件 / 失敗: %d件", summary.Generated, summary.Executed, summary.Failed))
+	}
+	lines = append(lines, fmt.Sprintf("所要時間: %s / ホスト: %s", time.Duration(summary.DurationSeconds*float64(time.Second)).Round(time.Second), summary.Host))
+	if summary.Error != "" {
+		lines = append(lines, "エラー: "+summary.Error)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,275 @@
+package infra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+// objectHTMLStorageは、オブジェクトストレージ（S3またはGCS）を用いたHTMLWriterとHTMLLoaderの実装です。
+// クロールとスクレイプを別のマシンで実行する場合に、共有ディスクなしでバケットを介してHTMLを共有できます。
+// キーは "<prefix>/<ディレクトリ>/<ファイル名>" の形式で、ディレクトリは名前空間として扱います。
+// メタデータはローカルのサイドカーと同じ規則で、"<ジョブID>.meta.json" のキーに保存します。
+//
+// フィールド:
+//
+//	ctx         : 再試行の待機を中断するためのコンテキスト
+//	client      : オブジェクトストレージのクライアント
+//	prefix      : キーの先頭に付ける文字列
+//	dir         : 保存時に使用する名前空間（クローラーのoutput_dir）
+//	compress    : gzipで圧縮してアップロードするかどうか
+//	maxAttempts : アップロードを試行する最大回数（初回を含む）
+//	backoff     : アップロードの最初の再試行までの待機時間（再試行のたびに2倍になります）
+type objectHTMLStorage struct {
+	ctx         context.Context
+	client      *objectStorageClient
+	prefix      string
+	dir         string
+	compress    bool
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewObjectHTMLStorageは、objectHTMLStorageの新しいインスタンスを生成します。
+//
+// args:
+//
+//	ctx     : アップロードの再試行の待機を中断するためのコンテキスト
+//	storage : 保存先の種類（s3またはgcs）
+//	cfg     : バケットとアップロードの設定
+//	creds   : リクエストの署名に使用する認証情報
+//	dir     : 保存時に使用する名前空間
+//
+// return:
+//
+//	*objectHTMLStorage : 生成されたストレージ
+//	error              : 認証情報やエンドポイントが不正な場合のエラー
+func NewObjectHTMLStorage(ctx context.Context, storage config.StorageType, cfg config.ObjectStorageConfig, creds ObjectStorageCredentials, dir string) (*objectHTMLStorage, error) {
+	client, err := newObjectStorageClient(storage, cfg, creds)
+	if err != nil {
+		return nil, err
+	}
+	return &objectHTMLStorage{
+		ctx:         ctx,
+		client:      client,
+		prefix:      strings.Trim(cfg.Prefix, "/"),
+		dir:         dir,
+		compress:    cfg.Compress,
+		maxAttempts: max(cfg.UploadAttempts(), 1),
+		backoff:     cfg.UploadBackoff(),
+	}, nil
+}
+
+// SaveHTMLは、HTMLをバケットにアップロードします。
+//
+// args:
+//
+//	filename : ファイル名
+//	content  : HTMLの内容
+//
+// return:
+//
+//	error : 再試行してもアップロードに失敗した場合のエラー
+func (s *objectHTMLStorage) SaveHTML(filename string, content string) error {
+	key := s.objectKey(path.Join(s.dir, filename))
+	if err := s.upload(key, []byte(content), "text/html; charset=utf-8"); err != nil {
+		return fmt.Errorf("HTMLをバケットに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// SavePDFは、PDFをバケットにアップロードします。
+//
+// args:
+//
+//	filename : PDFのファイル名
+//	content  : PDFの内容
+//
+// return:
+//
+//	error : 再試行してもアップロードに失敗した場合のエラー
+func (s *objectHTMLStorage) SavePDF(filename string, content []byte) error {
+	key := s.objectKey(path.Join(s.dir, filename))
+	if err := s.upload(key, content, "application/pdf"); err != nil {
+		return fmt.Errorf("PDFをバケットに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// SaveHTMLMetadataは、HTMLに対応するメタデータをバケットにアップロードします。
+//
+// args:
+//
+//	filename : HTMLのファイル名
+//	meta     : 保存するメタデータ
+//
+// return:
+//
+//	error : マーシャルに失敗した場合、または再試行してもアップロードに失敗した場合のエラー
+func (s *objectHTMLStorage) SaveHTMLMetadata(filename string, meta HTMLMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("メタデータのマーシャルに失敗しました: %w", err)
+	}
+
+	key := s.objectKey(MetadataPath(path.Join(s.dir, filename)))
+	if err := s.upload(key, data, "application/json"); err != nil {
+		return fmt.Errorf("メタデータをバケットに保存できませんでした: %w", err)
+	}
+	return nil
+}
+
+// uploadは、オブジェクトをアップロードし、一時的な失敗の場合は待機時間を2倍にしながら再試行します。
+// 再試行の待機中にコンテキストがキャンセルされた場合は、再試行せずに直前のエラーを返します。
+// 圧縮が有効な場合は、gzipで圧縮してContent-Encodingを設定します。
+//
+// args:
+//
+//	key         : オブジェクトのキー
+//	data        : オブジェクトの内容
+//	contentType : オブジェクトのContent-Type
+//
+// return:
+//
+//	error : 再試行しても失敗した場合、または再試行の対象でない失敗の場合のエラー
+func (s *objectHTMLStorage) upload(key string, data []byte, contentType string) error {
+	header := http.Header{"Content-Type": {contentType}}
+	if s.compress {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return fmt.Errorf("%s の圧縮に失敗しました: %w", key, err)
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("%s の圧縮に失敗しました: %w", key, err)
+		}
+		data = buf.Bytes()
+		header.Set("Content-Encoding", "gzip")
+	}
+
+	backoff := s.backoff
+	for attempt := 1; ; attempt++ {
+		err := s.client.put(context.Background(), key, data, header)
+		if err == nil {
+			return nil
+		}
+		if attempt >= s.maxAttempts || !isRetryableObjectStorageError(err) {
+			return fmt.Errorf("%d回試行しましたが失敗しました: %w", attempt, err)
+		}
+		if sleepContext(s.ctx, backoff) != nil {
+			return fmt.Errorf("%d回試行した後、再試行の待機中に中断されました: %w", attempt, err)
+		}
+		backoff *= 2
+	}
+}
+
+// objectKeyは、HTMLのパスをオブジェクトのキーに変換します。
+func (s *objectHTMLStorage) objectKey(p string) string {
+	return path.Join(s.prefix, p)
+}
+
+// ListHTMLFilePathsは、指定された名前空間に保存されたHTMLのパスを列挙します。
+// 拡張子は大文字と小文字を区別せずに判定します。パスは辞書順に並べて返します。
+//
+// args:
+//
+//	dir : 列挙する名前空間
+//
+// return:
+//
+//	[]string : 見つかったHTMLのパス（"<ディレクトリ>/<ファイル名>"）
+//	error    : 列挙に失敗した場合のエラー
+func (s *objectHTMLStorage) ListHTMLFilePaths(dir string) ([]string, error) {
+	keys, err := s.client.list(context.Background(), s.objectKey(path.Clean(dir))+"/")
+	if err != nil {
+		return nil, fmt.Errorf("バケットのHTMLの列挙に失敗しました: %w", err)
+	}
+
+	paths := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !strings.EqualFold(path.Ext(key), ".html") {
+			continue
+		}
+		if s.prefix != "" {
+			key = strings.TrimPrefix(key, s.prefix+"/")
+		}
+		paths = append(paths, key)
+	}
+	return paths, nil
+}
+
+// OpenHTMLFileは、指定されたパスのHTMLをバケットからストリームとして開きます。
+//
+// args:
+//
+//	path : HTMLのパス
+//
+// return:
+//
+//	io.ReadCloser : HTMLの内容
+//	error         : 取得に失敗した場合のエラー（HTMLがない場合はos.ErrNotExistをラップ）
+func (s *objectHTMLStorage) OpenHTMLFile(path string) (io.ReadCloser, error) {
+	resp, err := s.client.get(context.Background(), s.objectKey(path))
+	if err != nil {
+		return nil, fmt.Errorf("HTMLをバケットから読み込めませんでした %s: %w", path, err)
+	}
+	return resp.Body, nil
+}
+
+// LoadHTMLFileは、指定されたパスのHTMLをバケットから読み込みます。
+//
+// args:
+//
+//	path : HTMLのパス
+//
+// return:
+//
+//	string : HTMLの内容
+//	error  : 読み込みに失敗した場合のエラー
+func (s *objectHTMLStorage) LoadHTMLFile(path string) (string, error) {
+	body, err := s.OpenHTMLFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	html, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("HTMLをバケットから読み込めませんでした %s: %w", path, err)
+	}
+	return string(html), nil
+}
+
+// LoadHTMLMetadataは、HTMLに対応するメタデータをバケットから読み込みます。
+// メタデータが存在しない場合は、os.ErrNotExistをラップしたエラーを返します。
+//
+// args:
+//
+//	htmlPath : HTMLのパス
+//
+// return:
+//
+//	HTMLMetadata : 読み込んだメタデータ
+//	error        : 読み込みやデシリアライズに失敗した場合のエラー
+func (s *objectHTMLStorage) LoadHTMLMetadata(htmlPath string) (HTMLMetadata, error) {
+	resp, err := s.client.get(context.Background(), s.objectKey(MetadataPath(htmlPath)))
+	if err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータをバケットから読み込めませんでした %s: %w", htmlPath, err)
+	}
+	defer resp.Body.Close()
+
+	var meta HTMLMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return HTMLMetadata{}, fmt.Errorf("メタデータのデシリアライズに失敗しました: %w", err)
+	}
+	return meta, nil
+}
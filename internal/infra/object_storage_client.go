@@ -0,0 +1,421 @@
+package infra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+)
+
+const (
+	// gcsEndpointは、GCSのS3互換のXML APIのエンドポイントです。
+	gcsEndpoint = "https://storage.googleapis.com"
+	// objectStorageTimeoutは、オブジェクトストレージへの1回のリクエストのタイムアウトです。
+	objectStorageTimeout = 60 * time.Second
+	// objectStorageErrorBodyBytesは、エラーの原因として読み込むレスポンスの最大バイト数です。
+	objectStorageErrorBodyBytes = 4096
+	// sigV4Algorithmは、リクエストの署名方式です。
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+)
+
+// ObjectStorageCredentialsは、オブジェクトストレージへのリクエストの署名に使用する認証情報です。
+// S3の場合はアクセスキーを、GCSの場合はHMACキー（相互運用のためのキー）を指定します。
+//
+// フィールド:
+//
+//	AccessKeyID     : アクセスキーID
+//	SecretAccessKey : シークレットアクセスキー
+//	SessionToken    : 一時的な認証情報のセッショントークン（使用しない場合は空文字列）
+type ObjectStorageCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// ObjectStorageStatusErrorは、オブジェクトストレージが成功以外のステータスを返したことを示すエラーです。
+// ステータスが404の場合は、os.ErrNotExistとして判定できます。
+//
+// フィールド:
+//
+//	StatusCode : HTTPステータスコード
+//	Code       : ストレージが返したエラーコード（例: NoSuchKey、AccessDenied）
+//	Message    : ストレージが返したエラーの内容
+type ObjectStorageStatusError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ObjectStorageStatusError) Error() string {
+	msg := fmt.Sprintf("オブジェクトストレージがステータス %d を返しました", e.StatusCode)
+	for _, detail := range []string{e.Code, e.Message} {
+		if detail != "" {
+			msg += ": " + detail
+		}
+	}
+	return msg
+}
+
+func (e *ObjectStorageStatusError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+// objectStorageClientは、S3互換のAPIでオブジェクトストレージにアクセスするクライアントです。
+// リクエストにはAWS署名バージョン4で署名します。GCSにはHMACキーを使用した相互運用のAPIでアクセスします。
+//
+// フィールド:
+//
+//	http    : HTTPクライアント
+//	baseURL : バケットを表すURL（仮想ホスト形式またはパス形式）
+//	region  : 署名に使用するリージョン
+//	creds   : 署名に使用する認証情報
+type objectStorageClient struct {
+	http    *http.Client
+	baseURL *url.URL
+	region  string
+	creds   ObjectStorageCredentials
+}
+
+// newObjectStorageClientは、設定からobjectStorageClientを生成します。
+//
+// args:
+//
+//	storage : 保存先の種類（s3またはgcs）
+//	cfg     : バケットの設定
+//	creds   : 署名に使用する認証情報
+//
+// return:
+//
+//	*objectStorageClient : 生成されたクライアント
+//	error                : 認証情報やエンドポイントが不正な場合のエラー
+func newObjectStorageClient(storage config.StorageType, cfg config.ObjectStorageConfig, creds ObjectStorageCredentials) (*objectStorageClient, error) {
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("%sにアクセスするための認証情報が設定されていません", storage)
+	}
+
+	var rawURL string
+	switch {
+	case cfg.Endpoint != "":
+		rawURL = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	case storage == config.StorageGCS:
+		rawURL = gcsEndpoint + "/" + cfg.Bucket
+	case strings.Contains(cfg.Bucket, "."):
+		// ドットを含むバケット名は仮想ホスト形式ではTLSの証明書と一致しないため、パス形式を使用する
+		rawURL = fmt.Sprintf("https://s3.%s.amazonaws.com/%s", cfg.RegionOrDefault(storage), cfg.Bucket)
+	default:
+		rawURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.RegionOrDefault(storage))
+	}
+	baseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("オブジェクトストレージのURL %q を解釈できません: %w", rawURL, err)
+	}
+
+	return &objectStorageClient{
+		http:    &http.Client{Timeout: objectStorageTimeout},
+		baseURL: baseURL,
+		region:  cfg.RegionOrDefault(storage),
+		creds:   creds,
+	}, nil
+}
+
+// getは、オブジェクトを取得します。gzipで圧縮されたオブジェクトは展開して返します。レスポンスのBodyは呼び出し元で閉じてください。
+//
+// args:
+//
+//	ctx : コンテキスト
+//	key : オブジェクトのキー
+//
+// return:
+//
+//	*http.Response : オブジェクトのレスポンス
+//	error          : 取得に失敗した場合のエラー（オブジェクトがない場合はos.ErrNotExistをラップ）
+func (c *objectStorageClient) get(ctx context.Context, key string) (*http.Response, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	// gzipで圧縮してアップロードしたオブジェクトは、HTTPクライアントが展開しなかった場合にここで展開する
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") && !resp.Uncompressed {
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s の展開に失敗しました: %w", key, err)
+		}
+		resp.Body = gzipBody{Reader: reader, body: resp.Body}
+	}
+	return resp, nil
+}
+
+// gzipBodyは、展開しながら読み込むレスポンスのBodyです。閉じるとレスポンスのBodyも閉じます。
+type gzipBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b gzipBody) Close() error {
+	return errors.Join(b.Reader.Close(), b.body.Close())
+}
+
+// putは、オブジェクトをアップロードします。同じキーのオブジェクトがある場合は上書きします。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	key    : オブジェクトのキー
+//	body   : オブジェクトの内容
+//	header : Content-TypeやContent-Encodingなど、オブジェクトに設定するヘッダー
+//
+// return:
+//
+//	error : アップロードに失敗した場合のエラー
+func (c *objectStorageClient) put(ctx context.Context, key string, body []byte, header http.Header) error {
+	resp, err := c.do(ctx, http.MethodPut, key, nil, body, header)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// isRetryableObjectStorageErrorは、再試行すれば成功する可能性のある失敗かどうかを判定します。
+// 通信エラー、タイムアウト、429と5xxのステータスを再試行の対象とし、認証エラーなどのその他のステータスは対象としません。
+func isRetryableObjectStorageError(err error) bool {
+	var statusErr *ObjectStorageStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+}
+
+// listBucketResultは、ListObjectsV2のレスポンスです。
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// listは、指定された接頭辞で始まるオブジェクトのキーを、キーの辞書順にすべて列挙します。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	prefix : キーの接頭辞
+//
+// return:
+//
+//	[]string : 見つかったオブジェクトのキー
+//	error    : 列挙に失敗した場合のエラー
+func (c *objectStorageClient) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return keys, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return keys, fmt.Errorf("オブジェクトの一覧のデシリアライズに失敗しました: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// doは、署名したリクエストを送信します。成功以外のステータスの場合は、ObjectStorageStatusErrorを返します。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	method : HTTPメソッド
+//	key    : オブジェクトのキー（バケットに対するリクエストの場合は空文字列）
+//	query  : クエリパラメータ
+//	body   : リクエストの本文（本文がない場合はnil）
+//	header : 追加するヘッダー
+//
+// return:
+//
+//	*http.Response : レスポンス
+//	error          : 送信に失敗した場合、または成功以外のステータスの場合のエラー
+func (c *objectStorageClient) do(ctx context.Context, method, key string, query url.Values, body []byte, header http.Header) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	u.RawPath = awsURIEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("オブジェクトストレージへのリクエストを作成できませんでした: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	c.sign(req, u.RawPath, body, time.Now())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("オブジェクトストレージへのリクエストに失敗しました: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %w", method, key, readObjectStorageError(resp))
+	}
+	return resp, nil
+}
+
+// signは、リクエストにAWS署名バージョン4の署名を付与します。
+// 署名するヘッダーは、Hostとx-amz-で始まるヘッダーのみです。
+//
+// args:
+//
+//	req          : 署名するリクエスト
+//	canonicalURI : URIエンコードしたリクエストのパス
+//	body         : リクエストの本文
+//	now          : 署名の日時
+func (c *objectStorageClient) sign(req *http.Request, canonicalURI string, body []byte, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{sigV4Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.creds.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, c.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, c.creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// readObjectStorageErrorは、成功以外のレスポンスからエラーの内容を読み込みます。
+func readObjectStorageError(resp *http.Response) *ObjectStorageStatusError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, objectStorageErrorBodyBytes))
+
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.Unmarshal(data, &body); err != nil || body.Code == "" {
+		return &ObjectStorageStatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return &ObjectStorageStatusError{StatusCode: resp.StatusCode, Code: body.Code, Message: body.Message}
+}
+
+// canonicalQueryは、クエリパラメータを署名の正規化の規則に従って、キーの順に並べてエンコードします。
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, awsURIEncode(key, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncodeは、署名の正規化の規則に従って文字列をURIエンコードします。
+// 英数字と「-_.~」以外のバイトをエンコードし、encodeSlashがfalseの場合は「/」をそのまま残します。
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sha256Hexは、データのSHA-256を16進数の文字列で返します。
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256は、鍵とデータからHMAC-SHA256を計算します。
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
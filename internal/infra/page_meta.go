@@ -0,0 +1,62 @@
+package infra
+
+import (
+	"strings"
+	"time"
+)
+
+// PageMetaは、ページのOpenGraphなどのメタタグから取り出した値です。メタタグがない項目はゼロ値になります。
+//
+// フィールド:
+//
+//	Title         : ページのタイトル（og:title、なければ<title>）
+//	URL           : ページの正規のURL（og:url、なければ<link rel="canonical">）
+//	PublishedTime : ページの公開日時（article:published_time）
+//	Description   : ページの説明（og:description、なければdescription）
+type PageMeta struct {
+	Title         string
+	URL           string
+	PublishedTime time.Time
+	Description   string
+}
+
+// ExtractPageMetaは、HTMLのメタタグから、多くのサイトに共通するページの情報を取り出します。
+// 求人情報のマークアップが乏しいサイトで、セレクターで値が得られなかった項目を補うために使用します。
+//
+// return:
+//
+//	PageMeta : 取り出したページの情報
+func (h *htmlDocument) ExtractPageMeta() PageMeta {
+	// metaContentは、指定された名前のメタタグのうち、最初の空でない値を返す
+	metaContent := func(names ...string) string {
+		for _, name := range names {
+			// OpenGraphはproperty属性、その他はname属性で指定されるが、サイトによって混在するため両方を確認する
+			selector := `meta[property="` + name + `"], meta[name="` + name + `"]`
+			if content := strings.TrimSpace(h.document.Find(selector).First().AttrOr("content", "")); content != "" {
+				return content
+			}
+		}
+		return ""
+	}
+
+	meta := PageMeta{
+		Title:       metaContent("og:title", "twitter:title"),
+		URL:         metaContent("og:url"),
+		Description: metaContent("og:description", "description", "twitter:description"),
+	}
+	if meta.Title == "" {
+		meta.Title = strings.TrimSpace(h.document.Find("title").First().Text())
+	}
+	if meta.URL == "" {
+		meta.URL = strings.TrimSpace(h.document.Find(`link[rel="canonical"]`).First().AttrOr("href", ""))
+	}
+	if published := metaContent("article:published_time", "og:published_time"); published != "" {
+		for _, format := range jsonLDDateFormats {
+			if t, err := time.Parse(format, published); err == nil {
+				meta.PublishedTime = t
+				break
+			}
+		}
+	}
+	return meta
+}
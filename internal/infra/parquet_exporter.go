@@ -0,0 +1,318 @@
+package infra
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// parquetMagicは、Parquetファイルの先頭と末尾に書き込むマジックナンバーです。
+const parquetMagic = "PAR1"
+
+// parquetRowGroupSizeは、1つの行グループにまとめる求人情報の件数です。
+// 行グループ単位でメモリに保持してから書き込むため、件数が多いほどメモリを使用します。
+const parquetRowGroupSize = 5000
+
+// Parquetの物理型・エンコーディングなどの列挙値（parquet.thrift）
+const (
+	parquetTypeInt64     int32 = 2
+	parquetTypeDouble    int32 = 5
+	parquetTypeByteArray int32 = 6
+
+	parquetRepetitionOptional int32 = 1
+	parquetConvertedUTF8      int32 = 0
+	parquetEncodingPlain      int32 = 0
+	parquetEncodingRLE        int32 = 3
+	parquetCodecUncompressed  int32 = 0
+	parquetPageTypeData       int32 = 0
+)
+
+// parquetColumnChunkは、書き込んだ列チャンクの位置と大きさです。フッターのメタデータに記録します。
+type parquetColumnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+// parquetRowGroupは、書き込んだ行グループの情報です。フッターのメタデータに記録します。
+type parquetRowGroup struct {
+	columns []parquetColumnChunk
+	numRows int64
+}
+
+// ParquetExporterは、求人情報をParquetファイルにエクスポートするFileExporterの実装です。
+// 列の型は列定義に従い、整数はINT64、数値はDOUBLE、それ以外はUTF8の文字列になります。
+// 複数の値を持つ列はCSVと同じく区切り文字（;）で連結した文字列として出力します。
+// 外部のライブラリに依存しないよう、圧縮せずにPLAINエンコーディングで書き込みます。
+// 書き込みは一時ファイルに対して行われ、Closeが成功した時点で出力先へアトミックにリネームされます。
+//
+// フィールド:
+//
+//	file      : 書き込み対象の一時ファイル
+//	writer    : バッファ付きの書き込み先
+//	fields    : 出力する列の定義
+//	rows      : 書き込み前の行グループの値（列ごとのCSVのセルと同じ文字列）
+//	offset    : ファイルの先頭から書き込んだバイト数
+//	rowGroups : 書き込んだ行グループ
+//	numRows   : 書き込んだ件数
+type ParquetExporter struct {
+	file      *atomicFile
+	writer    *bufio.Writer
+	fields    []ExportField
+	rows      [][]string
+	offset    int64
+	rowGroups []parquetRowGroup
+	numRows   int64
+}
+
+// NewParquetExporterは、ParquetExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	filePath    : 出力するParquetファイルのパス
+//	fields      : 出力する列の定義
+//	keepPartial : 失敗時に書き込み途中のファイルを.partialとして残すかどうか
+//
+// return:
+//
+//	*ParquetExporter : 生成されたParquetExporterのインスタンス
+//	error            : ディレクトリやファイルの作成に失敗した場合のエラー
+func NewParquetExporter(filePath string, fields []ExportField, keepPartial bool) (*ParquetExporter, error) {
+	file, err := createAtomicFile(filePath, keepPartial)
+	if err != nil {
+		return nil, fmt.Errorf("Parquetファイルの作成に失敗しました: %w", err)
+	}
+
+	e := &ParquetExporter{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		fields: fields,
+		rows:   make([][]string, len(fields)),
+	}
+	if err := e.write([]byte(parquetMagic)); err != nil {
+		file.Abort()
+		return nil, fmt.Errorf("Parquetファイルの書き込みに失敗しました: %w", err)
+	}
+	return e, nil
+}
+
+// Writeは、1件の求人情報を行グループに追加します。行グループの件数が上限に達した場合はファイルに書き込みます。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : 行グループの書き込みに失敗した場合のエラー
+func (e *ParquetExporter) Write(job model.JobPosting) error {
+	for i, field := range e.fields {
+		e.rows[i] = append(e.rows[i], field.Value(job))
+	}
+	if len(e.rows[0]) >= parquetRowGroupSize {
+		return e.flushRowGroup()
+	}
+	return nil
+}
+
+// Closeは、残りの行グループとフッターを書き込み、一時ファイルを出力先へリネームして確定します。
+//
+// return:
+//
+//	error : 書き込みやファイルの確定に失敗した場合のエラー
+func (e *ParquetExporter) Close() error {
+	if err := e.flushRowGroup(); err != nil {
+		e.file.Abort()
+		return err
+	}
+
+	footer := e.footer()
+	var trailer [4]byte
+	binary.LittleEndian.PutUint32(trailer[:], uint32(len(footer)))
+	for _, b := range [][]byte{footer, trailer[:], []byte(parquetMagic)} {
+		if err := e.write(b); err != nil {
+			e.file.Abort()
+			return fmt.Errorf("Parquetのフッターの書き込みに失敗しました: %w", err)
+		}
+	}
+
+	if err := e.writer.Flush(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("Parquetのフラッシュに失敗しました: %w", err)
+	}
+	return e.file.Commit()
+}
+
+// Abortは、バッファをフラッシュした上で出力を確定せずに破棄します。
+//
+// return:
+//
+//	error : 一時ファイルの後始末に失敗した場合のエラー
+func (e *ParquetExporter) Abort() error {
+	e.writer.Flush()
+	return e.file.Abort()
+}
+
+// writeは、ファイルに書き込み、書き込んだバイト数を記録します。
+func (e *ParquetExporter) write(b []byte) error {
+	n, err := e.writer.Write(b)
+	e.offset += int64(n)
+	return err
+}
+
+// flushRowGroupは、保持している行グループを列ごとに1ページの列チャンクとして書き込みます。
+func (e *ParquetExporter) flushRowGroup() error {
+	if len(e.fields) == 0 || len(e.rows[0]) == 0 {
+		return nil
+	}
+
+	numRows := int64(len(e.rows[0]))
+	group := parquetRowGroup{numRows: numRows}
+	for i, field := range e.fields {
+		page := encodeParquetPage(field, e.rows[i])
+		chunk := parquetColumnChunk{offset: e.offset, size: int64(len(page)), numValues: numRows}
+		if err := e.write(page); err != nil {
+			return fmt.Errorf("列 %s の書き込みに失敗しました: %w", field.Key, err)
+		}
+		group.columns = append(group.columns, chunk)
+		e.rows[i] = e.rows[i][:0]
+	}
+
+	e.rowGroups = append(e.rowGroups, group)
+	e.numRows += numRows
+	return nil
+}
+
+// parquetTypeは、列定義の型に対応するParquetの物理型を返します。
+func parquetType(field ExportField) int32 {
+	if field.Multi {
+		return parquetTypeByteArray
+	}
+	switch field.Type {
+	case FieldTypeInteger:
+		return parquetTypeInt64
+	case FieldTypeNumber:
+		return parquetTypeDouble
+	default:
+		return parquetTypeByteArray
+	}
+}
+
+// encodeParquetPageは、1列の値をページヘッダー付きのデータページに変換します。
+// 値がnullかどうかは定義レベルで表し、null以外の値のみをPLAINエンコーディングで書き込みます。
+func encodeParquetPage(field ExportField, values []string) []byte {
+	typ := parquetType(field)
+	levels := make([]byte, len(values))
+	var data bytes.Buffer
+	for i, raw := range values {
+		// JSON Linesと同じく、空の値はnullとして扱う（nullを許容しない文字列の列では空文字列のまま出力する）
+		if raw == "" && (field.Nullable || field.Type != FieldTypeString) {
+			continue
+		}
+		switch typ {
+		case parquetTypeInt64:
+			v, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				continue
+			}
+			binary.Write(&data, binary.LittleEndian, v)
+		case parquetTypeDouble:
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			binary.Write(&data, binary.LittleEndian, math.Float64bits(v))
+		default:
+			binary.Write(&data, binary.LittleEndian, uint32(len(raw)))
+			data.WriteString(raw)
+		}
+		levels[i] = 1
+	}
+
+	definitionLevels := encodeRLELevels(levels)
+	body := make([]byte, 0, 4+len(definitionLevels)+data.Len())
+	body = binary.LittleEndian.AppendUint32(body, uint32(len(definitionLevels)))
+	body = append(body, definitionLevels...)
+	body = append(body, data.Bytes()...)
+
+	header := newThriftWriter()
+	header.I32(1, parquetPageTypeData)
+	header.I32(2, int32(len(body)))
+	header.I32(3, int32(len(body)))
+	header.Struct(5, func() {
+		header.I32(1, int32(len(values)))
+		header.I32(2, parquetEncodingPlain)
+		header.I32(3, parquetEncodingRLE)
+		header.I32(4, parquetEncodingRLE)
+	})
+	header.End()
+
+	return append(header.Bytes(), body...)
+}
+
+// encodeRLELevelsは、定義レベル（0または1）をRLEとビットパッキングのハイブリッドエンコーディングのRLEの連続として書き込みます。
+func encodeRLELevels(levels []byte) []byte {
+	var out []byte
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		out = binary.AppendUvarint(out, uint64(end-start)<<1)
+		out = append(out, levels[start])
+		start = end
+	}
+	return out
+}
+
+// footerは、スキーマと行グループの情報をFileMetaDataとしてThriftのコンパクトプロトコルで書き込みます。
+func (e *ParquetExporter) footer() []byte {
+	w := newThriftWriter()
+	w.I32(1, 1)
+	w.StructList(2, len(e.fields)+1, func(i int) {
+		if i == 0 {
+			w.String(4, "schema")
+			w.I32(5, int32(len(e.fields)))
+			return
+		}
+		field := e.fields[i-1]
+		typ := parquetType(field)
+		w.I32(1, typ)
+		w.I32(3, parquetRepetitionOptional)
+		w.String(4, field.Key)
+		if typ == parquetTypeByteArray {
+			w.I32(6, parquetConvertedUTF8)
+		}
+	})
+	w.I64(3, e.numRows)
+	w.StructList(4, len(e.rowGroups), func(i int) {
+		group := e.rowGroups[i]
+		var totalSize int64
+		w.StructList(1, len(group.columns), func(j int) {
+			chunk := group.columns[j]
+			field := e.fields[j]
+			totalSize += chunk.size
+			w.I64(2, chunk.offset)
+			w.Struct(3, func() {
+				w.I32(1, parquetType(field))
+				w.I32List(2, parquetEncodingPlain, parquetEncodingRLE)
+				w.StringList(3, field.Key)
+				w.I32(4, parquetCodecUncompressed)
+				w.I64(5, chunk.numValues)
+				w.I64(6, chunk.size)
+				w.I64(7, chunk.size)
+				w.I64(9, chunk.offset)
+			})
+		})
+		w.I64(2, totalSize)
+		w.I64(3, group.numRows)
+	})
+	w.String(6, "go-crawler")
+	w.End()
+	return w.Bytes()
+}
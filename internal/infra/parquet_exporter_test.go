@@ -0,0 +1,253 @@
+package infra
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// thriftReaderは、テストでParquetのメタデータを読み戻すためのThriftのコンパクトプロトコルの最小限の読み込み器です。
+// thriftWriterが書き込む型（i32・i64・binary・list・struct）のみをサポートし、構造体はフィールドIDをキーとしたmapとして返します。
+type thriftReader struct {
+	buf *bytes.Reader
+}
+
+// readStructは、終端までの構造体のフィールドを読み込みます。
+func (r *thriftReader) readStruct() (map[int16]any, error) {
+	fields := make(map[int16]any)
+	var lastID int16
+	for {
+		header, err := r.buf.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return fields, nil
+		}
+		typ := header & 0x0F
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		value, err := r.readValue(typ)
+		if err != nil {
+			return nil, fmt.Errorf("フィールド %d: %w", id, err)
+		}
+		fields[id] = value
+		lastID = id
+	}
+}
+
+// readValueは、型に応じた1件の値を読み込みます。
+func (r *thriftReader) readValue(typ byte) (any, error) {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		size, err := binary.ReadUvarint(r.buf)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, size)
+		if _, err := r.buf.Read(b); err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case thriftList:
+		header, err := r.buf.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = binary.ReadUvarint(r.buf); err != nil {
+				return nil, err
+			}
+		}
+		values := make([]any, 0, size)
+		for i := uint64(0); i < size; i++ {
+			v, err := r.readValue(header & 0x0F)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		return nil, fmt.Errorf("未対応の型です: %d", typ)
+	}
+}
+
+// varintは、ジグザグエンコードされた可変長整数を読み込みます。
+func (r *thriftReader) varint() (int64, error) {
+	v, err := binary.ReadUvarint(r.buf)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+// parquetTestRowsは、テストで書き込む行です。タイトルに行番号を入れ、列の値を行番号から引きます。
+var parquetTestRows = [][]string{
+	{"株式会社テスト", "4000000", "1.5"},
+	{"", "", ""},
+	{"テスト合同会社", "-12", "0.25"},
+}
+
+// parquetTestFieldsは、文字列・整数・数値の列を1つずつ持つ列定義を返します。
+func parquetTestFields() []ExportField {
+	column := func(i int) func(model.JobPosting) string {
+		return func(j model.JobPosting) string {
+			row, _ := strconv.Atoi(j.Title())
+			return parquetTestRows[row][i]
+		}
+	}
+	return []ExportField{
+		{Key: "company_name", Type: FieldTypeString, Value: column(0)},
+		{Key: "salary_min", Type: FieldTypeInteger, Nullable: true, Value: column(1)},
+		{Key: "score", Type: FieldTypeNumber, Nullable: true, Value: column(2)},
+	}
+}
+
+// decodeParquetColumnは、列チャンクの1ページを読み込み、行ごとの値を返します（nullはnil）。
+func decodeParquetColumn(t *testing.T, content []byte, offset, size int64, typ int32, numRows int) []any {
+	t.Helper()
+
+	r := &thriftReader{buf: bytes.NewReader(content[offset : offset+size])}
+	header, err := r.readStruct()
+	if err != nil {
+		t.Fatalf("ページヘッダーを読み込めません: %v", err)
+	}
+	body := content[offset+size-header[2].(int64) : offset+size]
+	if got := header[5].(map[int16]any)[1].(int64); got != int64(numRows) {
+		t.Fatalf("ページの値の数 = %d, want %d", got, numRows)
+	}
+
+	levelsSize := binary.LittleEndian.Uint32(body)
+	levels := bytes.NewReader(body[4 : 4+levelsSize])
+	data := body[4+levelsSize:]
+	values := make([]any, 0, numRows)
+	for levels.Len() > 0 {
+		run, err := binary.ReadUvarint(levels)
+		if err != nil {
+			t.Fatalf("定義レベルを読み込めません: %v", err)
+		}
+		level, _ := levels.ReadByte()
+		for i := uint64(0); i < run>>1; i++ {
+			if level == 0 {
+				values = append(values, nil)
+				continue
+			}
+			switch typ {
+			case parquetTypeInt64:
+				values = append(values, int64(binary.LittleEndian.Uint64(data)))
+				data = data[8:]
+			case parquetTypeDouble:
+				values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(data)))
+				data = data[8:]
+			default:
+				n := binary.LittleEndian.Uint32(data)
+				values = append(values, string(data[4:4+n]))
+				data = data[4+n:]
+			}
+		}
+	}
+	if len(data) != 0 {
+		t.Fatalf("ページに読み残しがあります: %d バイト", len(data))
+	}
+	return values
+}
+
+func TestParquetExporterRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.parquet")
+	fields := parquetTestFields()
+	e, err := NewParquetExporter(path, fields, false)
+	if err != nil {
+		t.Fatalf("NewParquetExporter: %v", err)
+	}
+	for i := range parquetTestRows {
+		if err := e.Write(model.NewJobPosting(model.JobPostingArgs{Title: strconv.Itoa(i)})); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte(parquetMagic)) || !bytes.HasSuffix(content, []byte(parquetMagic)) {
+		t.Fatalf("先頭と末尾にマジックナンバーがありません")
+	}
+	footerSize := int(binary.LittleEndian.Uint32(content[len(content)-8:]))
+	footerStart := len(content) - 8 - footerSize
+	r := &thriftReader{buf: bytes.NewReader(content[footerStart : len(content)-8])}
+	meta, err := r.readStruct()
+	if err != nil {
+		t.Fatalf("フッターを読み込めません: %v", err)
+	}
+	if r.buf.Len() != 0 {
+		t.Fatalf("フッターに読み残しがあります: %d バイト", r.buf.Len())
+	}
+
+	if got := meta[3].(int64); got != int64(len(parquetTestRows)) {
+		t.Errorf("num_rows = %d, want %d", got, len(parquetTestRows))
+	}
+	schema := meta[2].([]any)
+	if len(schema) != len(fields)+1 {
+		t.Fatalf("スキーマの要素数 = %d, want %d", len(schema), len(fields)+1)
+	}
+	if got := schema[0].(map[int16]any)[5].(int64); got != int64(len(fields)) {
+		t.Errorf("ルートの子要素数 = %d, want %d", got, len(fields))
+	}
+	for i, field := range fields {
+		element := schema[i+1].(map[int16]any)
+		if element[4] != field.Key || element[1] != int64(parquetType(field)) {
+			t.Errorf("スキーマの要素 %d = %v, want %s（型 %d）", i+1, element, field.Key, parquetType(field))
+		}
+	}
+
+	want := [][]any{
+		{"株式会社テスト", "", "テスト合同会社"},
+		{int64(4000000), nil, int64(-12)},
+		{1.5, nil, 0.25},
+	}
+	rowGroups := meta[4].([]any)
+	if len(rowGroups) != 1 {
+		t.Fatalf("行グループの数 = %d, want 1", len(rowGroups))
+	}
+	columns := rowGroups[0].(map[int16]any)[1].([]any)
+	nextOffset := int64(len(parquetMagic))
+	for i, field := range fields {
+		chunk := columns[i].(map[int16]any)
+		chunkMeta := chunk[3].(map[int16]any)
+		offset, size := chunkMeta[9].(int64), chunkMeta[7].(int64)
+		if offset != nextOffset || chunk[2] != offset {
+			t.Errorf("列 %s の開始位置 = %d（file_offset %v）, want %d", field.Key, offset, chunk[2], nextOffset)
+		}
+		nextOffset = offset + size
+
+		got := decodeParquetColumn(t, content, offset, size, parquetType(field), len(parquetTestRows))
+		if fmt.Sprint(got) != fmt.Sprint(want[i]) {
+			t.Errorf("列 %s の値 = %v, want %v", field.Key, got, want[i])
+		}
+	}
+	if nextOffset != int64(footerStart) {
+		t.Errorf("最後の列チャンクの終端 = %d, want フッターの開始位置 %d", nextOffset, footerStart)
+	}
+}
@@ -0,0 +1,308 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// benefitFlagは、福利厚生の項目名と、キーワードに一致した場合にフラグを設定する関数です。
+type benefitFlag struct {
+	name string
+	set  func(b *model.BenefitsArgs)
+}
+
+// benefitFlagsは、辞書で指定できる福利厚生の項目です。
+var benefitFlags = []benefitFlag{
+	{"social_insurance", func(b *model.BenefitsArgs) { b.SocialInsurance = true }},
+	{"transport_allowance", func(b *model.BenefitsArgs) { b.TransportAllowance = true }},
+	{"housing_allowance", func(b *model.BenefitsArgs) { b.HousingAllowance = true }},
+	{"company_housing", func(b *model.BenefitsArgs) { b.CompanyHousing = true }},
+	{"rent_subsidy", func(b *model.BenefitsArgs) { b.RentSubsidy = true }},
+	{"meal_allowance", func(b *model.BenefitsArgs) { b.MealAllowance = true }},
+	{"cafeteria_provided", func(b *model.BenefitsArgs) { b.CafeteriaProvided = true }},
+	{"training_support", func(b *model.BenefitsArgs) { b.TrainingSupport = true }},
+	{"certification_support", func(b *model.BenefitsArgs) { b.CertificationSupport = true }},
+	{"paid_leave", func(b *model.BenefitsArgs) { b.PaidLeave = true }},
+	{"special_leave", func(b *model.BenefitsArgs) { b.SpecialLeave = true }},
+	{"flex_time", func(b *model.BenefitsArgs) { b.FlexTime = true }},
+	{"short_working_hours", func(b *model.BenefitsArgs) { b.ShortWorkingHours = true }},
+	{"childcare_support", func(b *model.BenefitsArgs) { b.ChildcareSupport = true }},
+	{"maternity_leave", func(b *model.BenefitsArgs) { b.MaternityLeave = true }},
+	{"parental_leave", func(b *model.BenefitsArgs) { b.ParentalLeave = true }},
+	{"elder_care_support", func(b *model.BenefitsArgs) { b.ElderCareSupport = true }},
+	{"retirement_plan", func(b *model.BenefitsArgs) { b.RetirementPlan = true }},
+}
+
+// keywordValueは、辞書で指定する分類の名前と、その分類に対応する値です。
+type keywordValue[T ~string] struct {
+	name  string
+	value T
+}
+
+// jobTypeValuesは、辞書で指定できる雇用形態の分類です。
+var jobTypeValues = []keywordValue[model.JobType]{
+	{"full_time", model.FullTime},
+	{"part_time", model.PartTime},
+	{"contract", model.Contract},
+	{"temporary", model.Temporary},
+	{"freelance", model.Freelance},
+	{"internship", model.Internship},
+	{"other", model.Other},
+}
+
+// holidayPolicyValuesは、辞書で指定できる休日制度の分類です。
+var holidayPolicyValues = []keywordValue[model.HolidayPolicy]{
+	{"complete_two_days_a_week", model.CompleteTwoDaysAWeek},
+	{"two_days_a_week", model.TwoDaysAWeek},
+	{"one_day_a_week", model.OneDayAWeek},
+	{"shift_system", model.ShiftSystem},
+}
+
+// workplaceTypeValuesは、辞書で指定できる勤務形態の分類です。
+var workplaceTypeValues = []keywordValue[model.WorkplaceType]{
+	{"onsite", model.Onsite},
+	{"remote", model.Remote},
+	{"hybrid", model.Hybrid},
+	{"full_remote", model.FullRemote},
+}
+
+// smokingPolicyValuesは、辞書で指定できる受動喫煙対策の分類です。
+var smokingPolicyValues = []keywordValue[model.SmokingPolicy]{
+	{"no_smoking_premises", model.NoSmokingPremises},
+	{"smoking_room", model.SmokingRoom},
+	{"no_smoking_indoors", model.NoSmokingIndoors},
+	{"smoking_allowed", model.SmokingAllowed},
+}
+
+// KeywordRuleは、1つの分類に該当すると判定するキーワードと、複数の分類のキーワードを含む場合の優先度です。
+//
+// フィールド:
+//
+//	Keywords : 分類に該当すると判定するキーワード（同義語）の一覧
+//	Priority : 優先度（大きいほど先に判定します）
+type KeywordRule struct {
+	Keywords []string `yaml:"keywords"`
+	Priority int      `yaml:"priority"`
+}
+
+// ParserDictionaryは、パーサーがキーワードで判定する項目の辞書です。
+// 辞書ファイルで同義語を追加・変更することで、再ビルドせずにサイトごとの表記の揺れに対応できます。
+//
+// フィールド:
+//
+//	Benefits      : 福利厚生の項目名と、その項目があると判定するキーワード（同義語）の一覧
+//	JobType       : 雇用形態の分類ごとのキーワードと優先度
+//	HolidayPolicy : 休日制度の分類ごとのキーワードと優先度
+//	WorkplaceType : 勤務形態の分類ごとのキーワードと優先度
+//	SmokingPolicy : 受動喫煙対策の分類ごとのキーワードと優先度
+type ParserDictionary struct {
+	Benefits      map[string][]string    `yaml:"benefits"`
+	JobType       map[string]KeywordRule `yaml:"job_type"`
+	HolidayPolicy map[string]KeywordRule `yaml:"holiday_policy"`
+	WorkplaceType map[string]KeywordRule `yaml:"workplace_type"`
+	SmokingPolicy map[string]KeywordRule `yaml:"smoking_policy"`
+}
+
+// DefaultParserDictionaryは、辞書ファイルを指定しない場合に使用する辞書を返します。
+func DefaultParserDictionary() ParserDictionary {
+	return ParserDictionary{
+		Benefits: map[string][]string{
+			"social_insurance":      {"社会保険完備"},
+			"transport_allowance":   {"交通費支給"},
+			"housing_allowance":     {"住宅手当"},
+			"company_housing":       {"社宅・寮"},
+			"rent_subsidy":          {"家賃補助"},
+			"meal_allowance":        {"食事手当"},
+			"cafeteria_provided":    {"社員食堂"},
+			"training_support":      {"研修制度"},
+			"certification_support": {"資格取得支援"},
+			"paid_leave":            {"有給休暇"},
+			"special_leave":         {"特別休暇"},
+			"flex_time":             {"フレックスタイム"},
+			"short_working_hours":   {"時短勤務"},
+			"childcare_support":     {"育児支援"},
+			"maternity_leave":       {"産前産後休暇"},
+			"parental_leave":        {"育児休暇"},
+			"elder_care_support":    {"介護支援"},
+			"retirement_plan":       {"退職金制度"},
+		},
+		JobType: map[string]KeywordRule{
+			"full_time":  {Keywords: []string{"正社員"}, Priority: 70},
+			"part_time":  {Keywords: []string{"アルバイト", "パート", "バイト"}, Priority: 60},
+			"contract":   {Keywords: []string{"契約社員"}, Priority: 50},
+			"temporary":  {Keywords: []string{"派遣社員"}, Priority: 40},
+			"freelance":  {Keywords: []string{"業務委託", "フリーランス"}, Priority: 30},
+			"internship": {Keywords: []string{"インターン"}, Priority: 20},
+			"other":      {Priority: 10},
+		},
+		HolidayPolicy: map[string]KeywordRule{
+			"complete_two_days_a_week": {Keywords: []string{"完全週休二日制"}, Priority: 40},
+			"two_days_a_week":          {Keywords: []string{"週休二日制"}, Priority: 30},
+			"one_day_a_week":           {Keywords: []string{"週休制"}, Priority: 20},
+			"shift_system":             {Keywords: []string{"シフト制"}, Priority: 10},
+		},
+		WorkplaceType: map[string]KeywordRule{
+			"onsite":      {Keywords: []string{"出社"}, Priority: 40},
+			"remote":      {Keywords: []string{"在宅", "リモート", "フルリモート"}, Priority: 30},
+			"hybrid":      {Keywords: []string{"ハイブリッド"}, Priority: 20},
+			"full_remote": {Priority: 10},
+		},
+		SmokingPolicy: map[string]KeywordRule{
+			"no_smoking_premises": {Keywords: []string{"敷地内禁煙", "敷地内全面禁煙"}, Priority: 40},
+			"smoking_room":        {Keywords: []string{"喫煙室あり", "喫煙室設置", "喫煙専用室", "喫煙ルーム", "分煙"}, Priority: 30},
+			"no_smoking_indoors":  {Keywords: []string{"屋内禁煙", "屋内原則禁煙", "屋内全面禁煙", "全面禁煙"}, Priority: 20},
+			"smoking_allowed":     {Keywords: []string{"喫煙可", "対策なし"}, Priority: 10},
+		},
+	}
+}
+
+// LoadParserDictionaryは、辞書ファイル（YAMLまたはJSON）を読み込み、既定の辞書に上書きした辞書を返します。
+// 辞書ファイルに記載した項目はキーワードの一覧を置き換え、記載しない項目は既定のキーワードを使用します。
+//
+// args:
+//
+//	path : 辞書ファイルのパス
+//
+// return:
+//
+//	ParserDictionary : 読み込んだ辞書
+//	error            : 読み込みや解析に失敗した場合、または存在しない項目が指定された場合のエラー
+func LoadParserDictionary(path string) (ParserDictionary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ParserDictionary{}, fmt.Errorf("辞書ファイルを読み込めませんでした: %w", err)
+	}
+
+	var loaded ParserDictionary
+	if err := yaml.UnmarshalWithOptions(data, &loaded, yaml.Strict()); err != nil {
+		return ParserDictionary{}, fmt.Errorf("辞書ファイルの解析に失敗しました: %w", err)
+	}
+
+	dictionary := DefaultParserDictionary()
+	var unknown []string
+	for name, keywords := range loaded.Benefits {
+		if !isBenefitFlag(name) {
+			unknown = append(unknown, "benefits."+name)
+			continue
+		}
+		dictionary.Benefits[name] = keywords
+	}
+	unknown = append(unknown, mergeKeywordRules(dictionary.JobType, loaded.JobType, jobTypeValues, "job_type")...)
+	unknown = append(unknown, mergeKeywordRules(dictionary.HolidayPolicy, loaded.HolidayPolicy, holidayPolicyValues, "holiday_policy")...)
+	unknown = append(unknown, mergeKeywordRules(dictionary.WorkplaceType, loaded.WorkplaceType, workplaceTypeValues, "workplace_type")...)
+	unknown = append(unknown, mergeKeywordRules(dictionary.SmokingPolicy, loaded.SmokingPolicy, smokingPolicyValues, "smoking_policy")...)
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return ParserDictionary{}, fmt.Errorf("辞書の項目が存在しません: %s", strings.Join(unknown, ", "))
+	}
+	return dictionary, nil
+}
+
+// mergeKeywordRulesは、辞書ファイルで指定された分類のキーワードと優先度を既定の辞書に上書きします。
+// キーワードを指定しない場合は既定のキーワードを、優先度を指定しない（0の）場合は既定の優先度を使用します。
+//
+// args:
+//
+//	rules   : 上書きする既定の辞書
+//	loaded  : 辞書ファイルで指定された分類
+//	values  : 指定できる分類
+//	section : エラーに表示する辞書の項目名
+//
+// return:
+//
+//	[]string : 存在しない分類の名前（"<section>.<name>"）
+func mergeKeywordRules[T ~string](rules, loaded map[string]KeywordRule, values []keywordValue[T], section string) []string {
+	var unknown []string
+	for name, rule := range loaded {
+		if !slices.ContainsFunc(values, func(v keywordValue[T]) bool { return v.name == name }) {
+			unknown = append(unknown, section+"."+name)
+			continue
+		}
+		merged := rules[name]
+		if rule.Keywords != nil {
+			merged.Keywords = rule.Keywords
+		}
+		if rule.Priority != 0 {
+			merged.Priority = rule.Priority
+		}
+		rules[name] = merged
+	}
+	return unknown
+}
+
+// keywordClassifierは、文字列に含まれるキーワードから、優先度の高い順に分類を判定します。
+type keywordClassifier[T ~string] []keywordClass[T]
+
+// keywordClassは、1つの分類の値と、正規化済みのキーワードと優先度です。
+type keywordClass[T ~string] struct {
+	value    T
+	keywords []string
+	priority int
+}
+
+// newKeywordClassifierは、辞書の分類ごとのキーワードから、優先度の高い順に判定するkeywordClassifierを生成します。
+// 優先度が同じ場合は、valuesの順に判定します。
+//
+// args:
+//
+//	values    : 指定できる分類
+//	rules     : 分類ごとのキーワードと優先度
+//	normalize : キーワードの正規化に使用する関数
+//
+// return:
+//
+//	keywordClassifier[T] : 生成された分類器
+func newKeywordClassifier[T ~string](values []keywordValue[T], rules map[string]KeywordRule, normalize func(string) string) keywordClassifier[T] {
+	classifier := make(keywordClassifier[T], 0, len(values))
+	for _, v := range values {
+		rule := rules[v.name]
+		class := keywordClass[T]{value: v.value, priority: rule.Priority}
+		for _, keyword := range rule.Keywords {
+			if keyword = normalize(keyword); keyword != "" {
+				class.keywords = append(class.keywords, keyword)
+			}
+		}
+		classifier = append(classifier, class)
+	}
+	sort.SliceStable(classifier, func(i, j int) bool {
+		return classifier[i].priority > classifier[j].priority
+	})
+	return classifier
+}
+
+// classifyは、正規化済みの文字列に含まれるキーワードから分類を判定します。
+//
+// args:
+//
+//	s        : 正規化済みの文字列
+//	fallback : いずれのキーワードも含まない場合の値
+//
+// return:
+//
+//	T : 判定した分類の値
+func (c keywordClassifier[T]) classify(s string, fallback T) T {
+	for _, class := range c {
+		for _, keyword := range class.keywords {
+			if strings.Contains(s, keyword) {
+				return class.value
+			}
+		}
+	}
+	return fallback
+}
+
+// isBenefitFlagは、辞書で指定できる福利厚生の項目名かどうかを判定します。
+func isBenefitFlag(name string) bool {
+	for _, flag := range benefitFlags {
+		if flag.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// unknownPartitionは、分割キーを決定できない求人情報の出力先に付与する名前です。
+const unknownPartition = "unknown"
+
+// prefectureSlugsは、出力ファイル名に使用する都道府県のローマ字表記です。
+var prefectureSlugs = map[model.PrefectureCode]string{
+	model.Hokkaido:  "hokkaido",
+	model.Aomori:    "aomori",
+	model.Iwate:     "iwate",
+	model.Miyagi:    "miyagi",
+	model.Akita:     "akita",
+	model.Yamagata:  "yamagata",
+	model.Fukushima: "fukushima",
+	model.Ibaraki:   "ibaraki",
+	model.Tochigi:   "tochigi",
+	model.Gunma:     "gunma",
+	model.Saitama:   "saitama",
+	model.Chiba:     "chiba",
+	model.Tokyo:     "tokyo",
+	model.Kanagawa:  "kanagawa",
+	model.Niigata:   "niigata",
+	model.Toyama:    "toyama",
+	model.Ishikawa:  "ishikawa",
+	model.Fukui:     "fukui",
+	model.Yamanashi: "yamanashi",
+	model.Nagano:    "nagano",
+	model.Gifu:      "gifu",
+	model.Shizuoka:  "shizuoka",
+	model.Aichi:     "aichi",
+	model.Mie:       "mie",
+	model.Shiga:     "shiga",
+	model.Kyoto:     "kyoto",
+	model.Osaka:     "osaka",
+	model.Hyogo:     "hyogo",
+	model.Nara:      "nara",
+	model.Wakayama:  "wakayama",
+	model.Tottori:   "tottori",
+	model.Shimane:   "shimane",
+	model.Okayama:   "okayama",
+	model.Hiroshima: "hiroshima",
+	model.Yamaguchi: "yamaguchi",
+	model.Tokushima: "tokushima",
+	model.Kagawa:    "kagawa",
+	model.Ehime:     "ehime",
+	model.Kochi:     "kochi",
+	model.Fukuoka:   "fukuoka",
+	model.Saga:      "saga",
+	model.Nagasaki:  "nagasaki",
+	model.Kumamoto:  "kumamoto",
+	model.Oita:      "oita",
+	model.Miyazaki:  "miyazaki",
+	model.Kagoshima: "kagoshima",
+	model.Okinawa:   "okinawa",
+}
+
+// PartitionKeyFuncは、求人情報から出力ファイルの分割キーを決定する関数です。
+type PartitionKeyFunc func(job model.JobPosting) string
+
+// NewPartitionKeyFuncは、分割の単位に応じたPartitionKeyFuncを生成します。
+//
+// args:
+//
+//	partition : 分割の単位
+//
+// return:
+//
+//	PartitionKeyFunc : 生成された関数
+//	error            : サポートされていない分割の単位が指定された場合のエラー
+func NewPartitionKeyFunc(partition config.OutputPartition) (PartitionKeyFunc, error) {
+	switch partition {
+	case config.PartitionPrefecture:
+		return prefecturePartitionKey, nil
+	case config.PartitionPostedMonth:
+		return postedMonthPartitionKey, nil
+	default:
+		return nil, fmt.Errorf("サポートされていない分割の単位です: %s", partition)
+	}
+}
+
+// prefecturePartitionKeyは、先頭の勤務地の都道府県コードとローマ字表記を分割キーとして返します（例: 13_tokyo）。
+func prefecturePartitionKey(job model.JobPosting) string {
+	locations := job.Locations()
+	if len(locations) == 0 {
+		return unknownPartition
+	}
+
+	code := locations[0].PrefectureCode()
+	slug, ok := prefectureSlugs[code]
+	if !ok {
+		return unknownPartition
+	}
+	return string(code) + "_" + slug
+}
+
+// postedMonthPartitionKeyは、投稿日の年月を分割キーとして返します（例: 2025-01）。
+func postedMonthPartitionKey(job model.JobPosting) string {
+	if job.PostedAt().IsZero() {
+		return unknownPartition
+	}
+	return job.PostedAt().Format("2006-01")
+}
+
+// partitionedExporterは、分割キーごとに別のファイルへ書き込むFileExporterの実装です。
+// 出力ファイルは分割キーが初めて現れた時点で作成され、ファイル名には分割キーが付与されます（例: job_postings_13_tokyo.csv）。
+//
+// フィールド:
+//
+//	path      : 分割前の出力ファイルのパス
+//	keyFunc   : 分割キーを決定する関数
+//	newInner  : 分割ごとの出力ファイルのパスからエクスポーターを生成する関数
+//	exporters : 分割キーごとのエクスポーター
+type partitionedExporter struct {
+	path      string
+	keyFunc   PartitionKeyFunc
+	newInner  func(path string) (FileExporter, error)
+	exporters map[string]FileExporter
+}
+
+// NewPartitionedExporterは、partitionedExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	path     : 分割前の出力ファイルのパス
+//	keyFunc  : 分割キーを決定する関数
+//	newInner : 分割ごとの出力ファイルのパスからエクスポーターを生成する関数
+//
+// return:
+//
+//	*partitionedExporter : 生成されたエクスポーター
+func NewPartitionedExporter(path string, keyFunc PartitionKeyFunc, newInner func(path string) (FileExporter, error)) *partitionedExporter {
+	return &partitionedExporter{
+		path:      path,
+		keyFunc:   keyFunc,
+		newInner:  newInner,
+		exporters: make(map[string]FileExporter),
+	}
+}
+
+// PartitionPathは、分割前の出力ファイルのパスに分割キーを付与したパスを返します。
+//
+// args:
+//
+//	path : 分割前の出力ファイルのパス
+//	key  : 分割キー
+//
+// return:
+//
+//	string : 分割キーを付与したパス
+func PartitionPath(path, key string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_" + key + ext
+}
+
+// Writeは、求人情報を分割キーに対応するファイルに書き込みます。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : エクスポーターの生成や書き込みに失敗した場合のエラー
+func (p *partitionedExporter) Write(job model.JobPosting) error {
+	key := p.keyFunc(job)
+
+	exporter, ok := p.exporters[key]
+	if !ok {
+		var err error
+		exporter, err = p.newInner(PartitionPath(p.path, key))
+		if err != nil {
+			return fmt.Errorf("分割 %s のエクスポーターの生成に失敗しました: %w", key, err)
+		}
+		p.exporters[key] = exporter
+	}
+
+	return exporter.Write(job)
+}
+
+// Flushは、すべての分割のエクスポーターのバッファを書き出します。
+//
+// return:
+//
+//	error : いずれかの分割の書き出しに失敗した場合のエラー
+func (p *partitionedExporter) Flush() error {
+	var errs []error
+	for key, exporter := range p.exporters {
+		if err := flushExporter(exporter); err != nil {
+			errs = append(errs, fmt.Errorf("分割 %s のフラッシュに失敗しました: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Closeは、すべての分割のエクスポーターをクローズし、出力を確定します。
+// 一部の分割で失敗した場合も、残りの分割のクローズを続けます。
+//
+// return:
+//
+//	error : いずれかの分割のクローズに失敗した場合のエラー
+func (p *partitionedExporter) Close() error {
+	var errs []error
+	for key, exporter := range p.exporters {
+		if err := exporter.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("分割 %s のクローズに失敗しました: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Abortは、すべての分割のエクスポーターを、出力を確定せずに破棄します。
+//
+// return:
+//
+//	error : いずれかの分割の破棄に失敗した場合のエラー
+func (p *partitionedExporter) Abort() error {
+	var errs []error
+	for key, exporter := range p.exporters {
+		if err := exporter.Abort(); err != nil {
+			errs = append(errs, fmt.Errorf("分割 %s の破棄に失敗しました: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
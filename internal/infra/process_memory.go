@@ -0,0 +1,94 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// childProcessRSSは、指定したプロセスの子孫のプロセスのメモリ使用量（RSS）の合計を返します。
+// Playwrightのドライバーと、ドライバーが起動したブラウザのプロセスはこのプロセスの子孫になるため、
+// 自身のプロセスIDを指定するとブラウザ全体のメモリ使用量が得られます。
+// /procを読み込むため、Linux以外ではエラーを返します。
+//
+// args:
+//
+//	root : 子孫のプロセスを集計する起点のプロセスID（起点のプロセス自体は含まない）
+//
+// return:
+//
+//	int64 : 子孫のプロセスのRSSの合計（バイト）
+//	error : /procを読み込めない場合のエラー
+func childProcessRSS(root int) (int64, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, fmt.Errorf("プロセスの一覧を取得できませんでした: %w", err)
+	}
+
+	children := make(map[int][]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// 走査中に終了したプロセスは読み飛ばす
+		ppid, ok := parentProcessID(pid)
+		if !ok {
+			continue
+		}
+		children[ppid] = append(children[ppid], pid)
+	}
+
+	var total int64
+	queue := children[root]
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = append(queue[1:], children[pid]...)
+		total += processRSS(pid)
+	}
+	return total, nil
+}
+
+// parentProcessIDは、/proc/<pid>/statから親のプロセスIDを読み込みます。
+// プロセス名に空白や括弧が含まれる場合があるため、最後の閉じ括弧より後の項目を使用します。
+func parentProcessID(pid int) (int, bool) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, false
+	}
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, false
+	}
+	// 閉じ括弧の後には、状態、親のプロセスIDの順に並ぶ
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return 0, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, false
+	}
+	return ppid, true
+}
+
+// processRSSは、/proc/<pid>/statmからプロセスのRSSをバイト単位で読み込みます。読み込めない場合は0を返します。
+func processRSS(pid int) int64 {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "statm"))
+	if err != nil {
+		return 0
+	}
+	// 総ページ数、常駐ページ数の順に並ぶ
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * int64(os.Getpagesize())
+}
@@ -0,0 +1,162 @@
+package infra
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ProgressReporterは、処理の進捗を報告するインターフェースです。
+type ProgressReporter interface {
+	// Startは、処理の対象の総数を設定し、進捗の報告を開始します。
+	Start(total int)
+	// Incrementは、処理済みの件数を1件増やします。複数のゴルーチンから同時に呼び出せます。
+	Increment()
+	// Finishは、進捗の報告を終了し、最終的な進捗を出力します。
+	Finish()
+}
+
+// NopProgressReporterは、進捗を報告しないProgressReporterの実装です。
+type NopProgressReporter struct{}
+
+func (NopProgressReporter) Start(total int) {}
+
+func (NopProgressReporter) Increment() {}
+
+func (NopProgressReporter) Finish() {}
+
+// progressBarWidthは、端末に表示するプログレスバーの幅（文字数）です。
+const progressBarWidth = 30
+
+// terminalProgressReporterは、処理済みの件数・処理速度・残り時間の目安を定期的に出力するProgressReporterの実装です。
+// 出力先が端末の場合は同じ行を書き換えるプログレスバーを、それ以外（ファイルやパイプ）の場合は1行ずつ出力します。
+//
+// フィールド:
+//
+//	w         : 進捗の出力先
+//	interval  : 進捗を出力する間隔
+//	isTerm    : 出力先が端末かどうか
+//	total     : 処理の対象の総数
+//	processed : 処理済みの件数
+//	startedAt : 処理を開始した日時
+//	done      : 報告の終了を通知するチャネル
+//	wg        : 出力を行うゴルーチンの終了を待つためのWaitGroup
+type terminalProgressReporter struct {
+	w         io.Writer
+	interval  time.Duration
+	isTerm    bool
+	total     int
+	processed atomic.Int64
+	startedAt time.Time
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewTerminalProgressReporterは、terminalProgressReporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	w        : 進捗の出力先（*os.Fileで端末を指す場合はプログレスバーを表示する）
+//	interval : 進捗を出力する間隔
+//
+// return:
+//
+//	*terminalProgressReporter : 生成されたレポーター
+func NewTerminalProgressReporter(w io.Writer, interval time.Duration) *terminalProgressReporter {
+	return &terminalProgressReporter{
+		w:        w,
+		interval: interval,
+		isTerm:   isTerminal(w),
+		done:     make(chan struct{}),
+	}
+}
+
+func (p *terminalProgressReporter) Start(total int) {
+	p.total = total
+	p.startedAt = time.Now()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.print()
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+func (p *terminalProgressReporter) Increment() {
+	p.processed.Add(1)
+}
+
+func (p *terminalProgressReporter) Finish() {
+	close(p.done)
+	p.wg.Wait()
+	p.print()
+	if p.isTerm {
+		// プログレスバーの行を確定し、以降の出力と重ならないようにする
+		fmt.Fprintln(p.w)
+	}
+}
+
+// printは、現在の進捗を出力します。
+func (p *terminalProgressReporter) print() {
+	line := p.render(int(p.processed.Load()), time.Since(p.startedAt))
+	if p.isTerm {
+		// 前回の出力が長い場合に残らないよう、行末まで消去する
+		fmt.Fprintf(p.w, "\r%s\033[K", line)
+		return
+	}
+	fmt.Fprintln(p.w, line)
+}
+
+// renderは、処理済みの件数と経過時間から、進捗を表す1行の文字列を生成します。
+func (p *terminalProgressReporter) render(processed int, elapsed time.Duration) string {
+	var b strings.Builder
+
+	ratio := 1.0
+	if p.total > 0 {
+		ratio = min(float64(processed)/float64(p.total), 1)
+	}
+	if p.isTerm {
+		filled := int(ratio * progressBarWidth)
+		fmt.Fprintf(&b, "[%s%s] ", strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled))
+	}
+	fmt.Fprintf(&b, "%d/%d (%.1f%%)", processed, p.total, ratio*100)
+
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(processed) / elapsed.Seconds()
+	}
+	fmt.Fprintf(&b, " %.1f件/秒", rate)
+
+	// 処理速度が分からない間は残り時間を表示しない
+	if remaining := p.total - processed; remaining > 0 && rate > 0 {
+		eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+		fmt.Fprintf(&b, " 残り約%s", eta.Round(time.Second))
+	}
+	return b.String()
+}
+
+// isTerminalは、出力先が端末かどうかを判定します。
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
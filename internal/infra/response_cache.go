@@ -0,0 +1,227 @@
+package infra
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// responseCacheKeyPrefixは、Redisに保存するキャッシュのキーのプレフィックスです。
+const responseCacheKeyPrefix = "response_cache:"
+
+// CachedResponseは、URLごとにキャッシュした詳細ページのレスポンスです。
+//
+// フィールド:
+//
+//	URL          : 取得したURL
+//	FinalURL     : リダイレクト後のURL
+//	Status       : HTTPステータスコード
+//	ETag         : レスポンスのETagヘッダー
+//	LastModified : レスポンスのLast-Modifiedヘッダー
+//	FetchedAt    : ページを取得した日時（変更がないことを確認した場合はその日時）
+//	HTML         : 取得したHTML
+type CachedResponse struct {
+	URL          string    `json:"url"`
+	FinalURL     string    `json:"final_url"`
+	Status       int       `json:"status"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	HTML         string    `json:"html"`
+}
+
+// ResponseCacheは、URLをキーとして詳細ページのレスポンスをキャッシュするインターフェースです。
+type ResponseCache interface {
+	// Getは、URLのキャッシュを返します。キャッシュが存在しない場合はfalseを返します。
+	Get(url string) (CachedResponse, bool, error)
+	// Putは、レスポンスをURLのキャッシュとして保存します。
+	Put(response CachedResponse) error
+}
+
+// responseCacheKeyは、URLからキャッシュのキーを生成します。URLにはファイル名に使用できない文字が含まれるため、ハッシュ値を使用します。
+func responseCacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// fileResponseCacheは、ローカルのディレクトリにURLごとのJSONファイルとしてキャッシュを保存するResponseCacheの実装です。
+//
+// フィールド:
+//
+//	dir : キャッシュを保存するディレクトリ
+type fileResponseCache struct {
+	dir string
+}
+
+// NewFileResponseCacheは、fileResponseCacheの新しいインスタンスを生成します。
+//
+// args:
+//
+//	dir : キャッシュを保存するディレクトリ
+//
+// return:
+//
+//	*fileResponseCache : 生成されたキャッシュ
+func NewFileResponseCache(dir string) *fileResponseCache {
+	return &fileResponseCache{dir: dir}
+}
+
+// Getは、URLのキャッシュファイルを読み込みます。
+func (c *fileResponseCache) Get(url string) (CachedResponse, bool, error) {
+	data, err := os.ReadFile(c.path(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return CachedResponse{}, false, nil
+	}
+	if err != nil {
+		return CachedResponse{}, false, fmt.Errorf("キャッシュの読み込みに失敗しました: %w", err)
+	}
+
+	var response CachedResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return CachedResponse{}, false, fmt.Errorf("キャッシュのデシリアライズに失敗しました: %w", err)
+	}
+	return response, true, nil
+}
+
+// Putは、レスポンスをURLのキャッシュファイルに書き込みます。書き込み途中のファイルを読み込まないよう、アトミックに置き換えます。
+func (c *fileResponseCache) Put(response CachedResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("キャッシュのシリアライズに失敗しました: %w", err)
+	}
+
+	file, err := createAtomicFile(c.path(response.URL), false)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Abort()
+		return fmt.Errorf("キャッシュの書き込みに失敗しました: %w", err)
+	}
+	return file.Commit()
+}
+
+// pathは、URLのキャッシュファイルのパスを返します。1つのディレクトリのファイル数が増えすぎないよう、キーの先頭2文字で分割します。
+func (c *fileResponseCache) path(url string) string {
+	key := responseCacheKey(url)
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// redisResponseCacheは、Redisにキャッシュを保存するResponseCacheの実装です。
+// 複数のマシンでクロールする場合に、キャッシュを共有できます。
+//
+// フィールド:
+//
+//	redis     : Redisクライアント
+//	namespace : キーの名前空間
+type redisResponseCache struct {
+	redis     *redis.Client
+	namespace string
+}
+
+// NewRedisResponseCacheは、redisResponseCacheの新しいインスタンスを生成します。
+//
+// args:
+//
+//	rds       : Redisクライアント
+//	namespace : キーの名前空間
+//
+// return:
+//
+//	*redisResponseCache : 生成されたキャッシュ
+func NewRedisResponseCache(rds *redis.Client, namespace string) *redisResponseCache {
+	return &redisResponseCache{redis: rds, namespace: namespace}
+}
+
+// Getは、URLのキャッシュをRedisから読み込みます。
+func (c *redisResponseCache) Get(url string) (CachedResponse, bool, error) {
+	data, err := c.redis.Get(context.Background(), c.key(url)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return CachedResponse{}, false, nil
+	}
+	if err != nil {
+		return CachedResponse{}, false, fmt.Errorf("キャッシュの読み込みに失敗しました: %w", err)
+	}
+
+	var response CachedResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return CachedResponse{}, false, fmt.Errorf("キャッシュのデシリアライズに失敗しました: %w", err)
+	}
+	return response, true, nil
+}
+
+// Putは、レスポンスをURLのキャッシュとしてRedisに保存します。
+func (c *redisResponseCache) Put(response CachedResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("キャッシュのシリアライズに失敗しました: %w", err)
+	}
+	if err := c.redis.Set(context.Background(), c.key(response.URL), data, 0).Err(); err != nil {
+		return fmt.Errorf("キャッシュの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// keyは、URLのキャッシュのキーを返します。
+func (c *redisResponseCache) key(url string) string {
+	return responseCacheKeyPrefix + c.namespace + ":" + responseCacheKey(url)
+}
+
+// encryptedResponseCacheは、ResponseCacheをラップし、キャッシュするHTMLをAES-GCMで暗号化するResponseCacheの実装です。
+// encrypt_htmlを有効にした場合に、キャッシュからページの内容が平文で読み取られないようにします。
+//
+// フィールド:
+//
+//	ResponseCache : ラップするキャッシュ
+//	aead          : 暗号化に使用するAES-GCM
+type encryptedResponseCache struct {
+	ResponseCache
+	aead cipher.AEAD
+}
+
+// NewEncryptedResponseCacheは、encryptedResponseCacheの新しいインスタンスを生成します。
+//
+// args:
+//
+//	cache : ラップするキャッシュ
+//	aead  : 暗号化に使用するAES-GCM
+//
+// return:
+//
+//	*encryptedResponseCache : 生成されたキャッシュ
+func NewEncryptedResponseCache(cache ResponseCache, aead cipher.AEAD) *encryptedResponseCache {
+	return &encryptedResponseCache{ResponseCache: cache, aead: aead}
+}
+
+// Getは、キャッシュを読み込み、HTMLを復号します。
+func (c *encryptedResponseCache) Get(url string) (CachedResponse, bool, error) {
+	response, ok, err := c.ResponseCache.Get(url)
+	if err != nil || !ok {
+		return response, ok, err
+	}
+	plain, err := openHTML(c.aead, response.HTML)
+	if err != nil {
+		return CachedResponse{}, false, fmt.Errorf("キャッシュの復号に失敗しました: %w", err)
+	}
+	response.HTML = string(plain)
+	return response, true, nil
+}
+
+// Putは、HTMLを暗号化してキャッシュに保存します。
+func (c *encryptedResponseCache) Put(response CachedResponse) error {
+	sealed, err := sealHTML(c.aead, []byte(response.HTML))
+	if err != nil {
+		return fmt.Errorf("キャッシュの暗号化に失敗しました: %w", err)
+	}
+	response.HTML = sealed
+	return c.ResponseCache.Put(response)
+}
@@ -0,0 +1,316 @@
+package infra
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// recordWriterは、JSONに変換済みの求人情報を書き込めるエクスポーターが実装するインターフェースです。
+// 退避した求人情報を出力先へ再送する際に使用します。
+type recordWriter interface {
+	WriteRecords(records []json.RawMessage) error
+}
+
+// pendingRecorderは、送信待ちの求人情報をバッファに保持するエクスポーターが実装するインターフェースです。
+// Abortで送信待ちの求人情報が破棄される前に退避する際に使用します。
+type pendingRecorder interface {
+	PendingRecords() []json.RawMessage
+}
+
+// spillReplayBatchSizeは、退避した求人情報を再送する際に1回で書き込む件数です。
+const spillReplayBatchSize = 100
+
+// retryingExporterは、書き込みに失敗した場合に一定回数まで再試行し、それでも失敗した求人情報をローカルのファイルに退避するFileExporterの実装です。
+// 出力先が一時的に停止していても、スクレイプした求人情報を失わずにスクレイプを続けられます。
+// 退避した求人情報は、Closeの時点で出力先が復旧していれば再送されます。再送できなかった分は次回の実行で再送を試みます。
+//
+// フィールド:
+//
+//	ctx         : 再試行の待機を中断するためのコンテキスト（中断された場合は再試行せずに退避します）
+//	inner       : ラップするエクスポーター
+//	fields      : 退避する求人情報をJSONに変換するための列の定義
+//	maxAttempts : 書き込みを試行する最大回数（初回を含む）
+//	backoff     : 最初の再試行までの待機時間（再試行のたびに2倍になります）
+//	spillPath   : 求人情報を退避するファイルのパス（空の場合は退避せずにエラーを返します）
+//	spilled     : この実行で退避した件数
+//	replayed    : 退避ファイルから再送できた件数
+type retryingExporter struct {
+	ctx         context.Context
+	inner       FileExporter
+	fields      []ExportField
+	maxAttempts int
+	backoff     time.Duration
+	spillPath   string
+	spilled     int
+	replayed    int
+}
+
+// NewRetryingExporterは、retryingExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	ctx         : 再試行の待機を中断するためのコンテキスト
+//	inner       : ラップするエクスポーター
+//	fields      : 退避する求人情報をJSONに変換するための列の定義
+//	maxAttempts : 書き込みを試行する最大回数（初回を含む）
+//	backoff     : 最初の再試行までの待機時間
+//	spillPath   : 求人情報を退避するファイルのパス（空の場合は退避しない）
+//
+// return:
+//
+//	*retryingExporter : 生成されたエクスポーター
+func NewRetryingExporter(ctx context.Context, inner FileExporter, fields []ExportField, maxAttempts int, backoff time.Duration, spillPath string) *retryingExporter {
+	return &retryingExporter{
+		ctx:         ctx,
+		inner:       inner,
+		fields:      fields,
+		maxAttempts: max(maxAttempts, 1),
+		backoff:     backoff,
+		spillPath:   spillPath,
+	}
+}
+
+// Spilledは、この実行で退避した求人情報の件数を返します。
+func (r *retryingExporter) Spilled() int {
+	return r.spilled
+}
+
+// Replayedは、退避ファイルから出力先へ再送できた求人情報の件数を返します。
+func (r *retryingExporter) Replayed() int {
+	return r.replayed
+}
+
+// SpillPathは、求人情報を退避するファイルのパスを返します。
+func (r *retryingExporter) SpillPath() string {
+	return r.spillPath
+}
+
+// Writeは、求人情報を書き込み、失敗した場合は再試行します。
+// 再試行しても失敗した場合は求人情報を退避し、エラーを返さずに処理を続けます。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : 退避先が設定されていないか、退避にも失敗した場合のエラー
+func (r *retryingExporter) Write(job model.JobPosting) error {
+	err := r.inner.Write(job)
+	if err == nil {
+		return nil
+	}
+
+	records, err := r.retry(err, func() error { return r.inner.Write(job) })
+	if err == nil {
+		return nil
+	}
+	if records == nil {
+		record, marshalErr := MarshalExportRecord(r.fields, job)
+		if marshalErr != nil {
+			return errors.Join(err, marshalErr)
+		}
+		records = []json.RawMessage{record}
+	}
+	return r.spill(records, err)
+}
+
+// Flushは、innerのバッファを書き出します。
+func (r *retryingExporter) Flush() error {
+	return flushExporter(r.inner)
+}
+
+// Closeは、innerをクローズし、送信できなかった求人情報と過去に退避した求人情報の再送を試みます。
+//
+// return:
+//
+//	error : innerのクローズや退避に失敗した場合のエラー
+func (r *retryingExporter) Close() error {
+	if err := r.inner.Close(); err != nil {
+		var undelivered *UndeliveredRecordsError
+		if !errors.As(err, &undelivered) {
+			return err
+		}
+		records, retryErr := r.retry(err, nil)
+		if retryErr != nil {
+			if err := r.spill(records, retryErr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.replaySpill()
+}
+
+// Abortは、innerを出力を確定せずに破棄します。退避済みの求人情報はそのまま残ります。
+// 退避先が設定されている場合は、innerの送信待ちの求人情報を失わないよう、破棄する前に退避します。
+//
+// return:
+//
+//	error : 送信待ちの求人情報の退避、またはinnerの破棄に失敗した場合のエラー
+func (r *retryingExporter) Abort() error {
+	var spillErr error
+	if pending, ok := r.inner.(pendingRecorder); ok && r.spillPath != "" {
+		if records := pending.PendingRecords(); len(records) > 0 {
+			spillErr = r.spill(records, errors.New("中断により送信待ちの求人情報を送信できませんでした"))
+		}
+	}
+	return errors.Join(spillErr, r.inner.Abort())
+}
+
+// retryは、待機時間を空けながら書き込みを再試行します。
+// 送信できなかった求人情報がエラーに含まれる場合は、それらをWriteRecordsで再送します。
+// 待機中にコンテキストがキャンセルされた場合は、再試行を打ち切って直前のエラーを返します。
+//
+// args:
+//
+//	err   : 初回の書き込みで発生したエラー
+//	write : 求人情報を書き込み直す処理（送信できなかった求人情報を再送する場合は使用しません）
+//
+// return:
+//
+//	[]json.RawMessage : エラーに含まれていた送信できなかった求人情報（含まれていない場合はnil）
+//	error             : 再試行しても失敗した場合の最後のエラー
+func (r *retryingExporter) retry(err error, write func() error) ([]json.RawMessage, error) {
+	var records []json.RawMessage
+	var undelivered *UndeliveredRecordsError
+	rw, canResend := r.inner.(recordWriter)
+	if errors.As(err, &undelivered) {
+		records = undelivered.Records
+		if !canResend {
+			return records, err
+		}
+		write = func() error { return rw.WriteRecords(records) }
+	}
+	if write == nil {
+		return records, err
+	}
+
+	wait := r.backoff
+	for attempt := 2; attempt <= r.maxAttempts; attempt++ {
+		if sleepContext(r.ctx, wait) != nil {
+			return records, err
+		}
+		wait *= 2
+
+		if err = write(); err == nil {
+			return records, nil
+		}
+	}
+	return records, err
+}
+
+// spillは、求人情報を退避ファイルに追記します。
+//
+// args:
+//
+//	records : 退避する求人情報のJSON
+//	cause   : 書き込みに失敗した原因
+//
+// return:
+//
+//	error : 退避先が設定されていないか、追記に失敗した場合のエラー
+func (r *retryingExporter) spill(records []json.RawMessage, cause error) error {
+	if r.spillPath == "" {
+		return cause
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.spillPath), os.ModePerm); err != nil {
+		return errors.Join(cause, fmt.Errorf("退避先のディレクトリの作成に失敗しました: %w", err))
+	}
+	file, err := os.OpenFile(r.spillPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("退避ファイルを開けませんでした: %w", err))
+	}
+	defer file.Close()
+
+	for _, record := range records {
+		if _, err := file.Write(append(record, '\n')); err != nil {
+			return errors.Join(cause, fmt.Errorf("退避ファイルへの書き込みに失敗しました: %w", err))
+		}
+	}
+
+	r.spilled += len(records)
+	return nil
+}
+
+// replaySpillは、退避ファイルの求人情報を出力先へ再送し、再送できた分を退避ファイルから取り除きます。
+// innerが再送に対応していない場合は何もしません。
+//
+// return:
+//
+//	error : 退避ファイルの読み込みや書き換えに失敗した場合のエラー
+func (r *retryingExporter) replaySpill() error {
+	rw, ok := r.inner.(recordWriter)
+	if r.spillPath == "" || !ok {
+		return nil
+	}
+
+	file, err := os.Open(r.spillPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("退避ファイルを開けませんでした: %w", err)
+	}
+
+	var remaining []json.RawMessage
+	batch := make([]json.RawMessage, 0, spillReplayBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// 一度失敗した場合は、残りを再送せずに退避ファイルへ残す
+		if len(remaining) > 0 || rw.WriteRecords(batch) != nil {
+			remaining = append(remaining, batch...)
+		} else {
+			r.replayed += len(batch)
+		}
+		batch = make([]json.RawMessage, 0, spillReplayBatchSize)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		batch = append(batch, json.RawMessage(append([]byte(nil), scanner.Bytes()...)))
+		if len(batch) == spillReplayBatchSize {
+			flush()
+		}
+	}
+	flush()
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("退避ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	if len(remaining) == 0 {
+		if err := os.Remove(r.spillPath); err != nil {
+			return fmt.Errorf("退避ファイルの削除に失敗しました: %w", err)
+		}
+		return nil
+	}
+
+	// 再送できなかった分だけを残すよう、退避ファイルを書き換える
+	spillFile, err := createAtomicFile(r.spillPath, false)
+	if err != nil {
+		return err
+	}
+	for _, record := range remaining {
+		if _, err := spillFile.Write(append(record, '\n')); err != nil {
+			spillFile.Abort()
+			return fmt.Errorf("退避ファイルの書き換えに失敗しました: %w", err)
+		}
+	}
+	return spillFile.Commit()
+}
@@ -0,0 +1,298 @@
+package infra
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// RunDiffReportは、同じサイトに対する2回の実行の出力を比較した結果です。
+//
+// フィールド:
+//
+//	Old       : 比較元（以前の実行）の出力
+//	New       : 比較先（新しい実行）の出力
+//	Added     : 新しい実行にのみ存在する求人情報
+//	Removed   : 以前の実行にのみ存在する求人情報
+//	Changed   : 両方に存在し、内容が変わった求人情報
+//	Unchanged : 両方に存在し、内容が変わらなかった求人情報の件数
+type RunDiffReport struct {
+	Old       RunDiffSource    `json:"old"`
+	New       RunDiffSource    `json:"new"`
+	Added     []DiffPosting    `json:"added"`
+	Removed   []DiffPosting    `json:"removed"`
+	Changed   []ChangedPosting `json:"changed"`
+	Unchanged int              `json:"unchanged"`
+}
+
+// RunDiffSourceは、比較した1回の実行の出力です。
+//
+// フィールド:
+//
+//	Files    : 読み込んだCSVファイル
+//	Postings : 求人情報の件数（正規化したURLで重複を除いた件数）
+type RunDiffSource struct {
+	Files    []string `json:"files"`
+	Postings int      `json:"postings"`
+}
+
+// DiffPostingは、比較結果に含まれる1件の求人情報です。
+//
+// フィールド:
+//
+//	URL         : 正規化した求人ページのURL
+//	Title       : 求人のタイトル
+//	CompanyName : 会社名
+//	ContentHash : 比較対象の列の値から計算したハッシュ値
+type DiffPosting struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	CompanyName string `json:"company_name"`
+	ContentHash string `json:"content_hash"`
+}
+
+// ChangedPostingは、内容が変わった1件の求人情報です。
+//
+// フィールド:
+//
+//	DiffPosting    : 新しい実行での求人情報
+//	OldContentHash : 以前の実行でのハッシュ値
+//	Changes        : 値が変わった列
+type ChangedPosting struct {
+	DiffPosting
+	OldContentHash string         `json:"old_content_hash"`
+	Changes        []ColumnChange `json:"changes"`
+}
+
+// ColumnChangeは、値が変わった1列です。
+//
+// フィールド:
+//
+//	Column : CSVのヘッダー
+//	Old    : 以前の実行での値
+//	New    : 新しい実行での値
+type ColumnChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// postingSnapshotは、1回の実行で出力された求人情報を、正規化したURLごとに保持します。
+//
+// フィールド:
+//
+//	files   : 読み込んだCSVファイル
+//	headers : CSVのヘッダー
+//	rows    : 正規化したURLごとの、ヘッダーから値へのマップ
+type postingSnapshot struct {
+	files   []string
+	headers []string
+	rows    map[string]map[string]string
+}
+
+// DiffPostingRunsは、同じサイトに対する2回の実行で出力されたCSVを比較し、追加・削除・変更された求人情報を返します。
+// 求人情報は正規化したURLで対応付け、ignoreに含まれない列の値のハッシュ値で変更を判定します。
+// 出力ファイルを分割している場合は、globパターンで複数のファイルをまとめて指定できます。
+//
+// args:
+//
+//	oldPattern : 以前の実行のCSVのパス（globパターン可）
+//	newPattern : 新しい実行のCSVのパス（globパターン可）
+//	fields     : 列定義（URL・タイトル・会社名の列の特定に使用）
+//	ignore     : 比較しない列のヘッダー（クロール日時など、実行ごとに変わる列）
+//
+// return:
+//
+//	RunDiffReport : 比較結果
+//	error         : CSVの読み込みに失敗した場合のエラー
+func DiffPostingRuns(oldPattern, newPattern string, fields []ExportField, ignore []string) (RunDiffReport, error) {
+	headerOf := func(key string) string {
+		for _, field := range fields {
+			if field.Key == key {
+				return field.Header
+			}
+		}
+		return key
+	}
+	urlHeader := headerOf("url")
+
+	oldSnapshot, err := loadPostingSnapshot(oldPattern, urlHeader)
+	if err != nil {
+		return RunDiffReport{}, fmt.Errorf("以前の実行の出力の読み込みに失敗しました: %w", err)
+	}
+	newSnapshot, err := loadPostingSnapshot(newPattern, urlHeader)
+	if err != nil {
+		return RunDiffReport{}, fmt.Errorf("新しい実行の出力の読み込みに失敗しました: %w", err)
+	}
+
+	// 列の追加や削除で差分が出ないよう、両方に存在する列のみを比較する
+	// URLは対応付けに使用するため比較しない（トラッキング用パラメータの有無などで変わるため）
+	var compared []string
+	for _, header := range newSnapshot.headers {
+		if header != urlHeader && slices.Contains(oldSnapshot.headers, header) && !slices.Contains(ignore, header) {
+			compared = append(compared, header)
+		}
+	}
+
+	posting := func(url string, row map[string]string) DiffPosting {
+		return DiffPosting{
+			URL:         url,
+			Title:       row[headerOf("title")],
+			CompanyName: row[headerOf("company_name")],
+			ContentHash: contentHash(row, compared),
+		}
+	}
+
+	report := RunDiffReport{
+		Old:     RunDiffSource{Files: oldSnapshot.files, Postings: len(oldSnapshot.rows)},
+		New:     RunDiffSource{Files: newSnapshot.files, Postings: len(newSnapshot.rows)},
+		Added:   []DiffPosting{},
+		Removed: []DiffPosting{},
+		Changed: []ChangedPosting{},
+	}
+
+	for _, url := range slices.Sorted(maps.Keys(newSnapshot.rows)) {
+		newRow := newSnapshot.rows[url]
+		oldRow, ok := oldSnapshot.rows[url]
+		if !ok {
+			report.Added = append(report.Added, posting(url, newRow))
+			continue
+		}
+
+		current := posting(url, newRow)
+		previousHash := contentHash(oldRow, compared)
+		if current.ContentHash == previousHash {
+			report.Unchanged++
+			continue
+		}
+
+		var changes []ColumnChange
+		for _, header := range compared {
+			if oldRow[header] != newRow[header] {
+				changes = append(changes, ColumnChange{Column: header, Old: oldRow[header], New: newRow[header]})
+			}
+		}
+		report.Changed = append(report.Changed, ChangedPosting{DiffPosting: current, OldContentHash: previousHash, Changes: changes})
+	}
+
+	for _, url := range slices.Sorted(maps.Keys(oldSnapshot.rows)) {
+		if _, ok := newSnapshot.rows[url]; !ok {
+			report.Removed = append(report.Removed, posting(url, oldSnapshot.rows[url]))
+		}
+	}
+
+	return report, nil
+}
+
+// loadPostingSnapshotは、globパターンに一致するCSVを読み込み、正規化したURLごとの求人情報を返します。
+// 同じURLの求人情報が複数ある場合は、後に読み込んだものを使用します。
+//
+// args:
+//
+//	pattern   : CSVのパス（globパターン可）
+//	urlHeader : URLの列のヘッダー
+//
+// return:
+//
+//	postingSnapshot : 読み込んだ求人情報
+//	error           : ファイルが存在しない場合や、CSVの読み込みに失敗した場合のエラー
+func loadPostingSnapshot(pattern, urlHeader string) (postingSnapshot, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return postingSnapshot{}, fmt.Errorf("パターン %s が不正です: %w", pattern, err)
+	}
+	if len(files) == 0 {
+		return postingSnapshot{}, fmt.Errorf("%s に一致するファイルがありません", pattern)
+	}
+
+	snapshot := postingSnapshot{files: files, rows: make(map[string]map[string]string)}
+	for _, file := range files {
+		headers, err := readPostingCSV(file, urlHeader, snapshot.rows)
+		if err != nil {
+			return postingSnapshot{}, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, header := range headers {
+			if !slices.Contains(snapshot.headers, header) {
+				snapshot.headers = append(snapshot.headers, header)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// readPostingCSVは、1つのCSVを読み込み、正規化したURLごとの行をrowsに追加します。
+//
+// args:
+//
+//	path      : CSVのパス
+//	urlHeader : URLの列のヘッダー
+//	rows      : 読み込んだ行を追加するマップ
+//
+// return:
+//
+//	[]string : CSVのヘッダー
+//	error    : CSVの読み込みに失敗した場合や、URLの列がない場合のエラー
+func readPostingCSV(path, urlHeader string, rows map[string]map[string]string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("ヘッダーの読み込みに失敗しました: %w", err)
+	}
+	urlIndex := slices.Index(headers, urlHeader)
+	if urlIndex < 0 {
+		return nil, fmt.Errorf("%s列がありません", urlHeader)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("行の読み込みに失敗しました: %w", err)
+		}
+
+		rawURL := record[urlIndex]
+		if rawURL == "" {
+			continue
+		}
+		url, err := model.CanonicalizeURL(rawURL)
+		if err != nil {
+			// 正規化できないURLはそのままキーにする
+			url = rawURL
+		}
+
+		row := make(map[string]string, len(headers))
+		for i, header := range headers {
+			row[header] = record[i]
+		}
+		rows[url] = row
+	}
+	return headers, nil
+}
+
+// contentHashは、指定した列の値から求人情報の内容のハッシュ値を計算します。
+func contentHash(row map[string]string, headers []string) string {
+	h := sha256.New()
+	for _, header := range headers {
+		// 値の区切りが曖昧にならないよう、CSVに現れにくい制御文字で区切る
+		io.WriteString(h, row[header])
+		h.Write([]byte{0x1f})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package infra
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFileNameBytesは、ファイル名の最大バイト数です。多くのファイルシステム（ext4、APFS、NTFS）の上限である255バイトに合わせます。
+const maxFileNameBytes = 255
+
+// windowsReservedNamesは、Windowsで拡張子に関係なくファイル名として使用できないデバイス名です。
+var windowsReservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// SafeFileNameは、ファイル名をWindows・macOS・Linuxのいずれでも保存できる名前に変換します。
+// URLから生成した名前など、予約文字やOSの上限を超える長さを含む可能性がある名前を保存する前に使用します。
+// 予約文字と制御文字は "_" に置き換え、Windowsのデバイス名には "_" を付与し、末尾のピリオドと空白を取り除きます。
+// 上限を超える長さの名前は、元の名前のハッシュ値を付与して切り詰めるため、長い名前どうしが衝突することはありません。
+// 変換が不要な名前はそのまま返します。
+//
+// args:
+//
+//	name : ファイル名（ディレクトリを含まない）
+//
+// return:
+//
+//	string : 保存できるファイル名
+func SafeFileName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			return '_'
+		case strings.ContainsRune(`<>:"/\|?*`, r):
+			return '_'
+		}
+		return r
+	}, name)
+	safe = strings.TrimRight(safe, ". ")
+	if safe == "" {
+		safe = "_"
+	}
+
+	ext := filepath.Ext(safe)
+	base := strings.TrimSuffix(safe, ext)
+	if _, ok := windowsReservedNames[strings.ToUpper(base)]; ok {
+		base += "_"
+	}
+
+	if len(base)+len(ext) > maxFileNameBytes {
+		sum := sha256.Sum256([]byte(name))
+		suffix := "_" + hex.EncodeToString(sum[:])[:16]
+		base = truncateUTF8(base, maxFileNameBytes-len(ext)-len(suffix)) + suffix
+	}
+	return base + ext
+}
+
+// truncateUTF8は、文字の途中で切れないように文字列をmaxBytesバイト以下に切り詰めます。
+func truncateUTF8(s string, maxBytes int) string {
+	if maxBytes <= 0 {
+		return ""
+	}
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
@@ -0,0 +1,49 @@
+package infra
+
+import "fmt"
+
+// FieldFillRateは、1つの項目について値が得られた求人情報の件数と割合です。
+//
+// フィールド:
+//
+//	Field  : 項目名（設定ファイルのセレクターの名前）
+//	Filled : 値が得られた求人情報の件数
+//	Rate   : 値が得られた求人情報の割合（0〜1）
+type FieldFillRate struct {
+	Field  string  `json:"field"`
+	Filled int     `json:"filled"`
+	Rate   float64 `json:"rate"`
+}
+
+// ScrapeStatsは、スクレイピングの1回の実行で出力した求人情報の項目ごとの充足率と、パースに失敗した件数です。
+// サイトごとのセレクターの品質を確認するために使用します。
+//
+// フィールド:
+//
+//	Written       : 集計の対象とした（出力した）求人情報の件数
+//	Incomplete    : required_fieldsの項目が欠けていたため出力しなかった求人情報の件数
+//	FillRates     : 項目ごとの充足率
+//	ParseFailures : 項目ごとのパースに失敗した件数（失敗がない項目は含みません）
+type ScrapeStats struct {
+	Written       int             `json:"written"`
+	Incomplete    int             `json:"incomplete"`
+	FillRates     []FieldFillRate `json:"fill_rates"`
+	ParseFailures map[string]int  `json:"parse_failures"`
+}
+
+// WriteScrapeStatsReportは、充足率の集計結果をJSONのレポートとして書き込みます。
+//
+// args:
+//
+//	path  : レポートの出力先のパス
+//	stats : 書き込む集計結果
+//
+// return:
+//
+//	error : レポートの書き込みに失敗した場合のエラー
+func WriteScrapeStatsReport(path string, stats ScrapeStats) error {
+	if err := writeJSONFile(path, stats); err != nil {
+		return fmt.Errorf("充足率のレポートの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
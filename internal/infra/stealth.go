@@ -0,0 +1,116 @@
+package infra
+
+import (
+	"maps"
+	"math/rand/v2"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/playwright-community/playwright-go"
+)
+
+// hideWebdriverScriptは、ページのスクリプトより先に実行され、自動操作の痕跡を隠すスクリプトです。
+// ヘッドレスChromiumで検知に使われやすいnavigator.webdriver、plugins、languages、window.chromeを補います。
+const hideWebdriverScript = `
+Object.defineProperty(navigator, 'webdriver', { get: () => undefined });
+Object.defineProperty(navigator, 'plugins', { get: () => [1, 2, 3, 4, 5] });
+if (!navigator.languages || navigator.languages.length === 0) {
+  Object.defineProperty(navigator, 'languages', { get: () => ['ja-JP', 'ja', 'en-US', 'en'] });
+}
+if (!window.chrome) {
+  window.chrome = { runtime: {} };
+}
+`
+
+// commonViewportsは、ランダムなビューポートの候補となる一般的な画面サイズです。
+var commonViewports = []playwright.Size{
+	{Width: 1920, Height: 1080},
+	{Width: 1536, Height: 864},
+	{Width: 1440, Height: 900},
+	{Width: 1366, Height: 768},
+	{Width: 1280, Height: 800},
+	{Width: 1280, Height: 720},
+}
+
+// applyStealthOptionsは、ステルス設定をブラウザコンテキストの作成オプションに反映します。
+//
+// args:
+//
+//	opts    : ブラウザコンテキストの作成オプション
+//	stealth : ステルス設定
+func applyStealthOptions(opts *playwright.BrowserNewContextOptions, stealth config.StealthConfig) {
+	if stealth.RandomViewport {
+		// 一般的な画面サイズに数ピクセルの揺らぎを加え、同一の指紋になりにくくする
+		base := commonViewports[rand.IntN(len(commonViewports))]
+		opts.Viewport = &playwright.Size{
+			Width:  base.Width - rand.IntN(16),
+			Height: base.Height - rand.IntN(16),
+		}
+	}
+	if stealth.Locale != "" {
+		opts.Locale = playwright.String(stealth.Locale)
+	}
+	if stealth.TimezoneID != "" {
+		opts.TimezoneId = playwright.String(stealth.TimezoneID)
+	}
+}
+
+// setupStealthScriptsは、設定に応じて自動操作の痕跡を隠す初期化スクリプトを登録します。
+//
+// args:
+//
+//	context : ブラウザコンテキスト
+//	stealth : ステルス設定
+//
+// return:
+//
+//	error : スクリプトの登録に失敗した場合のエラー
+func setupStealthScripts(context playwright.BrowserContext, stealth config.StealthConfig) error {
+	if !stealth.HideWebdriver {
+		return nil
+	}
+	return context.AddInitScript(playwright.Script{
+		Content: playwright.String(hideWebdriverScript),
+	})
+}
+
+// humanDelayは、人間の操作を模倣するため、設定された範囲でランダムな時間だけ待機します。
+//
+// args:
+//
+//	stealth : ステルス設定
+func humanDelay(stealth config.StealthConfig) {
+	if stealth.MaxDelayMillis <= 0 {
+		return
+	}
+	delay := stealth.MinDelayMillis
+	if span := stealth.MaxDelayMillis - stealth.MinDelayMillis; span > 0 {
+		delay += rand.IntN(span + 1)
+	}
+	time.Sleep(time.Duration(delay) * time.Millisecond)
+}
+
+// rotateUserAgentは、User-Agentのリストからランダムに1つ選び、以降のリクエストヘッダーに設定します。
+// リストが空の場合は何もしません。
+//
+// args:
+//
+//	page    : 対象のページ
+//	headers : 設定ファイルで指定されたカスタムヘッダー
+//	stealth : ステルス設定
+//
+// return:
+//
+//	error : ヘッダーの設定に失敗した場合のエラー
+func rotateUserAgent(page playwright.Page, headers map[string]string, stealth config.StealthConfig) error {
+	if len(stealth.UserAgents) == 0 {
+		return nil
+	}
+
+	merged := maps.Clone(headers)
+	if merged == nil {
+		merged = make(map[string]string, 1)
+	}
+	merged["User-Agent"] = stealth.UserAgents[rand.IntN(len(stealth.UserAgents))]
+	return page.SetExtraHTTPHeaders(merged)
+}
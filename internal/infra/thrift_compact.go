@@ -0,0 +1,140 @@
+package infra
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thriftのコンパクトプロトコルの型
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriterは、ParquetのメタデータをThriftのコンパクトプロトコルで書き込みます。
+// Parquetのファイルフッターとページヘッダーの書き込みに必要な型のみをサポートします。
+//
+// フィールド:
+//
+//	buf     : 書き込み先
+//	lastIDs : 構造体ごとの直前のフィールドID（フィールドIDの差分で書き込むため）
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+}
+
+// newThriftWriterは、最上位の構造体を書き込むthriftWriterを生成します。
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastIDs: []int16{0}}
+}
+
+// Bytesは、書き込んだ内容を返します。
+func (w *thriftWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// fieldHeaderは、フィールドIDと型を書き込みます。
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastIDs[len(w.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+// varintは、整数をジグザグエンコードした可変長整数として書き込みます。
+func (w *thriftWriter) varint(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+// uvarintは、符号なし整数を可変長整数として書き込みます。
+func (w *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+// I32は、32ビット整数のフィールドを書き込みます。
+func (w *thriftWriter) I32(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.varint(int64(v))
+}
+
+// I64は、64ビット整数のフィールドを書き込みます。
+func (w *thriftWriter) I64(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.varint(v)
+}
+
+// Stringは、文字列のフィールドを書き込みます。
+func (w *thriftWriter) String(id int16, v string) {
+	w.fieldHeader(id, thriftBinary)
+	w.stringValue(v)
+}
+
+// stringValueは、フィールドヘッダーなしで文字列を書き込みます（リストの要素用）。
+func (w *thriftWriter) stringValue(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+// I32Listは、32ビット整数のリストのフィールドを書き込みます。
+func (w *thriftWriter) I32List(id int16, values ...int32) {
+	w.listHeader(id, thriftI32, len(values))
+	for _, v := range values {
+		w.varint(int64(v))
+	}
+}
+
+// StringListは、文字列のリストのフィールドを書き込みます。
+func (w *thriftWriter) StringList(id int16, values ...string) {
+	w.listHeader(id, thriftBinary, len(values))
+	for _, v := range values {
+		w.stringValue(v)
+	}
+}
+
+// StructListは、構造体のリストのフィールドを書き込みます。要素ごとにwriteElementを呼び出します。
+func (w *thriftWriter) StructList(id int16, size int, writeElement func(i int)) {
+	w.listHeader(id, thriftStruct, size)
+	for i := 0; i < size; i++ {
+		w.lastIDs = append(w.lastIDs, 0)
+		writeElement(i)
+		w.stop()
+	}
+}
+
+// Structは、構造体のフィールドを書き込みます。writeFieldsで構造体のフィールドを書き込みます。
+func (w *thriftWriter) Struct(id int16, writeFields func()) {
+	w.fieldHeader(id, thriftStruct)
+	w.lastIDs = append(w.lastIDs, 0)
+	writeFields()
+	w.stop()
+}
+
+// Endは、最上位の構造体の終端を書き込みます。
+func (w *thriftWriter) End() {
+	w.buf.WriteByte(0)
+}
+
+// stopは、入れ子の構造体の終端を書き込みます。
+func (w *thriftWriter) stop() {
+	w.buf.WriteByte(0)
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+// listHeaderは、リストの要素の型と要素数を書き込みます。
+func (w *thriftWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xF0 | elemType)
+	w.uvarint(uint64(size))
+}
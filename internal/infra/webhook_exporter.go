@@ -0,0 +1,192 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+)
+
+// UndeliveredRecordsErrorは、リモートの出力先に送信できなかった求人情報を保持するエラーです。
+// 送信に失敗した求人情報は送信側のバッファから取り除かれるため、呼び出し側で再送や退避を行います。
+//
+// フィールド:
+//
+//	Records : 送信できなかった求人情報のJSON
+//	Err     : 送信に失敗した原因
+type UndeliveredRecordsError struct {
+	Records []json.RawMessage
+	Err     error
+}
+
+// Errorは、エラーメッセージを返します。
+func (e *UndeliveredRecordsError) Error() string {
+	return fmt.Sprintf("%d件の求人情報を送信できませんでした: %v", len(e.Records), e.Err)
+}
+
+// Unwrapは、送信に失敗した原因を返します。
+func (e *UndeliveredRecordsError) Unwrap() error {
+	return e.Err
+}
+
+// webhookExporterは、求人情報をHTTPのエンドポイントへまとめて送信するFileExporterの実装です。
+// 求人情報は1行に1件のJSON（application/x-ndjson）として、batchSize件ごとにPOSTされます。
+//
+// フィールド:
+//
+//	url       : 送信先のURL
+//	fields    : 出力する列の定義
+//	batchSize : 1回のリクエストで送信する件数
+//	timeout   : 1回のリクエストのタイムアウト
+//	buffer    : 送信待ちの求人情報
+type webhookExporter struct {
+	url       string
+	fields    []ExportField
+	batchSize int
+	timeout   time.Duration
+	buffer    []json.RawMessage
+}
+
+// NewWebhookExporterは、webhookExporterの新しいインスタンスを生成します。
+//
+// args:
+//
+//	url       : 送信先のURL
+//	fields    : 出力する列の定義
+//	batchSize : 1回のリクエストで送信する件数
+//	timeout   : 1回のリクエストのタイムアウト
+//
+// return:
+//
+//	*webhookExporter : 生成されたエクスポーター
+func NewWebhookExporter(url string, fields []ExportField, batchSize int, timeout time.Duration) *webhookExporter {
+	return &webhookExporter{
+		url:       url,
+		fields:    fields,
+		batchSize: batchSize,
+		timeout:   timeout,
+	}
+}
+
+// Writeは、求人情報を送信待ちに追加し、batchSize件に達した時点で送信します。
+//
+// args:
+//
+//	job : 書き込む対象のmodel.JobPosting
+//
+// return:
+//
+//	error : 送信に失敗した場合は*UndeliveredRecordsError
+func (w *webhookExporter) Write(job model.JobPosting) error {
+	record, err := MarshalExportRecord(w.fields, job)
+	if err != nil {
+		return err
+	}
+
+	w.buffer = append(w.buffer, record)
+	if len(w.buffer) < w.batchSize {
+		return nil
+	}
+	return w.flush()
+}
+
+// WriteRecordsは、JSONに変換済みの求人情報をすぐに送信します。
+//
+// args:
+//
+//	records : 送信するJSON
+//
+// return:
+//
+//	error : 送信に失敗した場合のエラー
+func (w *webhookExporter) WriteRecords(records []json.RawMessage) error {
+	for start := 0; start < len(records); start += w.batchSize {
+		end := min(start+w.batchSize, len(records))
+		if err := w.post(records[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Closeは、送信待ちの求人情報をすべて送信します。
+//
+// return:
+//
+//	error : 送信に失敗した場合は*UndeliveredRecordsError
+func (w *webhookExporter) Close() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	return w.flush()
+}
+
+// PendingRecordsは、まだ送信していない送信待ちの求人情報を返します。
+// Abortで破棄される前に、呼び出し側で退避するために使用します。
+func (w *webhookExporter) PendingRecords() []json.RawMessage {
+	return slices.Clone(w.buffer)
+}
+
+// Abortは、送信待ちの求人情報を送信せずに破棄します。
+func (w *webhookExporter) Abort() error {
+	w.buffer = nil
+	return nil
+}
+
+// flushは、送信待ちの求人情報を送信し、送信待ちを空にします。
+//
+// return:
+//
+//	error : 送信に失敗した場合は*UndeliveredRecordsError
+func (w *webhookExporter) flush() error {
+	records := w.buffer
+	w.buffer = nil
+	if err := w.post(records); err != nil {
+		return &UndeliveredRecordsError{Records: records, Err: err}
+	}
+	return nil
+}
+
+// postは、求人情報を1回のリクエストで送信します。
+//
+// args:
+//
+//	records : 送信するJSON
+//
+// return:
+//
+//	error : 送信に失敗したか、ステータスコードが2xx以外の場合のエラー
+func (w *webhookExporter) post(records []json.RawMessage) error {
+	var body bytes.Buffer
+	for _, record := range records {
+		body.Write(record)
+		body.WriteByte('\n')
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return fmt.Errorf("リクエストの作成に失敗しました: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("リクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("送信先がステータスコード %d を返しました", resp.StatusCode)
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
 	"net/url"
 	"path"
 	"regexp"
@@ -26,22 +30,58 @@ import (
 //
 // フィールド:
 //
-//	Cfg    : クローラーの設定情報
-//	Client : ブラウザクライアント
-//	Repo   : クロールジョブリポジトリ
-//	Logger : ロガー
+//	Cfg        : クローラーの設定情報
+//	Client     : ブラウザクライアント
+//	Storage    : 取得したHTMLの保存先
+//	Repo       : クロールジョブリポジトリ
+//	Control    : 操作指示のリポジトリ（nilの場合は操作指示を確認しない）
+//	Hook       : ジョブの完了時に呼び出すフック（nilの場合は呼び出さない）
+//	Logger     : ロガー
+//	ConfigHash : HTMLのメタデータに記録する設定のハッシュ値
+//	Expiry     : 掲載終了の判定器（nilの場合は判定しない）
+//	Blocks     : アクセスを拒否されたページの判定器（nilの場合は判定しない）
+//	Links      : 一覧ページのHTMLから正規表現で詳細ページのリンクを抽出する抽出器（nilの場合はセレクターで抽出する）
+//	PDFs       : 詳細ページのPDFの保存先（nilの場合は保存しない）
+//	Cache      : 詳細ページのレスポンスのキャッシュ（nilの場合はキャッシュしない）
+//	Hashes     : URLごとのページの内容のハッシュ値のストア（nilの場合は変更を判定しない）
+//	ListPages  : 一覧ページのHTMLの保存先（nilの場合は保存しない）
+//	Throttle   : エラーが増えた場合にジョブの間の待機時間を広げる制御（nilの場合は待機しない）
+//	Workers    : ワーカーの状態の報告先（nilの場合は報告しない）
+//	WorkerID   : 獲得したジョブと状態の報告に記録するワーカーのID
 type CrawlerArgs struct {
-	Cfg    *config.CrawlerConfig
-	Client infra.BrowserClient
-	Repo   repository.CrawlJobRepository
-	Logger logger.AppLogger
+	Cfg        *config.CrawlerConfig
+	Client     infra.BrowserClient
+	Storage    infra.HTMLWriter
+	Repo       repository.CrawlJobRepository
+	Control    repository.CrawlControlRepository
+	Hook       infra.Hook
+	Logger     logger.AppLogger
+	ConfigHash string
+	Expiry     *infra.ExpiryDetector
+	Blocks     *infra.BlockDetector
+	Links      *infra.DetailLinkPattern
+	PDFs       infra.PDFWriter
+	Cache      infra.ResponseCache
+	Hashes     infra.ContentHashStore
+	ListPages  infra.HTMLWriter
+	Throttle   *infra.AdaptiveThrottle
+	Workers    repository.CrawlWorkerRepository
+	WorkerID   string
 }
 
 type generateCrawlJobUseCase struct {
-	cfg    *config.CrawlerConfig
-	client infra.BrowserClient
-	repo   repository.CrawlJobRepository
-	logger logger.AppLogger
+	cfg        *config.CrawlerConfig
+	client     infra.BrowserClient
+	repo       repository.CrawlJobRepository
+	control    repository.CrawlControlRepository
+	logger     logger.AppLogger
+	listPages  infra.HTMLWriter
+	links      *infra.DetailLinkPattern
+	configHash string
+	startedAt  time.Time
+	jobs       *crawlJobBatch
+	workers    repository.CrawlWorkerRepository
+	workerID   string
 }
 
 // NewGenerateCrawlJobUseCaseはgenerateCrawlJobUseCaseのコンストラクタです。
@@ -55,16 +95,22 @@ type generateCrawlJobUseCase struct {
 //	*generateCrawlJobUseCase : 生成されたユースケースインスタンス
 func NewGenerateCrawlJobUseCase(args CrawlerArgs) *generateCrawlJobUseCase {
 	return &generateCrawlJobUseCase{
-		cfg:    args.Cfg,
-		client: args.Client,
-		repo:   args.Repo,
-		logger: args.Logger,
+		cfg:        args.Cfg,
+		client:     args.Client,
+		repo:       args.Repo,
+		control:    args.Control,
+		logger:     args.Logger,
+		listPages:  args.ListPages,
+		links:      args.Links,
+		configHash: args.ConfigHash,
+		jobs:       newCrawlJobBatch(args.Repo, args.Logger, crawlJobBatchSize),
+		workers:    args.Workers,
+		workerID:   args.WorkerID,
 	}
 }
 
 const (
 	maxListLinks = 100
-	batchSize    = 100
 )
 
 // GenerateCrawlJobは、クローラーのメイン実行ロジックです。
@@ -75,23 +121,43 @@ const (
 //
 // return:
 //
+//	int   : 作成したクロールジョブ数
 //	error : 実行中に発生したエラー
-func (u *generateCrawlJobUseCase) GenerateCrawlJob(ctx context.Context) error {
-	u.logger.Info("クローラーの実行を開始します", "baseURL", u.cfg.BaseURL, "strategy", u.cfg.Strategy)
+func (u *generateCrawlJobUseCase) GenerateCrawlJob(ctx context.Context) (int, error) {
+	u.logger.Info("クローラーの実行を開始します", "baseURL", u.cfg.BaseURL, "strategy", u.cfg.Strategy, "worker_id", u.workerID)
+	u.startedAt = time.Now()
+
+	heartbeat := startCrawlWorkerHeartbeat(ctx, u.workers, u.workerID, model.CrawlWorkerRoleGenerate, u.logger)
+	defer heartbeat.finish(ctx)
+
+	if u.draining(ctx) {
+		u.logger.Info("ドレインの指示を受け取っているため、ジョブを生成しません")
+		return 0, nil
+	}
 
 	// ベースURLに遷移
-	listLinks := u.listLinksByMode()
+	listLinks := u.listLinksByMode(ctx)
 
 	if len(listLinks) == 0 {
 		u.logger.Error("一覧ページのリンクが見つかりませんでした")
-		return fmt.Errorf("一覧ページのリンクが見つかりませんでした")
+		return 0, fmt.Errorf("一覧ページのリンクが見つかりませんでした")
 	}
 
 	// 一覧ページのリンクを抽出
 	u.logger.Info("一覧ページのリンクを見つけました", "count", len(listLinks))
 
 	// 一覧リンクの処理
+	createdJobs := 0
 	for i, link := range listLinks {
+		if ctx.Err() != nil {
+			u.logger.Warn("中断されたため、ジョブの生成を停止します", "processed", i, "total", len(listLinks))
+			break
+		}
+		if u.draining(ctx) {
+			u.logger.Info("ドレインの指示を受け取りました。ジョブの生成を停止します", "processed", i, "total", len(listLinks))
+			break
+		}
+
 		// BaseURLを基準にしてリンクを解決
 		resolvedLink, err := u.resolveURL(u.cfg.BaseURL, link)
 		if err != nil {
@@ -100,39 +166,72 @@ func (u *generateCrawlJobUseCase) GenerateCrawlJob(ctx context.Context) error {
 		}
 
 		u.logger.Info("一覧ページのリンクを処理中", "current", i+1, "total", len(listLinks), "link", resolvedLink)
-
-		if err := u.processListLink(ctx, resolvedLink); err != nil {
+		heartbeat.processing(resolvedLink)
+
+		jobCount, err := u.processListLink(ctx, resolvedLink)
+		createdJobs += jobCount
+		heartbeat.generated(createdJobs)
+		// 一覧ページごとに、バッファに残ったジョブを保存する。中断された場合も生成済みのジョブは保存する
+		if flushErr := u.jobs.flush(context.WithoutCancel(ctx)); flushErr != nil {
+			u.logger.Error("クロールジョブの保存に失敗しました", "link", resolvedLink, "error", flushErr)
+		}
+		if err != nil {
+			if errors.Is(err, infra.ErrQuotaExceeded) {
+				u.logger.Warn("クォータの上限に達したため、ジョブの生成を停止します", "link", resolvedLink, "error", err)
+				break
+			}
 			u.logger.Error("一覧ページのリンクの処理に失敗しました", "index", i+1, "link", resolvedLink, "error", err)
 			continue
 		}
 
-		time.Sleep(time.Duration(u.cfg.CrawlSleepSeconds) * time.Second)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(u.cfg.CrawlSleepSeconds) * time.Second):
+		}
 	}
 
-	u.logger.Info("クローラーの実行が完了しました", "count", len(listLinks))
-	return nil
+	u.logger.Info("クローラーの実行が完了しました", "count", len(listLinks), "created_jobs", createdJobs)
+	return createdJobs, nil
+}
+
+// drainingは、操作指示がDRAINかどうかを返します。
+// 操作指示を取得できない場合は、ジョブの生成を止めないようfalseを返します。
+func (u *generateCrawlJobUseCase) draining(ctx context.Context) bool {
+	if u.control == nil {
+		return false
+	}
+	command, err := u.control.Get(ctx)
+	if err != nil {
+		u.logger.Warn("操作指示の取得に失敗したため、ジョブの生成を続行します", "error", err)
+		return false
+	}
+	return command == model.CrawlControlDrain
 }
 
 // listLinksByModeは、設定モードに応じて一覧ページのリンクを取得します。
 //
+// args:
+//
+//	ctx : コンテキスト
+//
 // return:
 //
 //	[]string : 一覧ページのリンクリスト
-func (u *generateCrawlJobUseCase) listLinksByMode() []string {
+func (u *generateCrawlJobUseCase) listLinksByMode(ctx context.Context) []string {
 	listLinks := make([]string, 0, 100)
 
 	switch u.cfg.Mode {
 
 	case config.Manual:
-		listLinks = u.cfg.Urls
+		listLinks = u.cfg.ListURLs()
 
 	case config.Auto:
-		if err := u.client.Navigate(u.cfg.BaseURL); err != nil {
+		if err := u.client.Navigate(ctx, u.cfg.BaseURL); err != nil {
 			u.logger.Error("べースURLへのナビゲーションに失敗しました", "url", u.cfg.BaseURL, "error", err)
 			return listLinks
 		}
 
-		links, err := u.client.ExtractAttribute(u.cfg.Selector.ListLinksSelector, "href")
+		links, err := u.client.ExtractAttribute(ctx, u.cfg.Selector.ListLinksSelector, "href")
 		if err != nil {
 			u.logger.Error("一覧ページのリンクの抽出に失敗しました", "selector", u.cfg.Selector.ListLinksSelector, "error", err)
 			return listLinks
@@ -179,6 +278,7 @@ func (u *generateCrawlJobUseCase) resolveURL(baseURL, targetURL string) (string,
 }
 
 // processListLinkは、一覧ページのリンクを処理し、クロールジョブを作成します。
+// 検索フォームが設定されている場合は、フォームを送信して表示した一覧からクロールジョブを作成します。
 //
 // args:
 //
@@ -187,20 +287,26 @@ func (u *generateCrawlJobUseCase) resolveURL(baseURL, targetURL string) (string,
 //
 // return:
 //
+//	int   : 作成したジョブ数
 //	error : 処理中に発生したエラー
-func (u *generateCrawlJobUseCase) processListLink(ctx context.Context, link string) error {
-	if err := u.client.Navigate(link); err != nil {
-		return fmt.Errorf("ぺージネーションページ %s へのナビゲートに失敗しました: %w", link, err)
+func (u *generateCrawlJobUseCase) processListLink(ctx context.Context, link string) (int, error) {
+	if err := u.client.Navigate(ctx, link); err != nil {
+		return 0, fmt.Errorf("ぺージネーションページ %s へのナビゲートに失敗しました: %w", link, err)
+	}
+	if u.cfg.SearchForm != nil {
+		if err := u.client.FillAndSubmit(ctx, *u.cfg.SearchForm); err != nil {
+			return 0, fmt.Errorf("%s の検索フォームの送信に失敗しました: %w", link, err)
+		}
 	}
 
 	jobCount, err := u.createCrawlJobsByStrategy(ctx)
 	if err != nil {
-		return fmt.Errorf("%s のクロールジョブ作成に失敗しました: %w", link, err)
+		return jobCount, fmt.Errorf("%s のクロールジョブ作成に失敗しました: %w", link, err)
 	}
 
 	u.logger.Info("クロールジョブを作成しました", "count", jobCount)
 
-	return nil
+	return jobCount, nil
 }
 
 // createCrawlJobsByStrategyは、設定されたStrategyに基づいてクロールジョブを作成します。
@@ -222,6 +328,9 @@ func (u *generateCrawlJobUseCase) createCrawlJobsByStrategy(ctx context.Context)
 	case config.CrawlByTotalCount:
 		return u.createJobsByTotalCount(ctx)
 
+	case config.CrawlByInfiniteScroll:
+		return u.createJobsByInfiniteScroll(ctx)
+
 	default:
 		return 0, fmt.Errorf("サポートされていないStrategyです: %s", u.cfg.Strategy)
 	}
@@ -242,6 +351,10 @@ func (u *generateCrawlJobUseCase) createJobsByNextLink(ctx context.Context) (int
 	pageNum := 1
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return jobCount, fmt.Errorf("ページ%dの処理前に中断されました: %w", pageNum, err)
+		}
+
 		u.logger.Info("ページを処理中", "page", pageNum)
 
 		currentURL, err := u.client.CurrentURL()
@@ -250,67 +363,42 @@ func (u *generateCrawlJobUseCase) createJobsByNextLink(ctx context.Context) (int
 			return jobCount, fmt.Errorf("ページ%dで現在のURLの取得に失敗しました: %w", pageNum, err)
 		}
 
-		links, err := u.client.ExtractAttribute(u.cfg.Selector.DetailLinksSelector, "href")
+		links, err := u.extractDetailLinks(ctx)
 		if err != nil {
 			u.logger.Error("詳細ページのリンクの抽出に失敗しました", "page", pageNum, "error", err)
 			return jobCount, fmt.Errorf("ページ%dで詳細リンクの抽出に失敗しました: %w", pageNum, err)
 		}
 
 		u.logger.Info("詳細ページのリンクを抽出しました", "page", pageNum, "count", len(links))
+		u.archiveListPage(currentURL, pageNum)
 
-		var pageJobCount int32
-		// 求人詳細リンクの処理
-		eg, childCtx := errgroup.WithContext(ctx)
-		for _, link := range links {
-			targetLink := link
-
-			eg.Go(func() error {
-				select {
-
-				case <-childCtx.Done():
-					u.logger.Warn("コンテキストがキャンセルされたため、ジョブ作成を中断します。")
-					return childCtx.Err()
-
-				default:
-					// 現在のURLを基準にしてリンクを解決
-					var resolvedURL string
-					var err error
-
-					switch u.cfg.JobDetailResolveBaseURL {
+		// 間引く前の並び順を一覧ページでの掲載位置として記録する
+		positions := listingPositions(pageNum, links)
+		links = u.selectDetailLinks(links)
 
-					case "":
-						resolvedURL, err = u.resolveURL(currentURL.String(), targetLink)
-
-					default:
-						resolvedURL, err = u.resolveURL(u.cfg.JobDetailResolveBaseURL, targetLink)
-					}
-
-					if err != nil {
-						u.logger.Warn("URLの解決に失敗しました", "page", pageNum, "url", targetLink, "error", err)
-						return nil // エラーを返さずに続行
-					}
+		// ジョブ数の上限を超えないように、処理するリンクを残り件数までに絞る
+		if u.cfg.MaxJobs > 0 && jobCount+len(links) > u.cfg.MaxJobs {
+			links = links[:u.cfg.MaxJobs-jobCount]
+		}
 
-					u.logger.Info("求人詳細リンクが見つかりました", "url", resolvedURL)
+		pageJobCount, err := u.createJobsFromLinks(ctx, currentURL, pageNum, links, positions)
+		if err != nil {
+			return jobCount, err
+		}
 
-					if err := u.createCrawlJobByURL(ctx, resolvedURL); err != nil {
-						u.logger.Warn("クロールジョブの作成に失敗しました", "page", pageNum, "url", resolvedURL, "error", err)
-						return nil // エラーを返さずに続行
-					}
+		jobCount += pageJobCount
+		u.logger.Info("ジョブを作成しました", "page", pageNum, "count", pageJobCount)
 
-					atomic.AddInt32(&pageJobCount, 1)
-					return nil
-				}
-			})
+		if u.cfg.MaxJobs > 0 && jobCount >= u.cfg.MaxJobs {
+			u.logger.Info("ジョブ数の上限に達したため、ページネーションを停止します。", "page", pageNum, "jobs", jobCount, "max_jobs", u.cfg.MaxJobs)
+			return jobCount, nil
 		}
 
-		if err := eg.Wait(); err != nil {
-			u.logger.Error("並列処理中にエラーが発生しました", "error", err)
-			return int(jobCount), fmt.Errorf("ページ%dでの詳細リンク処理中にエラーが発生しました: %w", pageNum, err)
+		if u.cfg.MaxPages > 0 && pageNum >= u.cfg.MaxPages {
+			u.logger.Info("ページ数の上限に達したため、ページネーションを停止します。", "page", pageNum, "max_pages", u.cfg.MaxPages)
+			return jobCount, nil
 		}
 
-		jobCount += int(pageJobCount)
-		u.logger.Info("ジョブを作成しました", "page", pageNum, "count", pageJobCount)
-
 		// 次のページボタンが存在するか確認
 		exists, err := u.client.Exists(u.cfg.Selector.NextPageLocator)
 		if err != nil {
@@ -333,7 +421,31 @@ func (u *generateCrawlJobUseCase) createJobsByNextLink(ctx context.Context) (int
 	}
 }
 
-// createJobsByTotalCountは、総件数からページ数を計算し、ページネーションURLを構築してクロールジョブを作成します。
+// extractDetailLinksは、現在の一覧ページから詳細ページのリンクを抽出します。
+// detail_links_patternが指定されている場合はページのHTMLから正規表現で、指定されていない場合はCSSセレクターで抽出します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	[]string : 詳細ページのリンク
+//	error    : 抽出に失敗した場合のエラー
+func (u *generateCrawlJobUseCase) extractDetailLinks(ctx context.Context) ([]string, error) {
+	if u.links == nil {
+		return u.client.ExtractAttribute(ctx, u.cfg.Selector.DetailLinksSelector, "href")
+	}
+	html, err := u.client.GetHTML()
+	if err != nil {
+		return nil, err
+	}
+	return u.links.Extract(html), nil
+}
+
+// createJobsByInfiniteScrollは、一覧ページを繰り返しスクロールし、読み込まれた求人の詳細ページのリンクからクロールジョブを作成します。
+// 「次へ」ボタンも総件数もない一覧ページで使用します。新しい求人が見つからないスクロールが続いた場合、
+// スクロール回数またはジョブ数が上限に達した場合に停止します。
 //
 // args:
 //
@@ -343,26 +455,277 @@ func (u *generateCrawlJobUseCase) createJobsByNextLink(ctx context.Context) (int
 //
 //	int   : 作成したジョブ数
 //	error : エラー
-func (u *generateCrawlJobUseCase) createJobsByTotalCount(ctx context.Context) (int, error) {
-	texts, err := u.client.ExtractText(u.cfg.Selector.TotalCountSelector)
+func (u *generateCrawlJobUseCase) createJobsByInfiniteScroll(ctx context.Context) (int, error) {
+	scrollCfg := u.cfg.InfiniteScroll
+
+	currentURL, err := u.client.CurrentURL()
 	if err != nil {
-		return 0, fmt.Errorf("合計件数テキストの抽出に失敗しました: %w", err)
+		return 0, fmt.Errorf("現在のURLの取得に失敗しました: %w", err)
 	}
 
-	if len(texts) == 0 {
-		return 0, fmt.Errorf("合計件数テキストが見つかりませんでした")
+	// スクロールしても読み込み済みの求人は残るため、抽出済みのリンクを記録して新しいリンクのみを処理する
+	seen := make(map[string]bool)
+	jobCount := 0
+	idleScrolls := 0
+
+	for scroll := 0; ; scroll++ {
+		if err := ctx.Err(); err != nil {
+			return jobCount, fmt.Errorf("%d回目のスクロールの前に中断されました: %w", scroll, err)
+		}
+
+		links, err := u.extractDetailLinks(ctx)
+		if err != nil {
+			return jobCount, fmt.Errorf("%d回目のスクロールで詳細リンクの抽出に失敗しました: %w", scroll, err)
+		}
+
+		newLinks := make([]string, 0, len(links))
+		for _, link := range links {
+			if !seen[link] {
+				seen[link] = true
+				newLinks = append(newLinks, link)
+			}
+		}
+		u.logger.Info("詳細ページのリンクを抽出しました", "scroll", scroll, "count", len(links), "new", len(newLinks))
+
+		if len(newLinks) == 0 {
+			idleScrolls++
+		} else {
+			idleScrolls = 0
+		}
+
+		// 間引いた結果リンクが残らなくても、新しい求人は見つかっているためスクロールを続ける
+		selected := u.selectDetailLinks(newLinks)
+
+		// ジョブ数の上限を超えないように、処理するリンクを残り件数までに絞る
+		if u.cfg.MaxJobs > 0 && jobCount+len(selected) > u.cfg.MaxJobs {
+			selected = selected[:u.cfg.MaxJobs-jobCount]
+		}
+
+		if len(selected) > 0 {
+			// スクロールで読み込んだ求人は1つのページに続けて並ぶため、ページ全体での並び順を掲載位置とする
+			created, err := u.createJobsFromLinks(ctx, currentURL, scroll, selected, listingPositions(1, links))
+			jobCount += created
+			if err != nil {
+				return jobCount, err
+			}
+		}
+
+		// スクロールで読み込んだ求人はページに残るため、一覧ページのHTMLは停止する時点で1回だけ保存する
+		if u.cfg.MaxJobs > 0 && jobCount >= u.cfg.MaxJobs {
+			u.logger.Info("ジョブ数の上限に達したため、スクロールを停止します。", "scroll", scroll, "jobs", jobCount, "max_jobs", u.cfg.MaxJobs)
+			u.archiveListPage(currentURL, 1)
+			return jobCount, nil
+		}
+		if idleScrolls >= scrollCfg.MaxIdleScrollsOrDefault() {
+			u.logger.Info("新しい求人が見つからなくなったため、スクロールを停止します。", "scroll", scroll, "jobs", jobCount)
+			u.archiveListPage(currentURL, 1)
+			return jobCount, nil
+		}
+		if scrollCfg.MaxScrolls > 0 && scroll >= scrollCfg.MaxScrolls {
+			u.logger.Info("スクロール回数の上限に達したため、スクロールを停止します。", "scroll", scroll, "max_scrolls", scrollCfg.MaxScrolls)
+			u.archiveListPage(currentURL, 1)
+			return jobCount, nil
+		}
+
+		if err := u.client.ScrollToBottom(); err != nil {
+			return jobCount, fmt.Errorf("%d回目のスクロールに失敗しました: %w", scroll+1, err)
+		}
+
+		// 次の求人が読み込まれるまで待つ
+		select {
+		case <-ctx.Done():
+		case <-time.After(scrollCfg.Wait()):
+		}
 	}
+}
 
-	if len(texts) > 1 {
-		u.logger.Warn("合計件数セレクターに複数の要素がマッチしました。最初の要素を使用します。")
+// listPageFilePrefixは、一覧ページのHTMLのファイル名の先頭に付ける文字列です。
+const listPageFilePrefix = "list_"
+
+// archiveListPageは、ブラウザで表示している一覧ページのHTMLを、list_page_dirに保存します。
+// 詳細ページのリンクだけでは失われる掲載順位やバッジなど、一覧ページの情報を後から確認できるようにします。
+// ファイル名は "list_<実行の開始日時>_<一覧ページのURLのハッシュ値>_p<ページ番号>.html" です。
+// 保存に失敗してもジョブの生成は続けます。
+//
+// args:
+//
+//	pageURL : 一覧ページのURL
+//	pageNum : 一覧ページのページ番号（1始まり）
+func (u *generateCrawlJobUseCase) archiveListPage(pageURL *url.URL, pageNum int) {
+	if u.listPages == nil {
+		return
 	}
 
-	totalCount, err := u.extractTotalCount(texts[0])
+	html, err := u.client.GetHTML()
 	if err != nil {
-		return 0, fmt.Errorf("合計件数の抽出に失敗しました: %w", err)
+		u.logger.Warn("一覧ページのHTMLの取得に失敗しました", "page", pageNum, "url", pageURL.String(), "error", err)
+		return
 	}
 
-	u.logger.Info("総件数を抽出しました", "count", totalCount, "text", texts[0])
+	h := fnv.New32a()
+	h.Write([]byte(pageURL.String()))
+	filename := fmt.Sprintf("%s%s_%08x_p%03d.html", listPageFilePrefix, u.startedAt.Format("20060102T150405"), h.Sum32(), pageNum)
+	meta := infra.HTMLMetadata{
+		URL:        pageURL.String(),
+		FinalURL:   pageURL.String(),
+		Status:     u.client.LastStatus(),
+		FetchedAt:  time.Now(),
+		ConfigHash: u.configHash,
+		ListPage:   pageNum,
+	}
+	if err := u.listPages.SaveHTML(filename, html); err != nil {
+		u.logger.Warn("一覧ページのHTMLの保存に失敗しました", "page", pageNum, "url", pageURL.String(), "error", err)
+		return
+	}
+	if err := u.listPages.SaveHTMLMetadata(filename, meta); err != nil {
+		u.logger.Warn("一覧ページのメタデータの保存に失敗しました", "page", pageNum, "url", pageURL.String(), "error", err)
+	}
+}
+
+// selectDetailLinksは、sample_rateとmax_jobs_per_pageに従って、1ページ分の詳細ページのリンクを間引きます。
+// 市場調査などですべての求人を取得する必要がない場合に、クロールの時間と負荷を減らすために使用します。
+// sample_rateで一定の間隔のリンクのみを残したうえで、max_jobs_per_pageの件数までに絞ります。
+//
+// args:
+//
+//	links : 1ページ分の詳細ページのリンク
+//
+// return:
+//
+//	[]string : 間引いたリンク
+func (u *generateCrawlJobUseCase) selectDetailLinks(links []string) []string {
+	selected := links
+	if rate := u.cfg.SampleRate; rate > 0 && rate < 1 {
+		// 件数に対する割合が変わらないよう、累積した割合が整数を超えるたびに1件を残す（0.25の場合は4件ごとに1件）
+		selected = make([]string, 0, int(float64(len(links))*rate)+1)
+		for i, link := range links {
+			if math.Floor(float64(i+1)*rate) > math.Floor(float64(i)*rate) {
+				selected = append(selected, link)
+			}
+		}
+	}
+
+	if u.cfg.MaxJobsPerPage > 0 && len(selected) > u.cfg.MaxJobsPerPage {
+		selected = selected[:u.cfg.MaxJobsPerPage]
+	}
+
+	if len(selected) < len(links) {
+		u.logger.Info("詳細ページのリンクを間引きました", "count", len(links), "selected", len(selected), "sample_rate", u.cfg.SampleRate, "max_jobs_per_page", u.cfg.MaxJobsPerPage)
+	}
+	return selected
+}
+
+// listingPositionsは、一覧ページから抽出した詳細ページのリンクごとに、一覧ページでの掲載位置を返します。
+// 同じリンクが複数回現れる場合は、最初の位置を使用します。
+//
+// args:
+//
+//	pageNum : 一覧ページのページ番号
+//	links   : 一覧ページに並んでいる順の詳細ページのリンク
+//
+// return:
+//
+//	map[string]model.ListingPosition : リンクごとの掲載位置
+func listingPositions(pageNum int, links []string) map[string]model.ListingPosition {
+	positions := make(map[string]model.ListingPosition, len(links))
+	for i, link := range links {
+		if _, ok := positions[link]; !ok {
+			positions[link] = model.ListingPosition{Page: pageNum, Position: i + 1}
+		}
+	}
+	return positions
+}
+
+// createJobsFromLinksは、一覧ページから抽出した詳細ページのリンクを並列に解決し、クロールジョブを作成します。
+// 解決やジョブの作成に失敗したリンクはログに出力して読み飛ばします。
+//
+// args:
+//
+//	ctx        : コンテキスト
+//	currentURL : リンクを抽出した一覧ページのURL（相対パスの解決に使用）
+//	pageNum    : ログに出力するページ番号
+//	links      : 詳細ページのリンク
+//	positions  : リンクごとの一覧ページでの掲載位置
+//
+// return:
+//
+//	int   : 作成したジョブ数
+//	error : 中断された場合のエラー
+func (u *generateCrawlJobUseCase) createJobsFromLinks(ctx context.Context, currentURL *url.URL, pageNum int, links []string, positions map[string]model.ListingPosition) (int, error) {
+	var pageJobCount int32
+	// 求人詳細リンクの処理
+	eg, childCtx := errgroup.WithContext(ctx)
+	for _, link := range links {
+		targetLink := link
+
+		eg.Go(func() error {
+			select {
+
+			case <-childCtx.Done():
+				u.logger.Warn("コンテキストがキャンセルされたため、ジョブ作成を中断します。")
+				return childCtx.Err()
+
+			default:
+				// 現在のURLを基準にしてリンクを解決
+				var resolvedURL string
+				var err error
+
+				switch u.cfg.JobDetailResolveBaseURL {
+
+				case "":
+					resolvedURL, err = u.resolveURL(currentURL.String(), targetLink)
+
+				default:
+					resolvedURL, err = u.resolveURL(u.cfg.JobDetailResolveBaseURL, targetLink)
+				}
+
+				if err != nil {
+					u.logger.Warn("URLの解決に失敗しました", "page", pageNum, "url", targetLink, "error", err)
+					return nil // エラーを返さずに続行
+				}
+
+				u.logger.Info("求人詳細リンクが見つかりました", "url", resolvedURL)
+
+				if err := u.createCrawlJobByURL(ctx, resolvedURL, positions[targetLink]); err != nil {
+					u.logger.Warn("クロールジョブの作成に失敗しました", "page", pageNum, "url", resolvedURL, "error", err)
+					return nil // エラーを返さずに続行
+				}
+
+				atomic.AddInt32(&pageJobCount, 1)
+				return nil
+			}
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		u.logger.Error("並列処理中にエラーが発生しました", "error", err)
+		return int(pageJobCount), fmt.Errorf("ページ%dでの詳細リンク処理中にエラーが発生しました: %w", pageNum, err)
+	}
+
+	return int(pageJobCount), nil
+}
+
+// createJobsByTotalCountは、総件数からページ数を計算し、ページネーションURLを構築してクロールジョブを作成します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	int   : 作成したジョブ数
+//	error : エラー
+func (u *generateCrawlJobUseCase) createJobsByTotalCount(ctx context.Context) (int, error) {
+	var totalCount int
+	var err error
+	if u.cfg.TotalCountAPI != nil {
+		totalCount, err = u.fetchTotalCount(ctx)
+	} else {
+		totalCount, err = u.scrapeTotalCount(ctx)
+	}
+	if err != nil {
+		return 0, err
+	}
 
 	pageSize := u.cfg.Pagination.PerPage
 	if pageSize == 0 {
@@ -379,6 +742,10 @@ func (u *generateCrawlJobUseCase) createJobsByTotalCount(ctx context.Context) (i
 	baseURL := u.normalizeToPageOneURL(topListURL.String())
 	jobCount := 0
 	for page := u.cfg.Pagination.Start; page <= pageCount; page++ {
+		if err := ctx.Err(); err != nil {
+			return jobCount, fmt.Errorf("ページ%dの処理前に中断されました: %w", page, err)
+		}
+
 		pageURL, err := u.buildPaginatedURL(baseURL, page)
 		if err != nil {
 			u.logger.Error("ページネーションURL構築に失敗しました", "page", page, "baseURL", baseURL, "error", err)
@@ -391,7 +758,7 @@ func (u *generateCrawlJobUseCase) createJobsByTotalCount(ctx context.Context) (i
 			continue
 		}
 
-		if err := u.createCrawlJobByURL(ctx, resolvedURL); err != nil {
+		if err := u.createCrawlJobByURL(ctx, resolvedURL, model.ListingPosition{}); err != nil {
 			u.logger.Warn("クロールジョブ作成に失敗しました", "page", page, "url", resolvedURL, "error", err)
 			continue
 		}
@@ -400,6 +767,95 @@ func (u *generateCrawlJobUseCase) createJobsByTotalCount(ctx context.Context) (i
 	return jobCount, nil
 }
 
+// scrapeTotalCountは、一覧ページの総件数セレクターの要素から総件数を取得します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	int   : 総件数
+//	error : 要素が見つからない場合や数値を抽出できない場合のエラー
+func (u *generateCrawlJobUseCase) scrapeTotalCount(ctx context.Context) (int, error) {
+	texts, err := u.client.ExtractText(ctx, u.cfg.Selector.TotalCountSelector)
+	if err != nil {
+		return 0, fmt.Errorf("合計件数テキストの抽出に失敗しました: %w", err)
+	}
+
+	if len(texts) == 0 {
+		return 0, fmt.Errorf("合計件数テキストが見つかりませんでした")
+	}
+
+	if len(texts) > 1 {
+		u.logger.Warn("合計件数セレクターに複数の要素がマッチしました。最初の要素を使用します。")
+	}
+
+	totalCount, err := u.extractTotalCount(texts[0])
+	if err != nil {
+		return 0, fmt.Errorf("合計件数の抽出に失敗しました: %w", err)
+	}
+
+	u.logger.Info("総件数を抽出しました", "count", totalCount, "text", texts[0])
+	return totalCount, nil
+}
+
+// fetchTotalCountは、total_count_apiで指定したJSONのAPIを呼び出し、レスポンスから総件数を取得します。
+// URLの {query} は現在の一覧ページのクエリ文字列に置き換えるため、検索条件ごとの一覧ページで同じ設定を使用できます。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	int   : 総件数
+//	error : APIの呼び出しに失敗した場合や、レスポンスから総件数を取り出せない場合のエラー
+func (u *generateCrawlJobUseCase) fetchTotalCount(ctx context.Context) (int, error) {
+	apiCfg := u.cfg.TotalCountAPI
+
+	listURL, err := u.client.CurrentURL()
+	if err != nil {
+		return 0, fmt.Errorf("現在のURLの取得に失敗しました: %w", err)
+	}
+	rawURL := strings.ReplaceAll(apiCfg.URL, "{query}", listURL.RawQuery)
+	apiURL, err := u.resolveURL(listURL.String(), rawURL)
+	if err != nil {
+		return 0, fmt.Errorf("総件数を取得するAPIのURLの解決に失敗しました: %w", err)
+	}
+
+	body, err := u.client.Fetch(ctx, apiURL)
+	if err != nil {
+		return 0, fmt.Errorf("総件数を取得するAPIの呼び出しに失敗しました: %s: %w", apiURL, err)
+	}
+
+	value, err := infra.LookupJSONPath(body, apiCfg.JSONPath)
+	if err != nil {
+		return 0, fmt.Errorf("APIのレスポンスから総件数を取り出せませんでした: %w", err)
+	}
+
+	var totalCount int
+	switch v := value.(type) {
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("総件数 %s は整数ではありません", v)
+		}
+		totalCount = int(n)
+	case string:
+		// "1,234件" のような文字列で返すAPIもあるため、表示テキストと同じ方法で数値を抽出する
+		totalCount, err = u.extractTotalCount(v)
+		if err != nil {
+			return 0, fmt.Errorf("合計件数の抽出に失敗しました: %w", err)
+		}
+	default:
+		return 0, fmt.Errorf("%s の値は数値ではありません: %v", apiCfg.JSONPath, value)
+	}
+
+	u.logger.Info("APIから総件数を取得しました", "count", totalCount, "url", apiURL)
+	return totalCount, nil
+}
+
 // extractTotalCountは、テキストから合計件数を表す数値を正規表現で抽出し、カンマを除去して返します。
 //
 // args:
@@ -429,33 +885,32 @@ func (u *generateCrawlJobUseCase) extractTotalCount(text string) (int, error) {
 	return totalCount, nil
 }
 
-// createCrawlJobByURLは、指定されたURLからCrawlJobを作成し、リポジトリに保存します。
+// createCrawlJobByURLは、指定されたURLからCrawlJobを作成し、まとめて保存するバッファに追加します。
+// 既に存在するURLのジョブは、保存する時点で読み飛ばされます。
 //
 // args:
 //
-//	ctx  : コンテキスト
-//	link : クロール対象のURL
+//	ctx     : コンテキスト
+//	link    : クロール対象のURL
+//	listing : リンクが見つかった一覧ページでの掲載位置（不明な場合はゼロ値）
 //
 // return:
 //
-//	error : 保存や存在確認で発生したエラー
-func (u *generateCrawlJobUseCase) createCrawlJobByURL(ctx context.Context, rawURL string) error {
-	job, err := model.NewCrawlJob(rawURL)
+//	error : ジョブの作成や保存で発生したエラー
+func (u *generateCrawlJobUseCase) createCrawlJobByURL(ctx context.Context, rawURL string, listing model.ListingPosition) error {
+	// 経路の違いによるクエリ文字列の差異を除去し、同じ求人を重複して登録しないようにする
+	canonicalURL, err := model.CanonicalizeURL(rawURL)
 	if err != nil {
-		return fmt.Errorf("クロールジョブの作成に失敗しました: %w", err)
+		return fmt.Errorf("URLの正規化に失敗しました: %w", err)
 	}
 
-	isExist, err := u.repo.Exists(ctx, job)
+	job, err := model.NewCrawlJob(canonicalURL)
 	if err != nil {
-		return fmt.Errorf("クロールジョブの存在確認に失敗しました: %w", err)
-	}
-
-	if isExist {
-		u.logger.Info("既に存在するURLのためスキップします", "url", rawURL)
-		return nil
+		return fmt.Errorf("クロールジョブの作成に失敗しました: %w", err)
 	}
+	job = job.WithListingPosition(listing)
 
-	if err := u.repo.Save(ctx, job); err != nil {
+	if err := u.jobs.add(ctx, job); err != nil {
 		return fmt.Errorf("クロールジョブの保存に失敗しました: %w", err)
 	}
 
@@ -565,10 +1020,22 @@ func (u *generateCrawlJobUseCase) normalizeToPageOneURL(rawURL string) string {
 
 // CrawlJobExecutorUseCaseは、RedisからCrawlJobを消費し、ブラウザで実行するユースケースです。
 type executeCrawlJobUseCase struct {
-	cfg    *config.CrawlerConfig
-	client infra.BrowserClient
-	repo   repository.CrawlJobRepository
-	logger logger.AppLogger
+	cfg        *config.CrawlerConfig
+	client     infra.BrowserClient
+	storage    infra.HTMLWriter
+	repo       repository.CrawlJobRepository
+	control    repository.CrawlControlRepository
+	hook       infra.Hook
+	logger     logger.AppLogger
+	configHash string
+	expiry     *infra.ExpiryDetector
+	blocks     *infra.BlockDetector
+	cache      infra.ResponseCache
+	hashes     infra.ContentHashStore
+	pdfs       infra.PDFWriter
+	throttle   *infra.AdaptiveThrottle
+	workers    repository.CrawlWorkerRepository
+	workerID   string
 }
 
 // NewExecuteCrawlJobUseCaseは、executeCrawlJobUseCaseの新しいインスタンスを作成します。
@@ -582,10 +1049,22 @@ type executeCrawlJobUseCase struct {
 //	*executeCrawlJobUseCase : 生成されたユースケースインスタンス
 func NewExecuteCrawlJobUseCase(args CrawlerArgs) *executeCrawlJobUseCase {
 	return &executeCrawlJobUseCase{
-		cfg:    args.Cfg,
-		client: args.Client,
-		repo:   args.Repo,
-		logger: args.Logger,
+		cfg:        args.Cfg,
+		client:     args.Client,
+		storage:    args.Storage,
+		repo:       args.Repo,
+		control:    args.Control,
+		hook:       args.Hook,
+		logger:     args.Logger,
+		configHash: args.ConfigHash,
+		expiry:     args.Expiry,
+		blocks:     args.Blocks,
+		cache:      args.Cache,
+		hashes:     args.Hashes,
+		pdfs:       args.PDFs,
+		throttle:   args.Throttle,
+		workers:    args.Workers,
+		workerID:   args.WorkerID,
 	}
 }
 
@@ -593,8 +1072,44 @@ var (
 	ErrNoPendingJobs = errors.New("pending job not found")
 )
 
+// errPageGoneは、ページが存在しないことを示すステータスコード（404・410）が返されたことを示すエラーです。
+// 次回の実行で再試行しても取得できないため、ジョブをPENDINGに戻さずFAILEDにします。
+var errPageGone = errors.New("ページが存在しません")
+
+// errServerErrorは、retry_countに従って再試行した後もサーバーエラー（5xx）が返されたことを示すエラーです。
+// PENDINGに戻すと次回以降の実行で際限なく再試行されるため、ジョブをFAILEDにします。
+var errServerError = errors.New("サーバーエラーが返されました")
+
+// errPageBlockedは、ボット検知のチャレンジページやCAPTCHAなど、アクセスを拒否されたページが返されたことを示すエラーです。
+// ジョブをPENDINGに戻さずBLOCKEDにし、blocked.actionに従って実行を続けるかを決めます。
+var errPageBlocked = errors.New("アクセスを拒否されたページが返されました")
+
+// CrawlExecuteResultは、クロールジョブの1回の実行結果です。
+//
+// フィールド:
+//
+//	Success   : 成功したジョブ数
+//	Failed    : 失敗したジョブ数
+//	Blocked   : 失敗したジョブのうち、アクセスを拒否されたジョブ数
+//	Drained   : DRAINの指示を受けてキューを処理し終えた場合はtrue
+//	Errors    : 失敗したジョブのエラーメッセージごとの件数
+//	FetchTime : 処理したジョブの取得と保存にかかった時間の合計
+type CrawlExecuteResult struct {
+	Success   int
+	Failed    int
+	Blocked   int
+	Drained   bool
+	Errors    map[string]int
+	FetchTime time.Duration
+}
+
+// controlPollIntervalは、一時停止中に操作指示を確認する間隔です。
+const controlPollInterval = 5 * time.Second
+
 // ExecuteCrawlJobは、CrawlJobExecutorUseCaseのメイン実行ロジックです。
-// PENDING状態のCrawlJobを定期的に取得し、処理します。
+// キューからPENDING状態のCrawlJobを順に取り出し、キューが空になるまで処理します。
+// 失敗してキューに戻したジョブを再び取り出した場合は、キューを一巡したとみなして終了します。
+// DRAINの指示を受けた状態でキューを処理し終えた場合は、ドレインを完了します。
 //
 // args:
 //
@@ -602,45 +1117,487 @@ var (
 //
 // return:
 //
-//	error : 実行中に発生したエラー
-func (u *executeCrawlJobUseCase) ExecuteCrawlJob(ctx context.Context) error {
-	u.logger.Info("クローラーを開始します")
+//	CrawlExecuteResult : 成功・失敗したジョブ数
+//	error              : 実行中に発生したエラー
+func (u *executeCrawlJobUseCase) ExecuteCrawlJob(ctx context.Context) (CrawlExecuteResult, error) {
+	u.logger.Info("クローラーを開始します", "worker_id", u.workerID)
+
+	// 複数のマシンでキューを共有している場合に、動作中のワーカーとワーカーごとの処理件数を確認できるようにする
+	heartbeat := startCrawlWorkerHeartbeat(ctx, u.workers, u.workerID, model.CrawlWorkerRoleExecute, u.logger)
+	defer heartbeat.finish(ctx)
 
-	successJob, failedJob := 0, 0
+	successJob, failedJob, blockedJob := 0, 0, 0
 	totalProcessedJob := successJob + failedJob
+	// 実行結果のレポートに出力するため、失敗の原因と所要時間を集計する
+	errorCounts := make(map[string]int)
+	var fetchTime time.Duration
+
+	// この実行で処理したジョブのURL。失敗してキューに戻したジョブを繰り返し処理しないために使用する
+	attempted := make(map[string]bool)
+	// キューが空になるか一巡して終了した場合はtrue。DRAINの指示の完了の判定に使用する
+	exhausted := false
+
+	// 中断された場合は、処理中のジョブを終えた時点で停止する
+	for ctx.Err() == nil {
+		if !u.awaitControl(ctx) {
+			break
+		}
 
-	resultStream := u.repo.FindListByStatusStream(ctx, batchSize, model.CrawlJobStatusPending)
-	for result := range resultStream {
-		if result.Err != nil {
-			u.logger.Error("クロールジョブの取得中にエラーが発生しました", "error", result.Err)
-			failedJob++
-			continue
+		// 複数のプロセスでキューを共有しても同じジョブを重複して処理しないよう、取り出しと同時に獲得する
+		job, found, err := u.repo.Dequeue(ctx, u.workerID)
+		if err != nil {
+			if ctx.Err() == nil {
+				u.logger.Error("キューからのジョブの取得に失敗しました", "error", err)
+			}
+			break
+		}
+		if !found {
+			exhausted = true
+			break
+		}
+
+		if attempted[job.URL()] {
+			if _, err := changeJobStatus(context.WithoutCancel(ctx), u.repo, job, model.CrawlJobStatusPending); err != nil {
+				u.logger.Error("ジョブをPENDINGに戻せませんでした", "jobID", job.ID(), "url", job.URL(), "error", err)
+			}
+			u.logger.Info("キューを一巡したため、残りのジョブは次回の実行で処理します")
+			exhausted = true
+			break
+		}
+		attempted[job.URL()] = true
+		heartbeat.processing(job.URL())
+
+		startedAt := time.Now()
+		meta, crawlErr := u.processCrawl(ctx, job)
+		finishedAt := time.Now()
+
+		// 失敗したジョブを再実行せずに調べられるよう、クロールの結果をジョブとともに保存する
+		attempt := model.CrawlAttempt{
+			StartedAt:  startedAt,
+			FinishedAt: finishedAt,
+			Duration:   finishedAt.Sub(startedAt),
+			HTTPStatus: meta.Status,
+		}
+		status := model.CrawlJobStatusSuccess
+		if crawlErr != nil {
+			attempt.Error = crawlErr.Error()
+			job = job.WithRetryCount(job.RetryCount() + 1)
+
+			// 失敗したジョブは次回の実行で再試行できるようPENDINGに戻す。ページが存在しない場合と、再試行してもサーバーエラーが返された場合はFAILEDにする
+			status = model.CrawlJobStatusPending
+			switch {
+			case errors.Is(crawlErr, errPageGone), errors.Is(crawlErr, errServerError):
+				status = model.CrawlJobStatusFailed
+			case errors.Is(crawlErr, errPageBlocked):
+				status = model.CrawlJobStatusBlocked
+			}
+		}
+		job = job.WithLastAttempt(attempt)
+
+		// HTMLの保存後に中断された場合でもジョブの状態を確定させるため、キャンセルを伝播させない
+		completedJob := job
+		updatedJob, err := changeJobStatus(context.WithoutCancel(ctx), u.repo, job, status)
+		switch {
+		case err != nil && crawlErr == nil:
+			u.logger.Error("ジョブのステータスをSUCCESSに更新できませんでした", "jobID", job.ID(), "url", job.URL(), "error", err)
+			crawlErr = fmt.Errorf("ジョブのステータス更新に失敗しました: %w", err)
+		case err != nil:
+			u.logger.Error("ジョブのステータスを更新できませんでした", "jobID", job.ID(), "url", job.URL(), "status", status, "error", err)
+		default:
+			completedJob = updatedJob
+		}
+
+		if u.hook != nil {
+			if err := u.hook.OnJobCompleted(ctx, completedJob, crawlErr); err != nil {
+				u.logger.Warn("job_completedフックの呼び出しに失敗しました", "jobID", job.ID(), "error", err)
+			}
 		}
 
-		job := result.Job
-		if err := u.processCrawl(ctx, job); err != nil {
+		if err := crawlErr; err != nil {
+			if errors.Is(err, infra.ErrQuotaExceeded) {
+				u.logger.Warn("クォータの上限に達したため、クローラーを停止します", "jobID", job.ID(), "error", err)
+				break
+			}
 			u.logger.Error("クロール処理に失敗しました", "jobID", job.ID(), "url", job.URL(), "error", err)
 			failedJob++
+			errorCounts[err.Error()]++
+			if errors.Is(err, errPageBlocked) {
+				blockedJob++
+				if !u.handleBlocked(ctx) {
+					break
+				}
+			}
+		} else {
+			successJob++
 		}
-		successJob++
 
 		totalProcessedJob = successJob + failedJob
+		fetchTime += attempt.Duration
+		heartbeat.processed(successJob, failedJob, blockedJob)
 
 		if totalProcessedJob%10 == 0 {
 			u.logger.Info("ジョブを処理しました", "total_processed", totalProcessedJob, "jobID", job.ID(), "url", job.URL())
 		}
+
+		u.throttleAfter(ctx, attempt.HTTPStatus, crawlErr != nil)
+	}
+
+	if ctx.Err() != nil {
+		// 未処理のジョブはPENDINGのまま残るため、次回の実行で再開される
+		u.logger.Warn("中断されたため、クローラーを停止しました。未処理のジョブは次回の実行で処理されます", "total_processed", totalProcessedJob, "success", successJob, "failed", failedJob, "blocked", blockedJob)
+		return CrawlExecuteResult{Success: successJob, Failed: failedJob, Blocked: blockedJob, Errors: errorCounts, FetchTime: fetchTime}, nil
 	}
 
+	drained := exhausted && u.completeDrain(ctx)
+
 	if totalProcessedJob == 0 {
 		u.logger.Info("保留中のクロールジョブが見つかりませんでした。処理を終了します。")
-		return nil
+		return CrawlExecuteResult{Drained: drained}, nil
+	}
+
+	u.logger.Info("クローラーが完了しました", "total_processed", totalProcessedJob, "success", successJob, "failed", failedJob, "blocked", blockedJob)
+	return CrawlExecuteResult{Success: successJob, Failed: failedJob, Blocked: blockedJob, Drained: drained, Errors: errorCounts, FetchTime: fetchTime}, nil
+}
+
+// changeJobStatusは、ジョブのステータスを変更してリポジトリに反映します。
+// 新しいステータスのジョブを保存してから古いステータスのジョブを削除するため、
+// 途中で失敗してもジョブが失われることはありません（両方のステータスに残る場合があります）。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	repo   : クロールジョブリポジトリ
+//	job    : 対象のCrawlJob
+//	status : 変更後のステータス
+//
+// return:
+//
+//	model.CrawlJob : ステータス変更後のCrawlJob
+//	error          : 変更や保存、削除に失敗した場合のエラー
+func changeJobStatus(ctx context.Context, repo repository.CrawlJobRepository, job model.CrawlJob, status model.CrawlJobStatus) (model.CrawlJob, error) {
+	// ChangeStatusはレシーバーも変更するため、削除用に変更前のジョブを控えておく
+	oldJob := job
+	newJob, err := job.ChangeStatus(status)
+	if err != nil {
+		return model.CrawlJob{}, fmt.Errorf("ジョブのステータス変更に失敗しました: %w", err)
+	}
+
+	if err := repo.Save(ctx, newJob); err != nil {
+		return model.CrawlJob{}, fmt.Errorf("ステータス%sのジョブの保存に失敗しました: %w", status, err)
+	}
+	if err := repo.Delete(ctx, oldJob); err != nil {
+		return model.CrawlJob{}, fmt.Errorf("ステータス%sのジョブの削除に失敗しました: %w", oldJob.Status(), err)
+	}
+	return newJob, nil
+}
+
+// awaitControlは、操作指示を確認し、一時停止中は再開または停止の指示があるまで待機します。
+// 停止の指示は、同じ操作指示を確認する他のクローラーも停止できるよう、読み取った後もそのまま残します。
+// 操作指示を取得できない場合は、クロールを止めないよう処理を続行します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	bool : 処理を続行する場合はtrue、停止する場合はfalse
+func (u *executeCrawlJobUseCase) awaitControl(ctx context.Context) bool {
+	if u.control == nil {
+		return true
+	}
+
+	paused := false
+	for {
+		command, err := u.control.Get(ctx)
+		if err != nil {
+			u.logger.Warn("操作指示の取得に失敗したため、処理を続行します", "error", err)
+			return true
+		}
+
+		switch command {
+		case model.CrawlControlStop:
+			// キューを共有するすべてのクローラーが停止するよう、指示はRUNに戻さない（crawler control resume またはdaemonの次の実行で戻す）
+			u.logger.Info("停止の指示を受け取りました。クローラーを停止します。再開するには crawler control resume を実行してください")
+			return false
+
+		case model.CrawlControlPause:
+			if !paused {
+				u.logger.Info("一時停止の指示を受け取りました。再開の指示を待機します")
+				paused = true
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case <-time.After(controlPollInterval):
+			}
+
+		default:
+			if paused {
+				u.logger.Info("再開の指示を受け取りました。クローラーを再開します")
+			}
+			return true
+		}
+	}
+}
+
+// throttleAfterは、ジョブの結果から次のジョブまでの待機時間を調整し、待機します。
+// 待機時間を広げた場合と戻した場合はログに出力します。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	status : ジョブで受け取ったHTTPステータスコード
+//	failed : ジョブが失敗した場合はtrue
+func (u *executeCrawlJobUseCase) throttleAfter(ctx context.Context, status int, failed bool) {
+	if u.throttle == nil {
+		return
+	}
+
+	before, after := u.throttle.Record(status, failed)
+	switch {
+	case after > before:
+		u.logger.Warn("エラーが増えたため、ジョブの間の待機時間を広げます", "status", status, "delay", after.String())
+	case after < before:
+		u.logger.Info("成功が続いたため、ジョブの間の待機時間を戻します", "delay", after.String())
+	}
+
+	if after > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(after):
+		}
+	}
+}
+
+// completeDrainは、キューを処理し終えた時点で操作指示がDRAINかどうかを確認し、ドレインを完了します。
+// まだジョブを処理中のワーカーもキューを処理し終えた時点で停止できるよう、指示はRUNに戻しません（crawler control resume で戻します）。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	bool : ドレインを完了した場合はtrue
+func (u *executeCrawlJobUseCase) completeDrain(ctx context.Context) bool {
+	if u.control == nil {
+		return false
+	}
+	command, err := u.control.Get(ctx)
+	if err != nil {
+		u.logger.Warn("操作指示の取得に失敗しました", "error", err)
+		return false
+	}
+	if command != model.CrawlControlDrain {
+		return false
+	}
+
+	u.logger.Info("キューのジョブを処理し終えたため、ドレインを完了します。再びジョブを生成するには crawler control resume を実行してください")
+	return true
+}
+
+// handleBlockedは、アクセスを拒否されたページが返された場合に、blocked.actionに従って実行を続けるかを決めます。
+// pauseの場合は操作指示をPAUSEにし、キューを共有するすべてのクローラーを次のジョブの前で一時停止させます。
+// 再開するには crawler control resume を実行します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	bool : 処理を続行する場合はtrue、停止する場合はfalse
+func (u *executeCrawlJobUseCase) handleBlocked(ctx context.Context) bool {
+	switch u.cfg.Blocked.ActionOrDefault() {
+	case config.BlockedStop:
+		u.logger.Warn("アクセスを拒否されたため、クローラーを停止します")
+		return false
+
+	case config.BlockedPause:
+		if u.control == nil {
+			u.logger.Warn("操作指示を使用できないため、一時停止せずにクローラーを停止します")
+			return false
+		}
+		if err := u.control.Set(context.WithoutCancel(ctx), model.CrawlControlPause); err != nil {
+			u.logger.Error("一時停止の指示を設定できなかったため、クローラーを停止します", "error", err)
+			return false
+		}
+		u.logger.Warn("アクセスを拒否されたため、クローラーを一時停止します。再開するには crawler control resume を実行してください")
+		return true
+
+	default:
+		return true
 	}
+}
 
-	u.logger.Info("クローラーが完了しました", "total_processed", totalProcessedJob)
+// runDetailActionsは、詳細ページのHTMLを取得する前に、設定された操作を実行順に行います。
+// 対象の要素が存在しないページもあるため、操作に失敗した場合はログに出力して次の操作に進みます。
+//
+// args:
+//
+//	ctx : コンテキスト
+//	job : 対象のCrawlJob
+//
+// return:
+//
+//	error : 待機中に中断された場合のエラー
+func (u *executeCrawlJobUseCase) runDetailActions(ctx context.Context, job model.CrawlJob) error {
+	for i, action := range u.cfg.DetailActions() {
+		var err error
+		switch action.Type {
+		case config.ActionClick:
+			err = u.client.Click(action.Selector)
+		case config.ActionScrollBottom:
+			err = u.client.ScrollToBottom()
+		case config.ActionPress:
+			err = u.client.Press(action.Selector, action.Key)
+		case config.ActionWait:
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(action.WaitMillis) * time.Millisecond):
+			}
+		}
+		if err != nil {
+			u.logger.Warn("詳細ページの操作に失敗しました", "id", job.ID(), "url", job.URL(), "index", i, "type", action.Type, "selector", action.Selector, "error", err)
+		}
+	}
 	return nil
 }
 
-// processCrawlは、1件のCrawlJobを実行し、HTML保存・ステータス更新を行います。
+// savePDFは、現在のページのPDFを出力し、HTMLと同じ保存先に保存したファイル名をメタデータに記録します。
+//
+// args:
+//
+//	job  : 対象のCrawlJob
+//	meta : PDFのファイル名を記録するメタデータ
+//
+// return:
+//
+//	error : PDFの出力または保存に失敗した場合のエラー
+func (u *executeCrawlJobUseCase) savePDF(job model.CrawlJob, meta *infra.HTMLMetadata) error {
+	pdf, err := u.client.PDF()
+	if err != nil {
+		return fmt.Errorf("PDFの出力に失敗しました: %w", err)
+	}
+	filename := job.ID() + ".pdf"
+	if err := u.pdfs.SavePDF(filename, pdf); err != nil {
+		return fmt.Errorf("PDFの保存に失敗しました: %w", err)
+	}
+	meta.PDF = filename
+	return nil
+}
+
+// lookupCacheは、ジョブのURLのキャッシュを再利用できる場合に、キャッシュしたHTMLを返します。
+// cache_ttlの期間内のキャッシュはそのまま再利用し、期間を過ぎたキャッシュはETag・Last-Modifiedによる条件付きリクエストで変更がないことを確認してから再利用します。
+// キャッシュの読み込みや確認に失敗した場合は、ページを取得し直すためにキャッシュがないものとして扱います。
+//
+// args:
+//
+//	job : 対象のCrawlJob
+//
+// return:
+//
+//	string                : キャッシュしたHTML
+//	*infra.CachedResponse : 再利用するキャッシュ（再利用できない場合はnil）
+func (u *executeCrawlJobUseCase) lookupCache(job model.CrawlJob) (string, *infra.CachedResponse) {
+	if u.cache == nil {
+		return "", nil
+	}
+
+	cached, ok, err := u.cache.Get(job.URL())
+	if err != nil {
+		u.logger.Warn("キャッシュを読み込めませんでした。ページを取得し直します", "id", job.ID(), "url", job.URL(), "error", err)
+		return "", nil
+	}
+	if !ok {
+		return "", nil
+	}
+
+	if time.Since(cached.FetchedAt) < u.cfg.Cache.TTL() {
+		u.logger.Info("キャッシュを再利用します", "id", job.ID(), "url", job.URL(), "fetched_at", cached.FetchedAt)
+		return cached.HTML, &cached
+	}
+
+	if cached.ETag == "" && cached.LastModified == "" {
+		return "", nil
+	}
+	notModified, err := u.client.CheckNotModified(job.URL(), cached.ETag, cached.LastModified)
+	if err != nil {
+		u.logger.Warn("ページの変更を確認できませんでした。ページを取得し直します", "id", job.ID(), "url", job.URL(), "error", err)
+		return "", nil
+	}
+	if !notModified {
+		return "", nil
+	}
+
+	// 変更がないことを確認した日時を記録し、次回はcache_ttlの期間内であれば確認を省略する
+	cached.FetchedAt = time.Now()
+	if err := u.cache.Put(cached); err != nil {
+		u.logger.Warn("キャッシュを更新できませんでした", "id", job.ID(), "url", job.URL(), "error", err)
+	}
+	u.logger.Info("ページに変更がないため、キャッシュを再利用します", "id", job.ID(), "url", job.URL())
+	return cached.HTML, &cached
+}
+
+// storeCacheは、取得したページをジョブのURLのキャッシュとして保存します。
+// エラーページを再利用しないよう、ステータスコードが200のページのみを保存します。
+//
+// args:
+//
+//	job  : 対象のCrawlJob
+//	meta : 取得したページのメタデータ
+//	html : 取得したHTML
+func (u *executeCrawlJobUseCase) storeCache(job model.CrawlJob, meta infra.HTMLMetadata, html string) {
+	if u.cache == nil || meta.Status != http.StatusOK {
+		return
+	}
+
+	response := infra.CachedResponse{
+		URL:          job.URL(),
+		FinalURL:     meta.FinalURL,
+		Status:       meta.Status,
+		ETag:         u.client.LastHeader("ETag"),
+		LastModified: u.client.LastHeader("Last-Modified"),
+		FetchedAt:    meta.FetchedAt,
+		HTML:         html,
+	}
+	if err := u.cache.Put(response); err != nil {
+		u.logger.Warn("キャッシュを保存できませんでした", "id", job.ID(), "url", job.URL(), "error", err)
+	}
+}
+
+// isUnchangedは、ページの内容が前回のクロールから変更されていないかを判定します。
+// 初めてクロールしたページや、前回のハッシュ値を取得できなかったページは変更されたものとして扱います。
+//
+// args:
+//
+//	ctx  : コンテキスト
+//	job  : 対象のCrawlJob
+//	hash : 今回取得したページの内容のハッシュ値
+//
+// return:
+//
+//	bool : 前回のクロールから変更されていない場合はtrue
+func (u *executeCrawlJobUseCase) isUnchanged(ctx context.Context, job model.CrawlJob, hash string) bool {
+	if u.hashes == nil {
+		return false
+	}
+
+	previous, ok, err := u.hashes.Get(ctx, job.URL())
+	if err != nil {
+		u.logger.Warn("前回のページの内容のハッシュ値を取得できませんでした。変更されたものとして扱います", "id", job.ID(), "url", job.URL(), "error", err)
+		return false
+	}
+	if ok && previous == hash {
+		u.logger.Info("ページの内容は前回のクロールから変更されていません", "id", job.ID(), "url", job.URL())
+		return true
+	}
+	return false
+}
+
+// processCrawlは、1件のCrawlJobを実行し、HTMLとメタデータを保存します。ジョブのステータスは呼び出し元で更新します。
 //
 // args:
 //
@@ -649,51 +1606,114 @@ func (u *executeCrawlJobUseCase) ExecuteCrawlJob(ctx context.Context) error {
 //
 // return:
 //
-//	error : 実行中に発生したエラー
-func (u *executeCrawlJobUseCase) processCrawl(ctx context.Context, job model.CrawlJob) error {
+//	infra.HTMLMetadata : 保存したメタデータ（失敗した場合も、それまでに取得したステータスコードを含む）
+//	error              : 実行中に発生したエラー
+func (u *executeCrawlJobUseCase) processCrawl(ctx context.Context, job model.CrawlJob) (infra.HTMLMetadata, error) {
 	u.logger.Info("クロールジョブを処理中", "id", job.ID(), "url", job.URL())
 
-	if err := u.client.Navigate(job.URL()); err != nil {
-		u.logger.Error("ナビゲーションに失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
-		return fmt.Errorf("ナビゲーションに失敗しました: %w", err)
+	// 取得元URLやクロール日時をサイドカーとして保存する
+	meta := infra.HTMLMetadata{
+		JobID:      job.ID(),
+		URL:        job.URL(),
+		FinalURL:   job.URL(),
+		ConfigHash: u.configHash,
+	}
+	// 一覧ページでの掲載位置をスクレイパーの出力に引き継ぐ
+	if listing := job.ListingPosition(); !listing.IsZero() {
+		meta.ListPage = listing.Page
+		meta.ListPosition = listing.Position
 	}
 
-	if u.cfg.Selector.TabClickSelector != "" {
-		u.logger.Info("タブをクリックします", "selector", u.cfg.Selector.TabClickSelector)
-		// タブをクリック
-		if err := u.client.Click(u.cfg.Selector.TabClickSelector); err != nil {
-			u.logger.Error("タブのクリックに失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
+	// 変更のないページはキャッシュのHTMLを再利用し、ページの取得を省略する
+	html, cached := u.lookupCache(job)
+	if cached != nil {
+		meta.FinalURL = cached.FinalURL
+		meta.Status = cached.Status
+		meta.FetchedAt = cached.FetchedAt
+		meta.FromCache = true
+	} else {
+		if err := u.client.Navigate(ctx, job.URL()); err != nil {
+			u.logger.Error("ナビゲーションに失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
+			return meta, fmt.Errorf("ナビゲーションに失敗しました: %w", err)
 		}
+
+		// エラーページを求人のHTMLとして保存しないよう、ページが存在しない場合とサーバーエラーの場合は失敗とする
+		meta.Status = u.client.LastStatus()
+		switch status := meta.Status; {
+		case status == http.StatusNotFound || status == http.StatusGone:
+			return meta, fmt.Errorf("%w（ステータスコード %d）", errPageGone, status)
+		case status >= http.StatusInternalServerError:
+			return meta, fmt.Errorf("%w（ステータスコード %d）", errServerError, status)
+		}
+
+		// 「もっと見る」ボタンのクリックやスクロールなど、隠れた情報を表示させる操作を行う
+		if err := u.runDetailActions(ctx, job); err != nil {
+			return meta, fmt.Errorf("詳細ページの操作中に中断されました: %w", err)
+		}
+
+		// HTMLを取得
+		var err error
+		html, err = u.client.GetHTML()
+		if err != nil {
+			u.logger.Error("HTMLの取得に失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
+			return meta, fmt.Errorf("HTMLの取得に失敗しました: %w", err)
+		}
+
+		// チャレンジページを求人のHTMLとして保存しないよう、保存やキャッシュの前に判定する
+		if u.blocks != nil {
+			if reason, blocked := u.blocks.Detect(html); blocked {
+				return meta, fmt.Errorf("%w: %s", errPageBlocked, reason)
+			}
+		}
+
+		meta.Status = u.client.LastStatus()
+		meta.Redirects = u.client.LastRedirects()
+		meta.FetchedAt = time.Now()
+		if finalURL, err := u.client.CurrentURL(); err == nil {
+			meta.FinalURL = finalURL.String()
+		}
+		u.storeCache(job, meta, html)
 	}
-	// HTMLを取得
-	html, err := u.client.GetHTML()
-	if err != nil {
-		u.logger.Error("HTMLの取得に失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
-		return fmt.Errorf("HTMLの取得に失敗しました: %w", err)
-	}
+
+	// 前回のクロールから内容が変更されたかを記録し、スクレイパーが変更のあったページのみを処理できるようにする
+	meta.ContentHash = infra.ContentHash(html)
+	meta.Unchanged = u.isUnchanged(ctx, job, meta.ContentHash)
 
 	// HTMLを保存
-	if err := u.client.SaveHTML(job.ID()+".html", html); err != nil {
+	filename := job.ID() + ".html"
+	if err := u.storage.SaveHTML(filename, html); err != nil {
 		u.logger.Error("HTMLの保存に失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
-		return fmt.Errorf("HTMLの保存に失敗しました: %w", err)
+		return meta, fmt.Errorf("HTMLの保存に失敗しました: %w", err)
 	}
 
-	// 現在は、削除が成功してもステータス更新が失敗する可能性があるため、トランザクション管理を検討してください。
-	if err := u.repo.Delete(ctx, job); err != nil {
-		u.logger.Error("処理済みクロールジョブの削除に失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
-		return fmt.Errorf("クロールジョブの削除に失敗しました: %w", err)
+	// 証跡としてブラウザで描画した状態を残せるよう、HTMLと同じ名前でPDFも保存する。
+	// キャッシュのHTMLを再利用した場合はページを描画していないため保存しない
+	if u.pdfs != nil && !meta.FromCache {
+		if err := u.savePDF(job, &meta); err != nil {
+			u.logger.Error("PDFの保存に失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
+			return meta, err
+		}
 	}
 
-	newJob, err := job.ChangeStatus(model.CrawlJobStatusSuccess)
-	if err != nil {
-		return fmt.Errorf("ジョブのステータス変更に失敗しました: %w", err)
+	// 掲載終了のページもHTMLは保存し、スクレイパーが判定結果に従って扱えるようメタデータに記録する
+	if u.expiry != nil {
+		if reason, expired := u.expiry.Detect(html, meta.URL, meta.FinalURL); expired {
+			u.logger.Info("掲載終了のページと判定しました", "id", job.ID(), "url", job.URL(), "reason", reason)
+			meta.ExpiredReason = reason
+		}
+	}
+	if err := u.storage.SaveHTMLMetadata(filename, meta); err != nil {
+		u.logger.Error("メタデータの保存に失敗しました", "id", job.ID(), "url", job.URL(), "error", err)
+		return meta, fmt.Errorf("メタデータの保存に失敗しました: %w", err)
 	}
 
-	// ジョブのステータスをSUCCESSに更新
-	if err := u.repo.Save(ctx, newJob); err != nil {
-		u.logger.Error("ジョブのステータスをSUCCESSに更新できませんでした", "id", job.ID(), "url", job.URL(), "error", err)
-		return fmt.Errorf("ジョブのステータス更新に失敗しました: %w", err)
+	// 次回のクロールで変更を判定できるよう、メタデータの保存後にハッシュ値を記録する。
+	// HTMLの保存後に中断された場合でも記録するため、キャンセルを伝播させない
+	if u.hashes != nil {
+		if err := u.hashes.Set(context.WithoutCancel(ctx), job.URL(), meta.ContentHash); err != nil {
+			u.logger.Warn("ページの内容のハッシュ値を記録できませんでした", "id", job.ID(), "url", job.URL(), "error", err)
+		}
 	}
 
-	return nil
+	return meta, nil
 }
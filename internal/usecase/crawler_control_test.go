@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nrad-K/go-crawler/internal/config"
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+)
+
+// memoryCrawlControlは、複数のクローラーで共有する操作指示をメモリ上に保持するCrawlControlRepositoryです。
+type memoryCrawlControl struct {
+	mu      sync.Mutex
+	command model.CrawlControlCommand
+}
+
+func (c *memoryCrawlControl) Get(ctx context.Context) (model.CrawlControlCommand, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.command == "" {
+		return model.CrawlControlRun, nil
+	}
+	return c.command, nil
+}
+
+func (c *memoryCrawlControl) Set(ctx context.Context, command model.CrawlControlCommand) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.command = command
+	return nil
+}
+
+func (c *memoryCrawlControl) Reset(ctx context.Context, command model.CrawlControlCommand) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.command != command {
+		return false, nil
+	}
+	c.command = model.CrawlControlRun
+	return true, nil
+}
+
+// emptyCrawlJobQueueは、常に空のキューを表すCrawlJobRepositoryです。取り出しを試みた回数を数えます。
+// テストで使用しないメソッドは、埋め込んだインターフェースがnilのため呼び出すとpanicします。
+type emptyCrawlJobQueue struct {
+	repository.CrawlJobRepository
+	mu       sync.Mutex
+	dequeues int
+}
+
+func (q *emptyCrawlJobQueue) Dequeue(ctx context.Context, workerID string) (model.CrawlJob, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dequeues++
+	return model.CrawlJob{}, false, nil
+}
+
+// nopLoggerは、ログを出力しないAppLoggerです。
+type nopLogger struct{}
+
+func (nopLogger) Info(format string, args ...any)  {}
+func (nopLogger) Warn(format string, args ...any)  {}
+func (nopLogger) Error(format string, args ...any) {}
+
+// newTestExecutorは、操作指示とキューを共有するワーカーの実行ユースケースを生成します。
+func newTestExecutor(control repository.CrawlControlRepository, queue repository.CrawlJobRepository, workerID string) *executeCrawlJobUseCase {
+	return NewExecuteCrawlJobUseCase(CrawlerArgs{
+		Cfg:      &config.CrawlerConfig{},
+		Repo:     queue,
+		Control:  control,
+		Logger:   nopLogger{},
+		WorkerID: workerID,
+	})
+}
+
+func TestExecuteCrawlJobStopsEveryExecutorSharingControl(t *testing.T) {
+	ctx := context.Background()
+	control := &memoryCrawlControl{command: model.CrawlControlStop}
+	queue := &emptyCrawlJobQueue{}
+
+	for _, workerID := range []string{"worker-a", "worker-b"} {
+		result, err := newTestExecutor(control, queue, workerID).ExecuteCrawlJob(ctx)
+		if err != nil {
+			t.Fatalf("%s: ExecuteCrawlJob: %v", workerID, err)
+		}
+		if result.Drained {
+			t.Errorf("%s: Drained = true, want false", workerID)
+		}
+	}
+
+	if queue.dequeues != 0 {
+		t.Errorf("停止の指示を受けた後にキューから %d 回取り出しました", queue.dequeues)
+	}
+	if command, _ := control.Get(ctx); command != model.CrawlControlStop {
+		t.Errorf("操作指示 = %s, want %s", command, model.CrawlControlStop)
+	}
+}
+
+func TestExecuteCrawlJobDrainsEveryWorkerSharingControl(t *testing.T) {
+	ctx := context.Background()
+	control := &memoryCrawlControl{command: model.CrawlControlDrain}
+	queue := &emptyCrawlJobQueue{}
+
+	// 先にキューを処理し終えたワーカーが指示を戻すと、後から処理し終えたワーカーがドレインの完了を検知できない
+	for _, workerID := range []string{"worker-a", "worker-b", "worker-c"} {
+		result, err := newTestExecutor(control, queue, workerID).ExecuteCrawlJob(ctx)
+		if err != nil {
+			t.Fatalf("%s: ExecuteCrawlJob: %v", workerID, err)
+		}
+		if !result.Drained {
+			t.Errorf("%s: Drained = false, want true", workerID)
+		}
+	}
+
+	if command, _ := control.Get(ctx); command != model.CrawlControlDrain {
+		t.Errorf("操作指示 = %s, want %s", command, model.CrawlControlDrain)
+	}
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+	"github.com/nrad-K/go-crawler/internal/logger"
+)
+
+// ReapStaleCrawlJobArgsは、放置されたジョブを回収するユースケースを構築するための引数を保持します。
+//
+// フィールド:
+//
+//	Repo          : クロールジョブリポジトリ
+//	Workers       : 実行中のワーカーの状態を取得するリポジトリ（nilの場合はワーカーの状態を確認しない）
+//	Logger        : ロガー
+//	StreamOptions : IN_PROGRESSのジョブを走査する際の取得方法（担当するパーティションなど）
+type ReapStaleCrawlJobArgs struct {
+	Repo          repository.CrawlJobRepository
+	Workers       repository.CrawlWorkerRepository
+	Logger        logger.AppLogger
+	StreamOptions model.CrawlJobStreamOptions
+}
+
+// reapStaleCrawlJobUseCaseは、IN_PROGRESSのまま放置されたジョブをPENDINGに戻すユースケースです。
+// 実行中のプロセスがクラッシュした場合でも、ジョブが取り残されないようにします。
+type reapStaleCrawlJobUseCase struct {
+	repo          repository.CrawlJobRepository
+	workers       repository.CrawlWorkerRepository
+	logger        logger.AppLogger
+	streamOptions model.CrawlJobStreamOptions
+}
+
+// NewReapStaleCrawlJobUseCaseは、reapStaleCrawlJobUseCaseの新しいインスタンスを作成します。
+//
+// args:
+//
+//	args : ReapStaleCrawlJobArgs構造体（リポジトリ・ロガー・ストリームの取得方法）
+//
+// return:
+//
+//	*reapStaleCrawlJobUseCase : 生成されたユースケースインスタンス
+func NewReapStaleCrawlJobUseCase(args ReapStaleCrawlJobArgs) *reapStaleCrawlJobUseCase {
+	return &reapStaleCrawlJobUseCase{
+		repo:          args.Repo,
+		workers:       args.Workers,
+		logger:        args.Logger,
+		streamOptions: args.StreamOptions,
+	}
+}
+
+// ReapStaleJobsは、最後の更新からstaleAfter以上経過したIN_PROGRESSのジョブをPENDINGに戻します。
+// 更新日時が記録されていないジョブは、放置されたものとして扱います。
+// 獲得したワーカーが状態を報告し続けているジョブは、ページの読み込みに時間がかかっているだけのため戻しません。
+//
+// args:
+//
+//	ctx        : コンテキスト
+//	staleAfter : 放置されたとみなすまでの時間
+//
+// return:
+//
+//	int   : PENDINGに戻したジョブ数
+//	error : 実行中に発生したエラー
+func (u *reapStaleCrawlJobUseCase) ReapStaleJobs(ctx context.Context, staleAfter time.Duration) (int, error) {
+	u.logger.Info("放置されたジョブの回収を開始します", "stale_after", staleAfter.String(), "partition", u.streamOptions.Partition, "partitions", u.streamOptions.Partitions)
+
+	if err := u.requeueDeadWorkerQueues(ctx); err != nil {
+		return 0, err
+	}
+
+	alive, err := u.aliveWorkers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	threshold := time.Now().Add(-staleAfter)
+	reaped := 0
+
+	resultStream := u.repo.FindListByStatusStream(ctx, model.CrawlJobStatusInProgress, u.streamOptions)
+	for result := range resultStream {
+		if result.Err != nil {
+			u.logger.Error("クロールジョブの取得中にエラーが発生しました", "error", result.Err)
+			continue
+		}
+
+		job := result.Job
+		if !job.UpdatedAt().IsZero() && job.UpdatedAt().After(threshold) {
+			continue
+		}
+		if alive[job.WorkerID()] {
+			u.logger.Info("獲得したワーカーが実行中のため、ジョブを戻しません", "jobID", job.ID(), "url", job.URL(), "worker_id", job.WorkerID())
+			continue
+		}
+
+		if _, err := changeJobStatus(ctx, u.repo, job, model.CrawlJobStatusPending); err != nil {
+			u.logger.Error("ジョブをPENDINGに戻せませんでした", "jobID", job.ID(), "url", job.URL(), "error", err)
+			continue
+		}
+		u.logger.Info("放置されたジョブをPENDINGに戻しました", "jobID", job.ID(), "url", job.URL(), "updated_at", job.UpdatedAt())
+		reaped++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return reaped, err
+	}
+
+	u.logger.Info("放置されたジョブの回収が完了しました", "count", reaped)
+	return reaped, nil
+}
+
+// requeueDeadWorkerQueuesは、停止したワーカーがキューから取り出したまま獲得を終えていないURLをキューに戻します。
+// 取り出した直後にワーカーが停止した場合でもURLが失われないよう、ジョブの回収の前に実行します。
+// 実行中のワーカーの処理中のURLを戻さないよう、ワーカーの状態を取得するリポジトリがない場合は何もしません。
+// 走査を始めた後に起動したワーカーを停止したと判定しないよう、処理中リストを取得した後に実行中のワーカーを取得します。
+func (u *reapStaleCrawlJobUseCase) requeueDeadWorkerQueues(ctx context.Context) error {
+	if u.workers == nil {
+		return nil
+	}
+
+	workerIDs, err := u.repo.ProcessingWorkerIDs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(workerIDs) == 0 {
+		return nil
+	}
+	alive, err := u.aliveWorkers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, workerID := range workerIDs {
+		if alive[workerID] {
+			continue
+		}
+		moved, err := u.repo.RequeueProcessing(ctx, workerID)
+		if err != nil {
+			u.logger.Error("停止したワーカーの処理中のURLをキューに戻せませんでした", "worker_id", workerID, "error", err)
+			continue
+		}
+		if moved > 0 {
+			u.logger.Info("停止したワーカーの処理中のURLをキューに戻しました", "worker_id", workerID, "count", moved)
+		}
+	}
+	return nil
+}
+
+// aliveWorkersは、状態を報告している実行中のワーカーのIDを返します。
+func (u *reapStaleCrawlJobUseCase) aliveWorkers(ctx context.Context) (map[string]bool, error) {
+	alive := make(map[string]bool)
+	if u.workers == nil {
+		return alive, nil
+	}
+	workers, err := u.workers.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("実行中のワーカーの取得に失敗しました: %w", err)
+	}
+	for _, worker := range workers {
+		alive[worker.ID] = true
+	}
+	return alive, nil
+}
+
+// DeadWorkerJobsは、停止したワーカー（状態の報告が途絶えたワーカー）が獲得したままのIN_PROGRESSのジョブ数を、ワーカーごとに集計します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	map[string]int : 停止したワーカーのIDごとのジョブ数
+//	error          : 実行中に発生したエラー
+func (u *reapStaleCrawlJobUseCase) DeadWorkerJobs(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := u.eachDeadWorkerJob(ctx, func(job model.CrawlJob) {
+		counts[job.WorkerID()]++
+	})
+	return counts, err
+}
+
+// ReapDeadWorkerJobsは、停止したワーカーが獲得したままのIN_PROGRESSのジョブを、放置されたとみなすまでの時間を待たずにPENDINGに戻します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	int   : PENDINGに戻したジョブ数
+//	error : 実行中に発生したエラー
+func (u *reapStaleCrawlJobUseCase) ReapDeadWorkerJobs(ctx context.Context) (int, error) {
+	u.logger.Info("停止したワーカーが獲得したジョブの回収を開始します")
+
+	if err := u.requeueDeadWorkerQueues(ctx); err != nil {
+		return 0, err
+	}
+
+	reaped := 0
+	err := u.eachDeadWorkerJob(ctx, func(job model.CrawlJob) {
+		if _, err := changeJobStatus(ctx, u.repo, job, model.CrawlJobStatusPending); err != nil {
+			u.logger.Error("ジョブをPENDINGに戻せませんでした", "jobID", job.ID(), "url", job.URL(), "error", err)
+			return
+		}
+		u.logger.Info("停止したワーカーが獲得したジョブをPENDINGに戻しました", "jobID", job.ID(), "url", job.URL(), "worker_id", job.WorkerID())
+		reaped++
+	})
+	if err != nil {
+		return reaped, err
+	}
+
+	u.logger.Info("停止したワーカーが獲得したジョブの回収が完了しました", "count", reaped)
+	return reaped, nil
+}
+
+// eachDeadWorkerJobは、停止したワーカーが獲得したままのIN_PROGRESSのジョブごとにfnを呼び出します。
+// ワーカーのIDが記録されていない古いジョブは対象にしません。ワーカーの状態を取得するリポジトリがない場合は何もしません。
+// 走査を始めた後に起動したワーカーが獲得したジョブを含めないよう、実行中のワーカーを取得した後に更新されたジョブも対象にしません。
+func (u *reapStaleCrawlJobUseCase) eachDeadWorkerJob(ctx context.Context, fn func(job model.CrawlJob)) error {
+	if u.workers == nil {
+		return nil
+	}
+
+	checkedAt := time.Now()
+	alive, err := u.aliveWorkers(ctx)
+	if err != nil {
+		return err
+	}
+
+	resultStream := u.repo.FindListByStatusStream(ctx, model.CrawlJobStatusInProgress, u.streamOptions)
+	for result := range resultStream {
+		if result.Err != nil {
+			u.logger.Error("クロールジョブの取得中にエラーが発生しました", "error", result.Err)
+			continue
+		}
+
+		job := result.Job
+		if job.WorkerID() == "" || alive[job.WorkerID()] || job.UpdatedAt().After(checkedAt) {
+			continue
+		}
+		fn(job)
+	}
+	return ctx.Err()
+}
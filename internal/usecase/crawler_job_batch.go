@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+	"github.com/nrad-K/go-crawler/internal/logger"
+)
+
+// crawlJobBatchSizeは、生成したクロールジョブをまとめて保存する件数です。
+const crawlJobBatchSize = 500
+
+// crawlJobBatchは、生成したクロールジョブを一定の件数までためてから、まとめてリポジトリに保存するバッファです。
+// ジョブごとに存在確認と保存を行うとRedisとの往復が件数分発生するため、SaveAllでまとめて保存します。
+// 詳細ページのリンクは並列に処理されるため、排他制御を行います。
+//
+// フィールド:
+//
+//	repo   : 保存先のリポジトリ
+//	logger : ロガー
+//	size   : まとめて保存する件数
+//	mu     : jobsへのアクセスを保護するミューテックス
+//	jobs   : 保存していないジョブ
+//	saved  : 保存したジョブの累計（既に存在したため読み飛ばしたジョブを除く）
+type crawlJobBatch struct {
+	repo   repository.CrawlJobRepository
+	logger logger.AppLogger
+	size   int
+	mu     sync.Mutex
+	jobs   []model.CrawlJob
+	saved  int
+}
+
+// newCrawlJobBatchは、crawlJobBatchの新しいインスタンスを生成します。
+//
+// args:
+//
+//	repo   : 保存先のリポジトリ
+//	logger : ロガー
+//	size   : まとめて保存する件数
+//
+// return:
+//
+//	*crawlJobBatch : 生成されたバッファ
+func newCrawlJobBatch(repo repository.CrawlJobRepository, logger logger.AppLogger, size int) *crawlJobBatch {
+	return &crawlJobBatch{
+		repo:   repo,
+		logger: logger,
+		size:   size,
+		jobs:   make([]model.CrawlJob, 0, size),
+	}
+}
+
+// addは、ジョブをバッファに追加し、件数がsizeに達した場合はまとめて保存します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//	job : 追加するジョブ
+//
+// return:
+//
+//	error : 保存に失敗した場合のエラー
+func (b *crawlJobBatch) add(ctx context.Context, job model.CrawlJob) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.jobs = append(b.jobs, job)
+	if len(b.jobs) < b.size {
+		return nil
+	}
+	return b.flushLocked(ctx)
+}
+
+// flushは、バッファに残っているジョブをまとめて保存します。
+//
+// args:
+//
+//	ctx : コンテキスト
+//
+// return:
+//
+//	error : 保存に失敗した場合のエラー
+func (b *crawlJobBatch) flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked(ctx)
+}
+
+// flushLockedは、ロックを保持した状態でバッファのジョブを保存し、バッファを空にします。
+// 保存に失敗したジョブは、バッファが際限なく大きくならないよう再試行せずに破棄します。
+func (b *crawlJobBatch) flushLocked(ctx context.Context) error {
+	if len(b.jobs) == 0 {
+		return nil
+	}
+
+	jobs := b.jobs
+	b.jobs = make([]model.CrawlJob, 0, b.size)
+
+	saved, err := b.repo.SaveAll(ctx, jobs)
+	b.saved += saved
+	if err != nil {
+		return fmt.Errorf("%d件のクロールジョブの保存に失敗しました: %w", len(jobs)-saved, err)
+	}
+	if skipped := len(jobs) - saved; skipped > 0 {
+		b.logger.Info("既に存在するURLのためスキップしました", "count", skipped)
+	}
+	b.logger.Info("クロールジョブをまとめて保存しました", "count", saved)
+	return nil
+}
+
+// savedCountは、これまでに保存したジョブの累計を返します。
+func (b *crawlJobBatch) savedCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.saved
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/logger"
+)
+
+// MaintainCrawlJobArgsは、クロールジョブを保守するユースケースを構築するための引数を保持します。
+//
+// フィールド:
+//
+//	Repo          : クロールジョブリポジトリ
+//	Logger        : ロガー
+//	StreamOptions : 対象のジョブを走査する際の取得方法
+type MaintainCrawlJobArgs struct {
+	Repo          repository.CrawlJobRepository
+	Logger        logger.AppLogger
+	StreamOptions model.CrawlJobStreamOptions
+}
+
+// maintainCrawlJobUseCaseは、指定したステータスのジョブをまとめて再実行待ちに戻したり削除したり、ファイルに出力したりするユースケースです。
+// Redisのキーの構造を知らなくても、失敗したジョブの再試行や完了したジョブの整理を行えるようにします。
+type maintainCrawlJobUseCase struct {
+	repo          repository.CrawlJobRepository
+	logger        logger.AppLogger
+	streamOptions model.CrawlJobStreamOptions
+}
+
+// NewMaintainCrawlJobUseCaseは、maintainCrawlJobUseCaseの新しいインスタンスを作成します。
+//
+// args:
+//
+//	args : MaintainCrawlJobArgs構造体（リポジトリ・ロガー・ストリームの取得方法）
+//
+// return:
+//
+//	*maintainCrawlJobUseCase : 生成されたユースケースインスタンス
+func NewMaintainCrawlJobUseCase(args MaintainCrawlJobArgs) *maintainCrawlJobUseCase {
+	return &maintainCrawlJobUseCase{
+		repo:          args.Repo,
+		logger:        args.Logger,
+		streamOptions: args.StreamOptions,
+	}
+}
+
+// Requeueは、指定したステータスのジョブのうち、最後の更新からolderThan以上経過したものをPENDINGに戻します。
+// PENDINGに戻したジョブはキューの末尾に積まれ、次回の実行で処理されます。
+//
+// args:
+//
+//	ctx       : コンテキスト
+//	status    : 対象のジョブステータス
+//	olderThan : 対象とする経過時間（0の場合はすべてのジョブ）
+//	dryRun    : trueの場合は対象のジョブ数を数えるのみで変更しない
+//
+// return:
+//
+//	int   : PENDINGに戻した（dryRunの場合は対象の）ジョブ数
+//	error : 実行中に発生したエラー
+func (u *maintainCrawlJobUseCase) Requeue(ctx context.Context, status model.CrawlJobStatus, olderThan time.Duration, dryRun bool) (int, error) {
+	return u.each(ctx, "PENDINGへの変更", status, olderThan, dryRun, func(job model.CrawlJob) error {
+		_, err := changeJobStatus(ctx, u.repo, job, model.CrawlJobStatusPending)
+		return err
+	})
+}
+
+// Purgeは、指定したステータスのジョブのうち、最後の更新からolderThan以上経過したものを削除します。
+//
+// args:
+//
+//	ctx       : コンテキスト
+//	status    : 対象のジョブステータス
+//	olderThan : 対象とする経過時間（0の場合はすべてのジョブ）
+//	dryRun    : trueの場合は対象のジョブ数を数えるのみで削除しない
+//
+// return:
+//
+//	int   : 削除した（dryRunの場合は対象の）ジョブ数
+//	error : 実行中に発生したエラー
+func (u *maintainCrawlJobUseCase) Purge(ctx context.Context, status model.CrawlJobStatus, olderThan time.Duration, dryRun bool) (int, error) {
+	return u.each(ctx, "削除", status, olderThan, dryRun, func(job model.CrawlJob) error {
+		return u.repo.Delete(ctx, job)
+	})
+}
+
+// Exportは、指定したステータスのジョブのうち、最後の更新からolderThan以上経過したものをexporterに書き込みます。
+// 監査や手動での再クロールの対象の確認に使用します。出力の確定（Close）は呼び出し元で行います。
+//
+// args:
+//
+//	ctx       : コンテキスト
+//	status    : 対象のジョブステータス
+//	olderThan : 対象とする経過時間（0の場合はすべてのジョブ）
+//	exporter  : 書き込み先のエクスポーター
+//
+// return:
+//
+//	int   : 書き込んだジョブ数
+//	error : 実行中に発生したエラー
+func (u *maintainCrawlJobUseCase) Export(ctx context.Context, status model.CrawlJobStatus, olderThan time.Duration, exporter infra.CrawlJobExporter) (int, error) {
+	return u.each(ctx, "エクスポート", status, olderThan, false, exporter.Write)
+}
+
+// CrawlJobImportResultは、クロールジョブの取り込み結果です。
+//
+// フィールド:
+//
+//	Read    : ファイルから読み込んだURLの数
+//	Created : 作成したジョブ数
+//	Skipped : 既にPENDINGのジョブがあるため読み飛ばしたURLの数
+//	Invalid : 不正なURLのため読み飛ばした行の数
+type CrawlJobImportResult struct {
+	Read    int
+	Created int
+	Skipped int
+	Invalid int
+}
+
+// Importは、ファイルから読み込んだURLのPENDINGのジョブをまとめて作成します。
+// 一覧ページをクロールせずに、他の情報源から得たURLの一覧をクロールの対象にできます。
+// URLは一覧ページから作成したジョブと同じく正規化し、既にPENDINGのジョブがあるURLは読み飛ばします。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	reader : 取り込むファイルのリーダー
+//
+// return:
+//
+//	CrawlJobImportResult : 取り込み結果
+//	error                : ファイルの読み込みやジョブの保存に失敗した場合のエラー
+func (u *maintainCrawlJobUseCase) Import(ctx context.Context, reader *infra.CrawlJobImportReader) (CrawlJobImportResult, error) {
+	u.logger.Info("クロールジョブの取り込みを開始します")
+
+	var result CrawlJobImportResult
+	batch := newCrawlJobBatch(u.repo, u.logger, crawlJobBatchSize)
+	for ctx.Err() == nil {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("取り込むファイルの読み込みに失敗しました: %w", err)
+		}
+		result.Read++
+
+		canonicalURL, err := model.CanonicalizeURL(entry.URL)
+		if err != nil {
+			u.logger.Warn("不正なURLのため読み飛ばしました", "line", entry.Line, "url", entry.URL, "error", err)
+			result.Invalid++
+			continue
+		}
+		job, err := model.NewCrawlJob(canonicalURL)
+		if err != nil {
+			u.logger.Warn("不正なURLのため読み飛ばしました", "line", entry.Line, "url", entry.URL, "error", err)
+			result.Invalid++
+			continue
+		}
+
+		if err := batch.add(ctx, job.WithListingPosition(entry.Listing)); err != nil {
+			return result, err
+		}
+	}
+
+	// 中断された場合も、読み込み済みのURLのジョブは保存する
+	if err := batch.flush(context.WithoutCancel(ctx)); err != nil {
+		return result, err
+	}
+	result.Created = batch.savedCount()
+	result.Skipped = result.Read - result.Invalid - result.Created
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	u.logger.Info("クロールジョブの取り込みが完了しました", "read", result.Read, "created", result.Created, "skipped", result.Skipped, "invalid", result.Invalid)
+	return result, nil
+}
+
+// eachは、指定したステータスのジョブのうち、最後の更新からolderThan以上経過したものにactionを適用します。
+// 更新日時が記録されていないジョブは、経過時間にかかわらず対象とします。
+//
+// args:
+//
+//	ctx       : コンテキスト
+//	operation : ログに出力する操作の名前
+//	status    : 対象のジョブステータス
+//	olderThan : 対象とする経過時間（0の場合はすべてのジョブ）
+//	dryRun    : trueの場合はactionを適用しない
+//	action    : 対象のジョブに適用する処理
+//
+// return:
+//
+//	int   : actionを適用した（dryRunの場合は対象の）ジョブ数
+//	error : 実行中に発生したエラー
+func (u *maintainCrawlJobUseCase) each(ctx context.Context, operation string, status model.CrawlJobStatus, olderThan time.Duration, dryRun bool, action func(job model.CrawlJob) error) (int, error) {
+	u.logger.Info("ジョブの"+operation+"を開始します", "status", status, "older_than", olderThan.String(), "dry_run", dryRun)
+
+	threshold := time.Now().Add(-olderThan)
+	count := 0
+	failed := 0
+
+	for result := range u.repo.FindListByStatusStream(ctx, status, u.streamOptions) {
+		if result.Err != nil {
+			u.logger.Error("クロールジョブの取得中にエラーが発生しました", "error", result.Err)
+			failed++
+			continue
+		}
+
+		job := result.Job
+		if olderThan > 0 && !job.UpdatedAt().IsZero() && job.UpdatedAt().After(threshold) {
+			continue
+		}
+
+		if dryRun {
+			count++
+			continue
+		}
+		if err := action(job); err != nil {
+			u.logger.Error("ジョブの"+operation+"に失敗しました", "jobID", job.ID(), "url", job.URL(), "error", err)
+			failed++
+			continue
+		}
+		count++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return count, err
+	}
+
+	u.logger.Info("ジョブの"+operation+"が完了しました", "status", status, "count", count, "failed", failed, "dry_run", dryRun)
+	return count, nil
+}
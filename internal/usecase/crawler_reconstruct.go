@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/logger"
+)
+
+// ReconstructCrawlJobArgsは、保存済みのHTMLからクロールジョブを復元するユースケースを構築するための引数を保持します。
+//
+// フィールド:
+//
+//	Repo    : クロールジョブリポジトリ
+//	Loader  : 保存済みのHTMLとメタデータのローダー
+//	HTMLDir : HTMLの保存先（クローラーのoutput_dir）
+//	Logger  : ロガー
+type ReconstructCrawlJobArgs struct {
+	Repo    repository.CrawlJobRepository
+	Loader  infra.HTMLLoader
+	HTMLDir string
+	Logger  logger.AppLogger
+}
+
+// ReconstructResultは、クロールジョブの復元結果です。
+//
+// フィールド:
+//
+//	Restored : 復元した（dryRunの場合は復元する）ジョブ数
+//	Skipped  : すでにSUCCESSのジョブが存在したため復元しなかったHTMLの数
+//	Missing  : メタデータが存在しないため復元できなかったHTMLの数
+//	Failed   : メタデータの読み込みやジョブの保存に失敗したHTMLの数
+type ReconstructResult struct {
+	Restored int
+	Skipped  int
+	Missing  int
+	Failed   int
+}
+
+// reconstructCrawlJobUseCaseは、保存済みのHTMLのメタデータからSUCCESSのクロールジョブを復元するユースケースです。
+// Redisのデータを失った場合に、すべてのページを再クロールせずに取得済みのページを記録し直すために使用します。
+type reconstructCrawlJobUseCase struct {
+	repo    repository.CrawlJobRepository
+	loader  infra.HTMLLoader
+	htmlDir string
+	logger  logger.AppLogger
+}
+
+// NewReconstructCrawlJobUseCaseは、reconstructCrawlJobUseCaseの新しいインスタンスを作成します。
+//
+// args:
+//
+//	args : ReconstructCrawlJobArgs構造体（リポジトリ・ローダー・HTMLの保存先・ロガー）
+//
+// return:
+//
+//	*reconstructCrawlJobUseCase : 生成されたユースケースインスタンス
+func NewReconstructCrawlJobUseCase(args ReconstructCrawlJobArgs) *reconstructCrawlJobUseCase {
+	return &reconstructCrawlJobUseCase{
+		repo:    args.Repo,
+		loader:  args.Loader,
+		htmlDir: args.HTMLDir,
+		logger:  args.Logger,
+	}
+}
+
+// Reconstructは、保存済みのHTMLをすべて走査し、メタデータに記録されたジョブIDとURLからSUCCESSのジョブを保存します。
+// ジョブの更新日時にはHTMLの取得日時を使用します。同じURLのSUCCESSのジョブがすでに存在する場合は上書きしません。
+//
+// args:
+//
+//	ctx    : コンテキスト
+//	dryRun : trueの場合は復元するジョブ数を数えるのみで保存しない
+//
+// return:
+//
+//	ReconstructResult : 復元結果
+//	error             : HTMLの一覧の取得に失敗した場合や、中断された場合のエラー
+func (u *reconstructCrawlJobUseCase) Reconstruct(ctx context.Context, dryRun bool) (ReconstructResult, error) {
+	var result ReconstructResult
+
+	paths, err := u.loader.ListHTMLFilePaths(u.htmlDir)
+	if err != nil {
+		return result, fmt.Errorf("HTMLの一覧の取得に失敗しました: %w", err)
+	}
+	u.logger.Info("クロールジョブの復元を開始します", "html_count", len(paths), "dry_run", dryRun)
+
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		meta, err := u.loader.LoadHTMLMetadata(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// メタデータのない古いクロール結果は、取得元のURLが分からないため復元できない
+			result.Missing++
+			continue
+		}
+		if err != nil {
+			u.logger.Warn("メタデータの読み込みに失敗しました", "path", path, "error", err)
+			result.Failed++
+			continue
+		}
+
+		job, err := model.Reconstruct(meta.JobID, meta.URL, string(model.CrawlJobStatusSuccess), meta.FetchedAt)
+		if err != nil {
+			u.logger.Warn("メタデータからジョブを復元できませんでした", "path", path, "job_id", meta.JobID, "url", meta.URL, "error", err)
+			result.Failed++
+			continue
+		}
+		job = job.WithListingPosition(model.ListingPosition{Page: meta.ListPage, Position: meta.ListPosition})
+
+		exists, err := u.repo.Exists(ctx, job)
+		if err != nil {
+			u.logger.Error("ジョブの存在確認に失敗しました", "url", job.URL(), "error", err)
+			result.Failed++
+			continue
+		}
+		if exists {
+			result.Skipped++
+			continue
+		}
+
+		if !dryRun {
+			if err := u.repo.Save(ctx, job); err != nil {
+				u.logger.Error("ジョブの保存に失敗しました", "url", job.URL(), "error", err)
+				result.Failed++
+				continue
+			}
+		}
+		result.Restored++
+	}
+
+	u.logger.Info("クロールジョブの復元が完了しました",
+		"restored", result.Restored,
+		"skipped", result.Skipped,
+		"missing", result.Missing,
+		"failed", result.Failed,
+		"dry_run", dryRun,
+	)
+	return result, nil
+}
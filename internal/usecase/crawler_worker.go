@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/domain/repository"
+	"github.com/nrad-K/go-crawler/internal/infra"
+	"github.com/nrad-K/go-crawler/internal/logger"
+)
+
+// crawlWorkerHeartbeatは、実行中のワーカーの状態を定期的にリポジトリへ報告します。
+// 複数のマシンで1つのキューを共有している場合に、どのワーカーが動作しているかとワーカーごとの処理件数を確認し、
+// 停止したワーカーが獲得したままのジョブを検出できるようにします。
+// ページの読み込みや一時停止の間も報告が途絶えないよう、ジョブの処理とは別のゴルーチンで報告します。
+//
+// フィールド:
+//
+//	workers : ワーカーの状態を保存するリポジトリ
+//	logger  : ロガー
+//	mu      : workerの排他制御
+//	worker  : 報告するワーカーの状態
+//	stop    : 報告を止める関数
+//	done    : 報告のゴルーチンが終了したことを通知するチャネル
+type crawlWorkerHeartbeat struct {
+	workers repository.CrawlWorkerRepository
+	logger  logger.AppLogger
+	mu      sync.Mutex
+	worker  model.CrawlWorker
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+// startCrawlWorkerHeartbeatは、ワーカーの状態の報告を開始します。リポジトリがnilの場合は報告せずにnilを返します。
+//
+// args:
+//
+//	ctx      : コンテキスト
+//	workers  : ワーカーの状態を保存するリポジトリ
+//	workerID : ワーカーのID
+//	role     : ワーカーが実行している処理
+//	logger   : ロガー
+//
+// return:
+//
+//	*crawlWorkerHeartbeat : 報告を行うインスタンス（リポジトリがnilの場合はnil）
+func startCrawlWorkerHeartbeat(ctx context.Context, workers repository.CrawlWorkerRepository, workerID string, role model.CrawlWorkerRole, logger logger.AppLogger) *crawlWorkerHeartbeat {
+	if workers == nil {
+		return nil
+	}
+
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	// 中断された後も停止までの処理件数を報告できるよう、キャンセルは伝播させずstopで止める
+	heartbeatCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	h := &crawlWorkerHeartbeat{
+		workers: workers,
+		logger:  logger,
+		worker:  model.CrawlWorker{ID: workerID, Role: role, Hostname: hostname, StartedAt: now, LastSeen: now},
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+
+	h.report(heartbeatCtx)
+	go func() {
+		defer close(h.done)
+		ticker := time.NewTicker(infra.CrawlWorkerHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				h.report(heartbeatCtx)
+			}
+		}
+	}()
+	return h
+}
+
+// reportは、現在のワーカーの状態をリポジトリに保存します。保存に失敗してもクロールは続行します。
+func (h *crawlWorkerHeartbeat) report(ctx context.Context) {
+	h.mu.Lock()
+	h.worker.LastSeen = time.Now()
+	worker := h.worker
+	h.mu.Unlock()
+
+	if err := h.workers.Heartbeat(ctx, worker); err != nil && ctx.Err() == nil {
+		h.logger.Warn("ワーカーの状態を報告できませんでした", "worker_id", worker.ID, "error", err)
+	}
+}
+
+// processingは、処理を開始したジョブまたは一覧ページのURLを記録します。
+func (h *crawlWorkerHeartbeat) processing(url string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.worker.CurrentURL = url
+}
+
+// processedは、ジョブの処理を終えた時点の処理件数を記録します。
+func (h *crawlWorkerHeartbeat) processed(success, failed, blocked int) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.worker.Success = success
+	h.worker.Failed = failed
+	h.worker.Blocked = blocked
+	h.worker.CurrentURL = ""
+}
+
+// generatedは、一覧ページの処理を終えた時点の生成したジョブ数を記録します。
+func (h *crawlWorkerHeartbeat) generated(count int) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.worker.Generated = count
+	h.worker.CurrentURL = ""
+}
+
+// finishは、報告を止めてワーカーの状態を削除します。
+func (h *crawlWorkerHeartbeat) finish(ctx context.Context) {
+	if h == nil {
+		return
+	}
+	h.stop()
+	<-h.done
+
+	if err := h.workers.Remove(context.WithoutCancel(ctx), h.worker.ID); err != nil {
+		h.logger.Warn("ワーカーの状態を削除できませんでした", "worker_id", h.worker.ID, "error", err)
+	}
+}
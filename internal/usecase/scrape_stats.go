@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nrad-K/go-crawler/internal/domain/model"
+	"github.com/nrad-K/go-crawler/internal/infra"
+)
+
+// completenessFieldは、充足率を集計する項目と、求人情報にその項目の値があるかの判定です。
+type completenessField struct {
+	name   string
+	filled func(p *model.JobPosting) bool
+}
+
+// completenessFieldsは、充足率を集計する項目です。項目名と順序はconfig.ScrapeFieldNamesと同じです。
+var completenessFields = []completenessField{
+	{"title", func(p *model.JobPosting) bool { return p.Title() != "" }},
+	{"company_name", func(p *model.JobPosting) bool { return p.CompanyName() != "" }},
+	{"summary_url", func(p *model.JobPosting) bool { return p.SummaryURL() != "" }},
+	{"location", func(p *model.JobPosting) bool {
+		for _, location := range p.Locations() {
+			if location.PrefectureName() != "" {
+				return true
+			}
+		}
+		return false
+	}},
+	{"headquarters", func(p *model.JobPosting) bool { return p.Headquarters().PrefectureName() != "" }},
+	{"job_type", func(p *model.JobPosting) bool { return p.JobType() != "" && p.JobType() != model.Unknown }},
+	{"salary", func(p *model.JobPosting) bool {
+		minAmount := p.Salary().MinAmount()
+		return minAmount.Format() != ""
+	}},
+	{"posted_at", func(p *model.JobPosting) bool { return !p.PostedAt().IsZero() }},
+	{"expires_at", func(p *model.JobPosting) bool { return !p.ExpiresAt().IsZero() }},
+	{"job_name", func(p *model.JobPosting) bool { return p.Details().JobName() != "" }},
+	{"description", func(p *model.JobPosting) bool { return p.Details().Description() != "" }},
+	{"requirements", func(p *model.JobPosting) bool { return p.Details().Requirements() != "" }},
+	{"workplace_type", func(p *model.JobPosting) bool {
+		workplaceType := p.Details().WorkplaceType()
+		return workplaceType != "" && workplaceType != model.UnknownWorkplace
+	}},
+	{"holidays_per_year", func(p *model.JobPosting) bool { return p.Details().HolidaysPerYear() != nil }},
+	{"holiday_policy", func(p *model.JobPosting) bool {
+		holidayPolicy := p.Details().HolidayPolicy()
+		return holidayPolicy != "" && holidayPolicy != model.UnknownHoliday
+	}},
+	{"work_hours", func(p *model.JobPosting) bool { return p.Details().WorkHours() != "" }},
+	{"raise", func(p *model.JobPosting) bool { return p.Details().Raise() != nil }},
+	{"bonus", func(p *model.JobPosting) bool { return p.Details().Bonus() != nil }},
+	{"access", func(p *model.JobPosting) bool { return p.Details().NearestStation().Name() != "" }},
+	{"contract_period", func(p *model.JobPosting) bool {
+		contractPeriod := p.Details().ContractPeriod()
+		return contractPeriod.Months() != nil || contractPeriod.LongTerm() || contractPeriod.Renewable() != nil
+	}},
+	{"probation", func(p *model.JobPosting) bool {
+		probationPeriod := p.Details().ProbationPeriod()
+		return probationPeriod.Months() != nil || probationPeriod.ConditionChanged() != nil
+	}},
+	{"smoking_policy", func(p *model.JobPosting) bool {
+		smokingPolicy := p.Details().SmokingPolicy()
+		return smokingPolicy != "" && smokingPolicy != model.UnknownSmoking
+	}},
+	{"benefits", func(p *model.JobPosting) bool { return p.Details().Benefits().RawBenefits() != "" }},
+}
+
+// scrapeStatsCollectorは、出力した求人情報の項目ごとの充足率と、パースに失敗した件数を集計します。
+// パースの失敗は並列に動作するワーカーから記録されるため、排他制御を行います。
+//
+// フィールド:
+//
+//	mu            : parseFailuresとincompleteへのアクセスを保護するミューテックス
+//	parseFailures : 項目ごとのパースに失敗した件数
+//	incomplete    : 必須の項目が欠けていたため出力しなかった求人情報の件数
+//	filled        : 項目ごとの値が得られた求人情報の件数（completenessFieldsと同じ順）
+//	written       : 集計の対象とした求人情報の件数
+type scrapeStatsCollector struct {
+	mu            sync.Mutex
+	parseFailures map[string]int
+	incomplete    int
+	filled        []int
+	written       int
+}
+
+// missingFieldsは、求人情報に値がない必須の項目を、指定された順に返します。
+//
+// args:
+//
+//	posting  : 確認する求人情報
+//	required : 必須の項目名
+//
+// return:
+//
+//	[]string : 値がない項目名（すべて揃っている場合は空）
+func missingFields(posting *model.JobPosting, required []string) []string {
+	var missing []string
+	for _, name := range required {
+		for _, field := range completenessFields {
+			if field.name == name && !field.filled(posting) {
+				missing = append(missing, name)
+			}
+		}
+	}
+	return missing
+}
+
+// addIncompleteは、必須の項目が欠けていたため求人情報を出力しなかったことを記録します。
+func (c *scrapeStatsCollector) addIncomplete() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.incomplete++
+}
+
+// addParseFailureは、項目のパースに失敗したことを記録します。
+//
+// args:
+//
+//	field : パースに失敗した項目名
+func (c *scrapeStatsCollector) addParseFailure(field string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.parseFailures == nil {
+		c.parseFailures = make(map[string]int)
+	}
+	c.parseFailures[field]++
+}
+
+// addPostingは、出力した求人情報の各項目に値があるかを集計します。
+// 出力は1つのゴルーチンで行うため、排他制御は行いません。
+//
+// args:
+//
+//	posting : 出力した求人情報
+func (c *scrapeStatsCollector) addPosting(posting *model.JobPosting) {
+	if c.filled == nil {
+		c.filled = make([]int, len(completenessFields))
+	}
+	for i, field := range completenessFields {
+		if field.filled(posting) {
+			c.filled[i]++
+		}
+	}
+	c.written++
+}
+
+// statsは、集計した充足率とパースに失敗した件数を返します。
+//
+// return:
+//
+//	infra.ScrapeStats : 集計結果
+func (c *scrapeStatsCollector) stats() infra.ScrapeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := infra.ScrapeStats{
+		Written:       c.written,
+		Incomplete:    c.incomplete,
+		FillRates:     make([]infra.FieldFillRate, 0, len(completenessFields)),
+		ParseFailures: make(map[string]int, len(c.parseFailures)),
+	}
+	for i, field := range completenessFields {
+		rate := infra.FieldFillRate{Field: field.name}
+		if c.written > 0 {
+			rate.Filled = c.filled[i]
+			rate.Rate = float64(rate.Filled) / float64(c.written)
+		}
+		stats.FillRates = append(stats.FillRates, rate)
+	}
+	for field, count := range c.parseFailures {
+		stats.ParseFailures[field] = count
+	}
+	return stats
+}
+
+// logScrapeStatsは、項目ごとの充足率とパースに失敗した件数をログに出力します。
+func (u *saveJobPostingFromHTMLUseCase) logScrapeStats(stats infra.ScrapeStats) {
+	if stats.Incomplete > 0 {
+		u.logger.Warn("必須の項目が欠けていたため出力しなかった求人情報があります", "count", stats.Incomplete, "required_fields", u.cfg.RequiredFields)
+	}
+	if stats.Written == 0 {
+		return
+	}
+
+	rates := make([]any, 0, len(stats.FillRates)*2)
+	for _, rate := range stats.FillRates {
+		rates = append(rates, rate.Field, fmt.Sprintf("%.1f%%", rate.Rate*100))
+	}
+	u.logger.Info("項目ごとの充足率を集計しました", rates...)
+
+	if len(stats.ParseFailures) > 0 {
+		failures := make([]any, 0, len(stats.ParseFailures)*2)
+		for _, field := range completenessFields {
+			if count, ok := stats.ParseFailures[field.name]; ok {
+				failures = append(failures, field.name, count)
+			}
+		}
+		u.logger.Warn("パースに失敗した項目があります", failures...)
+	}
+}
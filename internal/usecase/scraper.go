@@ -2,8 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/nrad-K/go-crawler/internal/config"
 	"github.com/nrad-K/go-crawler/internal/constants"
@@ -16,29 +21,51 @@ import (
 //
 // フィールド:
 //
-//	Loader   : HTMLファイルのローダー
-//	Document : HTMLドキュメントのパーサー
-//	Exporter : ファイルエクスポーター
-//	Cfg      : スクレイパーの設定情報
-//	Parser   : 求人情報のパーサー
-//	Logger   : ロガー
+//	Loader      : HTMLのローダー（ローカルのディレクトリまたはRedis）
+//	HTMLParser  : HTMLの解析器（1つのHTMLは1回だけ解析し、すべての項目の抽出に使用する）
+//	Exporter    : ファイルエクスポーター
+//	Cfg         : スクレイパーの設定情報
+//	Parser      : 求人情報のパーサー
+//	Hook        : 抽出・出力時に呼び出すフック（nilの場合は呼び出さない）
+//	Progress    : HTMLファイルの処理の進捗を報告するレポーター（nilの場合は報告しない）
+//	Expiry      : 掲載終了の判定器（nilの場合はクロール時の判定結果のみを使用する）
+//	Enricher    : 抽出した求人情報に導出した項目を付加する処理（nilの場合は付加しない）
+//	Logger      : ロガー
+//	ChangedOnly : trueの場合は前回のクロールから内容が変更されていないページを出力しない
 type ScraperArgs struct {
-	Loader   infra.HTMLFileLoader
-	Document infra.HTMLDocument
-	Exporter infra.FileExporter
-	Cfg      config.ScraperConfig
-	Parser   infra.JobPostingParser
-	Logger   logger.AppLogger
+	Loader      infra.HTMLLoader
+	HTMLParser  infra.HTMLParser
+	Exporter    infra.FileExporter
+	Cfg         config.ScraperConfig
+	Parser      infra.JobPostingParser
+	Hook        infra.Hook
+	Progress    infra.ProgressReporter
+	Expiry      *infra.ExpiryDetector
+	Enricher    infra.JobPostingEnricher
+	Logger      logger.AppLogger
+	ChangedOnly bool
 }
 
+var (
+	// errPostingExpiredは、掲載終了と判定したため出力しない求人であることを表すエラーです。
+	errPostingExpired = errors.New("掲載終了の求人です")
+	// errPostingUnchangedは、前回のクロールから内容が変更されていないため出力しない求人であることを表すエラーです。
+	errPostingUnchanged = errors.New("前回のクロールから変更されていない求人です")
+)
+
 // saveJobPostingFromHTMLUseCaseは、HTMLファイルから求人情報を抽出し、保存するユースケースです。
 type saveJobPostingFromHTMLUseCase struct {
-	loader   infra.HTMLFileLoader
-	document infra.HTMLDocument
-	exporter infra.FileExporter
-	cfg      config.ScraperConfig
-	parser   infra.JobPostingParser
-	logger   logger.AppLogger
+	loader      infra.HTMLLoader
+	htmlParser  infra.HTMLParser
+	exporter    infra.FileExporter
+	cfg         config.ScraperConfig
+	parser      infra.JobPostingParser
+	hook        infra.Hook
+	progress    infra.ProgressReporter
+	expiry      *infra.ExpiryDetector
+	enricher    infra.JobPostingEnricher
+	logger      logger.AppLogger
+	changedOnly bool
 }
 
 // NewSaveJobPostingFromHTMLUseCaseは、saveJobPostingFromHTMLUseCaseの新しいインスタンスを生成します。
@@ -52,15 +79,42 @@ type saveJobPostingFromHTMLUseCase struct {
 //	*saveJobPostingFromHTMLUseCase : 生成されたユースケースインスタンス
 func NewSaveJobPostingFromHTMLUseCase(args ScraperArgs) *saveJobPostingFromHTMLUseCase {
 	return &saveJobPostingFromHTMLUseCase{
-		loader:   args.Loader,
-		document: args.Document,
-		exporter: args.Exporter,
-		cfg:      args.Cfg,
-		parser:   args.Parser,
-		logger:   args.Logger,
+		loader:      args.Loader,
+		htmlParser:  args.HTMLParser,
+		exporter:    args.Exporter,
+		cfg:         args.Cfg,
+		parser:      args.Parser,
+		hook:        args.Hook,
+		progress:    args.Progress,
+		expiry:      args.Expiry,
+		enricher:    args.Enricher,
+		logger:      args.Logger,
+		changedOnly: args.ChangedOnly,
 	}
 }
 
+// ScrapeResultは、スクレイピングの1回の実行結果です。
+//
+// フィールド:
+//
+//	Processed : 処理したHTMLの数
+//	Failed    : 処理に失敗したHTMLの数
+//	Written   : 出力した求人情報の件数
+//	Stats     : 出力した求人情報の項目ごとの充足率とパースに失敗した件数
+type ScrapeResult struct {
+	Processed int
+	Failed    int
+	Written   int
+	Stats     infra.ScrapeStats
+}
+
+// scrapeCountersは、並列に動作するワーカーが処理したHTMLの数と、項目ごとの充足率を集計します。
+type scrapeCounters struct {
+	processed atomic.Int64
+	failed    atomic.Int64
+	stats     scrapeStatsCollector
+}
+
 // SaveJobPostingCSVは、指定されたディレクトリからHTMLファイルを読み込み、
 // 求人情報を抽出してCSVファイルに保存するメインの処理です。
 //
@@ -70,64 +124,152 @@ func NewSaveJobPostingFromHTMLUseCase(args ScraperArgs) *saveJobPostingFromHTMLU
 //
 // return:
 //
-//	error : 処理中に発生したエラー
-func (u *saveJobPostingFromHTMLUseCase) SaveJobPostingCSV(ctx context.Context) error {
+//	ScrapeResult : 処理したHTMLの数と出力した求人情報の件数、項目ごとの充足率
+//	error        : 処理中に発生したエラー
+func (u *saveJobPostingFromHTMLUseCase) SaveJobPostingCSV(ctx context.Context) (ScrapeResult, error) {
 	u.logger.Info("HTMLファイルパスの一覧を取得します...")
 	dirpaths, err := u.loader.ListHTMLFilePaths(u.cfg.HtmlDir)
 	if err != nil {
 		u.logger.Error("HTMLファイルの一覧取得に失敗しました", "error", err)
-		return fmt.Errorf("HTMLファイルの一覧取得に失敗しました: %w", err)
+		u.exporter.Abort()
+		return ScrapeResult{}, fmt.Errorf("HTMLファイルの一覧取得に失敗しました: %w", err)
 	}
 
-	jobs := make(chan string, len(dirpaths))
-	jobPosting := make(chan model.JobPosting, len(dirpaths))
+	if u.hook == nil {
+		u.hook = infra.NopHook{}
+	}
+	if u.progress == nil {
+		u.progress = infra.NopProgressReporter{}
+	}
+	if u.enricher == nil {
+		u.enricher = infra.EnricherChain{}
+	}
+	u.progress.Start(len(dirpaths))
+
+	maxWorkers := u.cfg.MaxWorkersOrDefault()
+	u.logger.Info("HTMLファイルの処理を開始します", "files", len(dirpaths), "workers", maxWorkers)
+
+	// 件数が多くてもメモリの使用量が増えないよう、チャネルの容量はワーカー数に合わせる
+	jobs := make(chan string, maxWorkers)
+	jobPosting := make(chan model.JobPosting, maxWorkers)
 	var wg sync.WaitGroup
+	var counters scrapeCounters
+
+	// 異常終了した場合も抽出済みの求人情報を失わないよう、ワーカーの処理と並行して出力する
+	written := make(chan int, 1)
+	go func() {
+		written <- u.writeJobPostings(ctx, jobPosting, &counters)
+	}()
 
-	for i := 0; i < u.cfg.MaxWorkers; i++ {
+	for i := 0; i < maxWorkers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			u.worker(ctx, jobs, jobPosting)
+			u.worker(ctx, jobs, jobPosting, &counters)
 		}()
 	}
 
+	// 中断された場合にワーカーの終了を待ち続けないよう、残りのファイルは投入しない
+feed:
 	for _, path := range dirpaths {
-		jobs <- path
+		select {
+		case jobs <- path:
+		case <-ctx.Done():
+			break feed
+		}
 	}
 	close(jobs)
 
 	wg.Wait()
 	close(jobPosting)
+	writtenCount := <-written
+	u.progress.Finish()
 
-	writtenCount := 0
-	for post := range jobPosting {
-		if err := u.exporter.Write(post); err != nil {
-			u.logger.Error("求人情報の書き込みに失敗しました", "error", err)
-			continue
-		}
-		writtenCount++
-		if writtenCount%constants.LogBatchCount == 0 {
-			u.logger.Info("求人情報を書き込みました。", "count", writtenCount)
+	result := ScrapeResult{
+		Processed: int(counters.processed.Load()),
+		Failed:    int(counters.failed.Load()),
+		Written:   writtenCount,
+		Stats:     counters.stats.stats(),
+	}
+
+	if err := ctx.Err(); err != nil {
+		// 中断された場合は出力を確定させず、keep_partialが有効なら書き込み済みの行を.partialとして残す
+		u.logger.Warn("中断されたため、出力を確定せずに終了します", "written_count", writtenCount)
+		if abortErr := u.exporter.Abort(); abortErr != nil {
+			u.logger.Error("exporterの後始末に失敗しました", "error", abortErr)
 		}
+		return result, fmt.Errorf("スクレイピングが中断されました: %w", err)
 	}
 
 	if err := u.exporter.Close(); err != nil {
 		u.logger.Error("exporterのクローズに失敗しました", "error", err)
-		return fmt.Errorf("exporterのクローズに失敗しました: %w", err)
+		return result, fmt.Errorf("exporterのクローズに失敗しました: %w", err)
 	}
 
+	u.logScrapeStats(result.Stats)
 	u.logger.Info("スクレイピング処理が完了しました。", "total_count", writtenCount)
-	return nil
+	return result, nil
+}
+
+// writeJobPostingsは、ワーカーが抽出した求人情報を受け取った順に出力します。
+// 出力先がバッファを持つ場合は、constants.ExportFlushIntervalごとに出力先へ書き出します。
+// 出力は1つのゴルーチンで行うため、エクスポーターは並行呼び出しに対して安全である必要はありません。
+//
+// args:
+//
+//	ctx      : コンテキスト
+//	results  : 出力する求人情報を受信するチャネル（クローズされるまで出力を続けます）
+//	counters : 充足率の集計先
+//
+// return:
+//
+//	int : 出力した求人情報の件数
+func (u *saveJobPostingFromHTMLUseCase) writeJobPostings(ctx context.Context, results <-chan model.JobPosting, counters *scrapeCounters) int {
+	flushTicker := time.NewTicker(constants.ExportFlushInterval)
+	defer flushTicker.Stop()
+
+	writtenCount := 0
+	for {
+		select {
+		case post, ok := <-results:
+			if !ok {
+				return writtenCount
+			}
+			if err := u.exporter.Write(post); err != nil {
+				if errors.Is(err, infra.ErrDuplicatePosting) {
+					continue
+				}
+				u.logger.Error("求人情報の書き込みに失敗しました", "error", err)
+				continue
+			}
+			writtenCount++
+			counters.stats.addPosting(&post)
+			if err := u.hook.OnRowExported(ctx, post); err != nil {
+				u.logger.Warn("row_exportedフックの呼び出しに失敗しました", "url", post.SummaryURL(), "error", err)
+			}
+			if writtenCount%constants.LogBatchCount == 0 {
+				u.logger.Info("求人情報を書き込みました。", "count", writtenCount)
+			}
+
+		case <-flushTicker.C:
+			if flusher, ok := u.exporter.(infra.ExportFlusher); ok {
+				if err := flusher.Flush(); err != nil {
+					u.logger.Warn("出力のフラッシュに失敗しました", "error", err)
+				}
+			}
+		}
+	}
 }
 
 // workerは、ファイルパスを受け取って処理し、結果をチャネルに送信するワーカー関数です。
 //
 // args:
 //
-//	ctx     : コンテキスト
-//	jobs    : 処理対象のファイルパスを受信するチャネル
-//	results : 処理結果の求人情報を送信するチャネル
-func (u *saveJobPostingFromHTMLUseCase) worker(ctx context.Context, jobs <-chan string, results chan<- model.JobPosting) {
+//	ctx      : コンテキスト
+//	jobs     : 処理対象のファイルパスを受信するチャネル
+//	results  : 処理結果の求人情報を送信するチャネル
+//	counters : 処理したHTMLの数と充足率の集計先
+func (u *saveJobPostingFromHTMLUseCase) worker(ctx context.Context, jobs <-chan string, results chan<- model.JobPosting, counters *scrapeCounters) {
 	for path := range jobs {
 		select {
 
@@ -135,9 +277,41 @@ func (u *saveJobPostingFromHTMLUseCase) worker(ctx context.Context, jobs <-chan
 			return
 
 		default:
-			extractJobPosting, err := u.processFile(path)
+			extractJobPosting, err := u.processFile(path, &counters.stats)
+			u.progress.Increment()
+			counters.processed.Add(1)
+			if errors.Is(err, errPostingExpired) {
+				u.logger.Info("掲載終了の求人のため出力しません", "path", path, "reason", err)
+				continue
+			}
+			if errors.Is(err, errPostingUnchanged) {
+				continue
+			}
 			if err != nil {
 				u.logger.Error("求人情報の処理に失敗しました", "path", path, "error", err)
+				counters.failed.Add(1)
+				continue
+			}
+
+			// フックにも導出した項目を渡せるよう、フックの呼び出しより前に付加する
+			extractJobPosting = u.enricher.Enrich(extractJobPosting)
+
+			// フックの呼び出しに失敗した場合は、求人情報を失わないよう抽出した値のまま出力する
+			hookedJobPosting, err := u.hook.OnPostingParsed(ctx, extractJobPosting)
+			switch {
+			case errors.Is(err, infra.ErrHookVeto):
+				u.logger.Info("フックによって求人情報の出力が拒否されました", "path", path, "reason", err)
+				continue
+			case err != nil:
+				u.logger.Warn("posting_parsedフックの呼び出しに失敗しました", "path", path, "error", err)
+			default:
+				extractJobPosting = hookedJobPosting
+			}
+
+			// 空の列ばかりの行を出力しないよう、必須の項目が欠けている求人情報は出力しない
+			if missing := missingFields(&extractJobPosting, u.cfg.RequiredFields); len(missing) > 0 {
+				u.logger.Info("必須の項目が得られなかったため出力しません", "path", path, "missing", strings.Join(missing, ","))
+				counters.stats.addIncomplete()
 				continue
 			}
 
@@ -154,58 +328,129 @@ func (u *saveJobPostingFromHTMLUseCase) worker(ctx context.Context, jobs <-chan
 //
 // args:
 //
-//	path : 処理対象のHTMLファイルのパス
+//	path  : 処理対象のHTMLファイルのパス
+//	stats : パースに失敗した件数の集計先
 //
 // return:
 //
 //	model.JobPosting : 抽出された求人情報
 //	error            : ファイルの読み込みや処理中に発生したエラー
-func (u *saveJobPostingFromHTMLUseCase) processFile(path string) (model.JobPosting, error) {
+func (u *saveJobPostingFromHTMLUseCase) processFile(path string, stats *scrapeStatsCollector) (model.JobPosting, error) {
 	htmlContent, err := u.loader.LoadHTMLFile(path)
+	if errors.Is(err, infra.ErrHTMLTruncated) {
+		u.logger.Warn("HTMLが上限サイズを超えたため、途中までを解析します", "path", path)
+		err = nil
+	}
 	if err != nil {
 		return model.JobPosting{}, fmt.Errorf("HTMLファイルの読み込みに失敗しました: %w", err)
 	}
 
-	extractJobPosting := u.extractJobPosting(htmlContent)
+	// サイドカーが存在しない場合（古いクロール結果など）はセレクターの値のみを使用する
+	meta, err := u.loader.LoadHTMLMetadata(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		u.logger.Warn("メタデータの読み込みに失敗しました", "path", path, "error", err)
+	}
+
+	if u.changedOnly && meta.Unchanged {
+		return model.JobPosting{}, errPostingUnchanged
+	}
+
+	// クロール時に判定していない場合は、スクレイパーの判定条件で判定する
+	if meta.ExpiredReason == "" && u.expiry != nil {
+		if reason, expired := u.expiry.Detect(htmlContent, meta.URL, meta.FinalURL); expired {
+			meta.ExpiredReason = reason
+		}
+	}
+	if meta.ExpiredReason != "" && u.cfg.Expired.ActionOrDefault() == config.ExpiredSkip {
+		return model.JobPosting{}, fmt.Errorf("%w: %s", errPostingExpired, meta.ExpiredReason)
+	}
+
+	// 相対的な掲載日（例: 3日前）はクロール日時を基準に解析する。メタデータがない場合はファイルの更新日時を使用する
+	reference := meta.FetchedAt
+	if reference.IsZero() {
+		if modTimer, ok := u.loader.(infra.HTMLModTimer); ok {
+			reference, _ = modTimer.HTMLModTime(path)
+		}
+	}
+
+	// 項目ごとにHTMLを解析し直さないよう、1回だけ解析してすべての項目の抽出に使用する
+	document, err := u.htmlParser.Parse(htmlContent)
+	if err != nil {
+		return model.JobPosting{}, fmt.Errorf("HTMLの解析に失敗しました: %w", err)
+	}
+
+	extractJobPosting := u.extractJobPosting(document, meta, reference, stats)
 	return extractJobPosting, nil
 }
 
 // extractJobPostingは、HTMLコンテンツから求人情報の詳細を抽出し、JobPostingオブジェクトを生成します。
+// メタデータが存在する場合、取得元URLとクロール日時はメタデータの値を優先します。
+// extractionがjson_ldの場合、タイトル・会社名・勤務地・給与・掲載日はJSON-LDの値を優先します。
+// meta_fallbackが有効な場合、値が得られなかったタイトル・URL・掲載日・募集要項はメタタグの値で補います。
 //
 // args:
 //
-//	htmlContent : 解析対象のHTMLコンテンツ
+//	document  : 解析済みのHTML
+//	meta      : HTMLのメタデータ（サイドカーが存在しない場合はゼロ値）
+//	reference : 相対的な掲載日の基準とする日時（不明な場合はゼロ値）
+//	stats     : パースに失敗した件数の集計先
 //
 // return:
 //
 //	model.JobPosting : 抽出された情報を持つJobPostingオブジェクト
-func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) model.JobPosting {
+func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(document infra.HTMLDocument, meta infra.HTMLMetadata, reference time.Time, stats *scrapeStatsCollector) model.JobPosting {
 	var args model.JobPostingArgs
-	// タイトルを抽出
-	extractedTitles, err := u.extractValues(htmlContent, u.cfg.Title)
-	if err != nil {
-		u.logger.Warn("タイトルの抽出に失敗しました", "error", err)
+	args.CrawledAt = meta.FetchedAt
+	args.Expired = meta.ExpiredReason != ""
+	args.Listing = model.ListingPosition{Page: meta.ListPage, Position: meta.ListPosition}
+
+	// JSON-LDのJobPostingを優先し、含まれない項目はCSSセレクターで抽出する
+	var ld infra.JSONLDJobPosting
+	if u.cfg.ExtractionOrDefault() == config.ExtractByJSONLD {
+		ld, _ = document.ExtractJobPostingJSONLD()
 	}
-	if len(extractedTitles) > 0 {
-		args.Title = extractedTitles[0]
+
+	// タイトルを抽出
+	if ld.Title != "" {
+		args.Title = ld.Title
+	} else {
+		extractedTitles, err := u.extractValues(document, u.cfg.Title)
+		if err != nil {
+			u.logger.Warn("タイトルの抽出に失敗しました", "error", err)
+		}
+		if len(extractedTitles) > 0 {
+			args.Title = extractedTitles[0]
+		}
 	}
 
 	// Locationを抽出
-	extractedLocation, err := u.extractValues(htmlContent, u.cfg.Location)
-	if err != nil {
-		u.logger.Warn("勤務地の抽出に失敗しました", "error", err)
-	}
-	if len(extractedLocation) > 0 {
-		location, err := u.parser.ParseLocation(extractedLocation[0])
+	if len(ld.Locations) > 0 {
+		for _, address := range ld.Locations {
+			location, err := u.parser.ParseLocation(address)
+			if err != nil {
+				u.logger.Warn("勤務地のパースに失敗しました", "error", err)
+				stats.addParseFailure("location")
+			}
+			args.Locations = append(args.Locations, location)
+		}
+	} else {
+		extractedLocation, err := u.extractValues(document, u.cfg.Location)
 		if err != nil {
-			u.logger.Warn("勤務地のパースに失敗しました", "error", err)
+			u.logger.Warn("勤務地の抽出に失敗しました", "error", err)
 		}
+		if len(extractedLocation) > 0 {
+			locations, err := u.parser.ParseLocations(extractedLocation[0])
+			if err != nil {
+				u.logger.Warn("勤務地のパースに失敗しました", "error", err)
+				stats.addParseFailure("location")
+			}
 
-		args.Location = location
+			args.Locations = locations
+		}
 	}
 
 	// Headquarters（本社所在地）の抽出
-	extractedHeadquarters, err := u.extractValues(htmlContent, u.cfg.Headquarters)
+	extractedHeadquarters, err := u.extractValues(document, u.cfg.Headquarters)
 	if err != nil {
 		u.logger.Warn("本社所在地の抽出に失敗しました", "error", err)
 	}
@@ -213,31 +458,39 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 		headquarters, err := u.parser.ParseLocation(extractedHeadquarters[0])
 		if err != nil {
 			u.logger.Warn("本社所在地のパースに失敗しました", "error", err)
+			stats.addParseFailure("headquarters")
 		}
 
 		args.Headquarters = headquarters
 	}
 
 	// 会社名を抽出
-	extractedCompanyNames, err := u.extractValues(htmlContent, u.cfg.CompanyName)
-	if err != nil {
-		u.logger.Warn("会社名の抽出に失敗しました", "error", err)
-	}
-	if len(extractedCompanyNames) > 0 {
-		args.CompanyName = extractedCompanyNames[0]
+	if ld.CompanyName != "" {
+		args.CompanyName = ld.CompanyName
+	} else {
+		extractedCompanyNames, err := u.extractValues(document, u.cfg.CompanyName)
+		if err != nil {
+			u.logger.Warn("会社名の抽出に失敗しました", "error", err)
+		}
+		if len(extractedCompanyNames) > 0 {
+			args.CompanyName = extractedCompanyNames[0]
+		}
 	}
 
 	// 概要URLを抽出
-	extractedSummaryURLs, err := u.extractValues(htmlContent, u.cfg.SummaryURL)
+	extractedSummaryURLs, err := u.extractValues(document, u.cfg.SummaryURL)
 	if err != nil {
 		u.logger.Warn("概要URLの抽出に失敗しました", "error", err)
 	}
 	if len(extractedSummaryURLs) > 0 {
 		args.SummaryURL = extractedSummaryURLs[0]
 	}
+	if meta.URL != "" {
+		args.SummaryURL = meta.URL
+	}
 
 	// JobTypeを抽出
-	extractedJobTypesStr, err := u.extractValues(htmlContent, u.cfg.JobType)
+	extractedJobTypesStr, err := u.extractValues(document, u.cfg.JobType)
 	if err != nil {
 		u.logger.Warn("JobTypeの抽出に失敗しました", "error", err)
 	}
@@ -246,40 +499,68 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// Salaryを抽出
-	var salaryStr string
-	extractedSalaryStrs, err := u.document.ExtractText(htmlContent, u.cfg.Salary.Selector)
-	if err != nil {
-		u.logger.Warn("給与情報の抽出に失敗しました", "error", err)
-	}
-	if len(extractedSalaryStrs) > 0 {
-		salaryStr = extractedSalaryStrs[0]
-	}
+	if ld.HasSalary {
+		args.Salary = ld.Salary
+	} else {
+		var salaryStr string
+		extractedSalaryStrs, err := document.ExtractText(u.cfg.Salary.Selector)
+		if err != nil {
+			u.logger.Warn("給与情報の抽出に失敗しました", "error", err)
+		}
+		if len(extractedSalaryStrs) > 0 {
+			salaryStr = extractedSalaryStrs[0]
+		}
 
-	salary, err := u.parser.ParseSalaryDetails(salaryStr)
-	// 空文字列のパースエラーはログに出さない
-	if err != nil && salaryStr != "" {
-		u.logger.Warn("給与情報のパースに失敗しました", "error", err)
+		salary, err := u.parser.ParseSalaryDetails(salaryStr)
+		// 空文字列のパースエラーはログに出さない
+		if err != nil && salaryStr != "" {
+			u.logger.Warn("給与情報のパースに失敗しました", "error", err)
+			stats.addParseFailure("salary")
+		}
+		args.Salary = salary
 	}
-	args.Salary = salary
 
 	// PostedAtを抽出
-	extractedPostedAtStr, err := u.extractValues(htmlContent, u.cfg.PostedAt)
-	if err != nil {
-		u.logger.Warn("PostedAtの抽出に失敗しました", "error", err)
+	if !ld.DatePosted.IsZero() {
+		args.PostedAt = ld.DatePosted
+	} else {
+		extractedPostedAtStr, err := u.extractValues(document, u.cfg.PostedAt)
+		if err != nil {
+			u.logger.Warn("PostedAtの抽出に失敗しました", "error", err)
+		}
+		if len(extractedPostedAtStr) > 0 {
+			parsedTime, err := u.parser.ParsePostedAt(extractedPostedAtStr[0], reference)
+			if err != nil {
+				u.logger.Warn("PostedAtのパースに失敗しました", "error", err)
+				stats.addParseFailure("posted_at")
+			}
+			args.PostedAt = parsedTime
+		}
 	}
-	if len(extractedPostedAtStr) > 0 {
-		parsedTime, err := u.parser.ParsePostedAt(extractedPostedAtStr[0])
+
+	// ExpiresAtを抽出（任意）
+	if !ld.ValidThrough.IsZero() {
+		args.ExpiresAt = ld.ValidThrough
+	} else if u.cfg.ExpiresAt != nil {
+		extractedExpiresAtStr, err := u.extractValues(document, *u.cfg.ExpiresAt)
 		if err != nil {
-			u.logger.Warn("PostedAtのパースに失敗しました", "error", err)
+			u.logger.Warn("応募締切日の抽出に失敗しました", "error", err)
+		}
+		if len(extractedExpiresAtStr) > 0 {
+			parsedTime, err := u.parser.ParseExpiresAt(extractedExpiresAtStr[0])
+			if err != nil {
+				u.logger.Warn("応募締切日のパースに失敗しました", "error", err)
+				stats.addParseFailure("expires_at")
+			}
+			args.ExpiresAt = parsedTime
 		}
-		args.PostedAt = parsedTime
 	}
 
 	// Detailsを抽出
 	var details model.JobPostingDetailArgs
 
 	// JobName
-	extractedJobName, err := u.extractValues(htmlContent, u.cfg.Details.JobName)
+	extractedJobName, err := u.extractValues(document, u.cfg.Details.JobName)
 	if err != nil {
 		u.logger.Warn("職種名の抽出に失敗しました", "error", err)
 	}
@@ -288,7 +569,7 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// Description
-	extractedDescription, err := u.extractValues(htmlContent, u.cfg.Details.Description)
+	extractedDescription, err := u.extractValues(document, u.cfg.Details.Description)
 	if err != nil {
 		u.logger.Warn("募集要項の抽出に失敗しました", "error", err)
 	}
@@ -297,7 +578,7 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// Requirements
-	extractedRequirements, err := u.extractValues(htmlContent, u.cfg.Details.Requirements)
+	extractedRequirements, err := u.extractValues(document, u.cfg.Details.Requirements)
 	if err != nil {
 		u.logger.Warn("応募資格・条件の抽出に失敗しました", "error", err)
 	}
@@ -306,16 +587,17 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// WorkHours
-	extractedWorkHours, err := u.extractValues(htmlContent, u.cfg.Details.WorkHours)
+	extractedWorkHours, err := u.extractValues(document, u.cfg.Details.WorkHours)
 	if err != nil {
 		u.logger.Warn("勤務時間の抽出に失敗しました", "error", err)
 	}
 	if len(extractedWorkHours) > 0 {
 		details.WorkHours = extractedWorkHours[0]
+		details.WorkShifts = u.parser.ParseWorkHours(extractedWorkHours[0])
 	}
 
 	// WorkplaceType
-	extractedWorkplaceType, err := u.extractValues(htmlContent, u.cfg.Details.WorkplaceType)
+	extractedWorkplaceType, err := u.extractValues(document, u.cfg.Details.WorkplaceType)
 	if err != nil {
 		u.logger.Warn("勤務地タイプ情報の抽出に失敗しました", "error", err)
 	}
@@ -324,7 +606,7 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// Benefits
-	extractedBenefits, err := u.extractValues(htmlContent, u.cfg.Details.Benefits)
+	extractedBenefits, err := u.extractValues(document, u.cfg.Details.Benefits)
 	if err != nil {
 		u.logger.Warn("福利厚生の抽出に失敗しました", "error", err)
 	}
@@ -333,7 +615,7 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// Raise
-	extractedRaise, err := u.extractValues(htmlContent, u.cfg.Details.Raise)
+	extractedRaise, err := u.extractValues(document, u.cfg.Details.Raise)
 	if err != nil {
 		u.logger.Warn("昇給情報の抽出に失敗しました", "error", err)
 	}
@@ -343,7 +625,7 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// Bonus
-	extractedBonus, err := u.extractValues(htmlContent, u.cfg.Details.Bonus)
+	extractedBonus, err := u.extractValues(document, u.cfg.Details.Bonus)
 	if err != nil {
 		u.logger.Warn("賞与情報の抽出に失敗しました", "error", err)
 	}
@@ -353,7 +635,7 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 	}
 
 	// HolidaysPerYear
-	extractedHolidaysPerYear, err := u.extractValues(htmlContent, u.cfg.Details.HolidaysPerYear)
+	extractedHolidaysPerYear, err := u.extractValues(document, u.cfg.Details.HolidaysPerYear)
 	if err != nil {
 		u.logger.Warn("年間休日数の抽出に失敗しました", "error", err)
 	}
@@ -361,21 +643,93 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 		parsedHolidaysPerYear, err := u.parser.ParseOptionalUint(extractedHolidaysPerYear[0])
 		if err != nil {
 			u.logger.Warn("年間休日数のパースに失敗しました", "error", err)
+			stats.addParseFailure("holidays_per_year")
 		}
 		details.HolidaysPerYear = parsedHolidaysPerYear
 	}
 
 	// HolidayPolicy
-	extractedHolidayPolicy, err := u.extractValues(htmlContent, u.cfg.Details.HolidayPolicy)
+	extractedHolidayPolicy, err := u.extractValues(document, u.cfg.Details.HolidayPolicy)
 	if err != nil {
 		u.logger.Warn("休日休暇ポリシーの抽出に失敗しました", "error", err)
 	}
 	if len(extractedHolidayPolicy) > 0 {
 		details.HolidayPolicy = u.parser.ParseHolidayPolicy(extractedHolidayPolicy[0])
 	}
+	// Access（任意）
+	if u.cfg.Details.Access != nil {
+		extractedAccess, err := u.extractValues(document, *u.cfg.Details.Access)
+		if err != nil {
+			u.logger.Warn("アクセス情報の抽出に失敗しました", "error", err)
+		}
+		if len(extractedAccess) > 0 {
+			details.NearestStation = u.parser.ParseNearestStation(extractedAccess[0])
+		}
+	}
+	// ContractPeriod（任意）
+	if u.cfg.Details.ContractPeriod != nil {
+		extractedContractPeriod, err := u.extractValues(document, *u.cfg.Details.ContractPeriod)
+		if err != nil {
+			u.logger.Warn("契約期間の抽出に失敗しました", "error", err)
+		}
+		if len(extractedContractPeriod) > 0 {
+			details.ContractPeriod = u.parser.ParseContractPeriod(extractedContractPeriod[0])
+		}
+	}
+	// Probation（任意）
+	if u.cfg.Details.Probation != nil {
+		extractedProbation, err := u.extractValues(document, *u.cfg.Details.Probation)
+		if err != nil {
+			u.logger.Warn("試用期間の抽出に失敗しました", "error", err)
+		}
+		if len(extractedProbation) > 0 {
+			details.ProbationPeriod = u.parser.ParseProbationPeriod(extractedProbation[0])
+		}
+	}
+	// SmokingPolicy（任意）
+	if u.cfg.Details.SmokingPolicy != nil {
+		extractedSmokingPolicy, err := u.extractValues(document, *u.cfg.Details.SmokingPolicy)
+		if err != nil {
+			u.logger.Warn("受動喫煙対策の抽出に失敗しました", "error", err)
+		}
+		if len(extractedSmokingPolicy) > 0 {
+			details.SmokingPolicy = u.parser.ParseSmokingPolicy(extractedSmokingPolicy[0])
+		}
+	}
+
+	// マークアップが乏しいサイト向けに、値が得られなかった項目をOpenGraphなどのメタタグの値で補う
+	if u.cfg.MetaFallback {
+		pageMeta := document.ExtractPageMeta()
+		if args.Title == "" {
+			args.Title = pageMeta.Title
+		}
+		if args.SummaryURL == "" {
+			args.SummaryURL = pageMeta.URL
+		}
+		if args.PostedAt.IsZero() {
+			args.PostedAt = pageMeta.PublishedTime
+		}
+		if details.Description == "" {
+			details.Description = pageMeta.Description
+		}
+	}
 	extractDetails := model.NewJobPostingDetail(details)
 	args.Details = extractDetails
 
+	// extra_fieldsで定義した追加の項目は、パースせずに抽出した値をそのまま出力する
+	if len(u.cfg.ExtraFields) > 0 {
+		args.Extras = make(map[string]string, len(u.cfg.ExtraFields))
+		for name, selector := range u.cfg.ExtraFields {
+			extracted, err := u.extractValues(document, selector)
+			if err != nil {
+				u.logger.Warn("追加の項目の抽出に失敗しました", "field", name, "error", err)
+			}
+			if len(extracted) > 0 {
+				args.Extras[name] = extracted[0]
+			}
+		}
+	}
+
 	// JobPostingを生成して返す
 	return model.NewJobPosting(args)
 }
@@ -385,27 +739,27 @@ func (u *saveJobPostingFromHTMLUseCase) extractJobPosting(htmlContent string) mo
 //
 // args:
 //
-//	htmlContent : 解析対象のHTMLコンテンツ
-//	cfg         : 使用するセレクター設定
+//	document : 解析済みのHTML
+//	cfg      : 使用するセレクター設定
 //
 // return:
 //
 //	[]string : 抽出された文字列のスライス
 //	error    : 抽出処理中に発生したエラー
-func (u *saveJobPostingFromHTMLUseCase) extractValues(htmlContent string, cfg config.SelectorConfig) ([]string, error) {
+func (u *saveJobPostingFromHTMLUseCase) extractValues(document infra.HTMLDocument, cfg config.SelectorConfig) ([]string, error) {
 	var extracted []string
 	var err error
 
 	if cfg.Attr != "" {
-		extracted, err = u.document.ExtractAttribute(htmlContent, cfg.Selector, cfg.Attr)
+		extracted, err = document.ExtractAttribute(cfg.Selector, cfg.Attr)
 		return extracted, err
 	}
 
 	if cfg.Regex != "" {
-		extracted, err = u.document.ExtractTextByRegex(htmlContent, cfg.Selector, cfg.Regex)
+		extracted, err = document.ExtractTextByRegex(cfg.Selector, cfg.Regex)
 		return extracted, err
 	}
 
-	extracted, err = u.document.ExtractText(htmlContent, cfg.Selector)
+	extracted, err = document.ExtractText(cfg.Selector)
 	return extracted, err
 }